@@ -494,6 +494,62 @@ func (m *AisCloudProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (err
 	return extractErrCode(err)
 }
 
+//
+// replication: the destination bucket is not (necessarily) in the local BMD (see mirror.XactReplic)
+//
+
+func (m *AisCloudProvider) PutReplica(remoteBck cmn.Bck, lom *cluster.LOM, r cmn.ReadOpenCloser) error {
+	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	if err != nil {
+		return err
+	}
+	err = m.try(remoteBck, func(bck cmn.Bck) error {
+		args := api.PutObjectArgs{
+			BaseParams: aisCluster.bp,
+			Bck:        bck,
+			Object:     lom.ObjName,
+			Cksum:      lom.Cksum(),
+			Reader:     r,
+			Size:       uint64(lom.Size()),
+			UserMD:     lom.UserMD(),
+		}
+		return api.PutObject(args)
+	})
+	err, _ = extractErrCode(err)
+	return err
+}
+
+// DeleteReplica succeeds if the object does not exist at the destination.
+func (m *AisCloudProvider) DeleteReplica(remoteBck cmn.Bck, objName string) error {
+	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	if err != nil {
+		return err
+	}
+	err = m.try(remoteBck, func(bck cmn.Bck) error {
+		return api.DeleteObject(aisCluster.bp, bck, objName)
+	})
+	if err, errCode := extractErrCode(err); errCode != http.StatusNotFound {
+		return err
+	}
+	return nil
+}
+
+// HeadReplica returns nil props (and no error) if the object does not exist at the destination.
+func (m *AisCloudProvider) HeadReplica(remoteBck cmn.Bck, objName string) (props *cmn.ObjectProps, err error) {
+	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
+	if err != nil {
+		return nil, err
+	}
+	err = m.try(remoteBck, func(bck cmn.Bck) (err error) {
+		props, err = api.HeadObject(aisCluster.bp, bck, objName)
+		return
+	})
+	if err, errCode := extractErrCode(err); errCode != http.StatusNotFound {
+		return props, err
+	}
+	return nil, nil
+}
+
 func (m *AisCloudProvider) try(remoteBck cmn.Bck, f func(bck cmn.Bck) error) (err error) {
 	remoteBck.Ns.UUID = ""
 	for i := 0; i < aisCloudRetries+1; i++ {
@@ -67,6 +67,7 @@ type (
 		notifs     notifs
 		ic         ic
 		qm         queryMem
+		manifests  manifestCache
 		gmm        *memsys.MMSA // system pagesize-based memory manager and slab allocator
 	}
 	remBckAddArgs struct {
@@ -128,6 +129,7 @@ func (p *proxyrunner) Run() error {
 	p.notifs.init(p)
 	p.ic.init(p)
 	p.qm.init()
+	p.manifests.init()
 
 	//
 	// REST API: register proxy handlers and start listening
@@ -1027,6 +1029,16 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		p.listObjects(w, r, bck, msg, begin)
+	case cmn.ActGetBatch:
+		if err := p.checkPermissions(r.Header, &bck.Bck, cmn.AccessGET); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err = bck.Allow(cmn.AccessGET); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		p.getBatch(w, r, bck, &msg)
 	case cmn.ActInvalListCache:
 		if err = bck.Allow(cmn.AccessObjLIST); err != nil {
 			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
@@ -1304,6 +1316,28 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		}
 		p.promoteFQN(w, r, bck, &msg)
 		return
+	case cmn.ActCompose:
+		if err := p.checkPermissions(r.Header, &bck.Bck, cmn.AccessPUT); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err = bck.Allow(cmn.AccessPUT); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		p.objCompose(w, r, bck, &msg)
+		return
+	case cmn.ActUpdateObjMD:
+		if err := p.checkPermissions(r.Header, &bck.Bck, cmn.AccessPUT); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err = bck.Allow(cmn.AccessPUT); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		p.objUpdateMD(w, r, bck)
+		return
 	default:
 		p.invalmsghdlrf(w, r, fmtUnknownAct, msg)
 	}
@@ -1920,6 +1954,85 @@ func (p *proxyrunner) objRename(w http.ResponseWriter, r *http.Request, bck *clu
 	p.statsT.Add(stats.RenameCount, 1)
 }
 
+// update user-defined object metadata: redirect to the object's HRW target
+func (p *proxyrunner) objUpdateMD(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) {
+	started := time.Now()
+	apiItems, err := p.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	objName := apiItems[1]
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("UPDATE-MD %s/%s => %s", bck, objName, si)
+	}
+	// NOTE: code 307 to redirect with the original JSON payload
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraControl)
+	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+}
+
+// compose (concatenate) source objects into the destination: validate sources
+// and redirect to the destination's HRW target that does the rest
+func (p *proxyrunner) objCompose(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, msg *cmn.ActionMsg) {
+	started := time.Now()
+	apiItems, err := p.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	objName := apiItems[1]
+	cmsg := &cmn.ComposeMsg{}
+	if err := cmn.MorphMarshal(msg.Value, cmsg); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(cmsg.Sources) == 0 {
+		p.invalmsghdlr(w, r, "compose: no source objects specified", http.StatusBadRequest)
+		return
+	}
+	checked := make(map[string]struct{}, 2)
+	for _, src := range cmsg.Sources {
+		if src.ObjName == "" {
+			p.invalmsghdlr(w, r, "compose: source object name is empty", http.StatusBadRequest)
+			return
+		}
+		uname := src.Bck.String()
+		if _, ok := checked[uname]; ok {
+			continue
+		}
+		srcBck := cluster.NewBckEmbed(src.Bck)
+		if err = srcBck.Init(p.owner.bmd, p.si); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err := p.checkPermissions(r.Header, &srcBck.Bck, cmn.AccessGET); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err = srcBck.Allow(cmn.AccessGET); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		checked[uname] = struct{}{}
+	}
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("COMPOSE %d object(s) => %s/%s @ %s", len(cmsg.Sources), bck, objName, si)
+	}
+	// NOTE: code 307 to redirect with the original JSON payload
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraControl)
+	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+}
+
 func (p *proxyrunner) promoteFQN(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, msg *cmn.ActionMsg) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Objects)
 	if err != nil {
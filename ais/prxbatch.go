@@ -0,0 +1,477 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/dsort"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Multi-object GET (aka "get batch"): the proxy resolves the requested names,
+// fans out GETs to the owning (HRW) targets via intra-data network, and streams
+// back a single archive, in request or completion order.
+// Alternatively, the proxy assembles a shard of the dSort virtual shuffle from
+// the files of the input shards (see dsort.Manifest); the manifests are cached.
+
+const (
+	batchWorkers = 16 // max number of objects that are concurrently fetched (and buffered)
+
+	manifestCacheMax = 16               // max number of cached dSort manifests
+	manifestIdleTime = 10 * time.Minute // evict manifests not accessed for that long
+)
+
+type (
+	batchEntry struct {
+		idx  int
+		name string
+		sgl  *memsys.SGL
+		err  error
+		code int
+	}
+	batchCtx struct {
+		p       *proxyrunner
+		bck     *cluster.Bck
+		msg     *cmn.GetBatchMsg
+		names   []string
+		started time.Time
+
+		// assembling dSort shard: bucket of the input shards and files to fetch
+		srcBck  *cluster.Bck
+		members []dsort.ManifestMember
+
+		sem   chan struct{}
+		work  chan int
+		done  chan *batchEntry   // completion order
+		slots []chan *batchEntry // request order
+		stop  chan struct{}
+		wg    sync.WaitGroup
+	}
+
+	manifestEntry struct {
+		mf         *dsort.Manifest
+		tag        string // checksum and version of the manifest object
+		epoch      int    // the last requested epoch
+		order      []int  // and its order of records
+		lastAccess int64
+	}
+	manifestCache struct {
+		mtx sync.Mutex
+		m   map[string]*manifestEntry // by uname
+	}
+)
+
+// POST { action: getbatch } /v1/buckets/bucket-name
+func (p *proxyrunner) getBatch(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, amsg *cmn.ActionMsg) {
+	var (
+		err error
+		msg = &cmn.GetBatchMsg{}
+	)
+	if err = cmn.MorphMarshal(amsg.Value, msg); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if msg.Format, err = archive.ValidateFormat(msg.Format); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := &batchCtx{p: p, bck: bck, msg: msg, started: time.Now()}
+	if msg.Manifest != "" {
+		err = p.batchManifestShard(ctx)
+	} else {
+		ctx.names, err = p.batchObjNames(bck, msg)
+	}
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s %s: %d object(s), format %q, ordered %t", amsg.Action, bck, len(ctx.names), msg.Format, msg.Ordered)
+	}
+	ctx.run(w, r)
+}
+
+// resolve ObjNames | Template | Prefix into the list of object names
+func (p *proxyrunner) batchObjNames(bck *cluster.Bck, msg *cmn.GetBatchMsg) (names []string, err error) {
+	var cnt int
+	if len(msg.ObjNames) > 0 {
+		cnt++
+	}
+	if msg.Template != "" {
+		cnt++
+	}
+	if msg.Prefix != "" {
+		cnt++
+	}
+	if cnt != 1 {
+		return nil, errors.New("exactly one of object names, template, or prefix must be specified")
+	}
+	switch {
+	case len(msg.ObjNames) > 0:
+		return msg.ObjNames, nil
+	case msg.Template != "":
+		pt, err := cmn.ParseBashTemplate(msg.Template)
+		if err != nil {
+			return nil, err
+		}
+		return pt.ToSlice(), nil
+	default:
+		smsg := cmn.SelectMsg{UUID: cmn.GenUUID(), Prefix: msg.Prefix, Props: cmn.GetPropsName}
+		if !bck.IsAIS() {
+			smsg.Flags = cmn.SelectCached
+		}
+		for {
+			list, err := p.listObjectsAIS(bck, smsg)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range list.Entries {
+				names = append(names, e.Name)
+			}
+			if list.ContinuationToken == "" {
+				break
+			}
+			smsg.ContinuationToken = list.ContinuationToken
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s: no objects with prefix %q", bck, msg.Prefix)
+		}
+		return names, nil
+	}
+}
+
+// resolve the files of the shard `msg.Shard` of the epoch `msg.Epoch`
+func (p *proxyrunner) batchManifestShard(ctx *batchCtx) error {
+	msg := ctx.msg
+	if len(msg.ObjNames) > 0 || msg.Template != "" || msg.Prefix != "" {
+		return errors.New("exactly one of object names, template, prefix, or manifest must be specified")
+	}
+	if msg.Epoch < 0 {
+		return fmt.Errorf("invalid epoch %d", msg.Epoch)
+	}
+	mf, order, err := p.manifests.get(p, ctx.bck, msg.Manifest, msg.Epoch)
+	if err != nil {
+		return err
+	}
+	if ctx.members, err = mf.Members(order, msg.Shard); err != nil {
+		return err
+	}
+	ctx.srcBck = cluster.NewBckEmbed(mf.Bck)
+	if err = ctx.srcBck.Init(p.owner.bmd, p.si); err != nil {
+		return err
+	}
+	ctx.names = make([]string, 0, len(ctx.members))
+	for _, member := range ctx.members {
+		ctx.names = append(ctx.names, member.Archpath)
+	}
+	msg.Ordered = true // the order is defined by the epoch
+	return nil
+}
+
+func (entry *batchEntry) free() {
+	if entry.sgl != nil {
+		entry.sgl.Free()
+		entry.sgl = nil
+	}
+}
+
+//////////////
+// batchCtx //
+//////////////
+
+func (ctx *batchCtx) run(w http.ResponseWriter, r *http.Request) {
+	var (
+		aw      archive.Writer
+		cnt     int64
+		written bool
+		n       = len(ctx.names)
+		workers = cmn.Min(batchWorkers, n)
+	)
+	ctx.sem = make(chan struct{}, workers)
+	ctx.work = make(chan int, workers)
+	ctx.stop = make(chan struct{})
+	if ctx.msg.Ordered {
+		ctx.slots = make([]chan *batchEntry, n)
+		for i := range ctx.slots {
+			ctx.slots[i] = make(chan *batchEntry, 1)
+		}
+	} else {
+		ctx.done = make(chan *batchEntry, workers)
+	}
+	defer close(ctx.stop)
+
+	go ctx.dispatch()
+	ctx.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go ctx.worker()
+	}
+
+	for i := 0; i < n; i++ {
+		var entry *batchEntry
+		if ctx.msg.Ordered {
+			entry = <-ctx.slots[i]
+		} else {
+			entry = <-ctx.done
+		}
+		if entry.err != nil {
+			if entry.code == http.StatusNotFound && ctx.msg.SkipMissing {
+				<-ctx.sem
+				continue
+			}
+			ctx.drain()
+			if !written {
+				ctx.p.invalmsghdlr(w, r, entry.err.Error(), entry.code)
+				return
+			}
+			// the response is already on the wire - the best we can do is to terminate the stream
+			glog.Errorf("%s: aborting get-batch from %s: %v", ctx.p.si, ctx.bck, entry.err)
+			return
+		}
+		if !written {
+			written = true
+			w.Header().Set(cmn.HeaderContentType, cmn.ContentBinary)
+			aw = archive.NewWriter(ctx.msg.Format, w)
+		}
+		err := aw.Write(entry.name, entry.sgl.Size(), entry.sgl)
+		entry.free()
+		<-ctx.sem
+		if err != nil {
+			ctx.drain()
+			if !cmn.IsErrConnectionReset(err) {
+				glog.Errorf("%s: failed to write %s/%s: %v", ctx.p.si, ctx.bck, entry.name, err)
+			}
+			return
+		}
+		cnt++
+	}
+	if !written {
+		w.Header().Set(cmn.HeaderContentType, cmn.ContentBinary)
+		aw = archive.NewWriter(ctx.msg.Format, w)
+	}
+	if err := aw.Close(); err != nil && !cmn.IsErrConnectionReset(err) {
+		glog.Errorf("%s: failed to finalize get-batch from %s: %v", ctx.p.si, ctx.bck, err)
+	}
+	ctx.p.statsT.AddMany(
+		stats.NamedVal64{Name: stats.GetBatchCount, Value: 1},
+		stats.NamedVal64{Name: stats.GetBatchObjCount, Value: cnt},
+		stats.NamedVal64{Name: stats.GetBatchLatency, Value: int64(time.Since(ctx.started))},
+	)
+}
+
+// dispatch indices in order; acquiring the semaphore prior to dispatching
+// guarantees that in-flight entries are always the lowest not-yet-written ones
+func (ctx *batchCtx) dispatch() {
+	defer close(ctx.work)
+	for i := range ctx.names {
+		select {
+		case ctx.sem <- struct{}{}:
+		case <-ctx.stop:
+			return
+		}
+		select {
+		case ctx.work <- i:
+		case <-ctx.stop:
+			return
+		}
+	}
+}
+
+func (ctx *batchCtx) worker() {
+	defer ctx.wg.Done()
+	for idx := range ctx.work {
+		entry := ctx.fetch(idx)
+		if ctx.msg.Ordered {
+			ctx.slots[idx] <- entry // buffered
+			continue
+		}
+		select {
+		case ctx.done <- entry:
+		case <-ctx.stop:
+			entry.free()
+		}
+	}
+}
+
+// upon abort: free what's been already fetched but won't be written
+func (ctx *batchCtx) drain() {
+	go func() {
+		ctx.wg.Wait()
+		for _, slot := range ctx.slots {
+			select {
+			case entry := <-slot:
+				entry.free()
+			default:
+			}
+		}
+		for {
+			select {
+			case entry := <-ctx.done:
+				entry.free()
+			default:
+				return
+			}
+		}
+	}()
+}
+
+func (ctx *batchCtx) fetch(idx int) (entry *batchEntry) {
+	var (
+		p       = ctx.p
+		smap    = p.owner.smap.get()
+		query   = url.Values{}
+		bck     = ctx.bck
+		objName = ctx.names[idx]
+	)
+	entry = &batchEntry{idx: idx, name: objName}
+	if ctx.members != nil {
+		bck, objName = ctx.srcBck, ctx.members[idx].ObjName
+		query.Set(cmn.URLParamArchpath, ctx.members[idx].Archpath)
+	}
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		entry.err, entry.code = err, http.StatusInternalServerError
+		return
+	}
+	query = cmn.AddBckToQuery(query, bck.Bck)
+	query.Add(cmn.URLParamProxyID, p.si.ID())
+	query.Add(cmn.URLParamUnixTime, cmn.UnixNano2S(time.Now().UnixNano()))
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objName),
+		Query:  query,
+	}
+	req, _, cancel, err := reqArgs.ReqWithTimeout(cmn.GCO.Get().Timeout.SendFile)
+	if err != nil {
+		entry.err, entry.code = err, http.StatusInternalServerError
+		return
+	}
+	defer cancel()
+	resp, err := p.httpclientGetPut.Do(req)
+	if err != nil {
+		entry.err, entry.code = fmt.Errorf("%s: failed to GET %s/%s from %s: %v", p.si, bck, entry.name, si, err),
+			http.StatusInternalServerError
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		cmn.DrainReader(resp.Body)
+		entry.err = fmt.Errorf("%s: failed to GET %s/%s from %s: status %d", p.si, bck, entry.name, si, resp.StatusCode)
+		entry.code = resp.StatusCode
+		return
+	}
+	entry.sgl = p.gmm.NewSGL(cmn.MaxI64(resp.ContentLength, 0))
+	if _, err = entry.sgl.ReadFrom(resp.Body); err != nil {
+		entry.free()
+		entry.err, entry.code = err, http.StatusInternalServerError
+	}
+	return
+}
+
+///////////////////
+// manifestCache //
+///////////////////
+
+func (c *manifestCache) init() {
+	c.m = make(map[string]*manifestEntry, manifestCacheMax)
+	hk.Reg("dsort-manifest-cache", c.housekeep, manifestIdleTime)
+}
+
+// returns the manifest along with the order of records of the given epoch;
+// the cached manifest is used if (and only if) the object has not changed since
+func (c *manifestCache) get(p *proxyrunner, bck *cluster.Bck, objName string, epoch int) (*dsort.Manifest, []int, error) {
+	uname := bck.MakeUname(objName)
+	res := p.callManifest(http.MethodHead, bck, objName, nil)
+	if res.err != nil {
+		return nil, nil, fmt.Errorf("failed to HEAD %s/%s manifest: %v", bck, objName, res.err)
+	}
+	tag := res.header.Get(cmn.HeaderObjCksumVal) + "/" + res.header.Get(cmn.HeaderObjVersion)
+
+	c.mtx.Lock()
+	e, ok := c.m[uname]
+	if !ok || e.tag != tag {
+		c.mtx.Unlock()
+		mf := &dsort.Manifest{}
+		if res := p.callManifest(http.MethodGet, bck, objName, mf); res.err != nil {
+			return nil, nil, fmt.Errorf("failed to load %s/%s manifest: %v", bck, objName, res.err)
+		}
+		e = &manifestEntry{mf: mf, tag: tag, epoch: -1}
+		c.mtx.Lock()
+		if tag != "/" { // cannot tell whether the object changes - do not cache
+			if _, ok := c.m[uname]; !ok && len(c.m) >= manifestCacheMax {
+				c.evictOldest()
+			}
+			c.m[uname] = e
+		}
+	}
+	if e.epoch != epoch {
+		e.order, e.epoch = e.mf.EpochOrder(epoch), epoch
+	}
+	e.lastAccess = mono.NanoTime()
+	mf, order := e.mf, e.order
+	c.mtx.Unlock()
+	return mf, order, nil
+}
+
+// under lock
+func (c *manifestCache) evictOldest() {
+	var (
+		oldest string
+		access int64
+	)
+	for uname, e := range c.m {
+		if oldest == "" || e.lastAccess < access {
+			oldest, access = uname, e.lastAccess
+		}
+	}
+	delete(c.m, oldest)
+}
+
+func (c *manifestCache) housekeep() time.Duration {
+	now := mono.NanoTime()
+	c.mtx.Lock()
+	for uname, e := range c.m {
+		if time.Duration(now-e.lastAccess) > manifestIdleTime {
+			delete(c.m, uname)
+		}
+	}
+	c.mtx.Unlock()
+	return manifestIdleTime
+}
+
+func (p *proxyrunner) callManifest(method string, bck *cluster.Bck, objName string, v interface{}) callResult {
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		return callResult{err: err}
+	}
+	query := cmn.AddBckToQuery(nil, bck.Bck)
+	query.Set(cmn.URLParamProxyID, p.si.ID())
+	query.Set(cmn.URLParamUnixTime, cmn.UnixNano2S(time.Now().UnixNano()))
+	return p.call(callArgs{
+		si: si,
+		req: cmn.ReqArgs{
+			Method: method,
+			Base:   si.URL(cmn.NetworkIntraData),
+			Path:   cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objName),
+			Query:  query,
+		},
+		timeout: cmn.LongTimeout,
+		v:       v,
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
@@ -89,7 +91,7 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method, path string, msg *do
 
 		respJSON := cmn.MustMarshal(resp)
 		return respJSON, http.StatusOK, nil
-	case http.MethodDelete:
+	case http.MethodDelete, http.MethodPut:
 		res := validResponses[0]
 		return res.bytes, res.status, res.err
 	default:
@@ -98,9 +100,60 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method, path string, msg *do
 	}
 }
 
+// broadcastDownloadScheduleRequest lists (GET) or removes (DELETE) download schedules.
+func (p *proxyrunner) broadcastDownloadScheduleRequest(method, path string, msg *downloader.DlAdminBody) ([]byte, int, error) {
+	var (
+		notFoundCnt int
+		err         error
+		aggregate   = make(map[string]*downloader.DlScheduleInfo)
+	)
+	body := cmn.MustMarshal(msg)
+	responses := p.broadcastDownloadRequest(method, path, body, url.Values{})
+	respCnt := len(responses)
+	cmn.Assert(respCnt > 0)
+	for resp := range responses {
+		if resp.status == http.StatusNotFound {
+			notFoundCnt++
+			err = resp.err
+			continue
+		}
+		if resp.status != http.StatusOK {
+			return nil, resp.status, resp.err
+		}
+		if method != http.MethodGet {
+			continue
+		}
+		var parsedResp map[string]*downloader.DlScheduleInfo
+		err := jsoniter.Unmarshal(resp.bytes, &parsedResp)
+		cmn.AssertNoErr(err)
+		for k, v := range parsedResp {
+			if info, ok := aggregate[k]; ok {
+				info.Aggregate(v)
+			} else {
+				aggregate[k] = v
+			}
+		}
+	}
+
+	if notFoundCnt == respCnt { // all responded with 404
+		return nil, http.StatusNotFound, err
+	}
+	if method != http.MethodGet {
+		return nil, http.StatusOK, nil
+	}
+	schedules := make(downloader.DlScheduleInfos, 0, len(aggregate))
+	for _, v := range aggregate {
+		schedules = append(schedules, v)
+	}
+	sort.Sort(schedules)
+	return cmn.MustMarshal(schedules), http.StatusOK, nil
+}
+
 func (p *proxyrunner) broadcastStartDownloadRequest(r *http.Request, id string, body []byte) (err error, errCode int) {
 	query := r.URL.Query()
 	query.Set(cmn.URLParamUUID, id)
+	// creation time - the runs of the scheduled download are started relative to it
+	query.Set(cmn.URLParamUnixTime, cmn.UnixNano2S(time.Now().UnixNano()))
 
 	responses := p.broadcastDownloadRequest(http.MethodPost, r.URL.Path, body, query)
 	failures := make([]error, 0, len(responses))
@@ -124,20 +177,22 @@ func (p *proxyrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	switch r.Method {
-	case http.MethodGet, http.MethodDelete:
+	case http.MethodGet, http.MethodDelete, http.MethodPut:
 		p.httpDownloadAdmin(w, r)
 	case http.MethodPost:
 		p.httpDownloadPost(w, r)
 	default:
-		s := fmt.Sprintf("invalid method %s for /download path; expected one of %s, %s, %s",
-			r.Method, http.MethodGet, http.MethodDelete, http.MethodPost)
+		s := fmt.Sprintf("invalid method %s for /download path; expected one of %s, %s, %s, %s",
+			r.Method, http.MethodGet, http.MethodDelete, http.MethodPut, http.MethodPost)
 		cmn.InvalidHandlerWithMsg(w, r, s)
 	}
 }
 
-// httpDownloadAdmin is meant for aborting, removing and getting status updates for downloads.
+// httpDownloadAdmin is meant for aborting, removing, pausing and getting status updates for downloads.
 // GET /v1/download?id=...
-// DELETE /v1/download/{abort, remove}?id=...
+// GET /v1/download/schedule?id=...
+// DELETE /v1/download/{abort, remove, schedule}?id=...
+// PUT /v1/download/{pause, resume, priority}?id=...
 func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request) {
 	var (
 		payload = &downloader.DlAdminBody{}
@@ -149,7 +204,7 @@ func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request)
 	if err := cmn.ReadJSON(w, r, &payload); err != nil {
 		return
 	}
-	if err := payload.Validate(r.Method == http.MethodDelete); err != nil {
+	if err := payload.Validate(r.Method == http.MethodDelete || r.Method == http.MethodPut); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
@@ -161,9 +216,30 @@ func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		if items[0] != cmn.Abort && items[0] != cmn.Remove {
-			s := fmt.Sprintf("Invalid action for DELETE request: %s (expected either %s or %s).",
-				items[0], cmn.Abort, cmn.Remove)
+		if items[0] != cmn.Abort && items[0] != cmn.Remove && items[0] != cmn.Schedule {
+			s := fmt.Sprintf("Invalid action for DELETE request: %s (expected one of %s, %s, %s).",
+				items[0], cmn.Abort, cmn.Remove, cmn.Schedule)
+			cmn.InvalidHandlerWithMsg(w, r, s)
+			return
+		}
+	}
+	if r.Method == http.MethodPut {
+		items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
+		if err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error())
+			return
+		}
+
+		switch items[0] {
+		case cmn.Pause, cmn.Resume:
+		case cmn.Priority:
+			if err := downloader.ValidatePriority(payload.Priority); err != nil {
+				p.invalmsghdlr(w, r, err.Error())
+				return
+			}
+		default:
+			s := fmt.Sprintf("Invalid action for PUT request: %s (expected one of %s, %s, %s).",
+				items[0], cmn.Pause, cmn.Resume, cmn.Priority)
 			cmn.InvalidHandlerWithMsg(w, r, s)
 			return
 		}
@@ -173,7 +249,17 @@ func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request)
 		glog.Infof("httpDownloadAdmin payload %v", payload)
 	}
 
-	resp, statusCode, err := p.broadcastDownloadAdminRequest(r.Method, r.URL.Path, payload)
+	var (
+		resp       []byte
+		statusCode int
+		err        error
+	)
+	items, _ := cmn.MatchRESTItems(r.URL.Path, 0, false, cmn.Version, cmn.Download)
+	if len(items) > 0 && items[0] == cmn.Schedule {
+		resp, statusCode, err = p.broadcastDownloadScheduleRequest(r.Method, r.URL.Path, payload)
+	} else {
+		resp, statusCode, err = p.broadcastDownloadAdminRequest(r.Method, r.URL.Path, payload)
+	}
 	if err != nil {
 		p.invalmsghdlr(w, r, err.Error(), statusCode)
 		return
@@ -235,6 +321,46 @@ func (p *proxyrunner) validateStartDownloadRequest(w http.ResponseWriter, r *htt
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if payload.Schedule != nil {
+		if err := payload.Schedule.Validate(); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if payload.Credentials != nil {
+		if err := payload.Credentials.Validate(); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if dlb.Type == downloader.DlTypeManifest {
+		return p.validateDownloadManifest(w, r, dlb)
+	}
+	return true
+}
+
+func (p *proxyrunner) validateDownloadManifest(w http.ResponseWriter, r *http.Request, dlb downloader.DlBody) (ok bool) {
+	payload := downloader.DlManifestBody{}
+	if err := jsoniter.Unmarshal(dlb.RawMessage, &payload); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := payload.Validate(); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if payload.ManifestBck.Name == "" {
+		return true
+	}
+	bck := cluster.NewBckEmbed(payload.ManifestBck)
+	if err := bck.Init(p.owner.bmd, p.si); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := bck.Allow(cmn.AccessGET); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 	return true
 }
 
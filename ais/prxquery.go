@@ -5,12 +5,16 @@
 package ais
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"sort"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/query"
@@ -75,9 +79,22 @@ func (p *proxyrunner) queryHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (p *proxyrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
-	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Query, cmn.Init); err != nil {
+	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Query)
+	if err != nil {
 		return
 	}
+	switch apiItems[0] {
+	case cmn.Init:
+		p.httpqueryinit(w, r)
+	case cmn.Select:
+		p.httpqueryselect(w, r)
+	default:
+		p.invalmsghdlrf(w, r, "unknown path /%s/%s/%s", cmn.Version, cmn.Query, apiItems[0])
+	}
+}
+
+// /v1/query/init
+func (p *proxyrunner) httpqueryinit(w http.ResponseWriter, r *http.Request) {
 
 	// A target will return error if given handle already exists (though is very unlikely).
 	handle := cmn.GenUUID()
@@ -86,6 +103,30 @@ func (p *proxyrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
 	if err := cmn.ReadJSON(w, r, msg); err != nil {
 		return
 	}
+	if msg.Expr != "" {
+		def, err := query.Parse(msg.Expr)
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		msg.QueryMsg, msg.Expr = *def, "" // targets get the parsed query
+	}
+	if msg.QueryMsg.InnerSelect.Content != nil {
+		p.invalmsghdlrf(w, r, "content select must be requested via /%s/%s/%s", cmn.Version, cmn.Query, cmn.Select)
+		return
+	}
+	var manifest *query.ResultsManifest
+	if msg.Persist != nil {
+		var err error
+		if manifest, err = p.initQueryPersist(handle, msg); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if manifest.Done {
+			w.Write([]byte(manifest.Handle))
+			return
+		}
+	}
 
 	if _, err := query.NewQueryFromMsg(p, &msg.QueryMsg); err != nil {
 		p.invalmsghdlr(w, r, "Failed to parse query message: "+err.Error())
@@ -118,9 +159,114 @@ func (p *proxyrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
 	nlq.hrwOwner(smap)
 	p.ic.registerEqual(regIC{nl: nlq, smap: smap, msg: msg})
 
+	if manifest != nil {
+		go p.persistQuery(handle, msg.Persist, manifest)
+		handle = manifest.Handle // stable handle of the persisted results
+	}
 	w.Write([]byte(handle))
 }
 
+// initQueryPersist validates the request to persist query results and stores
+// the initial manifest; when resuming, the query is restarted after the last
+// persisted entry (see query.PersistMsg)
+func (p *proxyrunner) initQueryPersist(handle string, msg *query.InitMsg) (*query.ResultsManifest, error) {
+	persist := msg.Persist
+	bck := cluster.NewBckEmbed(persist.Bck)
+	if err := bck.Init(p.owner.bmd, p.si); err != nil {
+		return nil, err
+	}
+	persist.Bck = bck.Bck
+	if err := persist.Validate(); err != nil {
+		return nil, err
+	}
+	if persist.Resume == "" {
+		if msg.QueryMsg.Aggregate != nil {
+			return nil, errors.New("results of aggregation query cannot be persisted")
+		}
+		manifest := &query.ResultsManifest{Handle: handle, Query: msg.QueryMsg}
+		return manifest, p.putQueryObj(persist.Bck, persist.ManifestName(handle), manifest)
+	}
+
+	manifest := &query.ResultsManifest{}
+	if err := p.getQueryObj(persist.Bck, persist.ManifestName(persist.Resume), manifest); err != nil {
+		return nil, fmt.Errorf("failed to load %q query results manifest: %v", persist.Resume, err)
+	}
+	if !manifest.Done {
+		msg.QueryMsg = manifest.Query
+		msg.QueryMsg.OuterSelect.StartAfter = manifest.Last
+		manifest.Err = ""
+	}
+	return manifest, nil
+}
+
+// persistQuery fetches the query results page by page and stores them in the
+// bucket; the manifest is updated after each page
+func (p *proxyrunner) persistQuery(handle string, persist *query.PersistMsg, manifest *query.ResultsManifest) {
+	var (
+		next         = &query.NextMsg{Handle: handle, Size: persist.PageSize}
+		manifestName = persist.ManifestName(manifest.Handle)
+	)
+	for {
+		entries, err, _ := p.queryNext(next)
+		if err != nil {
+			manifest.Err = err.Error()
+			break
+		}
+		if len(entries) == 0 {
+			manifest.Done = true
+			break
+		}
+		if err := p.putQueryObj(persist.Bck, persist.PageName(manifest.Handle, manifest.Pages), entries); err != nil {
+			manifest.Err = err.Error()
+			break
+		}
+		manifest.AddPage(entries)
+		if err := p.putQueryObj(persist.Bck, manifestName, manifest); err != nil {
+			manifest.Err = err.Error()
+			break
+		}
+	}
+	if manifest.Err != "" {
+		glog.Errorf("%s: failed to persist query %q results: %s", p.si, manifest.Handle, manifest.Err)
+	}
+	if err := p.putQueryObj(persist.Bck, manifestName, manifest); err != nil {
+		glog.Errorf("%s: failed to store query %q manifest: %v", p.si, manifest.Handle, err)
+	}
+}
+
+// putQueryObj stores JSON-encoded value as an object (e.g., query results page)
+func (p *proxyrunner) putQueryObj(bck cmn.Bck, objName string, v interface{}) error {
+	return p.callQueryObj(http.MethodPut, bck, objName, cmn.MustMarshal(v), nil)
+}
+
+func (p *proxyrunner) getQueryObj(bck cmn.Bck, objName string, v interface{}) error {
+	return p.callQueryObj(http.MethodGet, bck, objName, nil, v)
+}
+
+func (p *proxyrunner) callQueryObj(method string, bck cmn.Bck, objName string, body []byte, v interface{}) error {
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(cluster.NewBckEmbed(bck).MakeUname(objName), &smap.Smap)
+	if err != nil {
+		return err
+	}
+	q := cmn.AddBckToQuery(nil, bck)
+	q.Set(cmn.URLParamProxyID, p.si.ID())
+	q.Set(cmn.URLParamUnixTime, cmn.UnixNano2S(time.Now().UnixNano()))
+	res := p.call(callArgs{
+		si: si,
+		req: cmn.ReqArgs{
+			Method: method,
+			Base:   si.URL(cmn.NetworkIntraData),
+			Path:   cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objName),
+			Query:  q,
+			Body:   body,
+		},
+		timeout: cmn.LongTimeout,
+		v:       v,
+	})
+	return res.err
+}
+
 func (p *proxyrunner) httpqueryget(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Query)
 	if err != nil {
@@ -132,6 +278,8 @@ func (p *proxyrunner) httpqueryget(w http.ResponseWriter, r *http.Request) {
 		p.httpquerygetnext(w, r)
 	case cmn.WorkerOwner:
 		p.httpquerygetworkertarget(w, r)
+	case cmn.Aggregate:
+		p.httpquerygetaggregate(w, r)
 	default:
 		p.invalmsghdlrf(w, r, "unknown path /%s/%s/%s", cmn.Version, cmn.Query, apiItems[0])
 	}
@@ -194,6 +342,22 @@ func (p *proxyrunner) httpquerygetnext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	entries, err, status := p.queryNext(msg)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error(), status)
+		return
+	}
+	if len(entries) == 0 {
+		// TODO: Maybe we should just return empty response and `http.StatusNoContent`?
+		p.invalmsghdlrstatusf(w, r, http.StatusGone, "%q finished", msg.Handle)
+		return
+	}
+	p.writeJSON(w, r, entries, "query_objects")
+}
+
+// queryNext returns (and discards on the targets) the next `msg.Size` entries;
+// no entries means that the query has finished
+func (p *proxyrunner) queryNext(msg *query.NextMsg) ([]*cmn.BucketEntry, error, int) {
 	var (
 		results = p.bcastToGroup(bcastArgs{
 			req: cmn.ReqArgs{
@@ -212,29 +376,162 @@ func (p *proxyrunner) httpquerygetnext(w http.ResponseWriter, r *http.Request) {
 			if res.status == http.StatusNotFound {
 				continue
 			}
-			p.invalmsghdlr(w, r, res.err.Error(), res.status)
-			return
+			return nil, res.err, res.status
 		}
 		lists = append(lists, res.v.(*cmn.BucketList))
 	}
 
 	result := cmn.ConcatObjLists(lists, msg.Size)
-	if len(result.Entries) == 0 {
-		// TODO: Maybe we should just return empty response and `http.StatusNoContent`?
-		p.invalmsghdlrstatusf(w, r, http.StatusGone, "%q finished", msg.Handle)
-		return
-	}
-
 	if len(result.Entries) > 0 {
 		last := result.Entries[len(result.Entries)-1]
 		discardResults := p.callTargets(http.MethodPut, cmn.URLPath(cmn.Version, cmn.Query, cmn.Discard, msg.Handle, last.Name), nil)
 
 		for res := range discardResults {
 			if res.err != nil && res.status != http.StatusNotFound {
-				p.invalmsghdlr(w, r, res.err.Error())
-				return
+				return nil, res.err, http.StatusInternalServerError
 			}
 		}
 	}
-	p.writeJSON(w, r, result.Entries, "query_objects")
+	return result.Entries, nil, 0
+}
+
+// /v1/query/select
+// Content select: the targets scan their objects one at a time - the next target
+// is requested only when the rows of the previous one have been streamed back
+// to the client. An error that occurs after the streaming has started is
+// reported via the `Error` trailer.
+func (p *proxyrunner) httpqueryselect(w http.ResponseWriter, r *http.Request) {
+	msg := &query.InitMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.Expr != "" {
+		def, err := query.Parse(msg.Expr)
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		def.InnerSelect.Content = msg.QueryMsg.InnerSelect.Content
+		msg.QueryMsg, msg.Expr = *def, ""
+	}
+	if msg.QueryMsg.InnerSelect.Content == nil {
+		p.invalmsghdlr(w, r, "content select is not defined", http.StatusBadRequest)
+		return
+	}
+	if _, err := query.NewQueryFromMsg(p, &msg.QueryMsg); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		smap    = p.owner.smap.get()
+		targets = smap.Tmap.Nodes()
+		body    = cmn.MustMarshal(&msg.QueryMsg)
+		client  = *p.httpclientGetPut
+		started bool
+	)
+	// streaming: the duration is up to the targets, the client must not time out
+	client.Timeout = 0
+	sort.Slice(targets, func(i, j int) bool { return targets[i].DaemonID < targets[j].DaemonID })
+	for _, si := range targets {
+		resp, err := p.selectContent(r.Context(), &client, si, body)
+		if err != nil {
+			if !started {
+				p.invalmsghdlr(w, r, err.Error())
+			} else {
+				w.Header().Set(cmn.HeaderError, err.Error())
+			}
+			return
+		}
+		if !started {
+			w.Header().Set("Trailer", cmn.HeaderError)
+			started = true
+		}
+		_, err = io.Copy(w, resp.Body)
+		if err == nil {
+			if errMsg := resp.Trailer.Get(cmn.HeaderError); errMsg != "" {
+				err = errors.New(errMsg)
+			}
+		}
+		resp.Body.Close()
+		if err != nil {
+			w.Header().Set(cmn.HeaderError, fmt.Sprintf("%s: %v", si, err))
+			return
+		}
+	}
+}
+
+// selectContent requests the content select from the target; the caller reads
+// out and closes the response body.
+func (p *proxyrunner) selectContent(ctx context.Context, client *http.Client, si *cluster.Snode,
+	body []byte) (*http.Response, error) {
+	args := cmn.ReqArgs{
+		Method: http.MethodPost,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Query, cmn.Select),
+		Body:   body,
+	}
+	req, err := args.Req()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx)) // nolint:bodyclose // closed by the caller
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", si, b)
+	}
+	return resp, nil
+}
+
+// /v1/query/aggregate
+// Aggregation result is collected from all targets once all of them are done;
+// until then, the response has `done` = false.
+func (p *proxyrunner) httpquerygetaggregate(w http.ResponseWriter, r *http.Request) {
+	msg := &query.NextMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.Handle == "" {
+		p.invalmsghdlr(w, r, "handle cannot be empty", http.StatusBadRequest)
+		return
+	}
+	if p.ic.reverseToOwner(w, r, msg.Handle, msg) {
+		return
+	}
+	// NOTE: not using `checkEntry` - aggregation result is collected after the query finishes
+	nl, exists := p.notifs.entry(msg.Handle)
+	if !exists {
+		p.invalmsghdlrstatusf(w, r, http.StatusNotFound, "%q not found", msg.Handle)
+		return
+	}
+	if err := nl.err(); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	result := &query.AggregateResult{Done: nl.finished(), Groups: map[string]*query.AggregateStats{}}
+	if !result.Done {
+		p.writeJSON(w, r, result, "query_aggregate")
+		return
+	}
+	results := p.bcastToGroup(bcastArgs{
+		req: cmn.ReqArgs{
+			Method: http.MethodGet,
+			Path:   cmn.URLPath(cmn.Version, cmn.Query, cmn.Aggregate),
+			Body:   cmn.MustMarshal(msg),
+		},
+		timeout: cmn.DefaultTimeout,
+		fv:      func() interface{} { return &query.AggregateResult{} },
+	})
+	for res := range results {
+		if res.err != nil {
+			p.invalmsghdlr(w, r, res.err.Error(), res.status)
+			return
+		}
+		result.Merge(res.v.(*query.AggregateResult))
+	}
+	p.writeJSON(w, r, result, "query_aggregate")
 }
@@ -80,11 +80,21 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		}
 		p.putObjS3(w, r, apiItems)
 	case http.MethodPost:
+		q := r.URL.Query()
+		if len(apiItems) > 1 && s3compat.IsSelect(q) {
+			// select object content: read access, handled by the object's target
+			p.getObjS3(w, r, apiItems)
+			return
+		}
+		if len(apiItems) > 1 && s3compat.IsMultipart(q) {
+			// create or complete multipart upload
+			p.directPutObjS3(w, r, apiItems)
+			return
+		}
 		if len(apiItems) != 1 {
 			p.invalmsghdlr(w, r, "bucket name expected")
 			return
 		}
-		q := r.URL.Query()
 		if _, multiple := q[s3compat.URLParamMultiDelete]; !multiple {
 			p.invalmsghdlr(w, r, "invalid request")
 			return
@@ -366,9 +376,33 @@ func (p *proxyrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []s
 		p.directPutObjS3(w, r, items)
 		return
 	}
+	if s3compat.IsMultipart(r.URL.Query()) {
+		p.copyPartS3(w, r, items)
+		return
+	}
 	p.copyObjS3(w, r, items)
 }
 
+// PUT s3/bckName/objName?partNumber=<N>&uploadId=<ID> with `x-amz-copy-source`:
+// unlike object copy, upload part copy is handled by the destination's target
+func (p *proxyrunner) copyPartS3(w http.ResponseWriter, r *http.Request, items []string) {
+	bucket, _, err := s3compat.ParseCopySrc(r.Header.Get(s3compat.HeaderObjSrc))
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	bckSrc := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bckSrc.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := bckSrc.Allow(cmn.AccessGET); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	p.directPutObjS3(w, r, items)
+}
+
 // GET s3/bckName/objName[?uuid=<UUID for transformer>]
 func (p *proxyrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	started := time.Now()
@@ -247,6 +247,9 @@ func (p *proxyrunner) setBucketProps(msg *cmn.ActionMsg, bck *cluster.Bck,
 		if err = p.checkBackendBck(bck, nprops); err != nil {
 			return
 		}
+		if err = p.checkReplicBck(bck, nprops); err != nil {
+			return
+		}
 	case cmn.ActResetBprops:
 		if bck.IsCloud() {
 			if bck.HasBackendBck() {
@@ -746,4 +749,19 @@ func (p *proxyrunner) checkBackendBck(bck *cluster.Bck, nprops *cmn.BucketProps)
 	return
 }
 
+// checkReplicBck makes sure the (new) replication destination exists in the remote cluster.
+func (p *proxyrunner) checkReplicBck(bck *cluster.Bck, nprops *cmn.BucketProps) (err error) {
+	var (
+		curr = &bck.Props.Replication
+		next = &nprops.Replication
+	)
+	if !next.Enabled || (curr.Enabled && curr.Bck.Equal(next.Bck)) {
+		return
+	}
+	if _, err, _ = p.headCloudBck(next.Bck, nil); err != nil {
+		err = fmt.Errorf("failed to enable replication of bucket %s to %s: %v", bck, next.Bck, err)
+	}
+	return
+}
+
 func (e *backendDoesNotExistErr) Error() string { return e.err.Error() }
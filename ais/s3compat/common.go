@@ -24,9 +24,10 @@ const (
 	// TODO: can it be omitted? // storageClass = "STANDARD"
 
 	// Headers
-	headerETag    = "ETag"
+	HeaderETag    = "ETag"
 	headerVersion = "x-amz-version-id"
 	HeaderObjSrc  = "x-amz-copy-source"
+	headerUserMD  = "x-amz-meta-" // prefix
 
 	headerAtime = "Last-Modified"
 )
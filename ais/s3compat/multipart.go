@@ -0,0 +1,117 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+const (
+	// multipart upload
+	URLParamMultipartUploads = "uploads"
+	URLParamUploadID         = "uploadId"
+	URLParamPartNumber       = "partNumber"
+
+	HeaderObjSrcRange = "x-amz-copy-source-range"
+)
+
+type (
+	// Response for create multipart upload request
+	InitiateMultipartUploadResult struct {
+		Ns       string `xml:"xmlns,attr"`
+		Bucket   string `xml:"Bucket"`
+		Key      string `xml:"Key"`
+		UploadID string `xml:"UploadId"`
+	}
+
+	// Complete multipart upload request: list of parts in ascending order
+	CompleteMultipartUpload struct {
+		Parts []*PartInfo `xml:"Part"`
+	}
+	PartInfo struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+
+	// Response for complete multipart upload request
+	CompleteMultipartUploadResult struct {
+		Ns     string `xml:"xmlns,attr"`
+		Bucket string `xml:"Bucket"`
+		Key    string `xml:"Key"`
+		ETag   string `xml:"ETag"`
+	}
+
+	// Response for upload part copy request
+	CopyPartResult struct {
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+	}
+)
+
+// IsMultipart returns true if the request is a part of multipart upload.
+func IsMultipart(query url.Values) bool {
+	if _, ok := query[URLParamMultipartUploads]; ok {
+		return true
+	}
+	return query.Get(URLParamUploadID) != ""
+}
+
+// ParseCopySrc splits `x-amz-copy-source` ("[/]bucket/object[?versionId=...]")
+// into bucket and object names.
+func ParseCopySrc(src string) (bucket, objName string, err error) {
+	if idx := strings.IndexByte(src, '?'); idx >= 0 {
+		src = src[:idx]
+	}
+	if s, errU := url.PathUnescape(src); errU == nil {
+		src = s
+	}
+	src = strings.Trim(src, "/") // in AWS examples the path starts with "/"
+	parts := strings.SplitN(src, "/", 2)
+	if len(parts) < 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("invalid copy source %q", src)
+	}
+	return parts[0], strings.Trim(parts[1], "/"), nil
+}
+
+// ParseCopySrcRange parses `x-amz-copy-source-range` ("bytes=first-last") and
+// returns offset and length of the range.
+func ParseCopySrcRange(rng string) (offset, length int64, err error) {
+	var last int64
+	if _, err = fmt.Sscanf(rng, "bytes=%d-%d", &offset, &last); err != nil || last < offset || offset < 0 {
+		return 0, 0, fmt.Errorf("invalid copy source range %q", rng)
+	}
+	return offset, last - offset + 1, nil
+}
+
+func NewInitiateMultipartUploadResult(bucket, key, uploadID string) *InitiateMultipartUploadResult {
+	return &InitiateMultipartUploadResult{Ns: s3Namespace, Bucket: bucket, Key: key, UploadID: uploadID}
+}
+
+func (r *InitiateMultipartUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func NewCompleteMultipartUploadResult(bucket, key, etag string) *CompleteMultipartUploadResult {
+	return &CompleteMultipartUploadResult{Ns: s3Namespace, Bucket: bucket, Key: key, ETag: etag}
+}
+
+func (r *CompleteMultipartUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func (r *CopyPartResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
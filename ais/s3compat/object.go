@@ -106,13 +106,31 @@ func FormatTime(t time.Time) string {
 func SetHeaderFromLOM(header http.Header, lom *cluster.LOM, size int64) {
 	if v, exists := lom.GetCustomMD(cluster.SourceObjMD); exists && v == cluster.SourceAmazonObjMD {
 		if v, exists := lom.GetCustomMD(cluster.MD5ObjMD); exists {
-			header.Set(headerETag, v)
+			header.Set(HeaderETag, v)
 		}
 	}
 	header.Set(headerAtime, FormatTime(lom.Atime()))
 	header.Set(cmn.HeaderContentLength, strconv.FormatInt(size, 10))
 	header.Set(cmn.HeaderContentType, cmn.ContentBinary)
 	header.Set(headerVersion, lom.Version())
+	for k, v := range lom.UserMD() {
+		header.Set(headerUserMD+k, v)
+	}
+}
+
+// UserMDFromHdr returns user-defined metadata from `x-amz-meta-*` request headers.
+func UserMDFromHdr(header http.Header) (cmn.SimpleKVs, error) {
+	var md cmn.SimpleKVs
+	for k, v := range header {
+		if len(v) == 0 || !strings.HasPrefix(strings.ToLower(k), headerUserMD) {
+			continue
+		}
+		if md == nil {
+			md = make(cmn.SimpleKVs, 4)
+		}
+		md[k[len(headerUserMD):]] = strings.Join(v, ",")
+	}
+	return cmn.ValidateUserMD(md)
 }
 
 func SetETLHeader(header http.Header, lom *cluster.LOM) {
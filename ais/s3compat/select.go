@@ -0,0 +1,232 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/NVIDIA/aistore/query"
+)
+
+// SelectObjectContent: POST s3/bckName/objName?select&select-type=2
+// The response is a stream of events encoded as described in
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTSelectObjectAppendix.html
+
+const (
+	URLParamSelect = "select"
+
+	SelectErrCode = "InternalError" // error event code for failures that occur while streaming
+
+	selectChunkSize = 64 * 1024 // max size of a single `Records` event payload
+
+	// event header value type: string
+	eventHdrString = 7
+)
+
+type (
+	SelectObjectContentRequest struct {
+		Expression          string              `xml:"Expression"`
+		ExpressionType      string              `xml:"ExpressionType"`
+		InputSerialization  InputSerialization  `xml:"InputSerialization"`
+		OutputSerialization OutputSerialization `xml:"OutputSerialization"`
+	}
+	InputSerialization struct {
+		CompressionType string     `xml:"CompressionType"`
+		CSV             *CSVInput  `xml:"CSV"`
+		JSON            *JSONInput `xml:"JSON"`
+	}
+	CSVInput struct {
+		FileHeaderInfo  string `xml:"FileHeaderInfo"` // USE, IGNORE, or NONE
+		FieldDelimiter  string `xml:"FieldDelimiter"`
+		RecordDelimiter string `xml:"RecordDelimiter"`
+		Comments        string `xml:"Comments"`
+	}
+	JSONInput struct {
+		Type string `xml:"Type"` // only LINES is supported
+	}
+	OutputSerialization struct {
+		CSV  *CSVOutput  `xml:"CSV"`
+		JSON *JSONOutput `xml:"JSON"`
+	}
+	CSVOutput struct {
+		FieldDelimiter  string `xml:"FieldDelimiter"`
+		RecordDelimiter string `xml:"RecordDelimiter"`
+	}
+	JSONOutput struct {
+		RecordDelimiter string `xml:"RecordDelimiter"`
+	}
+
+	// SelectWriter sends the rows written to it as `Records` events; Finish
+	// completes the response with `Stats` and `End` events.
+	SelectWriter struct {
+		w        io.Writer
+		buf      []byte
+		returned int64
+	}
+
+	eventHeader struct {
+		name, value string
+	}
+)
+
+// IsSelect returns true for SelectObjectContent request.
+func IsSelect(query url.Values) bool {
+	_, ok := query[URLParamSelect]
+	return ok
+}
+
+func isNewline(delim string) bool { return delim == "" || delim == "\n" || delim == "\r\n" }
+
+// ContentMsg converts the request into query's content select message.
+func (req *SelectObjectContentRequest) ContentMsg() (*query.ContentMsg, error) {
+	if req.ExpressionType != "" && !strings.EqualFold(req.ExpressionType, "SQL") {
+		return nil, fmt.Errorf("unsupported expression type %q", req.ExpressionType)
+	}
+	if ct := req.InputSerialization.CompressionType; ct != "" && !strings.EqualFold(ct, "NONE") {
+		return nil, fmt.Errorf("unsupported compression type %q", ct)
+	}
+	msg, err := query.ParseSelectExpr(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	in := &req.InputSerialization
+	switch {
+	case in.CSV != nil:
+		msg.Format = query.FormatCSV
+		switch strings.ToUpper(in.CSV.FileHeaderInfo) {
+		case "USE":
+			msg.FileHeader = query.CSVHeaderUse
+		case "IGNORE":
+			msg.FileHeader = query.CSVHeaderIgnore
+		case "", "NONE":
+			msg.FileHeader = query.CSVHeaderNone
+		default:
+			return nil, fmt.Errorf("invalid file header info %q", in.CSV.FileHeaderInfo)
+		}
+		if !isNewline(in.CSV.RecordDelimiter) {
+			return nil, fmt.Errorf("unsupported record delimiter %q", in.CSV.RecordDelimiter)
+		}
+		msg.Delimiter, msg.Comment = in.CSV.FieldDelimiter, in.CSV.Comments
+	case in.JSON != nil:
+		if !strings.EqualFold(in.JSON.Type, "LINES") {
+			return nil, fmt.Errorf("unsupported JSON type %q (only LINES is supported)", in.JSON.Type)
+		}
+		msg.Format = query.FormatJSONL
+	default:
+		return nil, errors.New("input serialization must be either CSV or JSON")
+	}
+	out := &req.OutputSerialization
+	switch {
+	case out.CSV != nil:
+		if !isNewline(out.CSV.RecordDelimiter) {
+			return nil, fmt.Errorf("unsupported record delimiter %q", out.CSV.RecordDelimiter)
+		}
+		msg.OutputFormat, msg.OutputDelimiter = query.FormatCSV, out.CSV.FieldDelimiter
+	case out.JSON != nil:
+		if !isNewline(out.JSON.RecordDelimiter) {
+			return nil, fmt.Errorf("unsupported record delimiter %q", out.JSON.RecordDelimiter)
+		}
+		msg.OutputFormat = query.FormatJSONL
+	default:
+		return nil, errors.New("output serialization must be either CSV or JSON")
+	}
+	return msg, nil
+}
+
+//////////////////
+// SelectWriter //
+//////////////////
+
+func NewSelectWriter(w io.Writer) *SelectWriter {
+	return &SelectWriter{w: w, buf: make([]byte, 0, selectChunkSize)}
+}
+
+func (sw *SelectWriter) Write(b []byte) (int, error) {
+	sw.buf = append(sw.buf, b...)
+	sw.returned += int64(len(b))
+	if len(sw.buf) >= selectChunkSize {
+		if err := sw.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (sw *SelectWriter) flush() error {
+	if len(sw.buf) == 0 {
+		return nil
+	}
+	err := writeEvent(sw.w, []eventHeader{
+		{":event-type", "Records"},
+		{":content-type", "application/octet-stream"},
+		{":message-type", "event"},
+	}, sw.buf)
+	sw.buf = sw.buf[:0]
+	return err
+}
+
+// Finish sends the remaining rows, followed by the statistics and the end of
+// the response; `scanned` is the number of bytes read from the object.
+func (sw *SelectWriter) Finish(scanned int64) error {
+	if err := sw.flush(); err != nil {
+		return err
+	}
+	stats := fmt.Sprintf("<Stats><BytesScanned>%d</BytesScanned><BytesProcessed>%d</BytesProcessed>"+
+		"<BytesReturned>%d</BytesReturned></Stats>", scanned, scanned, sw.returned)
+	err := writeEvent(sw.w, []eventHeader{
+		{":event-type", "Stats"},
+		{":content-type", "text/xml"},
+		{":message-type", "event"},
+	}, []byte(stats))
+	if err != nil {
+		return err
+	}
+	return writeEvent(sw.w, []eventHeader{{":event-type", "End"}, {":message-type", "event"}}, nil)
+}
+
+// Fail terminates the response with an error event: once the streaming has
+// started, it is the only way to tell the client that something went wrong.
+func (sw *SelectWriter) Fail(code, msg string) error {
+	if err := sw.flush(); err != nil {
+		return err
+	}
+	return writeEvent(sw.w, []eventHeader{
+		{":error-code", code},
+		{":error-message", msg},
+		{":message-type", "error"},
+	}, nil)
+}
+
+// event := prelude (total length, headers length, prelude CRC) | headers | payload | message CRC
+func writeEvent(w io.Writer, headers []eventHeader, payload []byte) error {
+	var hdrs bytes.Buffer
+	for _, h := range headers {
+		hdrs.WriteByte(byte(len(h.name)))
+		hdrs.WriteString(h.name)
+		hdrs.WriteByte(eventHdrString)
+		binary.Write(&hdrs, binary.BigEndian, uint16(len(h.value)))
+		hdrs.WriteString(h.value)
+	}
+	var (
+		total = 12 + hdrs.Len() + len(payload) + 4
+		msg   = make([]byte, 12, total)
+	)
+	binary.BigEndian.PutUint32(msg[0:], uint32(total))
+	binary.BigEndian.PutUint32(msg[4:], uint32(hdrs.Len()))
+	binary.BigEndian.PutUint32(msg[8:], crc32.ChecksumIEEE(msg[:8]))
+	msg = append(msg, hdrs.Bytes()...)
+	msg = append(msg, payload...)
+	msg = msg[:total]
+	binary.BigEndian.PutUint32(msg[total-4:], crc32.ChecksumIEEE(msg[:total-4]))
+	_, err := w.Write(msg)
+	return err
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/etl"
@@ -81,6 +82,9 @@ type (
 		rebManager   *reb.Manager
 		dbDriver     dbdriver.Driver
 		transactions transactions
+		archIndexes  archIndexCache
+		coldFetches  coldFetches
+		s3Uploads    mptUploads
 		gfn          struct {
 			local  localGFN
 			global globalGFN
@@ -266,6 +270,11 @@ func (t *targetrunner) Run() error {
 	// transactions
 	t.transactions.init(t)
 
+	// cached indexes of archived objects
+	t.archIndexes.init()
+	t.coldFetches.init()
+	t.s3Uploads.init()
+
 	//
 	// REST API: register storage target's handler(s) and start listening
 	//
@@ -291,6 +300,11 @@ func (t *targetrunner) Run() error {
 
 	dsort.InitManagers(driver)
 	dsort.RegisterNode(t.owner.smap, t.owner.bmd, t.si, t.gmm, t, t.statsT)
+	downloader.InitScheduler(t, func() (*downloader.Downloader, error) {
+		return xaction.Registry.RenewDownloader(t, t.statsT)
+	})
+	t.initReplic()
+	t.initWriteBack()
 	if err := t.httprunner.run(); err != nil {
 		return err
 	}
@@ -616,6 +630,9 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 		ranges:  cmn.RangesQuery{Range: r.Header.Get(cmn.HeaderRange), Size: 0},
 		isGFN:   isGFNRequest,
 		chunked: config.Net.HTTP.Chunked,
+
+		archpath:  query.Get(cmn.URLParamArchpath),
+		archIndex: query.Get(cmn.URLParamWhat) == cmn.GetWhatArchIndex,
 	}
 	if bck.IsHTTP() {
 		originalURL := query.Get(cmn.URLParamOrigURL)
@@ -694,12 +711,17 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	}
 	lom.SetAtimeUnix(started.UnixNano())
 	appendTy := query.Get(cmn.URLParamAppendType)
-	if appendTy == "" {
+	switch appendTy {
+	case "":
 		if err, errCode := t.doPut(r, lom, started); err != nil {
 			t.fshc(err, lom.FQN)
 			t.invalmsghdlr(w, r, err.Error(), errCode)
 		}
-	} else {
+	case cmn.AppendArchOp:
+		if err, errCode := t.doAppendArch(r, lom, started); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+		}
+	default:
 		if handle, err, errCode := t.doAppend(r, lom, started); err != nil {
 			t.invalmsghdlr(w, r, err.Error(), errCode)
 		} else {
@@ -966,6 +988,18 @@ func (t *targetrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		t.promoteFQN(w, r, &msg)
+	case cmn.ActCompose:
+		if isRedirect(query) == "" {
+			t.invalmsghdlrf(w, r, "%s: %s-%s(obj) is expected to be redirected", t.si, r.Method, msg.Action)
+			return
+		}
+		t.composeObject(w, r, &msg)
+	case cmn.ActUpdateObjMD:
+		if isRedirect(query) == "" {
+			t.invalmsghdlrf(w, r, "%s: %s-%s(obj) is expected to be redirected", t.si, r.Method, msg.Action)
+			return
+		}
+		t.updateObjMD(w, r, &msg)
 	default:
 		t.invalmsghdlrf(w, r, fmtUnknownAct, msg)
 	}
@@ -1128,6 +1162,7 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		lom.PopulateHdr(hdr)
+		cmn.UserMDToHdr(hdr, lom.UserMD())
 	} else {
 		var objMeta cmn.SimpleKVs
 		objMeta, err, errCode = t.Cloud(lom.Bck()).HeadObj(context.Background(), lom)
@@ -1282,6 +1317,14 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 		recvType   = r.URL.Query().Get(cmn.URLParamRecvType)
 	)
 	lom.ParseHdr(header) // TODO: check that values parsed here are not coming from the user
+	if _, ok := header[http.CanonicalHeaderKey(cmn.HeaderObjCustomMD)]; !ok {
+		// user PUT (as opposed to intra-cluster): new data, new user metadata
+		md, err := cmn.UserMDFromHdr(header)
+		if err != nil {
+			return err, http.StatusBadRequest
+		}
+		lom.SetUserMD(md)
+	}
 	poi := &putObjInfo{
 		started:      started,
 		t:            t,
@@ -1347,7 +1390,13 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 	lom.Lock(true)
 	defer lom.Unlock(true)
 
-	delFromCloud := lom.Bck().IsRemote() && !evict
+	var (
+		delFromCloud = lom.Bck().IsRemote() && !evict
+		dirty        = mirror.IsDirty(lom) // see cmn.WritePolicyConf
+	)
+	if evict && dirty {
+		return fmt.Errorf("%s: cannot evict object that is yet to be written to the cloud", lom), http.StatusConflict
+	}
 	if err := lom.Load(false); err == nil {
 		delFromAIS = true
 	} else if !cmn.IsObjNotExist(err) {
@@ -1357,13 +1406,18 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 	}
 
 	if delFromCloud {
-		if err, errCode := t.Cloud(lom.Bck()).DeleteObj(ctx, lom); err != nil {
+		err, errCode := t.Cloud(lom.Bck()).DeleteObj(ctx, lom)
+		if err != nil && dirty && errCode == http.StatusNotFound {
+			err = nil // never written to the cloud
+		}
+		if err != nil {
 			cloudErr = err
 			cloudErrCode = errCode
 			t.statsT.Add(stats.DeleteCount, 1)
 		}
 	}
 	if delFromAIS {
+		t.archIndexes.del(lom)
 		errRet = lom.Remove()
 		if errRet != nil {
 			if !os.IsNotExist(errRet) {
@@ -1379,8 +1433,13 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 				stats.NamedVal64{Name: stats.LruEvictCount, Value: 1},
 				stats.NamedVal64{Name: stats.LruEvictSize, Value: lom.Size()},
 			)
+		} else {
+			t.putReplic(lom, mirror.ReplicDel)
 		}
 	}
+	if dirty && cloudErr == nil {
+		t.delDirty(lom)
+	}
 	if cloudErr != nil {
 		return cloudErr, cloudErrCode
 	}
@@ -1426,13 +1485,70 @@ func (t *targetrunner) renameObject(w http.ResponseWriter, r *http.Request, msg
 	}
 	if copied {
 		lom.Lock(true)
+		t.archIndexes.del(lom)
 		if err = lom.Remove(); err != nil {
 			t.invalmsghdlr(w, r, err.Error())
+		} else {
+			t.putReplic(lom, mirror.ReplicDel)
 		}
 		lom.Unlock(true)
 	}
 }
 
+// POST { action: updateobjmd } /v1/objects/bucket-name/object-name
+// Updates user-defined metadata in place - the object's data is not rewritten.
+func (t *targetrunner) updateObjMD(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	apiItems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	bucket, objName := apiItems[0], apiItems[1]
+	bck, err := newBckFromQuery(bucket, r.URL.Query())
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	umsg := &cmn.ObjUserMDMsg{}
+	if err := cmn.MorphMarshal(msg.Value, umsg); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	md, err := cmn.ValidateUserMD(umsg.MD)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lom := &cluster.LOM{T: t, ObjName: objName}
+	if err = lom.Init(bck.Bck); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err = lom.Load(); err != nil {
+		if cmn.IsObjNotExist(err) {
+			t.invalmsghdlrstatusf(w, r, http.StatusNotFound, "%s/%s %s", bucket, objName, cmn.DoesNotExist)
+		} else {
+			t.invalmsghdlr(w, r, err.Error())
+		}
+		return
+	}
+	if umsg.Replace {
+		lom.SetUserMD(md)
+	} else {
+		lom.UpdateUserMD(md)
+	}
+	if _, err = cmn.ValidateUserMD(lom.UserMD()); err != nil { // total size
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = lom.PersistAll(); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	lom.ReCache()
+}
+
 ///////////////////////////////////////
 // PROMOTE local file(s) => objects  //
 ///////////////////////////////////////
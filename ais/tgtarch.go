@@ -0,0 +1,264 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Reading files (members) from within archived objects (tar, tgz, zip):
+// the target builds the archive's index upon first access and caches it,
+// so that subsequent lookups do not rescan the archive.
+// Appending files to existing tar objects (see doAppendArch).
+
+const (
+	archIndexCacheMax = 4096             // max number of cached indexes
+	archIndexIdleTime = 10 * time.Minute // evict indexes not accessed for that long
+)
+
+type (
+	archIndexEntry struct {
+		idx        *archive.Index
+		size       int64
+		version    string
+		cksum      string
+		lastAccess int64
+	}
+	archIndexCache struct {
+		mtx sync.Mutex
+		m   map[string]*archIndexEntry // by lom.Uname()
+	}
+)
+
+func (c *archIndexCache) init() {
+	c.m = make(map[string]*archIndexEntry, 64)
+	hk.Reg("arch-index-cache", c.housekeep, archIndexIdleTime)
+}
+
+// returns cached index if (and only if) the archived object has not changed since
+func (c *archIndexCache) get(lom *cluster.LOM) *archive.Index {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	e, ok := c.m[lom.Uname()]
+	if !ok {
+		return nil
+	}
+	if e.size != lom.Size() || e.version != lom.Version() || e.cksum != archCksum(lom) {
+		delete(c.m, lom.Uname())
+		return nil
+	}
+	e.lastAccess = mono.NanoTime()
+	return e.idx
+}
+
+func (c *archIndexCache) put(lom *cluster.LOM, idx *archive.Index) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if len(c.m) >= archIndexCacheMax {
+		c.evictOldest()
+	}
+	c.m[lom.Uname()] = &archIndexEntry{
+		idx:        idx,
+		size:       lom.Size(),
+		version:    lom.Version(),
+		cksum:      archCksum(lom),
+		lastAccess: mono.NanoTime(),
+	}
+}
+
+// evicts the index of the archive that has been overwritten, removed, or renamed
+func (c *archIndexCache) del(lom *cluster.LOM) {
+	c.mtx.Lock()
+	delete(c.m, lom.Uname())
+	c.mtx.Unlock()
+}
+
+func archCksum(lom *cluster.LOM) string {
+	if cksum := lom.Cksum(); cksum != nil {
+		return cksum.Value()
+	}
+	return ""
+}
+
+// under lock
+func (c *archIndexCache) evictOldest() {
+	var (
+		oldest string
+		access int64
+	)
+	for uname, e := range c.m {
+		if oldest == "" || e.lastAccess < access {
+			oldest, access = uname, e.lastAccess
+		}
+	}
+	delete(c.m, oldest)
+}
+
+func (c *archIndexCache) housekeep() time.Duration {
+	now := mono.NanoTime()
+	c.mtx.Lock()
+	for uname, e := range c.m {
+		if time.Duration(now-e.lastAccess) > archIndexIdleTime {
+			delete(c.m, uname)
+		}
+	}
+	c.mtx.Unlock()
+	return archIndexIdleTime
+}
+
+///////////////////////////////
+// getObjInfo: archived file //
+///////////////////////////////
+
+func (goi *getObjInfo) isArch() bool { return goi.archpath != "" || goi.archIndex }
+
+func (goi *getObjInfo) archIndexOf(file *os.File) (idx *archive.Index, err error) {
+	t := goi.t
+	if idx = t.archIndexes.get(goi.lom); idx != nil {
+		return
+	}
+	format, err := archive.FormatFromName(goi.lom.ObjName)
+	if err != nil {
+		return nil, err
+	}
+	if idx, err = archive.BuildIndex(format, file, goi.lom.Size()); err != nil {
+		return nil, fmt.Errorf("%s: failed to read archive: %v", goi.lom, err)
+	}
+	t.archIndexes.put(goi.lom, idx)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: indexed %s (%d entries)", t.si, goi.lom, len(idx.Entries))
+	}
+	return
+}
+
+// GET archived file (`archpath`) or list archive members (`what=archindex`)
+func (goi *getObjInfo) finalizeArch(file *os.File, hdr http.Header) (err error, errCode int) {
+	idx, err := goi.archIndexOf(file)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	if goi.archIndex {
+		if hdr != nil {
+			hdr.Set(cmn.HeaderContentType, cmn.ContentJSON)
+		}
+		if _, err = goi.w.Write(cmn.MustMarshal(idx.Entries)); err != nil && !cmn.IsErrConnectionReset(err) {
+			return err, http.StatusInternalServerError
+		}
+		return nil, 0
+	}
+	e := idx.Find(goi.archpath)
+	if e == nil {
+		return cmn.NewNotFoundError("%s: file %q in archive %s", goi.t.si, goi.archpath, goi.lom), http.StatusNotFound
+	}
+	reader, err := idx.Open(file, goi.lom.Size(), e)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	defer reader.Close()
+	if hdr != nil {
+		hdr.Set(cmn.HeaderContentLength, strconv.FormatInt(e.Size, 10))
+		hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(e.Size, 10))
+	}
+	buf, slab := goi.t.gmm.Alloc(e.Size)
+	written, err := io.CopyBuffer(writerOnly{goi.w}, reader, buf)
+	slab.Free(buf)
+	if err != nil {
+		if cmn.IsErrConnectionReset(err) {
+			return nil, 0
+		}
+		goi.t.statsT.Add(stats.ErrGetCount, 1)
+		return fmt.Errorf("failed to GET %q from %s, err: %w", goi.archpath, goi.lom, err), http.StatusInternalServerError
+	}
+	goi.t.statsT.AddMany(
+		stats.NamedVal64{Name: stats.GetThroughput, Value: written},
+		stats.NamedVal64{Name: stats.GetLatency, Value: int64(time.Since(goi.started))},
+		stats.NamedVal64{Name: stats.GetCount, Value: 1},
+	)
+	return nil, 0
+}
+
+////////////////////
+// append to arch //
+////////////////////
+
+// PUT /v1/objects/bucket-name/object-name?appendty=arch
+// Request body is a tar stream with the file(s) to append; the resulting
+// object is written (and checksummed) anew and replaces the existing one.
+func (t *targetrunner) doAppendArch(r *http.Request, lom *cluster.LOM, started time.Time) (err error, errCode int) {
+	var (
+		file   *os.File
+		end    int64
+		create = cmn.IsParseBool(r.URL.Query().Get(cmn.URLParamCreate))
+	)
+	if format, errF := archive.FormatFromName(lom.ObjName); errF != nil || format != cmn.ExtTar {
+		return fmt.Errorf("%s: append is only supported for %s archives", lom, cmn.ExtTar), http.StatusBadRequest
+	}
+	// serialize appends: the object is read and replaced under the same lock
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err = lom.Load(); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			return err, http.StatusInternalServerError
+		}
+		if !create {
+			return cmn.NewNotFoundError("%s: archive %s", t.si, lom), http.StatusNotFound
+		}
+		if lom.Bck().IsRemote() {
+			return fmt.Errorf("%s: cannot create %s in remote bucket - not present in the cluster", t.si, lom),
+				http.StatusBadRequest
+		}
+	} else {
+		if file, err = os.Open(lom.FQN); err != nil {
+			t.fshc(err, lom.FQN)
+			return err, http.StatusInternalServerError
+		}
+		defer file.Close()
+		if end, err = archive.TarEnd(io.NewSectionReader(file, 0, lom.Size())); err != nil {
+			return fmt.Errorf("%s: not a valid %s archive: %v", lom, cmn.ExtTar, err), http.StatusBadRequest
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := archive.CopyTar(pw, r.Body)
+		pw.CloseWithError(err)
+	}()
+	var reader io.Reader = pr
+	if file != nil {
+		reader = io.MultiReader(io.NewSectionReader(file, 0, end), pr)
+	}
+	poi := &putObjInfo{
+		started: started,
+		t:       t,
+		lom:     lom,
+		r:       ioutil.NopCloser(reader),
+		ctx:     context.Background(),
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileAppend),
+		locked:  true,
+	}
+	err, errCode = poi.putObject()
+	pr.Close() // in case of early failure: unblock the writer
+	if err != nil && errCode == 0 {
+		errCode = http.StatusInternalServerError
+	}
+	return
+}
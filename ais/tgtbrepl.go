@@ -13,6 +13,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/mirror"
 )
 
 type replicInfo struct {
@@ -103,6 +104,7 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objNameTo string) (copied boo
 		dst.ReCache()
 		if ri.finalize {
 			ri.t.putMirror(dst)
+			ri.t.putReplic(dst, mirror.ReplicPut)
 		}
 	}
 	return
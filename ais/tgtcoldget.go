@@ -0,0 +1,335 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Coalescing of concurrent cold GETs.
+//
+// The first GET of an object that is not present in the cluster starts fetching
+// the object from the cloud in the background (see coldFetch.run). This and all
+// concurrent GETs of the same object join the in-flight fetch and stream the
+// object from the partially written workfile as the bytes arrive - the progress
+// is reported by the cloud provider's reader (see cmn.CtxReadWrapper). Since
+// GETs are always served by the object's (HRW) target, GETs issued by other
+// targets coalesce as well.
+
+type (
+	coldFetches struct {
+		mtx sync.Mutex
+		m   map[string]*coldFetch // in-flight fetches by lom.Uname()
+	}
+	coldFetch struct {
+		t       *targetrunner
+		lom     *cluster.LOM // private to the fetch
+		workFQN string
+		mtx     sync.Mutex
+		cond    *sync.Cond
+		// protected by mtx
+		size    int64  // as reported by the cloud (-1 if unknown)
+		version string // ditto
+		written int64  // bytes written to the workfile
+		pending int64  // bytes read from the cloud and being written
+		ready   bool   // the workfile exists
+		done    bool
+		err     error
+		errCode int
+	}
+	// wraps the reader of the cloud object
+	coldFetchReader struct {
+		io.ReadCloser
+		f *coldFetch
+	}
+)
+
+func (c *coldFetches) init() {
+	c.m = make(map[string]*coldFetch, 64)
+}
+
+// join returns the in-flight fetch of the object, starting a new one if need be.
+func (c *coldFetches) join(goi *getObjInfo) (f *coldFetch, err error) {
+	uname := goi.lom.Uname()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if f = c.m[uname]; f != nil {
+		goi.t.statsT.Add(stats.GetColdCoalescedCount, 1)
+		return
+	}
+	lom := &cluster.LOM{T: goi.t, ObjName: goi.lom.ObjName}
+	if err = lom.Init(goi.lom.Bck().Bck); err != nil {
+		return
+	}
+	lom.SetAtimeUnix(goi.started.UnixNano())
+	f = &coldFetch{
+		t:       goi.t,
+		lom:     lom,
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileColdget),
+		size:    -1,
+	}
+	f.cond = sync.NewCond(&f.mtx)
+	c.m[uname] = f
+	go f.run(goi.ctx)
+	return
+}
+
+func (c *coldFetches) del(lom *cluster.LOM) {
+	c.mtx.Lock()
+	delete(c.m, lom.Uname())
+	c.mtx.Unlock()
+}
+
+///////////////
+// coldFetch //
+///////////////
+
+func (f *coldFetch) run(ctx context.Context) {
+	var (
+		err     error
+		errCode int
+		lom     = f.lom
+	)
+	ctx = context.WithValue(ctx, cmn.CtxReadWrapper, cmn.ReadWrapperFunc(f.wrapReader))
+	ctx = context.WithValue(ctx, cmn.CtxSetSize, cmn.SetSizeFunc(f.setSize))
+
+	lom.Lock(true)
+	if err = lom.Load(false); err == nil {
+		// e.g., prefetched or PUT while waiting for the lock
+		lom.Unlock(true)
+		f.t.coldFetches.del(lom)
+		f.finish(nil, 0)
+		return
+	}
+	if err, errCode = f.t.getCold(ctx, lom, f.workFQN); err == nil {
+		f.t.statsT.AddMany(
+			stats.NamedVal64{Name: stats.GetColdCount, Value: 1},
+			stats.NamedVal64{Name: stats.GetColdSize, Value: lom.Size()},
+		)
+	}
+	lom.Unlock(true)
+	// from now on, GETs find the object in place
+	f.t.coldFetches.del(lom)
+	f.finish(err, errCode)
+	if err == nil {
+		f.t.putMirror(lom)
+	}
+}
+
+func (f *coldFetch) wrapReader(r io.ReadCloser) io.ReadCloser {
+	return &coldFetchReader{ReadCloser: r, f: f}
+}
+
+func (f *coldFetch) setSize(size int64) {
+	f.mtx.Lock()
+	f.size = size
+	f.mtx.Unlock()
+}
+
+// commit accounts for the bytes that have been written to the workfile by the
+// time the reader gets called again (or closed).
+func (f *coldFetch) commit() {
+	f.mtx.Lock()
+	if !f.ready {
+		f.ready = true
+		f.version = f.lom.Version()
+	}
+	f.written += f.pending
+	f.pending = 0
+	f.mtx.Unlock()
+	f.cond.Broadcast()
+}
+
+func (f *coldFetch) read(n int) {
+	f.mtx.Lock()
+	f.pending += int64(n)
+	f.mtx.Unlock()
+}
+
+func (f *coldFetch) finish(err error, errCode int) {
+	f.mtx.Lock()
+	f.written += f.pending
+	f.pending = 0
+	f.done, f.err, f.errCode = true, err, errCode
+	f.mtx.Unlock()
+	f.cond.Broadcast()
+}
+
+// wait blocks until more than `off` bytes are written to the workfile (or, if
+// `all` is true, until the fetch is done); returns the number of written bytes.
+func (f *coldFetch) wait(off int64, all bool) (written int64, done bool) {
+	f.mtx.Lock()
+	for !f.done && (all || f.written <= off) {
+		f.cond.Wait()
+	}
+	written, done = f.written, f.done
+	f.mtx.Unlock()
+	return
+}
+
+func (f *coldFetch) result() (err error, errCode int) {
+	f.mtx.Lock()
+	err, errCode = f.err, f.errCode
+	f.mtx.Unlock()
+	return
+}
+
+// stream sends the object to the GET requester as the bytes get written to the
+// workfile; returns streamed = false if the fetch completed (or failed) before
+// the streaming could start, or if the size of the object is unknown.
+//
+// NOTE: the last byte is held back until the object is fetched (and validated)
+// in its entirety, so that a failed fetch always results in a failed GET.
+func (f *coldFetch) stream(goi *getObjInfo) (written int64, streamed bool, err error, errCode int) {
+	f.mtx.Lock()
+	for !f.ready && !f.done {
+		f.cond.Wait()
+	}
+	done, size, version := f.done, f.size, f.version
+	f.mtx.Unlock()
+	if done || size < 0 {
+		f.wait(0, true)
+		err, errCode = f.result()
+		return
+	}
+	file, errOpen := os.Open(f.workFQN)
+	if errOpen != nil { // renamed or removed in the meantime
+		f.wait(0, true)
+		err, errCode = f.result()
+		return
+	}
+	defer file.Close()
+
+	var (
+		w         = goi.w
+		buf, slab = goi.t.gmm.Alloc(size)
+	)
+	defer slab.Free(buf)
+	if goi.chunked {
+		w = writerOnly{goi.w} // hide ReadFrom; CopyBuffer will use the buffer instead
+	}
+	streamed = true
+	for {
+		avail, done := f.wait(written, false)
+		if err, errCode = f.result(); err != nil {
+			return
+		}
+		if !done && avail >= size {
+			avail = size - 1
+		}
+		if written == 0 && (avail > 0 || done) {
+			goi.setColdHeaders(size, version)
+		}
+		if avail <= written {
+			if done {
+				return
+			}
+			f.wait(0, true) // the last byte
+			continue
+		}
+		var n int64
+		n, err = io.CopyBuffer(w, io.NewSectionReader(file, written, avail-written), buf)
+		written += n
+		if err != nil {
+			errCode = http.StatusInternalServerError
+			return
+		}
+		if written < avail {
+			if done {
+				err, errCode = io.ErrUnexpectedEOF, http.StatusInternalServerError
+				return
+			}
+			f.wait(0, true) // short read: wait for the outcome
+		}
+	}
+}
+
+/////////////////////
+// coldFetchReader //
+/////////////////////
+
+func (r *coldFetchReader) Read(p []byte) (n int, err error) {
+	r.f.commit() // the bytes read so far have been written by now
+	n, err = r.ReadCloser.Read(p)
+	r.f.read(n)
+	return
+}
+
+func (r *coldFetchReader) Close() error {
+	r.f.commit()
+	return r.ReadCloser.Close()
+}
+
+////////////////
+// getObjInfo //
+////////////////
+
+// coalescible returns true if the cold GET can join (or start) in-flight fetch.
+func (goi *getObjInfo) coalescible() bool {
+	return !goi.isGFN && !goi.isArch() && goi.ranges.Range == "" && goi.ranges.Size == 0
+}
+
+// NOTE: the checksum is not known until the object is fetched in its entirety
+func (goi *getObjInfo) setColdHeaders(size int64, version string) {
+	rw, ok := goi.w.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	hdr := rw.Header()
+	if version != "" {
+		hdr.Set(cmn.HeaderObjVersion, version)
+	}
+	hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(size, 10))
+	hdr.Set(cmn.HeaderObjAtime, cmn.UnixNano2S(goi.started.UnixNano()))
+	hdr.Set(cmn.HeaderContentLength, strconv.FormatInt(size, 10))
+}
+
+// getColdCoalesced joins the in-flight fetch of the object and streams the
+// object as it arrives; if the fetch completes before the streaming could start
+// the object is read locally, as usual.
+func (goi *getObjInfo) getColdCoalesced() (err error, errCode int) {
+	f, err := goi.t.coldFetches.join(goi)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	written, streamed, err, errCode := f.stream(goi)
+	if err != nil {
+		if streamed {
+			goi.t.statsT.Add(stats.ErrGetCount, 1)
+		}
+		return
+	}
+	if !streamed {
+		goi.lom.Lock(false)
+		defer goi.lom.Unlock(false)
+		if err = goi.lom.Load(); err != nil {
+			return err, http.StatusNotFound
+		}
+		_, err, errCode = goi.finalize(true /*coldGet*/)
+		return
+	}
+	delta := time.Since(goi.started)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("GET: %s(%s), %d µs (cold, streamed)", goi.lom, cmn.B2S(written, 1), int64(delta/time.Microsecond))
+	}
+	goi.t.statsT.AddMany(
+		stats.NamedVal64{Name: stats.GetThroughput, Value: written},
+		stats.NamedVal64{Name: stats.GetLatency, Value: int64(delta)},
+		stats.NamedVal64{Name: stats.GetCount, Value: 1},
+	)
+	return
+}
@@ -0,0 +1,319 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type (
+	// coldCloudMock serves objects the same way the cloud providers do: reports
+	// the size and wraps the reader via context (see cmn.CtxReadWrapper)
+	coldCloudMock struct {
+		cluster.CloudProvider
+		t       *targetrunner
+		data    []byte
+		size    int64      // reported size: -1 if unknown
+		cksum   *cmn.Cksum // provided by the cloud
+		gate    chan struct{}
+		eof     chan struct{}
+		err     error
+		errCode int
+		gets    atomic.Int32
+	}
+	// slowReader reads a chunk at a time once the gate is open and holds back
+	// io.EOF until `eof` is closed
+	slowReader struct {
+		data []byte
+		off  int
+		gate chan struct{}
+		eof  chan struct{}
+	}
+	// syncRW is http.ResponseWriter that can be checked while being written to
+	syncRW struct {
+		mtx  sync.Mutex
+		hdr  http.Header
+		body bytes.Buffer
+	}
+)
+
+const slowReaderChunk = 4 * cmn.KiB
+
+func (m *coldCloudMock) Provider() string { return cmn.ProviderAmazon }
+
+func (m *coldCloudMock) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	m.gets.Inc()
+	if m.err != nil {
+		return m.err, m.errCode
+	}
+	if m.size >= 0 {
+		ctx.Value(cmn.CtxSetSize).(cmn.SetSizeFunc)(m.size)
+	}
+	r := ctx.Value(cmn.CtxReadWrapper).(cmn.ReadWrapperFunc)(
+		ioutil.NopCloser(&slowReader{data: m.data, gate: m.gate, eof: m.eof}))
+	lom.SetVersion("1")
+	err = m.t.PutObject(cluster.PutObjectParams{
+		LOM:      lom,
+		Reader:   r,
+		WorkFQN:  workFQN,
+		RecvType: cluster.ColdGet,
+		Cksum:    m.cksum,
+	})
+	if err != nil {
+		errCode = http.StatusInternalServerError
+	}
+	return
+}
+
+func (r *slowReader) Read(p []byte) (n int, err error) {
+	<-r.gate
+	if r.off == len(r.data) {
+		<-r.eof
+		return 0, io.EOF
+	}
+	if len(p) > slowReaderChunk {
+		p = p[:slowReaderChunk]
+	}
+	n = copy(p, r.data[r.off:])
+	r.off += n
+	time.Sleep(100 * time.Microsecond)
+	return
+}
+
+func (rw *syncRW) Header() http.Header { return rw.hdr }
+func (rw *syncRW) WriteHeader(int)     {}
+
+func (rw *syncRW) Write(p []byte) (int, error) {
+	rw.mtx.Lock()
+	defer rw.mtx.Unlock()
+	return rw.body.Write(p)
+}
+
+func (rw *syncRW) len() int {
+	rw.mtx.Lock()
+	defer rw.mtx.Unlock()
+	return rw.body.Len()
+}
+
+func (rw *syncRW) bytes() []byte {
+	rw.mtx.Lock()
+	defer rw.mtx.Unlock()
+	return append([]byte(nil), rw.body.Bytes()...)
+}
+
+var _ = Describe("Cold GET coalescing", func() {
+	type result struct {
+		rw      *syncRW
+		err     error
+		errCode int
+	}
+
+	var (
+		mock  *coldCloudMock
+		data  []byte
+		loms  []*cluster.LOM
+		saved clouds
+	)
+
+	BeforeEach(func() {
+		data = make([]byte, 256*cmn.KiB+13)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		mock = &coldCloudMock{
+			t:    t,
+			data: data,
+			size: int64(len(data)),
+			gate: make(chan struct{}),
+			eof:  make(chan struct{}),
+		}
+		saved = t.cloud
+		t.cloud = clouds{cmn.ProviderAmazon: mock}
+		t.coldFetches.init()
+		loms = loms[:0]
+	})
+
+	AfterEach(func() {
+		for _, lom := range loms {
+			lom.Remove()
+		}
+		t.cloud = saved
+	})
+
+	openGates := func() {
+		close(mock.gate)
+		close(mock.eof)
+	}
+
+	newGoi := func(objName string) (*getObjInfo, *syncRW) {
+		lom := &cluster.LOM{T: t, ObjName: objName}
+		Expect(lom.Init(cmn.Bck{Name: testCloudBucket, Provider: cmn.ProviderAmazon, Ns: cmn.NsGlobal})).NotTo(HaveOccurred())
+		loms = append(loms, lom)
+		rw := &syncRW{hdr: make(http.Header)}
+		return &getObjInfo{started: time.Now(), t: t, lom: lom, w: rw, ctx: context.Background()}, rw
+	}
+
+	// runs `n` concurrent cold GETs of the object
+	getN := func(objName string, n int) <-chan result {
+		ch := make(chan result, n)
+		for i := 0; i < n; i++ {
+			goi, rw := newGoi(objName)
+			go func() {
+				err, errCode := goi.getColdCoalesced()
+				ch <- result{rw: rw, err: err, errCode: errCode}
+			}()
+		}
+		return ch
+	}
+
+	inflight := func() int {
+		t.coldFetches.mtx.Lock()
+		defer t.coldFetches.mtx.Unlock()
+		return len(t.coldFetches.m)
+	}
+
+	It("should share a single cloud GET among concurrent GETs", func() {
+		const n = 10
+		ch := getN("cold/shared", n)
+		Eventually(inflight).Should(Equal(1))
+		openGates()
+		for i := 0; i < n; i++ {
+			res := <-ch
+			Expect(res.err).NotTo(HaveOccurred())
+			Expect(res.rw.bytes()).To(Equal(data))
+		}
+		Expect(mock.gets.Load()).To(BeEquivalentTo(1))
+		Expect(inflight()).To(BeZero())
+
+		goi, _ := newGoi("cold/shared")
+		Expect(goi.lom.Load()).NotTo(HaveOccurred())
+		Expect(goi.lom.Size()).To(BeEquivalentTo(len(data)))
+		Expect(goi.lom.Cksum()).NotTo(BeNil())
+	})
+
+	It("should hold back the last byte until the object is fetched", func() {
+		var (
+			goi, rw = newGoi("cold/last-byte")
+			done    = make(chan result, 1)
+		)
+		go func() {
+			err, errCode := goi.getColdCoalesced()
+			done <- result{rw: rw, err: err, errCode: errCode}
+		}()
+		close(mock.gate)
+		Eventually(rw.len, 10*time.Second).Should(Equal(len(data) - 1))
+		Consistently(rw.len, 100*time.Millisecond).Should(Equal(len(data) - 1))
+		Expect(rw.Header().Get(cmn.HeaderObjSize)).To(Equal(strconv.Itoa(len(data))))
+		Expect(rw.Header().Get(cmn.HeaderObjVersion)).To(Equal("1"))
+
+		close(mock.eof)
+		res := <-done
+		Expect(res.err).NotTo(HaveOccurred())
+		Expect(rw.bytes()).To(Equal(data))
+	})
+
+	It("should fail all streamed GETs if the object fails validation", func() {
+		const n = 5
+		mock.cksum = cmn.NewCksum(cmn.ChecksumMD5, "00000000000000000000000000000000")
+		ch := getN("cold/bad-cksum", n)
+		Eventually(inflight).Should(Equal(1))
+		close(mock.gate)
+		time.Sleep(50 * time.Millisecond) // let the GETs start streaming
+		close(mock.eof)
+		streamed := 0
+		for i := 0; i < n; i++ {
+			res := <-ch
+			Expect(res.err).To(HaveOccurred())
+			Expect(res.rw.len()).To(BeNumerically("<", len(data)))
+			if res.rw.len() > 0 {
+				streamed++
+			}
+		}
+		Expect(streamed).To(BeNumerically(">", 0))
+		Expect(inflight()).To(BeZero())
+
+		goi, _ := newGoi("cold/bad-cksum")
+		Expect(goi.lom.Load()).To(HaveOccurred())
+	})
+
+	It("should read the object locally if its size is unknown", func() {
+		mock.size = -1
+		var (
+			goi, rw = newGoi("cold/unknown-size")
+			done    = make(chan result, 1)
+		)
+		go func() {
+			err, errCode := goi.getColdCoalesced()
+			done <- result{rw: rw, err: err, errCode: errCode}
+		}()
+		close(mock.gate)
+		Consistently(rw.len, 100*time.Millisecond).Should(BeZero())
+
+		close(mock.eof)
+		res := <-done
+		Expect(res.err).NotTo(HaveOccurred())
+		Expect(rw.bytes()).To(Equal(data))
+		Expect(mock.gets.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should not stream if the fetch completes first", func() {
+		goi, rw := newGoi("cold/completed")
+		f, err := t.coldFetches.join(goi)
+		Expect(err).NotTo(HaveOccurred())
+		openGates()
+		f.wait(0, true)
+
+		written, streamed, err, _ := f.stream(goi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(streamed).To(BeFalse())
+		Expect(written).To(BeZero())
+		Expect(rw.len()).To(BeZero())
+		Expect(inflight()).To(BeZero())
+	})
+
+	It("should fail the GET if the cloud GET fails", func() {
+		mock.err, mock.errCode = cmn.NewNotFoundError("cold/missing"), http.StatusNotFound
+		openGates()
+		res := <-getN("cold/missing", 1)
+		Expect(res.err).To(HaveOccurred())
+		Expect(res.errCode).To(Equal(http.StatusNotFound))
+		Expect(res.rw.len()).To(BeZero())
+		Expect(inflight()).To(BeZero())
+	})
+
+	It("should coalesce concurrent GETs of multiple objects", func() {
+		const (
+			numObjs = 8
+			n       = 4
+		)
+		openGates()
+		chs := make([]<-chan result, 0, numObjs)
+		for i := 0; i < numObjs; i++ {
+			chs = append(chs, getN("cold/multi-"+strconv.Itoa(i), n))
+		}
+		for _, ch := range chs {
+			for i := 0; i < n; i++ {
+				res := <-ch
+				Expect(res.err).NotTo(HaveOccurred())
+				Expect(res.rw.bytes()).To(Equal(data))
+			}
+		}
+		Expect(mock.gets.Load()).To(BeEquivalentTo(numObjs))
+		Expect(inflight()).To(BeZero())
+	})
+})
@@ -0,0 +1,230 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Server-side compose: the destination's (HRW) target reads source objects in
+// order - locally or from their respective targets via intra-cluster data
+// network - and writes them, back to back or tar-wrapped, into a new object.
+
+type (
+	composeSrc struct {
+		io.ReadCloser
+		size int64
+	}
+	// unlocks the local source object upon Close
+	composeLocalSrc struct {
+		*os.File
+		lom *cluster.LOM
+	}
+	composeErr struct {
+		err  error
+		code int
+	}
+)
+
+// POST { action: compose } /v1/objects/bucket-name/object-name
+func (t *targetrunner) composeObject(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	apiItems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	bucket, objName := apiItems[0], apiItems[1]
+	bck, err := newBckFromQuery(bucket, r.URL.Query())
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cmsg := &cmn.ComposeMsg{}
+	if err := cmn.MorphMarshal(msg.Value, cmsg); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lom := &cluster.LOM{T: t, ObjName: objName}
+	if err = lom.Init(bck.Bck); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err, errCode := t.compose(lom, cmsg.Sources, cmsg.WrapTar); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+	}
+}
+
+// compose sources into `lom` (which must be HRW-local)
+func (t *targetrunner) compose(lom *cluster.LOM, sources []cmn.ComposeSrc, wrapTar bool) (err error, errCode int) {
+	var (
+		started = time.Now()
+		cerr    = &composeErr{}
+		pr, pw  = io.Pipe()
+	)
+	if cs := fs.GetCapStatus(); cs.OOS {
+		return cs.Err, http.StatusInsufficientStorage
+	}
+	done := make(chan struct{})
+	go func() {
+		pw.CloseWithError(t.composeWrite(pw, sources, wrapTar, cerr))
+		close(done)
+	}()
+	poi := &putObjInfo{
+		started: started,
+		t:       t,
+		lom:     lom,
+		r:       ioutil.NopCloser(pr),
+		ctx:     context.Background(),
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+	}
+	if lom.Bck().IsAIS() && lom.VersionConf().Enabled {
+		lom.Load() // need to know the current version if versioning enabled
+	}
+	lom.SetUserMD(nil) // new object: no user metadata
+	lom.SetAtimeUnix(started.UnixNano())
+	err, errCode = poi.putObject()
+	pr.Close() // in case of early failure: unblock the writer
+	<-done
+	if err != nil {
+		if cerr.err != nil { // source failure is the root cause
+			err, errCode = cerr.err, cerr.code
+		} else if errCode == 0 {
+			errCode = http.StatusInternalServerError
+		}
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: composed %s from %d object(s) in %v", t.si, lom, len(sources), time.Since(started))
+	}
+	return
+}
+
+func (t *targetrunner) composeWrite(w io.Writer, sources []cmn.ComposeSrc, wrapTar bool, cerr *composeErr) error {
+	var (
+		tw        *tar.Writer
+		buf, slab = t.gmm.Alloc()
+	)
+	defer slab.Free(buf)
+	if wrapTar {
+		tw = tar.NewWriter(w)
+		w = tw
+	}
+	for i := range sources {
+		src, err, code := t.composeOpen(&sources[i])
+		if err != nil {
+			cerr.err, cerr.code = err, code
+			return err
+		}
+		if tw != nil {
+			hdr := &tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     sources[i].ObjName,
+				Size:     src.size,
+				Mode:     0644,
+				ModTime:  time.Now(),
+			}
+			if err = tw.WriteHeader(hdr); err != nil {
+				src.Close()
+				return err
+			}
+		}
+		n, err := io.CopyBuffer(w, io.LimitReader(src, src.size), buf)
+		src.Close()
+		if err == nil && n != src.size {
+			err = fmt.Errorf("%s/%s: short read (%d/%d)", sources[i].Bck, sources[i].ObjName, n, src.size)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if tw != nil {
+		return tw.Close()
+	}
+	return nil
+}
+
+// open source object for reading: local (HRW and present) or via intra-data GET
+func (t *targetrunner) composeOpen(src *cmn.ComposeSrc) (cs *composeSrc, err error, errCode int) {
+	bck := cluster.NewBckEmbed(src.Bck)
+	if err = bck.Init(t.owner.bmd, t.si); err != nil {
+		return nil, err, http.StatusNotFound
+	}
+	smap := t.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(src.ObjName), &smap.Smap)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if si.ID() == t.si.ID() {
+		lom := &cluster.LOM{T: t, ObjName: src.ObjName}
+		if err = lom.Init(bck.Bck); err != nil {
+			return nil, err, http.StatusInternalServerError
+		}
+		lom.Lock(false)
+		if err = lom.Load(); err == nil {
+			var file *os.File
+			if file, err = os.Open(lom.FQN); err == nil {
+				return &composeSrc{ReadCloser: &composeLocalSrc{File: file, lom: lom}, size: lom.Size()}, nil, 0
+			}
+		}
+		lom.Unlock(false)
+		// not present: fall through to GET (e.g., cold GET from the Cloud)
+	}
+
+	query := url.Values{}
+	query = cmn.AddBckToQuery(query, bck.Bck)
+	query.Add(cmn.URLParamProxyID, t.si.ID())
+	query.Add(cmn.URLParamUnixTime, cmn.UnixNano2S(time.Now().UnixNano()))
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, src.ObjName),
+		Query:  query,
+	}
+	req, err := reqArgs.Req()
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	resp, err := t.httpclientGetPut.Do(req) // nolint:bodyclose // closed by the caller
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to GET %s/%s from %s: %v", t.si, bck, src.ObjName, si, err),
+			http.StatusInternalServerError
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		cmn.DrainReader(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: failed to GET %s/%s from %s: status %d", t.si, bck, src.ObjName, si, resp.StatusCode),
+			resp.StatusCode
+	}
+	// NOTE: zero-size objects are returned without object headers
+	size := cmn.MaxI64(resp.ContentLength, 0)
+	if hdrSize := resp.Header.Get(cmn.HeaderObjSize); hdrSize != "" {
+		size, err = strconv.ParseInt(hdrSize, 10, 64)
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: GET %s/%s from %s: invalid size: %v", t.si, bck, src.ObjName, si, err),
+			http.StatusInternalServerError
+	}
+	return &composeSrc{ReadCloser: resp.Body, size: size}, nil, 0
+}
+
+func (src *composeLocalSrc) Close() (err error) {
+	err = src.File.Close()
+	src.lom.Unlock(false)
+	return
+}
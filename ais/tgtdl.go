@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
@@ -61,6 +62,19 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if dlBodyBase.Schedule != nil {
+			created, err := cmn.S2UnixNano(r.URL.Query().Get(cmn.URLParamUnixTime))
+			if err != nil {
+				t.invalmsghdlr(w, r, err.Error())
+				return
+			}
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Scheduling download: %s", uuid)
+			}
+			response, respErr, statusCode = downloaderXact.Schedule(uuid, time.Unix(0, created), dlb)
+			break
+		}
+
 		dlJob, err := downloader.ParseStartDownloadRequest(ctx, t, bck, uuid, dlb)
 		if err != nil {
 			t.invalmsghdlr(w, r, err.Error())
@@ -71,7 +85,7 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		response, respErr, statusCode = downloaderXact.Download(dlJob)
 	case http.MethodGet:
-		_, err := cmn.MatchRESTItems(r.URL.Path, 0, false, cmn.Version, cmn.Download)
+		items, err := cmn.MatchRESTItems(r.URL.Path, 0, false, cmn.Version, cmn.Download)
 		debug.AssertNoErr(err)
 
 		payload := &downloader.DlAdminBody{}
@@ -80,7 +94,19 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		debug.AssertNoErr(payload.Validate(false /*requireID*/))
 
-		if payload.ID != "" {
+		if len(items) > 0 && items[0] == cmn.Schedule {
+			var regex *regexp.Regexp
+			if payload.Regex != "" {
+				if regex, err = regexp.CompilePOSIX(payload.Regex); err != nil {
+					cmn.InvalidHandlerWithMsg(w, r, err.Error())
+					return
+				}
+			}
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Listing download schedules")
+			}
+			response, respErr, statusCode = downloaderXact.ListSchedules(payload.ID, regex)
+		} else if payload.ID != "" {
 			if glog.FastV(4, glog.SmoduleAIS) {
 				glog.Infof("Getting status of download: %s", payload)
 			}
@@ -119,16 +145,53 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 				glog.Infof("Removing download: %s", payload)
 			}
 			response, respErr, statusCode = downloaderXact.RemoveJob(payload.ID)
+		case cmn.Schedule:
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Removing download schedule: %s", payload)
+			}
+			response, respErr, statusCode = downloaderXact.RemoveSchedule(payload.ID)
+		default:
+			cmn.AssertMsg(false,
+				fmt.Sprintf("Invalid action for DELETE request: %s (expected one of %s, %s, %s).",
+					items[0], cmn.Abort, cmn.Remove, cmn.Schedule))
+			return
+		}
+	case http.MethodPut:
+		items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
+		debug.AssertNoErr(err)
+
+		payload := &downloader.DlAdminBody{}
+		if err = cmn.ReadJSON(w, r, payload); err != nil {
+			return
+		}
+		debug.AssertNoErr(payload.Validate(true))
+
+		switch items[0] {
+		case cmn.Pause:
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Pausing download: %s", payload)
+			}
+			response, respErr, statusCode = downloaderXact.PauseJob(payload.ID)
+		case cmn.Resume:
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Resuming download: %s", payload)
+			}
+			response, respErr, statusCode = downloaderXact.ResumeJob(payload.ID)
+		case cmn.Priority:
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Changing priority of download: %s", payload)
+			}
+			response, respErr, statusCode = downloaderXact.SetJobPriority(payload.ID, payload.Priority)
 		default:
 			cmn.AssertMsg(false,
-				fmt.Sprintf("Invalid action for DELETE request: %s (expected either %s or %s).",
-					items[0], cmn.Abort, cmn.Remove))
+				fmt.Sprintf("Invalid action for PUT request: %s (expected one of %s, %s, %s).",
+					items[0], cmn.Pause, cmn.Resume, cmn.Priority))
 			return
 		}
 	default:
 		cmn.AssertMsg(false,
-			fmt.Sprintf("Invalid http method %s; expected one of %s, %s, %s",
-				r.Method, http.MethodGet, http.MethodPost, http.MethodDelete))
+			fmt.Sprintf("Invalid http method %s; expected one of %s, %s, %s, %s",
+				r.Method, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete))
 		return
 	}
 
@@ -170,14 +170,33 @@ func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch b
 	var (
 		workFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileColdget)
 	)
-	if err, errCode = t.Cloud(lom.Bck()).GetObj(ctx, workFQN, lom); err != nil {
+	if err, errCode = t.getCold(ctx, lom, workFQN); err != nil {
+		lom.Unlock(true)
+		return
+	}
+
+	// NOTE: GET - downgrade and keep the lock, PREFETCH - unlock
+	if prefetch {
 		lom.Unlock(true)
+	} else {
+		t.statsT.AddMany(
+			stats.NamedVal64{Name: stats.GetColdCount, Value: 1},
+			stats.NamedVal64{Name: stats.GetColdSize, Value: lom.Size()},
+		)
+		lom.DowngradeLock()
+	}
+	return
+}
+
+// getCold fetches the object from the cloud into the workfile and, if successful,
+// renames the workfile => object; the caller must hold the object's write lock.
+func (t *targetrunner) getCold(ctx context.Context, lom *cluster.LOM, workFQN string) (err error, errCode int) {
+	if err, errCode = t.Cloud(lom.Bck()).GetObj(ctx, workFQN, lom); err != nil {
 		glog.Errorf("%s: GET failed %d, err: %v", lom, errCode, err)
 		return
 	}
 	defer func() {
 		if err != nil {
-			lom.Unlock(true)
 			if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
 				glog.Errorf("Nested error %s => (remove %s => err: %v)", err, workFQN, errRemove)
 				t.fshc(errRemove, workFQN)
@@ -189,21 +208,11 @@ func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch b
 		t.fshc(err, lom.FQN)
 		return
 	}
+	t.archIndexes.del(lom)
 	if err = lom.Persist(); err != nil {
 		return
 	}
 	lom.ReCache()
-
-	// NOTE: GET - downgrade and keep the lock, PREFETCH - unlock
-	if prefetch {
-		lom.Unlock(true)
-	} else {
-		t.statsT.AddMany(
-			stats.NamedVal64{Name: stats.GetColdCount, Value: 1},
-			stats.NamedVal64{Name: stats.GetColdSize, Value: lom.Size()},
-		)
-		lom.DowngradeLock()
-	}
 	return
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction"
 )
@@ -52,8 +53,12 @@ type (
 		migrated bool
 		// Determines if the recv is cold recv: either from another cluster or cloud.
 		cold bool
+		// true: the object is yet to be written to the cloud (see cmn.WritePolicyConf)
+		dirty bool
 		// if true, poi won't erasure-encode an object when finalizing
 		skipEC bool
+		// true: the caller already holds the object's (write) lock
+		locked bool
 	}
 
 	getObjInfo struct {
@@ -71,6 +76,10 @@ type (
 		isGFN bool
 		// true: chunked transfer (en)coding as per https://tools.ietf.org/html/rfc7230#page-36
 		chunked bool
+		// archived file to read from within the object (tar, tgz, zip)
+		archpath string
+		// true: list archive members instead of reading the object
+		archIndex bool
 	}
 
 	// Contains information packed in append handle.
@@ -151,6 +160,7 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 		poi.lom.Uncache()
 		return
 	}
+	poi.t.archIndexes.del(poi.lom)
 	if !poi.skipEC {
 		if ecErr := ec.ECM.EncodeObject(poi.lom); ecErr != nil && ecErr != ec.ErrorECDisabled {
 			err = ecErr
@@ -159,6 +169,12 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 	}
 
 	poi.t.putMirror(poi.lom)
+	if poi.dirty {
+		poi.t.wakeWriteBack(poi.lom)
+	}
+	if !poi.migrated {
+		poi.t.putReplic(poi.lom, mirror.ReplicPut)
+	}
 	return
 }
 
@@ -168,7 +184,17 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 		lom = poi.lom
 		bck = lom.Bck()
 	)
-	if bck.IsRemote() && !poi.migrated {
+	if bck.IsCloud() && !poi.cold && bck.Props.WritePolicy.IsDelayed() {
+		// NOTE: migrated object is dirty unless it has been written to the cloud
+		_, clean := lom.GetCustomMD(cluster.SourceObjMD)
+		poi.dirty = !poi.migrated || !clean
+	}
+	if poi.dirty && !poi.migrated {
+		// write-back or write-never: the previous version's cloud metadata is stale
+		userMD := lom.UserMD()
+		lom.SetCustomMD(nil)
+		lom.SetUserMD(userMD)
+	} else if bck.IsRemote() && !poi.migrated {
 		var version string
 		if bck.IsCloud() || bck.IsHTTP() {
 			version, err, errCode = poi.putCloud()
@@ -195,14 +221,23 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 		return
 	}
 
-	lom.Lock(true)
-	defer lom.Unlock(true)
+	if !poi.locked {
+		lom.Lock(true)
+		defer lom.Unlock(true)
+	}
 
 	if bck.IsAIS() && lom.VersionConf().Enabled && !poi.migrated {
 		if err = lom.IncVersion(); err != nil {
 			return
 		}
 	}
+	if poi.dirty {
+		// NOTE: a failed PUT may leave the entry queued - harmless, the object
+		// is then uploaded as is (or skipped if it does not exist)
+		if err = poi.t.queueDirty(lom); err != nil {
+			return err, http.StatusInternalServerError
+		}
+	}
 	if err := cmn.Rename(poi.workFQN, lom.FQN); err != nil {
 		return fmt.Errorf("rename failed => %s: %w", lom, err), 0
 	}
@@ -234,11 +269,13 @@ func (poi *putObjInfo) putCloud() (ver string, err error, errCode int) {
 		cluster.SourceObjMD: cloud.Provider(),
 	}
 
+	userMD := lom.UserMD()
 	ver, err, errCode = cloud.PutObj(poi.ctx, file, lom)
 	if ver != "" {
 		customMD[cluster.VersionObjMD] = ver
 	}
 	lom.SetCustomMD(customMD)
+	lom.SetUserMD(userMD)
 	debug.AssertNoErr(file.Close())
 	return
 }
@@ -377,8 +414,8 @@ write:
 
 func (goi *getObjInfo) getObject() (err error, errCode int) {
 	var (
-		cs                                            fs.CapStatus
-		doubleCheck, retry, retried, coldGet, capRead bool
+		cs                                                    fs.CapStatus
+		doubleCheck, retry, retried, coldGet, absent, capRead bool
 	)
 	// under lock: lom init, restore from cluster
 	goi.lom.Lock(false)
@@ -390,6 +427,7 @@ do:
 	err = goi.lom.Load()
 	if err != nil {
 		coldGet = cmn.IsObjNotExist(err)
+		absent = coldGet
 		if !coldGet {
 			goi.lom.Unlock(false)
 			return err, http.StatusInternalServerError
@@ -460,6 +498,10 @@ do:
 			// no space left to prefetch object
 			return cs.Err, http.StatusBadRequest
 		}
+		if absent && goi.coalescible() {
+			// join (or start) in-flight fetch and stream the object while downloading
+			return goi.getColdCoalesced()
+		}
 		goi.lom.SetAtimeUnix(goi.started.UnixNano())
 		if err, errCode := goi.t.GetCold(goi.ctx, goi.lom, false /*prefetch*/); err != nil {
 			return err, errCode
@@ -717,7 +759,7 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		return
 	}
 
-	if goi.lom.Size() == 0 {
+	if goi.lom.Size() == 0 && !goi.isArch() {
 		// TODO -- FIXME
 		return
 	}
@@ -743,6 +785,10 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		}
 		return
 	}
+	if goi.isArch() {
+		err, errCode = goi.finalizeArch(file, hdr)
+		return
+	}
 
 	var (
 		r    *cmn.HTTPRange
@@ -788,6 +834,7 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		if goi.lom.Version() != "" {
 			hdr.Set(cmn.HeaderObjVersion, goi.lom.Version())
 		}
+		cmn.UserMDToHdr(hdr, goi.lom.UserMD())
 		hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(goi.lom.Size(), 10))
 		hdr.Set(cmn.HeaderObjAtime, cmn.UnixNano2S(goi.lom.AtimeUnix()))
 		if r != nil {
@@ -23,8 +23,11 @@ import (
 )
 
 const (
-	testMountpath = "/tmp"
-	testBucket    = "bck"
+	testMountpath   = "/tmp"
+	testBucket      = "bck"
+	testCloudBucket = "cloud-bck"
+	testWNBucket    = "wn-bck"     // write-never cloud bucket
+	testRplBucket   = "replic-bck" // replicated to remote AIS cluster
 )
 
 var (
@@ -73,14 +76,34 @@ func TestMain(m *testing.M) {
 	cluster.InitTarget()
 
 	bck := cluster.NewBck(testBucket, cmn.ProviderAIS, cmn.NsGlobal)
+	cloudBck := cluster.NewBck(testCloudBucket, cmn.ProviderAmazon, cmn.NsGlobal)
+	wnBck := cluster.NewBck(testWNBucket, cmn.ProviderAmazon, cmn.NsGlobal)
+	rplBck := cluster.NewBck(testRplBucket, cmn.ProviderAIS, cmn.NsGlobal)
 	bmd := newBucketMD()
 	bmd.add(bck, &cmn.BucketProps{
 		Cksum: cmn.CksumConf{
 			Type: cmn.ChecksumNone,
 		},
 	})
-	t.owner.bmd.put(bmd)
-	fs.CreateBuckets("test", bck.Bck)
+	bmd.add(cloudBck, &cmn.BucketProps{
+		Cksum: cmn.CksumConf{
+			Type:            cmn.ChecksumXXHash,
+			ValidateColdGet: true,
+		},
+	})
+	bmd.add(wnBck, &cmn.BucketProps{
+		Cksum:       cmn.CksumConf{Type: cmn.ChecksumXXHash},
+		WritePolicy: cmn.WritePolicyConf{Cloud: cmn.WriteNever},
+	})
+	bmd.add(rplBck, &cmn.BucketProps{
+		Cksum: cmn.CksumConf{Type: cmn.ChecksumNone},
+		Replication: cmn.ReplicConf{
+			Enabled: true,
+			Bck:     cmn.Bck{Name: testRplBucket, Provider: cmn.ProviderAIS, Ns: cmn.Ns{UUID: "remote"}},
+		},
+	})
+	t.owner.bmd.(*bmdOwnerTgt)._put(bmd)
+	fs.CreateBuckets("test", bck.Bck, cloudBck.Bck, wnBck.Bck, rplBck.Bck)
 
 	os.Exit(m.Run())
 }
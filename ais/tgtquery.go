@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/query"
 	"github.com/NVIDIA/aistore/xaction"
@@ -35,9 +36,22 @@ func (t *targetrunner) queryHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (t *targetrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
-	if _, err := t.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Query, cmn.Init); err != nil {
+	apiItems, err := t.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Query)
+	if err != nil {
 		return
 	}
+	switch apiItems[0] {
+	case cmn.Init:
+		t.httpqueryinit(w, r)
+	case cmn.Select:
+		t.httpqueryselect(w, r)
+	default:
+		t.invalmsghdlrf(w, r, "unknown path /%s/%s/%s", cmn.Version, cmn.Query, apiItems[0])
+	}
+}
+
+// /v1/query/init
+func (t *targetrunner) httpqueryinit(w http.ResponseWriter, r *http.Request) {
 
 	var (
 		handle = r.Header.Get(cmn.HeaderHandle) // TODO: should it be from header or from body?
@@ -67,6 +81,16 @@ func (t *targetrunner) httpquerypost(w http.ResponseWriter, r *http.Request) {
 	if q.Cached {
 		smsg.Flags = cmn.SelectCached
 	}
+	if q.Misplaced {
+		smsg.Flags |= cmn.SelectMisplaced
+	}
+	if startAfter := q.ObjectsSource.StartAfter; startAfter != "" {
+		if q.BckSource.Bck.IsCloud() && !q.Cached {
+			t.invalmsghdlr(w, r, "start_after is not supported when listing Cloud bucket", http.StatusBadRequest)
+			return
+		}
+		smsg.ContinuationToken = startAfter // see walkinfo: entries up to the token are skipped
+	}
 
 	xact, isNew, err := xaction.Registry.RenewObjectsListingXact(ctx, t, q, smsg)
 	if err != nil {
@@ -94,6 +118,8 @@ func (t *targetrunner) httpqueryget(w http.ResponseWriter, r *http.Request) {
 		t.httpquerygetobjects(w, r)
 	case cmn.WorkerOwner:
 		t.httpquerygetworkertarget(w, r)
+	case cmn.Aggregate:
+		t.httpquerygetaggregate(w, r)
 	default:
 		t.invalmsghdlrf(w, r, "unknown path /%s/%s/%s", cmn.Version, cmn.Query, apiItems[0])
 	}
@@ -152,6 +178,57 @@ func (t *targetrunner) httpquerygetobjects(w http.ResponseWriter, r *http.Reques
 	t.writeJSON(w, r, objList, "query_objects")
 }
 
+// /v1/query/select
+// Scans the local objects and streams the matching rows; an error that occurs
+// once the streaming has started is reported via the `Error` trailer.
+func (t *targetrunner) httpqueryselect(w http.ResponseWriter, r *http.Request) {
+	msg := &query.DefMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.InnerSelect.Content == nil {
+		t.invalmsghdlr(w, r, "content select is not defined", http.StatusBadRequest)
+		return
+	}
+	q, err := query.NewQueryFromMsg(t, msg)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Trailer", cmn.HeaderError)
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush() // let the proxy know we are ready
+	}
+	if err := query.SelectContent(context.Background(), t, q, w); err != nil {
+		glog.Errorf("%s: content select failed: %v", t.si, err)
+		w.Header().Set(cmn.HeaderError, err.Error())
+	}
+}
+
+// /v1/query/aggregate
+func (t *targetrunner) httpquerygetaggregate(w http.ResponseWriter, r *http.Request) {
+	msg := &query.NextMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.Handle == "" {
+		t.invalmsghdlr(w, r, "handle cannot be empty", http.StatusBadRequest)
+		return
+	}
+	resultSet := query.Registry.Get(msg.Handle)
+	if resultSet == nil {
+		t.queryDoesntExist(w, r, msg.Handle)
+		return
+	}
+	result, err := resultSet.Aggregate()
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t.writeJSON(w, r, result, "query_aggregate")
+}
+
 // v1/query/discard/handle/value
 func (t *targetrunner) httpqueryput(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Query, cmn.Discard)
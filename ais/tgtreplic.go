@@ -0,0 +1,85 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/cloud"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+// Replication of AIS buckets to remote AIS clusters: see mirror/replic.go
+
+const replicHkInterval = 10 * time.Second
+
+func (t *targetrunner) initReplic() {
+	mirror.InitReplic(t.GetDB())
+	hk.Reg("replication", t.replicHousekeep, replicHkInterval)
+}
+
+func (t *targetrunner) aisCloud() *cloud.AisCloudProvider {
+	return t.cloud[cmn.ProviderAIS].(*cloud.AisCloudProvider)
+}
+
+// putReplic queues the PUT or DELETE of the object for replication (if enabled).
+func (t *targetrunner) putReplic(lom *cluster.LOM, op string) {
+	if !lom.Bprops().Replication.Enabled {
+		return
+	}
+	var size int64
+	if op == mirror.ReplicPut {
+		size = lom.Size()
+	}
+	if err := mirror.ReplicQ.Add(lom.Bck().Bck, lom.ObjName, op, size); err != nil {
+		glog.Errorf("%s: failed to queue %s of %s for replication: %v", t.si, op, lom, err)
+		t.statsT.Add(stats.ErrReplicCount, 1)
+		return
+	}
+	if xreplic := xaction.Registry.RenewReplic(t, t.statsT, t.aisCloud(), lom.Bck()); xreplic != nil {
+		xreplic.Notify()
+	}
+}
+
+// replicHousekeep reports the replication backlog and lag, and (re)starts the
+// replication of the buckets with non-empty backlog - e.g., upon restart.
+func (t *targetrunner) replicHousekeep() time.Duration {
+	if !t.ClusterStarted() {
+		return replicHkInterval
+	}
+	backlog, err := mirror.ReplicQ.Backlog()
+	if err != nil {
+		glog.Errorf("%s: %v", t.si, err)
+		return replicHkInterval
+	}
+	var count, oldest int64
+	for _, b := range backlog {
+		count += b.Count
+		if oldest == 0 || b.Oldest < oldest {
+			oldest = b.Oldest
+		}
+		bck := cluster.NewBckEmbed(b.Bck)
+		if err := bck.Init(t.GetBowner(), t.si); err == nil && !bck.Props.Replication.Enabled {
+			continue // keep the backlog until (and if) re-enabled
+		}
+		// NOTE: the xaction drops the backlog of the bucket that does not exist
+		xaction.Registry.RenewReplic(t, t.statsT, t.aisCloud(), bck)
+	}
+	var lag int64
+	if count > 0 {
+		lag = int64(time.Since(time.Unix(0, oldest)) / time.Microsecond)
+	}
+	t.statsT.AddMany(
+		stats.NamedVal64{Name: stats.ReplicBacklog, Value: count},
+		stats.NamedVal64{Name: stats.ReplicLag, Value: lag},
+	)
+	return replicHkInterval
+}
@@ -0,0 +1,98 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/NVIDIA/aistore/ais/cloud"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/xaction"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replication", func() {
+	var (
+		bck   = cmn.Bck{Name: testRplBucket, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+		loms  []*cluster.LOM
+		saved clouds
+	)
+
+	BeforeEach(func() {
+		if xaction.Registry == nil {
+			xaction.Init()
+		}
+		if smap := t.owner.smap.get(); smap == nil || smap.GetTarget(t.si.ID()) == nil {
+			smap = newSmap()
+			smap.Tmap[t.si.ID()] = t.si
+			t.owner.smap.put(smap)
+		}
+		// no remote clusters attached: replication fails and the entries remain queued
+		saved = t.cloud
+		t.cloud = clouds{cmn.ProviderAIS: cloud.NewAIS(t)}
+		mirror.InitReplic(dbdriver.NewDBMock())
+		loms = loms[:0]
+	})
+
+	AfterEach(func() {
+		xaction.Registry.AbortAllBuckets(cluster.NewBckEmbed(bck))
+		for _, lom := range loms {
+			lom.Uncache()
+			lom.Remove()
+		}
+		mirror.ReplicQ = nil
+		t.cloud = saved
+	})
+
+	newLOM := func(objName string) *cluster.LOM {
+		lom := &cluster.LOM{T: t, ObjName: objName}
+		Expect(lom.Init(bck)).NotTo(HaveOccurred())
+		loms = append(loms, lom)
+		return lom
+	}
+
+	// queued returns the queued operation on the object, if any
+	queued := func(objName string) string {
+		entries, err := mirror.ReplicQ.Entries(bck)
+		Expect(err).NotTo(HaveOccurred())
+		for _, e := range entries {
+			if e.ObjName == objName {
+				return e.Op
+			}
+		}
+		return ""
+	}
+
+	It("should replicate renaming of the object as PUT and DELETE", func() {
+		lom := newLOM("rename-src")
+		poi := &putObjInfo{
+			t:       t,
+			lom:     lom,
+			r:       ioutil.NopCloser(bytes.NewReader([]byte("renamed"))),
+			workFQN: lom.FQN + ".work",
+		}
+		err, _ := poi.putObject()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queued("rename-src")).To(Equal(mirror.ReplicPut))
+
+		dst := newLOM("rename-dst")
+		r := httptest.NewRequest(http.MethodPost, cmn.URLPath(cmn.Version, cmn.Objects, testRplBucket, "rename-src"), nil)
+		w := httptest.NewRecorder()
+		t.renameObject(w, r, &cmn.ActionMsg{Action: cmn.ActRenameObject, Name: "rename-dst"})
+		Expect(w.Code).To(Equal(http.StatusOK))
+
+		Expect(dst.Load(false)).NotTo(HaveOccurred())
+		Expect(newLOM("rename-src").Load(false)).To(HaveOccurred())
+		Expect(queued("rename-dst")).To(Equal(mirror.ReplicPut))
+		Expect(queued("rename-src")).To(Equal(mirror.ReplicDel))
+	})
+})
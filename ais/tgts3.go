@@ -6,6 +6,7 @@ package ais
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"path"
@@ -18,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/query"
 )
 
 // PUT s3/bckName/objName
@@ -34,6 +36,8 @@ func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		t.getObjS3(w, r, apiItems)
 	case http.MethodPut:
 		t.putObjS3(w, r, apiItems)
+	case http.MethodPost:
+		t.postObjS3(w, r, apiItems)
 	case http.MethodDelete:
 		t.delObjS3(w, r, apiItems)
 	default:
@@ -140,6 +144,12 @@ func (t *targetrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, it
 	lom.SetAtimeUnix(started.UnixNano())
 
 	// TODO: lom.SetCustomMD(cluster.AmazonMD5ObjMD, checksum)
+	md, err := s3compat.UserMDFromHdr(r.Header)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cmn.UserMDToHdr(r.Header, md)
 
 	if err, errCode := t.doPut(r, lom, started); err != nil {
 		t.fshc(err, lom.FQN)
@@ -150,6 +160,10 @@ func (t *targetrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, it
 
 // PUT s3/bckName/objName
 func (t *targetrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	if s3compat.IsMultipart(r.URL.Query()) {
+		t.putPartS3(w, r, items)
+		return
+	}
 	if r.Header.Get(s3compat.HeaderObjSrc) == "" {
 		t.directPutObjS3(w, r, items)
 		return
@@ -268,6 +282,10 @@ func (t *targetrunner) headObjS3(w http.ResponseWriter, r *http.Request, items [
 
 // DEL s3/bckName/objName
 func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	if s3compat.IsMultipart(r.URL.Query()) {
+		t.abortMptS3(w, r, items)
+		return
+	}
 	var (
 		config = cmn.GCO.Get()
 		bck    = cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
@@ -301,3 +319,38 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 	// EC cleanup if EC is enabled
 	ec.ECM.CleanupObject(lom)
 }
+
+// POST s3/bckName/objName?select&select-type=2
+func (t *targetrunner) selectObjS3(w http.ResponseWriter, r *http.Request, lom *cluster.LOM) {
+	req := &s3compat.SelectObjectContentRequest{}
+	if err := xml.NewDecoder(r.Body).Decode(req); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var cf *query.ContentFilter
+	msg, err := req.ContentMsg()
+	if err == nil {
+		cf, err = query.NewContentFilter(msg)
+	}
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := lom.Load(true); err != nil {
+		if cmn.IsObjNotExist(err) {
+			t.invalmsghdlrsilent(w, r, err.Error(), http.StatusNotFound)
+		} else {
+			t.invalmsghdlr(w, r, err.Error())
+		}
+		return
+	}
+	sw := s3compat.NewSelectWriter(w)
+	if err := query.SelectObjectContent(lom, cf, sw); err != nil {
+		glog.Errorf("select %s: %v", lom, err)
+		sw.Fail(s3compat.SelectErrCode, err.Error())
+		return
+	}
+	if err := sw.Finish(lom.Size()); err != nil && !cmn.IsErrConnectionReset(err) {
+		glog.Errorf("select %s: %v", lom, err)
+	}
+}
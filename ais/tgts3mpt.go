@@ -0,0 +1,411 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+)
+
+// S3 multipart upload: the parts are stored as workfiles on the destination
+// object's (HRW) target and get concatenated into the object upon completion.
+// UploadPartCopy (part from an existing object) reuses server-side compose
+// machinery to read the source - locally or from its target.
+
+const (
+	mptMaxPartNum  = 10000          // as in: S3
+	mptIdleTimeout = 24 * time.Hour // abort uploads that were not updated for that long
+)
+
+type (
+	mptPart struct {
+		fqn  string
+		size int64
+		md5  string
+	}
+	mptUpload struct {
+		bck        cmn.Bck
+		objName    string
+		parts      map[int]*mptPart // by part number
+		userMD     cmn.SimpleKVs    // x-amz-meta-*
+		lastAccess int64
+	}
+	mptUploads struct {
+		mtx sync.Mutex
+		m   map[string]*mptUpload // by upload ID
+	}
+)
+
+func (u *mptUploads) init() {
+	u.m = make(map[string]*mptUpload, 16)
+	hk.Reg("s3-multipart-uploads", u.housekeep, time.Hour)
+}
+
+func (u *mptUploads) add(lom *cluster.LOM, userMD cmn.SimpleKVs) (id string) {
+	id = cmn.GenUUID()
+	u.mtx.Lock()
+	u.m[id] = &mptUpload{
+		bck:        lom.Bck().Bck,
+		objName:    lom.ObjName,
+		parts:      make(map[int]*mptPart, 4),
+		userMD:     userMD,
+		lastAccess: mono.NanoTime(),
+	}
+	u.mtx.Unlock()
+	return
+}
+
+// under lock
+func (u *mptUploads) _get(id string, lom *cluster.LOM) (*mptUpload, error) {
+	upload, ok := u.m[id]
+	if !ok || upload.objName != lom.ObjName || !upload.bck.Equal(lom.Bck().Bck) {
+		return nil, cmn.NewNotFoundError("multipart upload %q for %s", id, lom)
+	}
+	return upload, nil
+}
+
+// adds (or replaces) the part; returns the part that was replaced, if any
+func (u *mptUploads) addPart(id string, lom *cluster.LOM, num int, part *mptPart) (prev *mptPart, err error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	upload, err := u._get(id, lom)
+	if err != nil {
+		return nil, err
+	}
+	prev = upload.parts[num]
+	upload.parts[num] = part
+	upload.lastAccess = mono.NanoTime()
+	return
+}
+
+// returns a copy of the upload that can be used without holding the lock
+func (u *mptUploads) get(id string, lom *cluster.LOM) (*mptUpload, error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	upload, err := u._get(id, lom)
+	if err != nil {
+		return nil, err
+	}
+	upload.lastAccess = mono.NanoTime()
+	clone := *upload
+	clone.parts = make(map[int]*mptPart, len(upload.parts))
+	for num, part := range upload.parts {
+		clone.parts[num] = part
+	}
+	return &clone, nil
+}
+
+// removes the upload from the registry (the caller is then responsible for its parts)
+func (u *mptUploads) del(id string, lom *cluster.LOM) (*mptUpload, error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	upload, err := u._get(id, lom)
+	if err != nil {
+		return nil, err
+	}
+	delete(u.m, id)
+	return upload, nil
+}
+
+func (u *mptUploads) housekeep() time.Duration {
+	var (
+		now   = mono.NanoTime()
+		stale []*mptUpload
+	)
+	u.mtx.Lock()
+	for id, upload := range u.m {
+		if time.Duration(now-upload.lastAccess) > mptIdleTimeout {
+			stale = append(stale, upload)
+			delete(u.m, id)
+		}
+	}
+	u.mtx.Unlock()
+	for _, upload := range stale {
+		glog.Warningf("aborting stale multipart upload of %s/%s", upload.bck, upload.objName)
+		upload.removeParts()
+	}
+	return time.Hour
+}
+
+func (upload *mptUpload) removeParts() {
+	for _, part := range upload.parts {
+		if err := cmn.RemoveFile(part.fqn); err != nil {
+			glog.Errorf("failed to remove %s: %v", part.fqn, err)
+		}
+	}
+}
+
+/////////////////////
+// S3 API handlers //
+/////////////////////
+
+func (t *targetrunner) initLomS3(w http.ResponseWriter, r *http.Request, items []string) *cluster.LOM {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return nil
+	}
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd, nil); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return nil
+	}
+	lom := &cluster.LOM{T: t, ObjName: path.Join(items[1:]...)}
+	if err := lom.Init(bck.Bck); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return nil
+	}
+	return lom
+}
+
+// POST s3/bckName/objName?uploads - create multipart upload
+// POST s3/bckName/objName?uploadId=<ID> - complete multipart upload
+// POST s3/bckName/objName?select - select object content
+func (t *targetrunner) postObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.initLomS3(w, r, items)
+	if lom == nil {
+		return
+	}
+	q := r.URL.Query()
+	if s3compat.IsSelect(q) {
+		t.selectObjS3(w, r, lom)
+		return
+	}
+	if _, ok := q[s3compat.URLParamMultipartUploads]; ok {
+		md, err := s3compat.UserMDFromHdr(r.Header)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id := t.s3Uploads.add(lom, md)
+		result := s3compat.NewInitiateMultipartUploadResult(lom.BckName(), lom.ObjName, id)
+		w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+		w.Write(result.MustMarshal())
+		return
+	}
+	id := q.Get(s3compat.URLParamUploadID)
+	if id == "" {
+		t.invalmsghdlr(w, r, "invalid request")
+		return
+	}
+	complete := &s3compat.CompleteMultipartUpload{}
+	if err := xml.NewDecoder(r.Body).Decode(complete); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	etag, err, errCode := t.completeMpt(lom, id, complete.Parts)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	result := s3compat.NewCompleteMultipartUploadResult(lom.BckName(), lom.ObjName, etag)
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(result.MustMarshal())
+}
+
+// PUT s3/bckName/objName?partNumber=<N>&uploadId=<ID> - upload part, or
+// upload part copy when `x-amz-copy-source` is specified
+func (t *targetrunner) putPartS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.initLomS3(w, r, items)
+	if lom == nil {
+		return
+	}
+	var (
+		q       = r.URL.Query()
+		id      = q.Get(s3compat.URLParamUploadID)
+		num, _  = strconv.Atoi(q.Get(s3compat.URLParamPartNumber))
+		copySrc = r.Header.Get(s3compat.HeaderObjSrc)
+		reader  io.ReadCloser
+		size    = r.ContentLength
+	)
+	if num < 1 || num > mptMaxPartNum {
+		t.invalmsghdlrf(w, r, "invalid part number %q", q.Get(s3compat.URLParamPartNumber))
+		return
+	}
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.invalmsghdlr(w, r, cs.Err.Error(), http.StatusInsufficientStorage)
+		return
+	}
+	if copySrc == "" {
+		reader = r.Body
+	} else {
+		var (
+			src          *composeSrc
+			err          error
+			errCode      int
+			offset       int64
+			bucket, name string
+		)
+		if bucket, name, err = s3compat.ParseCopySrc(copySrc); err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		csrc := &cmn.ComposeSrc{Bck: cmn.Bck{Name: bucket, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}, ObjName: name}
+		if src, err, errCode = t.composeOpen(csrc); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		defer src.Close()
+		reader, size = src, src.size
+		if rng := r.Header.Get(s3compat.HeaderObjSrcRange); rng != "" {
+			if offset, size, err = s3compat.ParseCopySrcRange(rng); err == nil && offset+size > src.size {
+				err = fmt.Errorf("copy source range %q exceeds the size of %s/%s (%d)", rng, bucket, name, src.size)
+			}
+			if err != nil {
+				t.invalmsghdlr(w, r, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			if _, err = io.CopyN(ioutil.Discard, src, offset); err != nil {
+				t.invalmsghdlr(w, r, err.Error())
+				return
+			}
+		}
+	}
+
+	var (
+		fqn       = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileMultipart)
+		buf, slab = t.gmm.Alloc()
+	)
+	cksum, err := cmn.SaveReader(fqn, reader, buf, cmn.ChecksumMD5, size, "")
+	slab.Free(buf)
+	if err != nil {
+		t.fshc(err, fqn)
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	// NOTE: the size is unknown (-1) when the part is sent with chunked encoding
+	finfo, err := os.Stat(fqn)
+	if err == nil && size >= 0 && finfo.Size() != size {
+		err = fmt.Errorf("%s: part %d: expected %d bytes, written %d", lom, num, size, finfo.Size())
+	}
+	if err != nil {
+		cmn.RemoveFile(fqn)
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	part := &mptPart{fqn: fqn, size: finfo.Size(), md5: cksum.Value()}
+	prev, err := t.s3Uploads.addPart(id, lom, num, part)
+	if err != nil {
+		cmn.RemoveFile(fqn)
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if prev != nil { // part re-uploaded
+		cmn.RemoveFile(prev.fqn)
+	}
+	if copySrc == "" {
+		w.Header().Set(s3compat.HeaderETag, part.md5)
+		return
+	}
+	result := s3compat.CopyPartResult{LastModified: s3compat.FormatTime(time.Now()), ETag: part.md5}
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(result.MustMarshal())
+}
+
+// DELETE s3/bckName/objName?uploadId=<ID> - abort multipart upload
+func (t *targetrunner) abortMptS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.initLomS3(w, r, items)
+	if lom == nil {
+		return
+	}
+	upload, err := t.s3Uploads.del(r.URL.Query().Get(s3compat.URLParamUploadID), lom)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	upload.removeParts()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// concatenates the specified parts into the object; returns S3-style ETag
+// (MD5 of the parts' MD5s followed by the number of parts)
+func (t *targetrunner) completeMpt(lom *cluster.LOM, id string, parts []*s3compat.PartInfo) (etag string, err error, errCode int) {
+	if len(parts) == 0 {
+		return "", fmt.Errorf("%s: no parts to complete multipart upload %q", lom, id), http.StatusBadRequest
+	}
+	if !sort.SliceIsSorted(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber }) {
+		return "", fmt.Errorf("%s: parts must be listed in ascending order", lom), http.StatusBadRequest
+	}
+	// NOTE: the upload remains registered (and can be completed again) until
+	// the object is written - as in S3, failure to complete is not an abort
+	upload, err := t.s3Uploads.get(id, lom)
+	if err != nil {
+		return "", err, http.StatusNotFound
+	}
+
+	var (
+		readers = make([]io.Reader, 0, len(parts))
+		files   = make([]*os.File, 0, len(parts))
+		md5s    = md5.New()
+		started = time.Now()
+	)
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	for _, p := range parts {
+		part, ok := upload.parts[p.PartNumber]
+		if !ok || (p.ETag != "" && strings.Trim(p.ETag, "\"") != part.md5) {
+			return "", fmt.Errorf("%s: invalid part %d", lom, p.PartNumber), http.StatusBadRequest
+		}
+		b, _ := hex.DecodeString(part.md5)
+		md5s.Write(b)
+		file, err := os.Open(part.fqn)
+		if err != nil {
+			return "", err, http.StatusInternalServerError
+		}
+		files = append(files, file)
+		readers = append(readers, io.LimitReader(file, part.size))
+	}
+	poi := &putObjInfo{
+		started: started,
+		t:       t,
+		lom:     lom,
+		r:       ioutil.NopCloser(io.MultiReader(readers...)),
+		ctx:     context.Background(),
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+	}
+	if lom.Bck().IsAIS() && lom.VersionConf().Enabled {
+		lom.Load() // need to know the current version if versioning enabled
+	}
+	lom.SetUserMD(upload.userMD)
+	lom.SetAtimeUnix(started.UnixNano())
+	if err, errCode = poi.putObject(); err != nil {
+		if errCode == 0 {
+			errCode = http.StatusInternalServerError
+		}
+		return
+	}
+	// completed: the parts are no longer needed
+	if upload, err := t.s3Uploads.del(id, lom); err == nil {
+		upload.removeParts()
+	}
+	etag = hex.EncodeToString(md5s.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: completed multipart upload of %s (%d parts) in %v", t.si, lom, len(parts), time.Since(started))
+	}
+	return
+}
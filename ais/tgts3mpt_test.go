@@ -0,0 +1,350 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("S3 multipart upload and compose", func() {
+	const objName = "mpt/obj"
+
+	var items = []string{testBucket, objName}
+
+	BeforeEach(func() {
+		t.s3Uploads.m = make(map[string]*mptUpload, 4)
+		if smap := t.owner.smap.get(); smap == nil || smap.GetTarget(t.si.ID()) == nil {
+			smap = newSmap()
+			smap.Tmap[t.si.ID()] = t.si
+			t.owner.smap.put(smap)
+		}
+	})
+
+	newLom := func(objName string) *cluster.LOM {
+		lom := &cluster.LOM{T: t, ObjName: objName}
+		Expect(lom.Init(cmn.Bck{Name: testBucket, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal})).NotTo(HaveOccurred())
+		return lom
+	}
+
+	putObj := func(objName string, data []byte) {
+		poi := &putObjInfo{
+			t:       t,
+			lom:     newLom(objName),
+			r:       ioutil.NopCloser(bytes.NewReader(data)),
+			workFQN: newLom(objName).FQN + ".work",
+		}
+		err, _ := poi.putObject()
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	readObj := func(objName string) []byte {
+		lom := newLom(objName)
+		Expect(lom.Load()).NotTo(HaveOccurred())
+		b, err := ioutil.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(int64(len(b))).To(Equal(lom.Size()))
+		return b
+	}
+
+	md5hex := func(b []byte) string {
+		h := md5.Sum(b)
+		return hex.EncodeToString(h[:])
+	}
+
+	initUpload := func() string {
+		r := httptest.NewRequest(http.MethodPost, "/s3/"+testBucket+"/"+objName+"?"+s3compat.URLParamMultipartUploads, nil)
+		w := httptest.NewRecorder()
+		t.postObjS3(w, r, items)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		result := &s3compat.InitiateMultipartUploadResult{}
+		Expect(xml.Unmarshal(w.Body.Bytes(), result)).NotTo(HaveOccurred())
+		Expect(result.UploadID).NotTo(BeEmpty())
+		return result.UploadID
+	}
+
+	partURL := func(id string, num int) string {
+		q := url.Values{}
+		q.Set(s3compat.URLParamUploadID, id)
+		q.Set(s3compat.URLParamPartNumber, strconv.Itoa(num))
+		return "/s3/" + testBucket + "/" + objName + "?" + q.Encode()
+	}
+
+	// chunked: the size of the part is unknown (as with `Transfer-Encoding: chunked`)
+	uploadPart := func(id string, num int, data []byte, chunked bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, partURL(id, num), bytes.NewReader(data))
+		if chunked {
+			r.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		t.putPartS3(w, r, items)
+		return w
+	}
+
+	copyPart := func(id string, num int, src, rng string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, partURL(id, num), nil)
+		r.Header.Set(s3compat.HeaderObjSrc, "/"+testBucket+"/"+src)
+		if rng != "" {
+			r.Header.Set(s3compat.HeaderObjSrcRange, rng)
+		}
+		w := httptest.NewRecorder()
+		t.putPartS3(w, r, items)
+		return w
+	}
+
+	complete := func(id string, parts ...*s3compat.PartInfo) *httptest.ResponseRecorder {
+		body, err := xml.Marshal(&s3compat.CompleteMultipartUpload{Parts: parts})
+		Expect(err).NotTo(HaveOccurred())
+		r := httptest.NewRequest(http.MethodPost, "/s3/"+testBucket+"/"+objName+"?"+s3compat.URLParamUploadID+"="+id,
+			bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		t.postObjS3(w, r, items)
+		return w
+	}
+
+	mptETag := func(parts ...[]byte) string {
+		md5s := md5.New()
+		for _, p := range parts {
+			h := md5.Sum(p)
+			md5s.Write(h[:])
+		}
+		return hex.EncodeToString(md5s.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+	}
+
+	Describe("multipart upload", func() {
+		It("should upload parts and complete the upload", func() {
+			var (
+				id    = initUpload()
+				part1 = bytes.Repeat([]byte("a"), 1000)
+				part2 = bytes.Repeat([]byte("b"), 10)
+			)
+			w := uploadPart(id, 1, part1, false)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get(s3compat.HeaderETag)).To(Equal(md5hex(part1)))
+			Expect(uploadPart(id, 2, part2, false).Code).To(Equal(http.StatusOK))
+
+			w = complete(id,
+				&s3compat.PartInfo{PartNumber: 1, ETag: "\"" + md5hex(part1) + "\""},
+				&s3compat.PartInfo{PartNumber: 2, ETag: md5hex(part2)},
+			)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			result := &s3compat.CompleteMultipartUploadResult{}
+			Expect(xml.Unmarshal(w.Body.Bytes(), result)).NotTo(HaveOccurred())
+			Expect(result.ETag).To(Equal(mptETag(part1, part2)))
+			Expect(readObj(objName)).To(Equal(append(part1, part2...)))
+			Expect(t.s3Uploads.m).To(BeEmpty())
+		})
+
+		It("should upload parts of unknown size", func() {
+			var (
+				id    = initUpload()
+				part1 = bytes.Repeat([]byte("c"), 4096)
+				part2 = []byte("tail")
+			)
+			Expect(uploadPart(id, 1, part1, true).Code).To(Equal(http.StatusOK))
+			Expect(uploadPart(id, 2, part2, true).Code).To(Equal(http.StatusOK))
+			Expect(t.s3Uploads.m[id].parts[1].size).To(BeEquivalentTo(len(part1)))
+
+			w := complete(id, &s3compat.PartInfo{PartNumber: 1}, &s3compat.PartInfo{PartNumber: 2})
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(readObj(objName)).To(Equal(append(part1, part2...)))
+		})
+
+		It("should fail to upload a part shorter than its content length", func() {
+			id := initUpload()
+			r := httptest.NewRequest(http.MethodPut, partURL(id, 1), bytes.NewReader(nil))
+			r.ContentLength = 16
+			w := httptest.NewRecorder()
+			t.putPartS3(w, r, items)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(t.s3Uploads.m[id].parts).To(BeEmpty())
+		})
+
+		It("should replace the re-uploaded part", func() {
+			var (
+				id   = initUpload()
+				old  = []byte("old content of the part")
+				part = []byte("new")
+			)
+			Expect(uploadPart(id, 1, old, false).Code).To(Equal(http.StatusOK))
+			oldFQN := t.s3Uploads.m[id].parts[1].fqn
+			Expect(uploadPart(id, 1, part, true).Code).To(Equal(http.StatusOK))
+			Expect(t.s3Uploads.m[id].parts).To(HaveLen(1))
+			_, err := ioutil.ReadFile(oldFQN)
+			Expect(err).To(HaveOccurred())
+
+			w := complete(id, &s3compat.PartInfo{PartNumber: 1, ETag: md5hex(part)})
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(readObj(objName)).To(Equal(part))
+		})
+
+		It("should reject the part with mismatching ETag and keep the upload", func() {
+			id := initUpload()
+			Expect(uploadPart(id, 1, []byte("new"), false).Code).To(Equal(http.StatusOK))
+			w := complete(id, &s3compat.PartInfo{PartNumber: 1, ETag: md5hex([]byte("old"))})
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			// retry with the correct ETag
+			w = complete(id, &s3compat.PartInfo{PartNumber: 1, ETag: md5hex([]byte("new"))})
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(readObj(objName)).To(Equal([]byte("new")))
+			Expect(t.s3Uploads.m).To(BeEmpty())
+		})
+
+		It("should keep the upload if failed to write the object", func() {
+			id := initUpload()
+			Expect(uploadPart(id, 1, []byte("part"), false).Code).To(Equal(http.StatusOK))
+			Expect(os.Remove(t.s3Uploads.m[id].parts[1].fqn)).NotTo(HaveOccurred())
+			w := complete(id, &s3compat.PartInfo{PartNumber: 1})
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			Expect(t.s3Uploads.m).To(HaveKey(id))
+
+			// re-upload the lost part and retry
+			Expect(uploadPart(id, 1, []byte("part"), false).Code).To(Equal(http.StatusOK))
+			Expect(complete(id, &s3compat.PartInfo{PartNumber: 1}).Code).To(Equal(http.StatusOK))
+			Expect(readObj(objName)).To(Equal([]byte("part")))
+		})
+
+		It("should reject invalid parts", func() {
+			id := initUpload()
+			Expect(uploadPart(id, 0, []byte("x"), false).Code).To(Equal(http.StatusBadRequest))
+			Expect(uploadPart(id, mptMaxPartNum+1, []byte("x"), false).Code).To(Equal(http.StatusBadRequest))
+			Expect(uploadPart("nonexistent", 1, []byte("x"), false).Code).To(Equal(http.StatusNotFound))
+
+			Expect(uploadPart(id, 1, []byte("x"), false).Code).To(Equal(http.StatusOK))
+			Expect(uploadPart(id, 2, []byte("y"), false).Code).To(Equal(http.StatusOK))
+			// not in ascending order
+			w := complete(id, &s3compat.PartInfo{PartNumber: 2}, &s3compat.PartInfo{PartNumber: 1})
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			// missing part
+			w = complete(id, &s3compat.PartInfo{PartNumber: 1}, &s3compat.PartInfo{PartNumber: 3})
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			// the stored parts are intact
+			w = complete(id, &s3compat.PartInfo{PartNumber: 1}, &s3compat.PartInfo{PartNumber: 2})
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(readObj(objName)).To(Equal([]byte("xy")))
+		})
+
+		It("should upload part copy with range", func() {
+			var (
+				src  = []byte("0123456789abcdefghij")
+				tail = []byte("-tail")
+			)
+			putObj("mpt/src", src)
+			id := initUpload()
+			w := copyPart(id, 1, "mpt/src", "bytes=5-14")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			result := &s3compat.CopyPartResult{}
+			Expect(xml.Unmarshal(w.Body.Bytes(), result)).NotTo(HaveOccurred())
+			Expect(result.ETag).To(Equal(md5hex(src[5:15])))
+
+			Expect(copyPart(id, 2, "mpt/src", "").Code).To(Equal(http.StatusOK))
+			Expect(uploadPart(id, 3, tail, false).Code).To(Equal(http.StatusOK))
+
+			w = complete(id,
+				&s3compat.PartInfo{PartNumber: 1, ETag: result.ETag},
+				&s3compat.PartInfo{PartNumber: 2},
+				&s3compat.PartInfo{PartNumber: 3},
+			)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			expected := append(append(append([]byte{}, src[5:15]...), src...), tail...)
+			Expect(readObj(objName)).To(Equal(expected))
+		})
+
+		It("should reject part copy with invalid range", func() {
+			putObj("mpt/src", []byte("0123456789"))
+			id := initUpload()
+			Expect(copyPart(id, 1, "mpt/src", "bytes=5-10").Code).To(Equal(http.StatusRequestedRangeNotSatisfiable))
+			Expect(copyPart(id, 1, "mpt/src", "bytes=5-1").Code).To(Equal(http.StatusRequestedRangeNotSatisfiable))
+			Expect(t.s3Uploads.m[id].parts).To(BeEmpty())
+		})
+
+		It("should abort the upload and remove its parts", func() {
+			id := initUpload()
+			Expect(uploadPart(id, 1, []byte("part"), false).Code).To(Equal(http.StatusOK))
+			fqn := t.s3Uploads.m[id].parts[1].fqn
+
+			r := httptest.NewRequest(http.MethodDelete, "/s3/"+testBucket+"/"+objName+"?"+s3compat.URLParamUploadID+"="+id, nil)
+			w := httptest.NewRecorder()
+			t.abortMptS3(w, r, items)
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			_, err := ioutil.ReadFile(fqn)
+			Expect(err).To(HaveOccurred())
+
+			Expect(uploadPart(id, 2, []byte("part"), false).Code).To(Equal(http.StatusNotFound))
+			Expect(complete(id, &s3compat.PartInfo{PartNumber: 1}).Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("compose", func() {
+		var sources []cmn.ComposeSrc
+
+		BeforeEach(func() {
+			sources = sources[:0]
+			for i := 0; i < 3; i++ {
+				name := fmt.Sprintf("compose/src%d", i)
+				putObj(name, bytes.Repeat([]byte{byte('a' + i)}, 100*(i+1)))
+				sources = append(sources, cmn.ComposeSrc{
+					Bck:     cmn.Bck{Name: testBucket, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal},
+					ObjName: name,
+				})
+			}
+		})
+
+		It("should compose objects back to back", func() {
+			err, _ := t.compose(newLom("compose/dst"), sources, false)
+			Expect(err).NotTo(HaveOccurred())
+			var expected []byte
+			for _, src := range sources {
+				expected = append(expected, readObj(src.ObjName)...)
+			}
+			Expect(readObj("compose/dst")).To(Equal(expected))
+		})
+
+		It("should compose objects into tar", func() {
+			err, _ := t.compose(newLom("compose/dst.tar"), sources, true)
+			Expect(err).NotTo(HaveOccurred())
+			tr := tar.NewReader(bytes.NewReader(readObj("compose/dst.tar")))
+			for _, src := range sources {
+				hdr, err := tr.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hdr.Name).To(Equal(src.ObjName))
+				b, err := ioutil.ReadAll(tr)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(b).To(Equal(readObj(src.ObjName)))
+			}
+			_, err = tr.Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+
+		It("should fail if a source does not exist", func() {
+			sources = append(sources, cmn.ComposeSrc{
+				Bck:     cmn.Bck{Name: "nonexistent", Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal},
+				ObjName: "obj",
+			})
+			err, errCode := t.compose(newLom("compose/dst-missing"), sources, false)
+			Expect(err).To(HaveOccurred())
+			Expect(errCode).To(Equal(http.StatusNotFound))
+			Expect(newLom("compose/dst-missing").Load()).To(HaveOccurred())
+		})
+	})
+})
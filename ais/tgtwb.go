@@ -0,0 +1,76 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+// Write-back and write-never cloud buckets: see mirror/writeback.go
+
+const wbHkInterval = 10 * time.Second
+
+func (t *targetrunner) initWriteBack() {
+	mirror.InitWriteBack(t.GetDB())
+	hk.Reg("writeback", t.wbHousekeep, wbHkInterval)
+}
+
+// queueDirty records the object that is yet to be written to the cloud; must be
+// called (under lock) before the object is put in place - otherwise, the object
+// that has no cloud copy would be left unprotected (e.g., evicted by LRU).
+func (t *targetrunner) queueDirty(lom *cluster.LOM) (err error) {
+	if err = mirror.DirtyQ.Add(lom.Bck().Bck, lom.ObjName, mirror.ReplicPut, lom.Size()); err != nil {
+		err = fmt.Errorf("%s: failed to queue %s for write-back: %v", t.si, lom, err)
+		t.statsT.Add(stats.ErrWriteBackCount, 1)
+	}
+	return
+}
+
+// wakeWriteBack wakes up the xaction to write back newly queued object.
+func (t *targetrunner) wakeWriteBack(lom *cluster.LOM) {
+	if lom.Bprops().WritePolicy.Policy() != cmn.WriteBack {
+		return
+	}
+	if xwb := xaction.Registry.RenewWriteBack(t, t.statsT, lom.Bck()); xwb != nil {
+		xwb.Notify()
+	}
+}
+
+// delDirty drops the queued object (if any) - the object has been deleted.
+func (t *targetrunner) delDirty(lom *cluster.LOM) {
+	if err := mirror.DirtyQ.Remove(lom.Bck().Bck, lom.ObjName); err != nil {
+		glog.Errorf("%s: failed to dequeue %s: %v", t.si, lom, err)
+	}
+}
+
+// wbHousekeep (re)starts writing back the buckets with dirty objects - e.g., upon restart.
+func (t *targetrunner) wbHousekeep() time.Duration {
+	if !t.ClusterStarted() {
+		return wbHkInterval
+	}
+	backlog, err := mirror.DirtyQ.Backlog()
+	if err != nil {
+		glog.Errorf("%s: %v", t.si, err)
+		return wbHkInterval
+	}
+	for _, b := range backlog {
+		bck := cluster.NewBckEmbed(b.Bck)
+		if err := bck.Init(t.GetBowner(), t.si); err == nil && bck.Props.WritePolicy.Policy() != cmn.WriteBack {
+			continue // write-never or write-through: until flushed
+		}
+		// NOTE: the xaction drops the backlog of the bucket that does not exist
+		xaction.Registry.RenewWriteBack(t, t.statsT, bck)
+	}
+	return wbHkInterval
+}
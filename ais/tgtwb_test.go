@@ -0,0 +1,212 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/mirror"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// wbCloudMock counts cloud PUTs and DELETEs; DELETE fails with `delErrCode`, if set
+type wbCloudMock struct {
+	cluster.CloudProvider
+	puts       atomic.Int32
+	dels       atomic.Int32
+	delErrCode int
+}
+
+func (m *wbCloudMock) Provider() string { return cmn.ProviderAmazon }
+
+func (m *wbCloudMock) PutObj(_ context.Context, r io.Reader, _ *cluster.LOM) (string, error, int) {
+	m.puts.Inc()
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	return "1", nil, 0
+}
+
+func (m *wbCloudMock) DeleteObj(_ context.Context, lom *cluster.LOM) (error, int) {
+	m.dels.Inc()
+	if m.delErrCode != 0 {
+		return cmn.NewNotFoundError(lom.String()), m.delErrCode
+	}
+	return nil, 0
+}
+
+var _ = Describe("Write-never and write-back", func() {
+	var (
+		mock  *wbCloudMock
+		loms  []*cluster.LOM
+		saved clouds
+		data  = bytes.Repeat([]byte("dirty"), 1000)
+	)
+
+	BeforeEach(func() {
+		mock = &wbCloudMock{}
+		saved = t.cloud
+		t.cloud = clouds{cmn.ProviderAmazon: mock}
+		mirror.InitWriteBack(dbdriver.NewDBMock())
+		loms = loms[:0]
+	})
+
+	AfterEach(func() {
+		for _, lom := range loms {
+			lom.Uncache()
+			lom.Remove()
+		}
+		mirror.DirtyQ = nil
+		t.cloud = saved
+	})
+
+	newLOM := func(bckName, objName string) *cluster.LOM {
+		lom := &cluster.LOM{T: t, ObjName: objName}
+		Expect(lom.Init(cmn.Bck{Name: bckName, Provider: cmn.ProviderAmazon, Ns: cmn.NsGlobal})).NotTo(HaveOccurred())
+		loms = append(loms, lom)
+		return lom
+	}
+
+	newPoi := func(lom *cluster.LOM) *putObjInfo {
+		return &putObjInfo{
+			started: time.Now(),
+			t:       t,
+			lom:     lom,
+			r:       ioutil.NopCloser(bytes.NewReader(data)),
+			ctx:     context.Background(),
+			workFQN: path.Join(testMountpath, lom.ObjName+".work"),
+		}
+	}
+
+	put := func(poi *putObjInfo) {
+		err, _ := poi.putObject()
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// load reads the object's metadata from disk
+	load := func(lom *cluster.LOM) *cluster.LOM {
+		clone := &cluster.LOM{T: t, ObjName: lom.ObjName}
+		Expect(clone.Init(lom.Bck().Bck)).NotTo(HaveOccurred())
+		clone.Uncache()
+		Expect(clone.Load(false)).NotTo(HaveOccurred())
+		return clone
+	}
+
+	Describe("PUT", func() {
+		It("should write the object to the cloud with write-through policy", func() {
+			lom := newLOM(testCloudBucket, "wb/through")
+			put(newPoi(lom))
+			Expect(mock.puts.Load()).To(BeEquivalentTo(1))
+			Expect(mirror.IsDirty(lom)).To(BeFalse())
+			Expect(load(lom).CustomMD()).To(HaveKeyWithValue(cluster.SourceObjMD, cmn.ProviderAmazon))
+		})
+
+		It("should queue the object instead of writing it to the cloud", func() {
+			lom := newLOM(testWNBucket, "wb/dirty")
+			// the object previously written to the cloud
+			lom.SetCustomMD(cmn.SimpleKVs{cluster.SourceObjMD: cmn.ProviderAmazon, cluster.VersionObjMD: "1"})
+			lom.SetUserMD(cmn.SimpleKVs{"color": "blue"})
+
+			put(newPoi(lom))
+			Expect(mock.puts.Load()).To(BeZero())
+			Expect(mirror.IsDirty(lom)).To(BeTrue())
+
+			lom = load(lom)
+			Expect(lom.Size()).To(BeEquivalentTo(len(data)))
+			Expect(lom.CustomMD()).NotTo(HaveKey(cluster.SourceObjMD))
+			Expect(lom.CustomMD()).NotTo(HaveKey(cluster.VersionObjMD))
+			Expect(lom.UserMD()).To(HaveKeyWithValue("color", "blue"))
+		})
+
+		It("should not queue the migrated object that has been written to the cloud", func() {
+			lom := newLOM(testWNBucket, "wb/migrated-clean")
+			lom.SetCustomMD(cmn.SimpleKVs{cluster.SourceObjMD: cmn.ProviderAmazon})
+			poi := newPoi(lom)
+			poi.migrated = true
+			put(poi)
+			Expect(mock.puts.Load()).To(BeZero())
+			Expect(mirror.IsDirty(lom)).To(BeFalse())
+			Expect(load(lom).CustomMD()).To(HaveKeyWithValue(cluster.SourceObjMD, cmn.ProviderAmazon))
+		})
+
+		It("should queue the migrated object that is yet to be written to the cloud", func() {
+			lom := newLOM(testWNBucket, "wb/migrated-dirty")
+			lom.SetUserMD(cmn.SimpleKVs{"color": "blue"})
+			poi := newPoi(lom)
+			poi.migrated = true
+			put(poi)
+			Expect(mock.puts.Load()).To(BeZero())
+			Expect(mirror.IsDirty(lom)).To(BeTrue())
+			Expect(load(lom).UserMD()).To(HaveKeyWithValue("color", "blue"))
+		})
+
+		It("should neither queue nor write back the migrated object with write-through policy", func() {
+			lom := newLOM(testCloudBucket, "wb/migrated-through")
+			poi := newPoi(lom)
+			poi.migrated = true
+			put(poi)
+			Expect(mock.puts.Load()).To(BeZero())
+			Expect(mirror.IsDirty(lom)).To(BeFalse())
+		})
+	})
+
+	Describe("DELETE", func() {
+		It("should not evict the dirty object", func() {
+			lom := newLOM(testWNBucket, "wb/evict-dirty")
+			put(newPoi(lom))
+
+			err, errCode := t.objDelete(context.Background(), lom, true /*evict*/)
+			Expect(err).To(HaveOccurred())
+			Expect(errCode).To(Equal(http.StatusConflict))
+			Expect(mirror.IsDirty(lom)).To(BeTrue())
+			load(lom)
+		})
+
+		It("should evict the object once it is written to the cloud", func() {
+			lom := newLOM(testWNBucket, "wb/evict-clean")
+			put(newPoi(lom))
+			Expect(mirror.DirtyQ.Remove(lom.Bck().Bck, lom.ObjName)).NotTo(HaveOccurred())
+
+			err, _ := t.objDelete(context.Background(), lom, true /*evict*/)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mock.dels.Load()).To(BeZero())
+			Expect(lom.Load(false)).To(HaveOccurred())
+		})
+
+		It("should delete the dirty object that does not exist in the cloud", func() {
+			lom := newLOM(testWNBucket, "wb/delete-dirty")
+			put(newPoi(lom))
+			mock.delErrCode = http.StatusNotFound
+
+			err, _ := t.objDelete(context.Background(), lom, false /*evict*/)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mock.dels.Load()).To(BeEquivalentTo(1))
+			Expect(mirror.IsDirty(lom)).To(BeFalse())
+			Expect(lom.Load(false)).To(HaveOccurred())
+		})
+
+		It("should fail to delete the clean object that does not exist in the cloud", func() {
+			lom := newLOM(testWNBucket, "wb/delete-clean")
+			put(newPoi(lom))
+			Expect(mirror.DirtyQ.Remove(lom.Bck().Bck, lom.ObjName)).NotTo(HaveOccurred())
+			mock.delErrCode = http.StatusNotFound
+
+			err, errCode := t.objDelete(context.Background(), lom, false /*evict*/)
+			Expect(err).To(HaveOccurred())
+			Expect(errCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -158,6 +158,48 @@ func (t *targetrunner) cmdXactStart(xactMsg cmn.XactReqMsg, bck *cluster.Bck) er
 			return err
 		}
 
+		xact.AddNotif(&cmn.NotifXact{
+			NotifBase: cmn.NotifBase{
+				When: cmn.UponTerm,
+				Ty:   notifXact,
+				Dsts: []string{equalIC},
+				F:    t.xactCallerNotify,
+			},
+		})
+		go xact.Run()
+	case cmn.ActReplicSync:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		if !bck.Props.Replication.Enabled {
+			return fmt.Errorf("cannot start xaction %q - replication of bucket %s is disabled", xactMsg.Kind, bck)
+		}
+		xact, err := xaction.Registry.RenewReplicSync(t, t.statsT, t.aisCloud(), bck, xactMsg.ID)
+		if err != nil {
+			return err
+		}
+
+		xact.AddNotif(&cmn.NotifXact{
+			NotifBase: cmn.NotifBase{
+				When: cmn.UponTerm,
+				Ty:   notifXact,
+				Dsts: []string{equalIC},
+				F:    t.xactCallerNotify,
+			},
+		})
+		go xact.Run()
+	case cmn.ActFlush:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		if !bck.IsCloud() {
+			return fmt.Errorf("cannot start xaction %q - bucket %s is not a cloud bucket", xactMsg.Kind, bck)
+		}
+		xact, err := xaction.Registry.RenewFlush(t, t.statsT, bck, xactMsg.ID)
+		if err != nil {
+			return err
+		}
+
 		xact.AddNotif(&cmn.NotifXact{
 			NotifBase: cmn.NotifBase{
 				When: cmn.UponTerm,
@@ -170,6 +212,10 @@ func (t *targetrunner) cmdXactStart(xactMsg cmn.XactReqMsg, bck *cluster.Bck) er
 	// 3. cannot start
 	case cmn.ActPutCopies:
 		return fmt.Errorf("cannot start xaction %q - it is invoked automatically by PUTs into mirrored bucket", xactMsg.Kind)
+	case cmn.ActReplicate:
+		return fmt.Errorf("cannot start xaction %q - it is invoked automatically by PUTs and DELETEs in replicated bucket", xactMsg.Kind)
+	case cmn.ActWriteBack:
+		return fmt.Errorf("cannot start xaction %q - it is invoked automatically by PUTs into write-back bucket", xactMsg.Kind)
 	case cmn.ActDownload, cmn.ActEvictObjects, cmn.ActDelete, cmn.ActMakeNCopies, cmn.ActECEncode:
 		return fmt.Errorf("initiating xaction %q must be done via a separate documented API", xactMsg.Kind)
 	// 4. unknown
@@ -32,6 +32,19 @@ func AbortDSort(baseParams BaseParams, managerUUID string) error {
 	})
 }
 
+// ResumeDSort resumes the dSort job which was interrupted (eg. by a target
+// failure) and returns the ID of the new job which continues it.
+func ResumeDSort(baseParams BaseParams, managerUUID string) (string, error) {
+	baseParams.Method = http.MethodPost
+	var id string
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Sort, cmn.Resume),
+		Query:      url.Values{cmn.URLParamUUID: []string{managerUUID}},
+	}, &id)
+	return id, err
+}
+
 func MetricsDSort(baseParams BaseParams, managerUUID string) (metrics map[string]*dsort.Metrics, err error) {
 	baseParams.Method = http.MethodGet
 	err = DoHTTPRequest(ReqParams{
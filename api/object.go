@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/etl"
@@ -52,7 +53,8 @@ type PutObjectArgs struct {
 	Object     string
 	Cksum      *cmn.Cksum
 	Reader     cmn.ReadOpenCloser
-	Size       uint64 // optional
+	Size       uint64        // optional
+	UserMD     cmn.SimpleKVs // optional: user-defined metadata
 }
 
 type PromoteArgs struct {
@@ -75,6 +77,16 @@ type AppendArgs struct {
 	Size       int64
 }
 
+// AppendToArchArgs is used to append files to an existing (or new) .tar object
+type AppendToArchArgs struct {
+	BaseParams BaseParams
+	Bck        cmn.Bck
+	Object     string
+	Reader     cmn.ReadOpenCloser // tar stream of the file(s) to append
+	Size       int64
+	Create     bool // create the object if it doesn't exist
+}
+
 type FlushArgs struct {
 	BaseParams BaseParams
 	Bck        cmn.Bck
@@ -124,6 +136,9 @@ func HeadObject(baseParams BaseParams, bck cmn.Bck, object string, checkExists .
 	if err != nil {
 		return nil, err
 	}
+	if objProps.UserMD, err = cmn.UserMDFromHdr(resp.Header); err != nil {
+		return nil, err
+	}
 	return objProps, nil
 }
 
@@ -254,6 +269,49 @@ func GetObjectWithResp(baseParams BaseParams, bck cmn.Bck, object string, option
 	return resp.Response, resp.n, nil
 }
 
+// GetBatch API
+//
+// Returns multiple objects, selected by `msg` (list of names, template, or prefix),
+// as a single archive (tar by default) written to `w`. Returns the number of bytes
+// written to `w`.
+func GetBatch(baseParams BaseParams, bck cmn.Bck, msg *cmn.GetBatchMsg, w io.Writer) (n int64, err error) {
+	baseParams.Method = http.MethodPost
+	resp, err := doHTTPRequestGetResp(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActGetBatch, Value: msg}),
+		Query:      cmn.AddBckToQuery(nil, bck),
+	}, w)
+	if err != nil {
+		return 0, err
+	}
+	return resp.n, nil
+}
+
+// GetArchivedFile API
+//
+// Reads a single file `archpath` from within the archived object (.tar, .tgz,
+// .tar.gz, or .zip) and writes it to `w`. Returns the number of bytes written.
+func GetArchivedFile(baseParams BaseParams, bck cmn.Bck, objName, archpath string, w io.Writer) (n int64, err error) {
+	return GetObject(baseParams, bck, objName, GetObjectInput{
+		Writer: w,
+		Query:  url.Values{cmn.URLParamArchpath: []string{archpath}},
+	})
+}
+
+// ListArchive API
+//
+// Returns the list of files (names and sizes) contained in the archived object.
+func ListArchive(baseParams BaseParams, bck cmn.Bck, objName string) (entries []*archive.Entry, err error) {
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objName),
+		Query:      cmn.AddBckToQuery(url.Values{cmn.URLParamWhat: []string{cmn.GetWhatArchIndex}}, bck),
+	}, &entries)
+	return
+}
+
 // PutObject API
 //
 // Creates an object from the body of the io.Reader parameter and puts it in the 'bucket' bucket
@@ -296,6 +354,7 @@ func PutObject(args PutObjectArgs) (err error) {
 		if args.Size != 0 {
 			req.ContentLength = int64(args.Size) // as per https://tools.ietf.org/html/rfc7230#section-3.3.2
 		}
+		cmn.UserMDToHdr(req.Header, args.UserMD)
 
 		setAuthToken(req, args.BaseParams)
 		return req, nil
@@ -350,6 +409,43 @@ func AppendObject(args AppendArgs) (handle string, err error) {
 	return resp.Header.Get(cmn.HeaderAppendHandle), err
 }
 
+// AppendToArch API
+//
+// Appends file(s) - packed by the caller as a tar stream - to an existing .tar
+// object. Unlike `AppendObject`, the resulting object remains a valid tar archive:
+// the end-of-archive marker is rewritten at the new end. If `args.Create` is set,
+// a missing object is created.
+func AppendToArch(args AppendToArchArgs) (err error) {
+	query := make(url.Values)
+	query.Add(cmn.URLParamAppendType, cmn.AppendArchOp)
+	if args.Create {
+		query.Add(cmn.URLParamCreate, "true")
+	}
+	query = cmn.AddBckToQuery(query, args.Bck)
+
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodPut,
+		Base:   args.BaseParams.URL,
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, args.Bck.Name, args.Object),
+		Query:  query,
+		BodyR:  args.Reader,
+	}
+	newRequest := func(reqArgs cmn.ReqArgs) (*http.Request, error) {
+		req, err := reqArgs.Req()
+		if err != nil {
+			return nil, cmn.NewFailedToCreateHTTPRequest(err)
+		}
+		req.GetBody = args.Reader.Open
+		if args.Size != 0 {
+			req.ContentLength = args.Size
+		}
+		setAuthToken(req, args.BaseParams)
+		return req, nil
+	}
+	_, err = DoReqWithRetry(args.BaseParams.Client, newRequest, reqArgs) // nolint:bodyclose // it's closed inside
+	return
+}
+
 // Makes Client.Do request and retries it when got Broken Pipe or Connection Refused error
 // Should be used for PUT requests as it puts reader into a request
 func DoReqWithRetry(client *http.Client, newRequest func(_ cmn.ReqArgs) (*http.Request, error), reqArgs cmn.ReqArgs) (resp *http.Response, err error) {
@@ -444,6 +540,38 @@ func RenameObject(baseParams BaseParams, bck cmn.Bck, oldName, newName string) e
 	})
 }
 
+// ComposeObject API
+//
+// Creates (or overwrites) object `objName` in bucket `bck` by concatenating
+// source objects in the specified order, server-side. Sources may reside in
+// different buckets. With `msg.WrapTar` the result is a tar archive containing
+// one file per source.
+func ComposeObject(baseParams BaseParams, bck cmn.Bck, objName string, msg *cmn.ComposeMsg) error {
+	baseParams.Method = http.MethodPost
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objName),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActCompose, Value: msg}),
+		Query:      cmn.AddBckToQuery(nil, bck),
+	})
+}
+
+// UpdateObjectMD API
+//
+// Updates user-defined metadata of an existing object in place (the object's
+// data is not rewritten). The entries are merged into the existing ones - an
+// empty value removes the key - or, if `replace` is true, replace them altogether.
+func UpdateObjectMD(baseParams BaseParams, bck cmn.Bck, objName string, md cmn.SimpleKVs, replace bool) error {
+	baseParams.Method = http.MethodPost
+	msg := &cmn.ObjUserMDMsg{MD: md, Replace: replace}
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objName),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActUpdateObjMD, Value: msg}),
+		Query:      cmn.AddBckToQuery(nil, bck),
+	})
+}
+
 // PromoteFileOrDir API
 //
 // promote AIS-colocated files and directories to objects (NOTE: advanced usage only)
@@ -489,6 +617,30 @@ func DownloadRange(baseParams BaseParams, description string, bck cmn.Bck, templ
 	return DownloadWithParam(baseParams, downloader.DlTypeRange, dlBody)
 }
 
+// DownloadManifest downloads the objects listed in the manifest - the object
+// `manifest` in `manifestBck` or, if `manifestBck` is empty, the link.
+func DownloadManifest(baseParams BaseParams, description string, bck, manifestBck cmn.Bck, manifest string) (string, error) {
+	dlBody := downloader.DlManifestBody{
+		ManifestBck: manifestBck,
+		Manifest:    manifest,
+	}
+	dlBody.Bck = bck
+	dlBody.Description = description
+	return DownloadWithParam(baseParams, downloader.DlTypeManifest, dlBody)
+}
+
+// DownloadCrawl downloads the files found by crawling the HTTP directory
+// index (or the sitemap) at `link`, see downloader.DlCrawlBody.
+func DownloadCrawl(baseParams BaseParams, description string, bck cmn.Bck, link string, depth int) (string, error) {
+	dlBody := downloader.DlCrawlBody{
+		Link:  link,
+		Depth: depth,
+	}
+	dlBody.Bck = bck
+	dlBody.Description = description
+	return DownloadWithParam(baseParams, downloader.DlTypeCrawl, dlBody)
+}
+
 func DownloadWithParam(baseParams BaseParams, dlt downloader.DlType, body interface{}) (string, error) {
 	baseParams.Method = http.MethodPost
 	return doDlDownloadRequest(ReqParams{
@@ -566,6 +718,99 @@ func DownloadRemove(baseParams BaseParams, id string) error {
 	})
 }
 
+// DownloadPause pauses the download job - the objects which are being
+// downloaded are finished, the remaining ones wait until DownloadResume.
+func DownloadPause(baseParams BaseParams, id string) error {
+	dlBody := downloader.DlAdminBody{
+		ID: id,
+	}
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Download, cmn.Pause),
+		Body:       cmn.MustMarshal(dlBody),
+	})
+}
+
+func DownloadResume(baseParams BaseParams, id string) error {
+	dlBody := downloader.DlAdminBody{
+		ID: id,
+	}
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Download, cmn.Resume),
+		Body:       cmn.MustMarshal(dlBody),
+	})
+}
+
+// DownloadSetPriority changes the priority of the running download job
+// (see downloader.DlPriorityMin and downloader.DlPriorityMax).
+func DownloadSetPriority(baseParams BaseParams, id string, priority int) error {
+	dlBody := downloader.DlAdminBody{
+		ID:       id,
+		Priority: priority,
+	}
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Download, cmn.Priority),
+		Body:       cmn.MustMarshal(dlBody),
+	})
+}
+
+// DownloadGetSchedules returns the recurring downloads (see downloader.DlSchedule)
+// with the description matching `regex`.
+func DownloadGetSchedules(baseParams BaseParams, regex string) (schedules downloader.DlScheduleInfos, err error) {
+	dlBody := downloader.DlAdminBody{
+		Regex: regex,
+	}
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Download, cmn.Schedule),
+		Body:       cmn.MustMarshal(dlBody),
+	}, &schedules)
+	return schedules, err
+}
+
+// DownloadScheduleStatus returns the recurring download with the history of its runs.
+func DownloadScheduleStatus(baseParams BaseParams, id string) (*downloader.DlScheduleInfo, error) {
+	var (
+		schedules downloader.DlScheduleInfos
+		dlBody    = downloader.DlAdminBody{
+			ID: id,
+		}
+	)
+	baseParams.Method = http.MethodGet
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Download, cmn.Schedule),
+		Body:       cmn.MustMarshal(dlBody),
+	}, &schedules)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, fmt.Errorf("download schedule %q not found", id)
+	}
+	return schedules[0], nil
+}
+
+// DownloadRemoveSchedule stops the recurring download - the run in progress,
+// if any, is not aborted.
+func DownloadRemoveSchedule(baseParams BaseParams, id string) error {
+	dlBody := downloader.DlAdminBody{
+		ID: id,
+	}
+	baseParams.Method = http.MethodDelete
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Download, cmn.Schedule),
+		Body:       cmn.MustMarshal(dlBody),
+	})
+}
+
 func doDlDownloadRequest(reqParams ReqParams) (string, error) {
 	var resp downloader.DlPostResp
 	err := DoHTTPRequest(reqParams, &resp)
@@ -5,10 +5,14 @@
 package api
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/query"
+	jsoniter "github.com/json-iterator/go"
 )
 
 func InitQuery(baseParams BaseParams, objectsTemplate string, bck cmn.Bck, filter *query.FilterMsg, workersCnts ...uint) (string, error) {
@@ -40,6 +44,123 @@ func InitQuery(baseParams BaseParams, objectsTemplate string, bck cmn.Bck, filte
 	return handle, err
 }
 
+// InitQueryExpr initializes the query given in the SQL-like form, e.g.:
+// "SELECT name,size FROM ais://imgs WHERE size > 1MiB AND name LIKE 'train/%'"
+func InitQueryExpr(baseParams BaseParams, expr string, workersCnts ...uint) (string, error) {
+	var (
+		workersCnt uint
+		handle     string
+	)
+	baseParams.Method = http.MethodPost
+	if len(workersCnts) > 0 {
+		workersCnt = workersCnts[0]
+	}
+	initMsg := query.InitMsg{Expr: expr, WorkersCnt: workersCnt}
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Init),
+		Body:       cmn.MustMarshal(initMsg),
+	}, &handle)
+	return handle, err
+}
+
+// InitQueryMsg initializes the query given its full definition (e.g., aggregation query).
+func InitQueryMsg(baseParams BaseParams, msg *query.InitMsg) (handle string, err error) {
+	baseParams.Method = http.MethodPost
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Init),
+		Body:       cmn.MustMarshal(msg),
+	}, &handle)
+	return
+}
+
+// GetQueryResultsManifest returns the manifest of the persisted query results
+// (see query.PersistMsg); only the bucket and prefix of `persist` are used.
+func GetQueryResultsManifest(baseParams BaseParams, persist *query.PersistMsg, handle string) (*query.ResultsManifest, error) {
+	var (
+		buf      bytes.Buffer
+		manifest = &query.ResultsManifest{}
+	)
+	if _, err := GetObject(baseParams, persist.Bck, persist.ManifestName(handle), GetObjectInput{Writer: &buf}); err != nil {
+		return nil, err
+	}
+	if err := jsoniter.Unmarshal(buf.Bytes(), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// NextPersistedQueryResults returns the page of persisted query results that
+// corresponds to `token` ("" - the first page), and the token of the next page.
+// The tokens are stable: reading can be resumed from any previously returned token.
+// Returns no entries (and the same token) if the page has not been persisted yet,
+// and query.ErrNoMoreResults once all the results have been read.
+func NextPersistedQueryResults(baseParams BaseParams, persist *query.PersistMsg, handle, token string) ([]*cmn.BucketEntry, string, error) {
+	page, err := query.ParsePageToken(token)
+	if err != nil {
+		return nil, token, err
+	}
+	manifest, err := GetQueryResultsManifest(baseParams, persist, handle)
+	if err != nil {
+		return nil, token, err
+	}
+	if page >= manifest.Pages {
+		switch {
+		case manifest.Done:
+			return nil, token, query.ErrNoMoreResults
+		case manifest.Err != "":
+			return nil, token, errors.New(manifest.Err)
+		default:
+			return nil, token, nil
+		}
+	}
+	var (
+		buf     bytes.Buffer
+		entries []*cmn.BucketEntry
+	)
+	if _, err := GetObject(baseParams, persist.Bck, persist.PageName(handle, page), GetObjectInput{Writer: &buf}); err != nil {
+		return nil, token, err
+	}
+	if err := jsoniter.Unmarshal(buf.Bytes(), &entries); err != nil {
+		return nil, token, err
+	}
+	return entries, query.PageToken(page + 1), nil
+}
+
+// SelectContent scans the contents of the (CSV or JSON-lines) objects that match
+// the query and writes the matching rows to `w` (see query.ContentMsg); the query
+// is given either by `msg.QueryMsg` or by `msg.Expr`, with the content select
+// in `msg.QueryMsg.InnerSelect.Content`. Returns the number of bytes written.
+func SelectContent(baseParams BaseParams, msg *query.InitMsg, w io.Writer) (int64, error) {
+	baseParams.Method = http.MethodPost
+	resp, err := doHTTPRequestGetResp(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Select),
+		Body:       cmn.MustMarshal(msg),
+	}, w)
+	if err != nil {
+		return 0, err
+	}
+	if errMsg := resp.Trailer.Get(cmn.HeaderError); errMsg != "" {
+		return resp.n, errors.New(errMsg)
+	}
+	return resp.n, nil
+}
+
+// QueryAggregate returns the result of the aggregation query; the result is
+// empty and not `Done` while the query is still running.
+func QueryAggregate(baseParams BaseParams, handle string) (*query.AggregateResult, error) {
+	result := &query.AggregateResult{}
+	baseParams.Method = http.MethodGet
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Query, cmn.Aggregate),
+		Body:       cmn.MustMarshal(query.NextMsg{Handle: handle}),
+	}, result)
+	return result, err
+}
+
 func NextQueryResults(baseParams BaseParams, handle string, size uint) ([]*cmn.BucketEntry, error) {
 	var (
 		objectsNames []*cmn.BucketEntry
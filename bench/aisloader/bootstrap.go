@@ -84,7 +84,8 @@ type (
 		op        int
 		proxyURL  string
 		bck       cmn.Bck
-		objName   string // In the format of 'virtual dir' + "/" + objName
+		objName   string   // In the format of 'virtual dir' + "/" + objName
+		objNames  []string // multi-object GET (see `getBatchSize`)
 		size      int64
 		err       error
 		start     time.Time
@@ -131,6 +132,7 @@ type (
 		putPct            int // % of puts, rest are gets
 		numWorkers        int
 		batchSize         int // batch is used for bootstraping(list) and delete
+		getBatchSize      int // >0: GET that many objects at a time via multi-object GET
 		loaderIDHashLen   uint
 		numEpochs         uint
 
@@ -268,6 +270,7 @@ func parseCmdLine() (params, error) {
 	f.IntVar(&p.statsdPort, "statsdport", 8125, "StatsD UDP port")
 	f.BoolVar(&p.statsdRequired, "check-statsd", false, "true: prior to benchmark make sure that StatsD is reachable")
 	f.IntVar(&p.batchSize, "batchsize", 100, "Batch size to list and delete")
+	f.IntVar(&p.getBatchSize, "getbatchsize", 0, "Number of objects to GET at a time via multi-object GET (as a single tar); 0 - GET objects one by one")
 	f.StringVar(&p.bPropsStr, "bprops", "", "JSON string formatted as per the SetBucketProps API and containing bucket properties to apply")
 	f.Int64Var(&p.seed, "seed", 0, "Random seed to achieve deterministic reproducible results (0 - use current time in nanoseconds)")
 	f.BoolVar(&p.jsonFormat, "json", false, "true: print the output in JSON")
@@ -955,12 +958,20 @@ func newGetWorkOrder() (*workOrder, error) {
 	}
 
 	getPending++
-	return &workOrder{
+	wo := &workOrder{
 		proxyURL: runParams.proxyURL,
 		bck:      runParams.bck,
 		op:       opGet,
-		objName:  bucketObjsNames.ObjName(),
-	}, nil
+	}
+	if runParams.getBatchSize == 0 {
+		wo.objName = bucketObjsNames.ObjName()
+		return wo, nil
+	}
+	wo.objNames = make([]string, runParams.getBatchSize)
+	for i := range wo.objNames {
+		wo.objNames[i] = bucketObjsNames.ObjName()
+	}
+	return wo, nil
 }
 
 func newGetConfigWorkOrder() *workOrder {
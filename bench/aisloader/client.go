@@ -335,6 +335,16 @@ func getDiscard(proxyURL string, bck cmn.Bck, objName string, validate bool, off
 	return n, err
 }
 
+// getBatchDiscard sends a multi-object GET request and discards returned archive
+func getBatchDiscard(proxyURL string, bck cmn.Bck, objNames []string) (int64, error) {
+	baseParams := api.BaseParams{
+		Client: httpClient,
+		URL:    proxyURL,
+	}
+	msg := &cmn.GetBatchMsg{ObjNames: objNames, SkipMissing: true}
+	return api.GetBatch(baseParams, bck, msg, ioutil.Discard)
+}
+
 // getConfig sends a {what:config} request to the url and discard the message
 // For testing purpose only
 func getConfig(server string) (httpLatencies, error) {
@@ -66,6 +66,10 @@ func doPut(wo *workOrder) {
 }
 
 func doGet(wo *workOrder) {
+	if len(wo.objNames) > 0 {
+		wo.size, wo.err = getBatchDiscard(wo.proxyURL, wo.bck, wo.objNames)
+		return
+	}
 	if !traceHTTPSig.Load() {
 		wo.size, wo.err = getDiscard(wo.proxyURL, wo.bck,
 			wo.objName, runParams.verifyHash, runParams.readOff, runParams.readLen)
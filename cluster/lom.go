@@ -106,6 +106,51 @@ func (lom *LOM) Bprops() *cmn.BucketProps   { return lom.bck.Props }
 func (lom *LOM) GetFQN() string             { return lom.FQN }
 func (lom *LOM) GetParsedFQN() fs.ParsedFQN { return lom.ParsedFQN }
 
+// UserMD returns user-defined metadata (custom metadata entries with
+// UserObjMDPrefix-prefixed keys - prefix stripped).
+func (lom *LOM) UserMD() (md cmn.SimpleKVs) {
+	for k, v := range lom.md.customMD {
+		if !strings.HasPrefix(k, UserObjMDPrefix) {
+			continue
+		}
+		if md == nil {
+			md = make(cmn.SimpleKVs, 4)
+		}
+		md[k[len(UserObjMDPrefix):]] = v
+	}
+	return
+}
+
+// SetUserMD replaces user-defined metadata, keeping system custom metadata intact.
+func (lom *LOM) SetUserMD(md cmn.SimpleKVs) {
+	customMD := make(cmn.SimpleKVs, len(lom.md.customMD)+len(md))
+	for k, v := range lom.md.customMD {
+		if !strings.HasPrefix(k, UserObjMDPrefix) {
+			customMD[k] = v
+		}
+	}
+	for k, v := range md {
+		customMD[UserObjMDPrefix+k] = v
+	}
+	lom.md.customMD = customMD
+}
+
+// UpdateUserMD merges `md` into user-defined metadata; empty value removes the key.
+func (lom *LOM) UpdateUserMD(md cmn.SimpleKVs) {
+	userMD := lom.UserMD()
+	if userMD == nil {
+		userMD = make(cmn.SimpleKVs, len(md))
+	}
+	for k, v := range md {
+		if v == "" {
+			delete(userMD, k)
+		} else {
+			userMD[k] = v
+		}
+	}
+	lom.SetUserMD(userMD)
+}
+
 func (lom *LOM) Config() *cmn.Config {
 	if lom.config == nil {
 		lom.config = cmn.GCO.Get()
@@ -320,6 +365,15 @@ func (lom *LOM) DelExtraCopies(fqn ...string) (removed bool, err error) {
 	return
 }
 
+// PersistAll persists metadata of the object and all its copies.
+// NOTE: uname for LOM must be already locked.
+func (lom *LOM) PersistAll() (err error) {
+	if err = lom.Persist(); err != nil {
+		return
+	}
+	return lom.syncMetaWithCopies()
+}
+
 // syncMetaWithCopies tries to make sure that all copies have identical metadata.
 // NOTE: uname for LOM must be already locked.
 func (lom *LOM) syncMetaWithCopies() (err error) {
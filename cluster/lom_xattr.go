@@ -76,6 +76,16 @@ const (
 	MD5ObjMD     = cmn.ChecksumMD5
 
 	OrigURLObjMD = "orig_url"
+
+	// web objects: validators of the source (used to detect changes)
+	ETagObjMD         = "etag"
+	LastModifiedObjMD = "last_modified"
+
+	// ID of the scheduled download which has downloaded the object
+	DlScheduleObjMD = "dl_schedule"
+
+	// user-defined metadata keys are stored with this prefix (see LOM.UserMD)
+	UserObjMDPrefix = "user."
 )
 
 func (lom *LOM) LoadMetaFromFS() error { _, err := lom.lmfs(true); return err }
@@ -155,6 +155,7 @@ func (aisCLI *AISCLI) setupCommands() {
 	app.Commands = append(app.Commands, waitCmds...)
 	app.Commands = append(app.Commands, objectSpecificCmds...)
 	app.Commands = append(app.Commands, etlCmds...)
+	app.Commands = append(app.Commands, queryCmds...)
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	setupCommandHelp(app.Commands)
@@ -289,6 +289,8 @@ func fetchSummaries(query cmn.QueryBcks, fast, cached bool) (summaries cmn.Bucke
 //  * `backend_bck=gcp://bucket_name` with `backend_bck.name=bucket_name` and
 //    `backend_bck.provider=gcp` so they match the expected fields in structs.
 //  * `backend_bck=none` with `backend_bck.name=""` and `backend_bck.provider=""`.
+//  * `replication.bck=ais://@alias/bucket_name` with `replication.bck.name=bucket_name`,
+//    `replication.bck.provider=ais`, and `replication.bck.namespace.uuid=alias`.
 
 // TODO: support `allow` and `deny` verbs/operations on existing access permissions
 
@@ -296,6 +298,9 @@ func reformatBucketProps(nvs cmn.SimpleKVs) (err error) {
 	if err = _reformatBackendProps(nvs); err != nil {
 		return
 	}
+	if err = _reformatReplicProps(nvs); err != nil {
+		return
+	}
 
 	if v, ok := nvs[cmn.HeaderBucketAccessAttrs]; ok {
 		switch v {
@@ -354,6 +359,32 @@ validate:
 	return err
 }
 
+func _reformatReplicProps(nvs cmn.SimpleKVs) (err error) {
+	var (
+		dstBck  cmn.Bck
+		objName string
+	)
+	v, ok := nvs[cmn.HeaderReplicBck]
+	if !ok {
+		return
+	}
+	delete(nvs, cmn.HeaderReplicBck)
+	if v != emptyOrigin {
+		dstBck, objName, err = cmn.ParseBckObjectURI(v)
+		if err != nil {
+			return err
+		}
+		if objName != "" || !dstBck.IsRemoteAIS() {
+			return fmt.Errorf("invalid format of %q: expecting %s://@alias/bucket_name", cmn.HeaderReplicBck, cmn.ProviderAIS)
+		}
+	}
+	nvs[cmn.HeaderReplicBckName] = dstBck.Name
+	nvs[cmn.HeaderReplicBckProvider] = dstBck.Provider
+	nvs[cmn.HeaderReplicBckNsUUID] = dstBck.Ns.UUID
+	nvs[cmn.HeaderReplicBckNsName] = dstBck.Ns.Name
+	return
+}
+
 // Sets bucket properties
 func setBucketProps(c *cli.Context, bck cmn.Bck, props cmn.BucketPropsToUpdate) (err error) {
 	if _, err = api.SetBucketProps(defaultAPIParams, bck, props); err != nil {
@@ -18,6 +18,7 @@ const (
 	commandAttach    = "attach"
 	commandAuth      = "auth"
 	commandCat       = "cat"
+	commandCompose   = "compose"
 	commandConcat    = "concat"
 	commandCopy      = "cp"
 	commandCreate    = "create"
@@ -40,6 +41,7 @@ const (
 	commandStop      = cmn.ActXactStop
 	commandWait      = "wait"
 	commandSearch    = "search"
+	commandQuery     = "query"
 	commandETL       = cmn.ETL
 
 	// Subcommands - preferably nouns
@@ -64,6 +66,7 @@ const (
 	subcmdList      = commandList
 	subcmdStop      = "stop"
 	subcmdLRU       = cmn.ActLRU
+	subcmdReplic    = "replication"
 
 	// Show subcommands
 	subcmdShowBucket    = subcmdBucket
@@ -77,6 +80,7 @@ const (
 	subcmdShowConfig    = subcmdConfig
 	subcmdShowRemoteAIS = subcmdRemoteAIS
 	subcmdShowCluster   = subcmdCluster
+	subcmdShowReplic    = subcmdReplic
 
 	// Create subcommands
 	subcmdCreateBucket = subcmdBucket
@@ -106,9 +110,11 @@ const (
 	subcmdStopDownload = subcmdDownload
 
 	// Set subcommand
-	subcmdSetConfig  = subcmdConfig
-	subcmdSetProps   = subcmdProps
-	subcmdSetPrimary = subcmdPrimary
+	subcmdSetConfig   = subcmdConfig
+	subcmdSetProps    = subcmdProps
+	subcmdSetPrimary  = subcmdPrimary
+	subcmdSetUserMD   = "user-md"
+	subcmdSetDownload = subcmdDownload
 
 	// Attach/Detach subcommand
 	subcmdAttachRemoteAIS = subcmdRemoteAIS
@@ -168,9 +174,11 @@ const (
 	getObjectArgument        = "BUCKET_NAME/OBJECT_NAME OUT_FILE"
 	putPromoteObjectArgument = "FILE|DIRECTORY BUCKET_NAME/[OBJECT_NAME]"
 	concatObjectArgument     = "FILE|DIRECTORY [FILE|DIRECTORY...] BUCKET_NAME/OBJECT_NAME"
+	composeObjectArgument    = "BUCKET_NAME/OBJECT_NAME [BUCKET_NAME/OBJECT_NAME...] DST_BUCKET_NAME/OBJECT_NAME"
 	objectArgument           = "BUCKET_NAME/OBJECT_NAME"
 	optionalObjectsArgument  = "BUCKET_NAME/[OBJECT_NAME]..."
 	objectOldNewArgument     = "BUCKET_NAME/OBJECT_NAME NEW_OBJECT_NAME"
+	objectUserMDArgument     = "BUCKET_NAME/OBJECT_NAME KEY=VALUE [KEY=VALUE...]"
 
 	// Daemons
 	daemonIDArgument         = "DAEMON_ID"
@@ -205,6 +213,7 @@ const (
 
 	// Search
 	searchArgument = "KEYWORD [KEYWORD...]"
+	queryArgument  = "\"SELECT PROPS FROM BUCKET_NAME[/PREFIX] [WHERE CONDITION]\""
 )
 
 // Flags
@@ -255,6 +264,23 @@ var (
 	limitBytesPerHourFlag = cli.StringFlag{Name: "limit-bytes-per-hour,limit-bph,bph", Usage: "number of bytes (can end with suffix (k, MB, GiB, ...)) that all targets can maximally download in hour"}
 	objectsListFlag       = cli.StringFlag{Name: "object-list,from", Usage: "path to file containing JSON array of strings with object names to download"}
 	syncFlag              = cli.BoolFlag{Name: "sync", Usage: "sync bucket with cloud"}
+	dlManifestFlag        = cli.BoolFlag{Name: "manifest", Usage: "source is the manifest (CSV or JSON lines) listing the objects to download"}
+	dlManifestFormatFlag  = cli.StringFlag{Name: "manifest-format", Usage: "format of the manifest: csv or jsonl (determined by the extension if omitted)"}
+	dlCronFlag            = cli.StringFlag{Name: "cron", Usage: "run the download periodically at the times given by cron spec (UTC), e.g. \"0 3 * * *\""}
+	dlIntervalFlag        = cli.StringFlag{Name: "interval", Usage: "run the download periodically with given interval, e.g. 24h"}
+	dlScheduleFlag        = cli.BoolFlag{Name: "schedule", Usage: "apply to recurring downloads (schedules) instead of download jobs"}
+	dlPriorityFlag        = cli.IntFlag{Name: "priority", Usage: "priority of the download job, from 1 (lowest) to 10 (highest), 5 by default"}
+	dlPauseFlag           = cli.BoolFlag{Name: "pause", Usage: "pause the download job instead of aborting it"}
+	dlResumeFlag          = cli.StringFlag{Name: "resume", Usage: "ID of the paused download job to resume (instead of starting a new one)"}
+	dlCredentialsFlag     = cli.StringFlag{Name: "credentials", Usage: "path to JSON file with credentials to access the source (headers, basic auth, bearer token, or S3 keys)"}
+	dlExtractFlag         = cli.BoolFlag{Name: "extract", Usage: "extract the downloaded archives (.tar, .tgz, .tar.gz, .zip) - each file is stored as a separate object"}
+	dlExtractPrefixFlag   = cli.StringFlag{Name: "extract-prefix", Usage: "prefix of the objects extracted from the archive (name of the archive without extension by default)"}
+	dlExtractRegexFlag    = cli.StringFlag{Name: "extract-regex", Usage: "extract only the files with names matching the regex"}
+	dlFlattenFlag         = cli.BoolFlag{Name: "flatten", Usage: "omit the directories of the files extracted from the archive"}
+	dlCrawlFlag           = cli.BoolFlag{Name: "crawl", Usage: "source is HTTP directory index (or sitemap) to crawl - all the files found are downloaded preserving their relative paths"}
+	dlCrawlDepthFlag      = cli.IntFlag{Name: "depth", Usage: "maximal depth of the crawl (1 - only the files listed by the source, 0 - no limit)"}
+	dlIncludeFlag         = cli.StringFlag{Name: "include", Usage: "comma-separated shell patterns of the files to download when crawling, e.g. \"*.tar,*.json\""}
+	dlExcludeFlag         = cli.StringFlag{Name: "exclude", Usage: "comma-separated shell patterns of the files and directories to skip when crawling"}
 
 	// dSort
 	dsortBucketFlag   = cli.StringFlag{Name: "bucket", Value: cmn.DSortNameLowercase + "-testing", Usage: "bucket where shards will be put"}
@@ -266,6 +292,7 @@ var (
 	concurrencyFlag   = cli.IntFlag{Name: "conc", Value: 10, Usage: "limits number of concurrent put requests and number of concurrent shards created"}
 	fileCountFlag     = cli.IntFlag{Name: "fcount", Value: 5, Usage: "number of files inside single shard"}
 	specFileFlag      = cli.StringFlag{Name: "file,f", Value: "", Usage: "path to file with dSort specification"}
+	dsortResumeFlag   = cli.StringFlag{Name: "resume", Value: "", Usage: "ID of the interrupted dSort job to resume (instead of starting a new one)"}
 
 	// Object
 	listFlag         = cli.StringFlag{Name: "list", Usage: "comma separated list of object names, eg. 'o1,o2,o3'"}
@@ -281,6 +308,18 @@ var (
 	chunkSizeFlag    = cli.StringFlag{Name: "chunk-size", Usage: "chunk size used for each request, can contain prefix 'b', 'KiB', 'MB'", Value: "10MB"}
 	computeCksumFlag = cli.BoolFlag{Name: "compute-cksum", Usage: "compute the checksum with the type configured for the bucket"}
 	useCacheFlag     = cli.BoolFlag{Name: "use-cache", Usage: "use proxy cache to speed up list object request"}
+	archFormatFlag   = cli.StringFlag{Name: "archive-format", Usage: "format of the multi-object archive: '.tar', '.zip', or '.msgpack'", Value: cmn.ExtTar}
+	orderedFlag      = cli.BoolFlag{Name: "ordered", Usage: "return objects in the requested order (default: in the order of completion)"}
+	skipMissingFlag  = cli.BoolFlag{Name: "skip-missing", Usage: "skip missing objects instead of failing the entire request"}
+	manifestFlag     = cli.StringFlag{Name: "manifest", Usage: "get the shard assembled as defined by the dSort virtual shuffle manifest (object name)"}
+	epochFlag        = cli.IntFlag{Name: "epoch", Usage: "epoch of the dSort virtual shuffle (used with --manifest)"}
+	shardIdxFlag     = cli.IntFlag{Name: "shard", Usage: "index of the shard of the dSort virtual shuffle (used with --manifest)"}
+	archpathFlag     = cli.StringFlag{Name: "archpath", Usage: "get the file with the given name (path) from within the archived object ('.tar', '.tgz', '.zip')"}
+	listArchFlag     = cli.BoolFlag{Name: "list-archive", Usage: "list files (members) of the archived object ('.tar', '.tgz', '.zip')"}
+	wrapTarFlag      = cli.BoolFlag{Name: "tar", Usage: "wrap each source object as a separate file of the resulting tar archive"}
+	archCreateFlag   = cli.BoolFlag{Name: "create", Usage: "create the archive if it doesn't exist (used with --archpath)"}
+	userMDFlag       = cli.StringFlag{Name: "user-md", Usage: "user-defined metadata: comma-separated KEY=VALUE pairs, e.g. 'owner=bob,dataset=imagenet'"}
+	replaceMDFlag    = cli.BoolFlag{Name: "replace", Usage: "replace all existing user-defined metadata (default: merge)"}
 	checksumFlags    = getCksumFlags()
 	// AuthN
 	tokenFileFlag = cli.StringFlag{Name: "file,f", Value: "", Usage: "save token to file"}
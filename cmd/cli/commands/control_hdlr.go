@@ -30,9 +30,26 @@ var (
 			descriptionFlag,
 			limitConnectionsFlag,
 			objectsListFlag,
+			dlManifestFlag,
+			dlManifestFormatFlag,
+			syncFlag,
+			dlCronFlag,
+			dlIntervalFlag,
+			dlPriorityFlag,
+			dlResumeFlag,
+			dlCredentialsFlag,
+			dlExtractFlag,
+			dlExtractPrefixFlag,
+			dlExtractRegexFlag,
+			dlFlattenFlag,
+			dlCrawlFlag,
+			dlCrawlDepthFlag,
+			dlIncludeFlag,
+			dlExcludeFlag,
 		},
 		subcmdStartDsort: {
 			specFileFlag,
+			dsortResumeFlag,
 		},
 		commandPrefetch: append(
 			baseLstRngFlags,
@@ -45,9 +62,11 @@ var (
 	}
 
 	stopCmdsFlags = map[string][]cli.Flag{
-		subcmdStopXaction:  {},
-		subcmdStopDownload: {},
-		subcmdStopDsort:    {},
+		subcmdStopXaction: {},
+		subcmdStopDownload: {
+			dlPauseFlag,
+		},
+		subcmdStopDsort: {},
 	}
 
 	controlCmds = []cli.Command{
@@ -215,6 +234,23 @@ func startDownloadHandler(c *cli.Context) error {
 		id              string
 	)
 
+	if flagIsSet(c, dlResumeFlag) {
+		if c.NArg() > 0 {
+			return &usageError{
+				context:      c,
+				message:      "source and destination cannot be provided when resuming a job",
+				helpData:     c.Command,
+				helpTemplate: cli.CommandHelpTemplate,
+			}
+		}
+		id = parseStrFlag(c, dlResumeFlag)
+		if err := api.DownloadResume(defaultAPIParams, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.Writer, "download job %q successfully resumed\n", id)
+		return nil
+	}
+
 	if c.NArg() == 0 {
 		return missingArgumentsError(c, "source", "destination")
 	}
@@ -233,10 +269,6 @@ func startDownloadHandler(c *cli.Context) error {
 	}
 
 	src, dst := c.Args().Get(0), c.Args().Get(1)
-	source, err := parseSource(src)
-	if err != nil {
-		return err
-	}
 	bucket, pathSuffix, err := parseDest(dst)
 	if err != nil {
 		return err
@@ -254,11 +286,79 @@ func startDownloadHandler(c *cli.Context) error {
 		},
 		Timeout:     timeout,
 		Description: description,
+		Priority:    parseIntFlag(c, dlPriorityFlag),
 		Limits: downloader.DlLimits{
 			Connections:  parseIntFlag(c, limitConnectionsFlag),
 			BytesPerHour: int(limitBPH),
 		},
 	}
+	if flagIsSet(c, dlCredentialsFlag) {
+		if basePayload.Credentials, err = loadDlCredentials(parseStrFlag(c, dlCredentialsFlag)); err != nil {
+			return err
+		}
+	}
+	if flagIsSet(c, dlExtractFlag) || flagIsSet(c, dlExtractPrefixFlag) || flagIsSet(c, dlExtractRegexFlag) ||
+		flagIsSet(c, dlFlattenFlag) {
+		basePayload.Extract = &downloader.DlExtract{
+			Prefix:  parseStrFlag(c, dlExtractPrefixFlag),
+			Regex:   parseStrFlag(c, dlExtractRegexFlag),
+			Flatten: flagIsSet(c, dlFlattenFlag),
+		}
+	}
+	if flagIsSet(c, dlCronFlag) || flagIsSet(c, dlIntervalFlag) {
+		basePayload.Schedule = &downloader.DlSchedule{
+			Cron:     parseStrFlag(c, dlCronFlag),
+			Interval: parseStrFlag(c, dlIntervalFlag),
+			Sync:     flagIsSet(c, syncFlag),
+		}
+	}
+
+	if flagIsSet(c, dlManifestFlag) {
+		if pathSuffix != "" {
+			return fmt.Errorf("destination of the manifest download must be a bucket (got %q)", dst)
+		}
+		payload := downloader.DlManifestBody{
+			DlBase: basePayload,
+			Format: parseStrFlag(c, dlManifestFormatFlag),
+		}
+		// The manifest is either the object in the cluster (ais://bucket/object) or the link.
+		if scheme, bck, objName, err := parseURI(src); err == nil && scheme == cmn.AISScheme {
+			payload.ManifestBck = cmn.Bck{Name: bck, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+			payload.Manifest = objName
+		} else {
+			source, err := parseDlSource(src, basePayload.Credentials)
+			if err != nil {
+				return err
+			}
+			payload.Manifest = source.link
+		}
+		if id, err = api.DownloadWithParam(defaultAPIParams, downloader.DlTypeManifest, payload); err != nil {
+			return err
+		}
+		printDownloadStarted(c, id, basePayload.Schedule != nil)
+		return nil
+	}
+
+	source, err := parseDlSource(src, basePayload.Credentials)
+	if err != nil {
+		return err
+	}
+
+	if flagIsSet(c, dlCrawlFlag) {
+		payload := downloader.DlCrawlBody{
+			DlBase:  basePayload,
+			Link:    source.link,
+			Subdir:  pathSuffix, // in this case pathSuffix is a subdirectory in which the objects are to be saved
+			Depth:   parseIntFlag(c, dlCrawlDepthFlag),
+			Include: splitPatterns(parseStrFlag(c, dlIncludeFlag)),
+			Exclude: splitPatterns(parseStrFlag(c, dlExcludeFlag)),
+		}
+		if id, err = api.DownloadWithParam(defaultAPIParams, downloader.DlTypeCrawl, payload); err != nil {
+			return err
+		}
+		printDownloadStarted(c, id, basePayload.Schedule != nil)
+		return nil
+	}
 
 	// Heuristics to determine the download type.
 	var dlType downloader.DlType
@@ -357,9 +457,17 @@ func startDownloadHandler(c *cli.Context) error {
 		return err
 	}
 
+	printDownloadStarted(c, id, basePayload.Schedule != nil)
+	return nil
+}
+
+func printDownloadStarted(c *cli.Context, id string, scheduled bool) {
 	fmt.Fprintln(c.App.Writer, id)
+	if scheduled {
+		fmt.Fprintf(c.App.Writer, "Run `ais show download %s --schedule` to see the runs of the scheduled download.\n", id)
+		return
+	}
 	fmt.Fprintf(c.App.Writer, "Run `ais show download %s --progress` to monitor the progress of downloading.\n", id)
-	return nil
 }
 
 func stopDownloadHandler(c *cli.Context) (err error) {
@@ -369,6 +477,14 @@ func stopDownloadHandler(c *cli.Context) (err error) {
 		return missingArgumentsError(c, "download job ID")
 	}
 
+	if flagIsSet(c, dlPauseFlag) {
+		if err = api.DownloadPause(defaultAPIParams, id); err != nil {
+			return
+		}
+		fmt.Fprintf(c.App.Writer, "download job %q successfully paused\n", id)
+		return
+	}
+
 	if err = api.DownloadAbort(defaultAPIParams, id); err != nil {
 		return
 	}
@@ -382,6 +498,21 @@ func startDsortHandler(c *cli.Context) (err error) {
 		id       string
 		specPath = parseStrFlag(c, specFileFlag)
 	)
+	if flagIsSet(c, dsortResumeFlag) {
+		if c.NArg() > 0 || specPath != "" {
+			return &usageError{
+				context:      c,
+				message:      "job specification cannot be provided when resuming a job",
+				helpData:     c.Command,
+				helpTemplate: cli.CommandHelpTemplate,
+			}
+		}
+		if id, err = api.ResumeDSort(defaultAPIParams, parseStrFlag(c, dsortResumeFlag)); err != nil {
+			return
+		}
+		fmt.Fprintln(c.App.Writer, id)
+		return
+	}
 	if c.NArg() == 0 && specPath == "" {
 		return missingArgumentsError(c, "job specification")
 	} else if c.NArg() > 0 && specPath != "" {
@@ -345,3 +345,61 @@ func showRebalance(c *cli.Context, keepMonitoring bool, refreshRate time.Duratio
 
 	return nil
 }
+
+func showReplication(c *cli.Context, bck cmn.Bck) error {
+	xactArgs := api.XactReqArgs{Kind: cmn.ActReplicate, Bck: bck}
+	replStats, err := api.QueryXactionStats(defaultAPIParams, xactArgs)
+	if err != nil {
+		if httpErr, ok := err.(*cmn.HTTPError); ok && httpErr.Status == http.StatusNotFound {
+			fmt.Fprintln(c.App.Writer, "Replication has not started yet.")
+			return nil
+		}
+		return err
+	}
+
+	// the latest xaction of each bucket on each target
+	type replRow struct {
+		daemonID string
+		st       *cmn.BaseXactStatsExt
+	}
+	latest := make(map[string]*replRow)
+	for daemonID, daemonStats := range replStats {
+		for _, st := range daemonStats {
+			key := st.BckX.String() + "/" + daemonID
+			if row, ok := latest[key]; !ok || row.st.StartTimeX.Before(st.StartTimeX) {
+				latest[key] = &replRow{daemonID: daemonID, st: st}
+			}
+		}
+	}
+	if len(latest) == 0 {
+		fmt.Fprintln(c.App.Writer, "Replication has not started yet.")
+		return nil
+	}
+	keys := make([]string, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tw := &tabwriter.Writer{}
+	tw.Init(c.App.Writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "Bucket\tDestination\tDaemonID\tBacklog\tLag\tObjSent\tSizeSent\tDeleted\tErrors\tRunning")
+	fmt.Fprintln(tw, strings.Repeat("======\t", 10 /* num of columns */))
+	for _, key := range keys {
+		var (
+			row          = latest[key]
+			extReplStats = &stats.ExtReplicStats{}
+		)
+		if err := cmn.MorphMarshal(row.st.Ext, extReplStats); err != nil {
+			continue
+		}
+		fmt.Fprintf(tw,
+			"%s\t%s\t%s\t%d\t%s\t%d\t%s\t%d\t%d\t%t\n",
+			row.st.BckX, extReplStats.Dest, row.daemonID,
+			extReplStats.Backlog, time.Duration(extReplStats.Lag).Round(time.Second),
+			row.st.ObjCountX, cmn.B2S(row.st.BytesCountX, 2),
+			extReplStats.Deleted, extReplStats.Errors, row.st.EndTimeX.IsZero(),
+		)
+	}
+	return tw.Flush()
+}
@@ -324,6 +324,22 @@ func downloadJobsList(c *cli.Context, regex string) error {
 	return templates.DisplayOutput(list, c.App.Writer, templates.DownloadListTmpl)
 }
 
+func downloadSchedulesList(c *cli.Context, regex string) error {
+	list, err := api.DownloadGetSchedules(defaultAPIParams, regex)
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(list, c.App.Writer, templates.DownloadScheduleListTmpl)
+}
+
+func downloadScheduleStatus(c *cli.Context, id string) error {
+	schedule, err := api.DownloadScheduleStatus(defaultAPIParams, id)
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(schedule, c.App.Writer, templates.DownloadScheduleTmpl)
+}
+
 func downloadJobStatus(c *cli.Context, id string) error {
 	// with progress bar
 	if flagIsSet(c, progressBarFlag) {
@@ -389,6 +405,9 @@ func printDownloadStatus(w io.Writer, d downloader.DlStatusResp, verbose bool) {
 		}
 		fmt.Fprintln(w, progressMsg)
 	}
+	if d.Paused {
+		fmt.Fprintf(w, "Download paused (priority: %d)\n", d.Priority)
+	}
 	if verbose {
 		if len(d.CurrentTasks) > 0 {
 			sort.Slice(d.CurrentTasks, func(i, j int) bool {
@@ -399,11 +418,15 @@ func printDownloadStatus(w io.Writer, d downloader.DlStatusResp, verbose bool) {
 			for _, task := range d.CurrentTasks {
 				fmt.Fprintf(w, "\t%s: ", task.Name)
 				if task.Total == 0 {
-					fmt.Fprintln(w, cmn.B2S(task.Downloaded, 2))
+					fmt.Fprint(w, cmn.B2S(task.Downloaded, 2))
 				} else {
 					pctDownloaded := 100 * float64(task.Downloaded) / float64(task.Total)
-					fmt.Fprintf(w, "%s/%s (%.2f%%)\n", cmn.B2S(task.Downloaded, 2), cmn.B2S(task.Total, 2), pctDownloaded)
+					fmt.Fprintf(w, "%s/%s (%.2f%%)", cmn.B2S(task.Downloaded, 2), cmn.B2S(task.Total, 2), pctDownloaded)
+				}
+				if task.Extracted > 0 {
+					fmt.Fprintf(w, ", extracted %d file(s)", task.Extracted)
 				}
+				fmt.Fprintln(w)
 			}
 		}
 		if d.ErrorCnt > 0 {
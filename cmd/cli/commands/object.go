@@ -24,6 +24,7 @@ import (
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmd/cli/templates"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
 	"github.com/urfave/cli"
 	"github.com/vbauerster/mpb/v4"
 	"github.com/vbauerster/mpb/v4/decor"
@@ -81,14 +82,21 @@ func getObject(c *cli.Context, bck cmn.Bck, object, origURL, outFile string, sil
 		objArgs.Query.Set(cmn.URLParamOrigURL, origURL)
 	}
 
-	if flagIsSet(c, checksumFlag) {
+	if flagIsSet(c, archpathFlag) {
+		if objArgs.Query == nil {
+			objArgs.Query = make(url.Values, 1)
+		}
+		objArgs.Query.Set(cmn.URLParamArchpath, parseStrFlag(c, archpathFlag))
+	}
+
+	if flagIsSet(c, checksumFlag) && !flagIsSet(c, archpathFlag) {
 		objLen, err = api.GetObjectWithValidation(defaultAPIParams, bck, object, objArgs)
 	} else {
 		objLen, err = api.GetObject(defaultAPIParams, bck, object, objArgs)
 	}
 	if err != nil {
 		if httpErr, ok := err.(*cmn.HTTPError); ok {
-			if httpErr.Status == http.StatusNotFound {
+			if httpErr.Status == http.StatusNotFound && !flagIsSet(c, archpathFlag) {
 				return fmt.Errorf("object \"%s/%s\" does not exist", bck, object)
 			}
 		}
@@ -105,6 +113,44 @@ func getObject(c *cli.Context, bck cmn.Bck, object, origURL, outFile string, sil
 	return
 }
 
+// get multiple objects as a single archive
+func getBatch(c *cli.Context, bck cmn.Bck, outFile string) (err error) {
+	var (
+		w   io.Writer
+		n   int64
+		msg = &cmn.GetBatchMsg{
+			Template:    parseStrFlag(c, templateFlag),
+			Prefix:      parseStrFlag(c, prefixFlag),
+			Format:      parseStrFlag(c, archFormatFlag),
+			Ordered:     flagIsSet(c, orderedFlag),
+			SkipMissing: flagIsSet(c, skipMissingFlag),
+			Manifest:    parseStrFlag(c, manifestFlag),
+			Epoch:       parseIntFlag(c, epochFlag),
+			Shard:       parseIntFlag(c, shardIdxFlag),
+		}
+	)
+	if flagIsSet(c, listFlag) {
+		msg.ObjNames = makeList(parseStrFlag(c, listFlag), ",")
+	}
+	if outFile == fileStdIO {
+		w = os.Stdout
+	} else {
+		var file *os.File
+		if file, err = os.Create(outFile); err != nil {
+			return
+		}
+		defer file.Close()
+		w = file
+	}
+	if n, err = api.GetBatch(defaultAPIParams, bck, msg, w); err != nil {
+		return
+	}
+	if outFile != fileStdIO {
+		fmt.Fprintf(c.App.ErrWriter, "%q has the size %s (%d B)\n", outFile, cmn.B2S(n, 2), n)
+	}
+	return
+}
+
 //////
 // Promote AIS-colocated files and directories to objects (NOTE: advanced usage only)
 //////
@@ -137,6 +183,10 @@ func putSingleObject(c *cli.Context, bck cmn.Bck, objName, path string) (err err
 		bars     []*mpb.Bar
 		cksum    *cmn.Cksum
 	)
+	userMD, err := parseUserMDFlag(c)
+	if err != nil {
+		return err
+	}
 	if flagIsSet(c, computeCksumFlag) {
 		bckProps, err := api.HeadBucket(defaultAPIParams, bck)
 		if err != nil {
@@ -174,6 +224,7 @@ func putSingleObject(c *cli.Context, bck cmn.Bck, objName, path string) (err err
 		Object:     objName,
 		Reader:     reader,
 		Cksum:      cksum,
+		UserMD:     userMD,
 	}
 
 	err = api.PutObject(putArgs)
@@ -400,6 +451,14 @@ func putObject(c *cli.Context, bck cmn.Bck, objName, fileName, cksumType string)
 	return putMultipleObjects(c, files, bck)
 }
 
+func composeObject(c *cli.Context, bck cmn.Bck, objName string, msg *cmn.ComposeMsg) error {
+	if err := api.ComposeObject(defaultAPIParams, bck, objName, msg); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.Writer, "COMPOSE %d object(s) => %s/%s\n", len(msg.Sources), bck, objName)
+	return nil
+}
+
 func concatObject(c *cli.Context, bck cmn.Bck, objName string, fileNames []string) (err error) {
 	var (
 		bar        *mpb.Bar
@@ -507,6 +566,10 @@ func uploadFiles(c *cli.Context, p uploadParams) error {
 		lastReport  = time.Now()
 		reportEvery = p.refresh
 	)
+	userMD, err := parseUserMDFlag(c)
+	if err != nil {
+		return err
+	}
 
 	if showProgress {
 		sizeBarArg := progressBarArgs{total: p.totalSize, barText: "Uploaded sizes progress", barType: sizeArg}
@@ -580,7 +643,13 @@ func uploadFiles(c *cli.Context, p uploadParams) error {
 		}
 		countReader := cmn.NewCallbackReadOpenCloser(reader, updateBar)
 
-		putArgs := api.PutObjectArgs{BaseParams: defaultAPIParams, Bck: p.bck, Object: f.name, Reader: countReader}
+		putArgs := api.PutObjectArgs{
+			BaseParams: defaultAPIParams,
+			Bck:        p.bck,
+			Object:     f.name,
+			Reader:     countReader,
+			UserMD:     userMD,
+		}
 		if err := api.PutObject(putArgs); err != nil {
 			str := fmt.Sprintf("Failed to put object %q: %v\n", f.name, err)
 			if showProgress {
@@ -674,6 +743,52 @@ func objectStats(c *cli.Context, bck cmn.Bck, object string) error {
 	return templates.DisplayOutput(objProps, c.App.Writer, tmpl, flagIsSet(c, jsonFlag))
 }
 
+// append a single file to the existing (or new, with --create) .tar object
+func appendToArch(c *cli.Context, bck cmn.Bck, objName, path string) (err error) {
+	var (
+		fh   *cmn.FileHandle
+		fi   os.FileInfo
+		buf  = &bytes.Buffer{}
+		name = parseStrFlag(c, archpathFlag)
+	)
+	if fh, err = cmn.NewFileHandle(path); err != nil {
+		return
+	}
+	defer fh.Close()
+	if fi, err = fh.Stat(); err != nil {
+		return
+	}
+	aw := archive.NewWriter(cmn.ExtTar, buf)
+	if err = aw.Write(name, fi.Size(), fh); err != nil {
+		return
+	}
+	if err = aw.Close(); err != nil {
+		return
+	}
+	err = api.AppendToArch(api.AppendToArchArgs{
+		BaseParams: defaultAPIParams,
+		Bck:        bck,
+		Object:     objName,
+		Reader:     cmn.NewByteHandle(buf.Bytes()),
+		Size:       int64(buf.Len()),
+		Create:     flagIsSet(c, archCreateFlag),
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "APPEND %q => %s/%s (as %q)\n", path, bck, objName, name)
+	return
+}
+
+// list files (members) of the archived object
+func listArchive(c *cli.Context, bck cmn.Bck, object string) error {
+	entries, err := api.ListArchive(defaultAPIParams, bck, object)
+	if err != nil {
+		return handleObjHeadError(err, bck, object)
+	}
+	return templates.DisplayOutput(entries, c.App.Writer, templates.ArchEntriesTmpl, flagIsSet(c, jsonFlag))
+}
+
 // This function is needed to print a nice error message for the user
 func handleObjHeadError(err error, bck cmn.Bck, object string) error {
 	httpErr, ok := err.(*cmn.HTTPError)
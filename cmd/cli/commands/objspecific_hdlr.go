@@ -26,6 +26,16 @@ var (
 			checksumFlag,
 			isCachedFlag,
 			forceFlag,
+			listFlag,
+			templateFlag,
+			prefixFlag,
+			archFormatFlag,
+			orderedFlag,
+			skipMissingFlag,
+			manifestFlag,
+			epochFlag,
+			shardIdxFlag,
+			archpathFlag,
 		},
 		commandPut: append(
 			checksumFlags,
@@ -38,6 +48,9 @@ var (
 			verboseFlag,
 			yesFlag,
 			computeCksumFlag,
+			archpathFlag,
+			archCreateFlag,
+			userMDFlag,
 		),
 		commandPromote: {
 			recursiveFlag,
@@ -49,6 +62,9 @@ var (
 			recursiveFlag,
 			progressBarFlag,
 		},
+		commandCompose: {
+			wrapTarFlag,
+		},
 		commandCat: {
 			offsetFlag,
 			lengthFlag,
@@ -97,6 +113,14 @@ var (
 			Flags:     objectSpecificCmdsFlags[commandConcat],
 			Action:    concatHandler,
 		},
+		{
+			Name:         commandCompose,
+			Usage:        "compose (concatenate) existing objects into a new object, server-side",
+			ArgsUsage:    composeObjectArgument,
+			Flags:        objectSpecificCmdsFlags[commandCompose],
+			Action:       composeHandler,
+			BashComplete: bucketCompletions(bckCompletionsOpts{separator: true, multiple: true}),
+		},
 		{
 			Name:         commandCat,
 			Usage:        "gets object from the specified bucket and prints it to STDOUT; alias for ais get BUCKET_NAME/OBJECT_NAME -",
@@ -232,6 +256,12 @@ func getHandler(c *cli.Context) (err error) {
 		}
 	}
 
+	if flagIsSet(c, listFlag) || flagIsSet(c, templateFlag) || flagIsSet(c, prefixFlag) || flagIsSet(c, manifestFlag) {
+		if objName != "" {
+			return incorrectUsageMsg(c, "object name not supported, use list, template, prefix, or manifest flag")
+		}
+		return getBatch(c, bck, outFile)
+	}
 	if objName == "" {
 		return incorrectUsageMsg(c, "%q: missing object name", fullObjName)
 	}
@@ -260,6 +290,12 @@ func putHandler(c *cli.Context) (err error) {
 	if bck, p, err = validateBucket(c, bck, fullObjName, false); err != nil {
 		return
 	}
+	if flagIsSet(c, archpathFlag) {
+		if objName == "" {
+			return incorrectUsageMsg(c, "%q: missing archive (object) name", fullObjName)
+		}
+		return appendToArch(c, bck, objName, fileName)
+	}
 
 	return putObject(c, bck, objName, fileName, p.Cksum.Type)
 }
@@ -296,6 +332,41 @@ func concatHandler(c *cli.Context) (err error) {
 	return concatObject(c, bck, objName, fileNames)
 }
 
+func composeHandler(c *cli.Context) (err error) {
+	if c.NArg() < 2 {
+		return missingArgumentsError(c, "at least one source object", "destination in the form bucket/object")
+	}
+	var (
+		bck     cmn.Bck
+		objName string
+		msg     = &cmn.ComposeMsg{WrapTar: flagIsSet(c, wrapTarFlag)}
+		dst     = c.Args().Get(c.NArg() - 1)
+	)
+	for _, src := range c.Args()[:c.NArg()-1] {
+		srcBck, srcObjName, err := cmn.ParseBckObjectURI(src)
+		if err != nil {
+			return err
+		}
+		if srcObjName == "" {
+			return incorrectUsageMsg(c, "%q: missing object name", src)
+		}
+		if srcBck, _, err = validateBucket(c, srcBck, src, false); err != nil {
+			return err
+		}
+		msg.Sources = append(msg.Sources, cmn.ComposeSrc{Bck: srcBck, ObjName: srcObjName})
+	}
+	if bck, objName, err = cmn.ParseBckObjectURI(dst); err != nil {
+		return
+	}
+	if objName == "" {
+		return incorrectUsageMsg(c, "%q: missing destination object name", dst)
+	}
+	if bck, _, err = validateBucket(c, bck, dst, false); err != nil {
+		return
+	}
+	return composeObject(c, bck, objName, msg)
+}
+
 func promoteHandler(c *cli.Context) (err error) {
 	var (
 		bck         cmn.Bck
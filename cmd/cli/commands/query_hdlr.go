@@ -0,0 +1,189 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+// This file handles commands that run queries over objects' metadata.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/query"
+	"github.com/urfave/cli"
+)
+
+var (
+	queryCmdFlags = []cli.Flag{
+		pageSizeFlag,
+		objLimitFlag,
+		noHeaderFlag,
+	}
+
+	queryCmds = []cli.Command{
+		{
+			Name:      commandQuery,
+			Usage:     "find objects using SQL-like query, e.g.: \"SELECT name,size FROM ais://imgs WHERE size > 1MiB AND name LIKE 'train/%'\"",
+			ArgsUsage: queryArgument,
+			Flags:     queryCmdFlags,
+			Action:    queryHandler,
+		},
+	}
+)
+
+func queryHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "query")
+	}
+	var (
+		expr     = strings.Join(c.Args(), " ")
+		pageSize = parseIntFlag(c, pageSizeFlag)
+		limit    = parseIntFlag(c, objLimitFlag)
+		cnt      int
+	)
+	if pageSize <= 0 {
+		return fmt.Errorf("page size (%d) must be positive", pageSize)
+	}
+	if limit < 0 {
+		return fmt.Errorf("max object count (%d) cannot be negative", limit)
+	}
+	// parse locally first to point at the syntax error, if any
+	def, err := query.Parse(expr)
+	if err != nil {
+		if perr, ok := err.(*query.ParseError); ok {
+			return fmt.Errorf("%v\n  %s\n  %s^", err, expr, strings.Repeat(" ", perr.Pos-1))
+		}
+		return err
+	}
+	if def.Aggregate != nil {
+		return queryAggregate(c, expr, def.Aggregate)
+	}
+	props := def.InnerSelect.Props
+	if !cmn.StringInSlice(cmn.GetPropsName, strings.Split(props, ",")) {
+		props = cmn.GetPropsName + "," + props
+	}
+
+	handle, err := api.InitQueryExpr(defaultAPIParams, expr)
+	if err != nil {
+		return err
+	}
+	for showHeaders := !flagIsSet(c, noHeaderFlag); limit == 0 || cnt < limit; showHeaders = false {
+		size := pageSize
+		if limit > 0 && limit-cnt < size {
+			size = limit - cnt
+		}
+		entries, err := api.NextQueryResults(defaultAPIParams, handle, uint(size))
+		if err != nil {
+			if httpErr, ok := err.(*cmn.HTTPError); ok && httpErr.Status == http.StatusGone {
+				break // query is done
+			}
+			return err
+		}
+		if err := printObjectProps(c, entries, &objectListFilter{}, props, false, showHeaders); err != nil {
+			return err
+		}
+		cnt += len(entries)
+		if len(entries) < size {
+			break
+		}
+	}
+	return nil
+}
+
+// queryAggregate runs aggregation query and waits for the (merged) result
+func queryAggregate(c *cli.Context, expr string, aggr *query.AggregateMsg) error {
+	handle, err := api.InitQueryExpr(defaultAPIParams, expr)
+	if err != nil {
+		return err
+	}
+	var res *query.AggregateResult
+	for {
+		if res, err = api.QueryAggregate(defaultAPIParams, handle); err != nil {
+			return err
+		}
+		if res.Done {
+			break
+		}
+		time.Sleep(refreshRateDefault)
+	}
+	groups := make([]string, 0, len(res.Groups))
+	for group := range res.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	// histogram, if requested, goes last: one line per atime bucket
+	histogram := cmn.StringInSlice(query.AggrHistogram, aggr.Columns)
+	tw := &tabwriter.Writer{}
+	tw.Init(c.App.Writer, 0, 8, 2, ' ', 0)
+	if !flagIsSet(c, noHeaderFlag) {
+		header := make([]string, 0, len(aggr.Columns)+2)
+		if aggr.GroupBy != "" {
+			header = append(header, "GROUP")
+		}
+		for _, col := range aggr.Columns {
+			switch col {
+			case query.AggrCount:
+				header = append(header, "COUNT")
+			case query.AggrSum:
+				header = append(header, "SIZE")
+			case query.AggrMin:
+				header = append(header, "MIN SIZE")
+			case query.AggrMax:
+				header = append(header, "MAX SIZE")
+			}
+		}
+		if histogram {
+			header = append(header, "ATIME", "ATIME COUNT")
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, group := range groups {
+		var (
+			stats = res.Groups[group]
+			row   = make([]string, 0, len(aggr.Columns)+1)
+		)
+		if aggr.GroupBy != "" {
+			if group == "" {
+				group = "-"
+			}
+			row = append(row, group)
+		}
+		for _, col := range aggr.Columns {
+			switch col {
+			case query.AggrCount:
+				row = append(row, fmt.Sprintf("%d", stats.Count))
+			case query.AggrSum:
+				row = append(row, cmn.B2S(stats.Size, 2))
+			case query.AggrMin:
+				row = append(row, cmn.B2S(stats.MinSize, 2))
+			case query.AggrMax:
+				row = append(row, cmn.B2S(stats.MaxSize, 2))
+			}
+		}
+		if !histogram {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+			continue
+		}
+		atimes := make([]int64, 0, len(stats.Atime))
+		for atime := range stats.Atime {
+			atimes = append(atimes, atime)
+		}
+		sort.Slice(atimes, func(i, j int) bool { return atimes[i] < atimes[j] })
+		blank := make([]string, len(row))
+		for i, atime := range atimes {
+			if i > 0 {
+				row = blank // group's stats on the first line only
+			}
+			line := append(row, time.Unix(0, atime).Format(time.RFC3339), fmt.Sprintf("%d", stats.Atime[atime]))
+			fmt.Fprintln(tw, strings.Join(line, "\t"))
+		}
+	}
+	return tw.Flush()
+}
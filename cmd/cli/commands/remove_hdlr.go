@@ -19,10 +19,12 @@ var (
 		subcmdRemoveBucket: {
 			ignoreErrorFlag,
 		},
-		subcmdRemoveObject:   baseLstRngFlags,
-		subcmdRemoveNode:     {},
-		subcmdRemoveDownload: {},
-		subcmdRemoveDsort:    {},
+		subcmdRemoveObject: baseLstRngFlags,
+		subcmdRemoveNode:   {},
+		subcmdRemoveDownload: {
+			dlScheduleFlag,
+		},
+		subcmdRemoveDsort: {},
 	}
 
 	removeCmds = []cli.Command{
@@ -135,6 +137,14 @@ func removeDownloadHandler(c *cli.Context) (err error) {
 		return missingArgumentsError(c, "download job ID")
 	}
 
+	if flagIsSet(c, dlScheduleFlag) {
+		if err = api.DownloadRemoveSchedule(defaultAPIParams, id); err != nil {
+			return
+		}
+		fmt.Fprintf(c.App.Writer, "download schedule %q successfully removed.\n", id)
+		return
+	}
+
 	if err = api.DownloadRemove(defaultAPIParams, id); err != nil {
 		return
 	}
@@ -20,6 +20,12 @@ var (
 			resetFlag,
 		},
 		subcmdSetPrimary: {},
+		subcmdSetUserMD: {
+			replaceMDFlag,
+		},
+		subcmdSetDownload: {
+			dlPriorityFlag,
+		},
 	}
 
 	setCmds = []cli.Command{
@@ -51,6 +57,22 @@ var (
 					Action:       setPrimaryHandler,
 					BashComplete: daemonCompletions(completeProxies),
 				},
+				{
+					Name:         subcmdSetUserMD,
+					Usage:        "add, update, or remove user-defined metadata of an object (empty value removes the key)",
+					ArgsUsage:    objectUserMDArgument,
+					Flags:        setCmdsFlags[subcmdSetUserMD],
+					Action:       setUserMDHandler,
+					BashComplete: bucketCompletions(bckCompletionsOpts{separator: true}),
+				},
+				{
+					Name:         subcmdSetDownload,
+					Usage:        "change priority of the running download job",
+					ArgsUsage:    jobIDArgument,
+					Flags:        setCmdsFlags[subcmdSetDownload],
+					Action:       setDownloadHandler,
+					BashComplete: downloadIDRunningCompletions,
+				},
 			},
 		},
 	}
@@ -139,3 +161,47 @@ func setPrimaryHandler(c *cli.Context) (err error) {
 	}
 	return err
 }
+
+func setUserMDHandler(c *cli.Context) (err error) {
+	fullObjName := c.Args().First()
+	if c.NArg() < 1 {
+		return missingArgumentsError(c, "object name in format bucket/object")
+	}
+	if c.NArg() == 1 && !flagIsSet(c, replaceMDFlag) {
+		return missingArgumentsError(c, "user metadata in format KEY=VALUE")
+	}
+	bck, objName, err := cmn.ParseBckObjectURI(fullObjName)
+	if err != nil {
+		return
+	}
+	if bck, _, err = validateBucket(c, bck, fullObjName, false); err != nil {
+		return
+	}
+	if objName == "" {
+		return incorrectUsageMsg(c, "no object specified in %q", fullObjName)
+	}
+	md, err := makeUserMD(c.Args().Tail())
+	if err != nil {
+		return
+	}
+	if err = api.UpdateObjectMD(defaultAPIParams, bck, objName, md, flagIsSet(c, replaceMDFlag)); err == nil {
+		fmt.Fprintf(c.App.Writer, "%q: user metadata updated\n", fullObjName)
+	}
+	return
+}
+
+func setDownloadHandler(c *cli.Context) (err error) {
+	id := c.Args().First()
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "download job ID")
+	}
+	if !flagIsSet(c, dlPriorityFlag) {
+		return incorrectUsageMsg(c, "flag %q must be provided", dlPriorityFlag.Name)
+	}
+	priority := parseIntFlag(c, dlPriorityFlag)
+	if err = api.DownloadSetPriority(defaultAPIParams, id, priority); err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "priority of download job %q set to %d\n", id, priority)
+	return
+}
@@ -47,6 +47,7 @@ var (
 			progressBarFlag,
 			refreshFlag,
 			verboseFlag,
+			dlScheduleFlag,
 		},
 		subcmdShowDsort: {
 			regexFlag,
@@ -59,6 +60,7 @@ var (
 			objPropsFlag,
 			noHeaderFlag,
 			jsonFlag,
+			listArchFlag,
 		},
 		subcmdShowCluster: append(
 			longRunFlags,
@@ -87,6 +89,7 @@ var (
 		subcmdShowRemoteAIS: {
 			noHeaderFlag,
 		},
+		subcmdShowReplic: {},
 	}
 
 	showCmds = []cli.Command{
@@ -180,6 +183,14 @@ var (
 					Flags:     showCmdsFlags[subcmdShowRebalance],
 					Action:    showRebalanceHandler,
 				},
+				{
+					Name:         subcmdShowReplic,
+					Usage:        "show replication of buckets to remote AIS clusters",
+					ArgsUsage:    optionalBucketArgument,
+					Flags:        showCmdsFlags[subcmdShowReplic],
+					Action:       showReplicHandler,
+					BashComplete: bucketCompletions(bckCompletionsOpts{provider: cmn.ProviderAIS}),
+				},
 				{
 					Name:         subcmdShowBckProps,
 					Usage:        "show bucket properties",
@@ -243,6 +254,15 @@ func showBucketHandler(c *cli.Context) (err error) {
 	if flagIsSet(c, fastFlag) {
 		tmpl = templates.BucketsSummariesFastTmpl
 	}
+	for _, summary := range summaries {
+		if summary.DirtyCount > 0 {
+			tmpl = templates.BucketsSummariesDirtyTmpl
+			if flagIsSet(c, fastFlag) {
+				tmpl = templates.BucketsSummariesDirtyFastTmpl
+			}
+			break
+		}
+	}
 	return templates.DisplayOutput(summaries, c.App.Writer, tmpl)
 }
 
@@ -273,6 +293,13 @@ func showDisksHandler(c *cli.Context) (err error) {
 func showDownloadsHandler(c *cli.Context) (err error) {
 	id := c.Args().First()
 
+	if flagIsSet(c, dlScheduleFlag) {
+		if c.NArg() < 1 { // list all download schedules
+			return downloadSchedulesList(c, parseStrFlag(c, regexFlag))
+		}
+		return downloadScheduleStatus(c, id)
+	}
+
 	if c.NArg() < 1 { // list all download jobs
 		return downloadJobsList(c, parseStrFlag(c, regexFlag))
 	}
@@ -393,6 +420,9 @@ func showObjectHandler(c *cli.Context) (err error) {
 	if object == "" {
 		return incorrectUsageMsg(c, "no object specified in %q", fullObjName)
 	}
+	if flagIsSet(c, listArchFlag) {
+		return listArchive(c, bck, object)
+	}
 	return objectStats(c, bck, object)
 }
 
@@ -400,6 +430,20 @@ func showRebalanceHandler(c *cli.Context) (err error) {
 	return showRebalance(c, flagIsSet(c, refreshFlag), calcRefreshRate(c))
 }
 
+func showReplicHandler(c *cli.Context) (err error) {
+	bck, objName, err := cmn.ParseBckObjectURI(c.Args().First(), true)
+	if err != nil {
+		return
+	}
+	if objName != "" {
+		return objectNameArgumentNotSupported(c, objName)
+	}
+	if bck.Name != "" && bck.Provider == "" {
+		bck.Provider = cmn.ProviderAIS // only ais buckets get replicated
+	}
+	return showReplication(c, bck)
+}
+
 func showBckPropsHandler(c *cli.Context) (err error) {
 	return showBucketProps(c)
 }
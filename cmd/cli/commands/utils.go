@@ -28,6 +28,7 @@ import (
 	"github.com/NVIDIA/aistore/cmd/cli/templates"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/containers"
+	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/stats"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/urfave/cli"
@@ -352,6 +353,40 @@ func parseSource(rawURL string) (source dlSource, err error) {
 	}, err
 }
 
+// parseDlSource is parseSource unless the S3 credentials are provided - then
+// "s3://bucket/object" is passed as is and resolved by the targets (the
+// endpoint is part of the credentials).
+func parseDlSource(rawURL string, creds *downloader.DlCredentials) (dlSource, error) {
+	if creds != nil && creds.S3 != nil && strings.HasPrefix(rawURL, cmn.S3Scheme+"://") {
+		return dlSource{link: rawURL}, nil
+	}
+	return parseSource(rawURL)
+}
+
+func loadDlCredentials(fileName string) (*downloader.DlCredentials, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	creds := &downloader.DlCredentials{}
+	if err := jsoniter.NewDecoder(file).Decode(creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %q: %v", fileName, err)
+	}
+	return creds, creds.Validate()
+}
+
+func splitPatterns(patterns string) []string {
+	if patterns == "" {
+		return nil
+	}
+	split := strings.Split(patterns, ",")
+	for i := range split {
+		split[i] = strings.TrimSpace(split[i])
+	}
+	return split
+}
+
 func parseDest(rawURL string) (bucket, pathSuffix string, err error) {
 	destScheme, destBucket, destPathSuffix, err := parseURI(rawURL)
 	if err != nil {
@@ -556,6 +591,30 @@ func makePairs(args []string) (nvs cmn.SimpleKVs, err error) {
 	return
 }
 
+// Converts a list of "key=value" into user-defined object metadata;
+// unlike makePairs, empty values are allowed (to remove existing keys)
+func makeUserMD(args []string) (md cmn.SimpleKVs, err error) {
+	md = make(cmn.SimpleKVs, len(args))
+	for _, arg := range args {
+		if arg == "" {
+			continue
+		}
+		kv := strings.SplitN(arg, keyAndValueSeparator, 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid user metadata %q (expecting KEY=VALUE)", arg)
+		}
+		md[kv[0]] = kv[1]
+	}
+	return cmn.ValidateUserMD(md)
+}
+
+func parseUserMDFlag(c *cli.Context) (cmn.SimpleKVs, error) {
+	if !flagIsSet(c, userMDFlag) {
+		return nil, nil
+	}
+	return makeUserMD(makeList(parseStrFlag(c, userMDFlag), ","))
+}
+
 func chooseTmpl(tmplShort, tmplLong string, useShort bool) string {
 	if useShort {
 		return tmplShort
@@ -904,7 +963,9 @@ func bckPropList(props *cmn.BucketProps, verbose bool) (propList []prop, err err
 			{"mirror", props.Mirror.String()},
 			{"ec", props.EC.String()},
 			{"lru", props.LRU.String()},
+			{"replication", props.Replication.String()},
 			{"versioning", props.Versioning.String()},
+			{"write_policy", props.WritePolicy.String()},
 		}
 		if props.Extra.OrigURLBck != "" {
 			propList = append(propList, prop{Name: "original-url", Value: props.Extra.OrigURLBck})
@@ -939,6 +1000,12 @@ func bckSummaryList(summary cmn.BucketSummary, approx bool) (propList []prop) {
 		{Name: prefix + "size", Value: cmn.UnsignedB2S(summary.Size, 2)},
 		{Name: prefix + "usage%", Value: fmt.Sprintf("%.2f", summary.UsedPct)},
 	}
+	if summary.DirtyCount > 0 {
+		propList = append(propList,
+			prop{Name: "dirty objects", Value: strconv.FormatUint(summary.DirtyCount, 10)},
+			prop{Name: "dirty size", Value: cmn.UnsignedB2S(summary.DirtySize, 2)},
+		)
+	}
 	return
 }
 
@@ -278,6 +278,36 @@ func TestMakePairsErrors(t *testing.T) {
 	}
 }
 
+func TestMakeUserMD(t *testing.T) {
+	var makeUserMDTest = []struct {
+		input []string
+		md    cmn.SimpleKVs
+		err   bool
+	}{
+		{[]string{"Owner=bob", "dataset=imagenet"}, cmn.SimpleKVs{"owner": "bob", "dataset": "imagenet"}, false},
+		{[]string{"label=", "expr=a=b"}, cmn.SimpleKVs{"label": "", "expr": "a=b"}, false},
+		{[]string{"label"}, nil, true},
+		{[]string{"=value"}, nil, true},
+		{[]string{"bad key=value"}, nil, true},
+	}
+
+	for _, test := range makeUserMDTest {
+		md, err := makeUserMD(test.input)
+		if test.err {
+			if err == nil {
+				t.Errorf("expected error for input %#v, but got none", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for input %#v: %v", test.input, err)
+		}
+		if !reflect.DeepEqual(md, test.md) {
+			t.Errorf("makeUserMD expected output: %#v, got: %#v", test.md, md)
+		}
+	}
+}
+
 func TestParseBckObjectURI(t *testing.T) {
 	var tests = []struct {
 		uri         string
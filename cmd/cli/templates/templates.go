@@ -7,6 +7,7 @@ package templates
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"text/template"
@@ -230,13 +231,28 @@ const (
 		"{{$p.Name}}\t {{$p.Value}}\n" +
 		"{{end}}"
 
-	DownloadListHeader = "JOB ID\t STATUS\t ERRORS\t DESCRIPTION\n"
+	DownloadListHeader = "JOB ID\t STATUS\t PRIORITY\t ERRORS\t DESCRIPTION\n"
 	DownloadListBody   = "{{$value.ID}}\t " +
 		"{{if $value.Aborted}}Aborted" +
-		"{{else}}{{if $value.JobFinished}}Finished{{else}}{{$value.PendingCnt}} pending{{end}}" +
-		"{{end}}\t {{$value.ErrorCnt}}\t {{$value.Description}}\n"
+		"{{else}}{{if $value.JobFinished}}Finished{{else}}{{$value.PendingCnt}} pending" +
+		"{{if $value.Paused}} (paused){{end}}{{end}}" +
+		"{{end}}\t {{$value.Priority}}\t {{$value.ErrorCnt}}\t {{$value.Description}}\n"
 	DownloadListTmpl = DownloadListHeader + "{{ range $key, $value := . }}" + DownloadListBody + "{{end}}"
 
+	DownloadScheduleListHeader = "SCHEDULE ID\t SCHEDULE\t NEXT RUN\t RUNS\t DESCRIPTION\n"
+	DownloadScheduleListBody   = "{{$value.ID}}\t {{$value.Schedule}}\t " +
+		"{{if (IsUnsetTime $value.NextRun)}}-{{else}}{{FormatTime $value.NextRun}}{{end}}\t " +
+		"{{len $value.Runs}}\t {{$value.Description}}\n"
+	DownloadScheduleListTmpl = DownloadScheduleListHeader + "{{ range $value := . }}" + DownloadScheduleListBody + "{{end}}"
+
+	DownloadScheduleRunsHeader = "RUN ID\t STATUS\t FINISHED\t SKIPPED\t ERRORS\t START\t FINISH\n"
+	DownloadScheduleRunsBody   = "{{$run.ID}}\t " +
+		"{{if $run.Aborted}}Aborted{{else}}Finished{{end}}\t " +
+		"{{$run.FinishedCnt}}\t {{$run.SkippedCnt}}\t {{$run.ErrorCnt}}\t " +
+		"{{FormatTime $run.StartedTime}}\t {{FormatTime $run.FinishedTime}}\n"
+	DownloadScheduleTmpl = DownloadScheduleListHeader + "{{ $value := . }}" + DownloadScheduleListBody + "\n" +
+		DownloadScheduleRunsHeader + "{{ range $run := $value.Runs }}" + DownloadScheduleRunsBody + "{{end}}"
+
 	DSortListHeader = "JOB ID\t STATUS\t START\t FINISH\t DESCRIPTION\n"
 	DSortListBody   = "{{$value.ID}}\t " +
 		"{{if $value.Aborted}}Aborted" +
@@ -277,6 +293,20 @@ const (
 		"{{$v.Bck}}\t {{$v.ObjCount}}\t {{FormatBytesUnsigned $v.Size 2}}\t {{FormatFloat $v.UsedPct}}%\n" +
 		"{{end}}"
 
+	// Same as above, with objects that are yet to be written to the cloud (write-back and write-never buckets)
+	BucketsSummariesDirtyFastTmpl = "NAME\t EST. OBJECTS\t EST. SIZE\t EST. USED %\t DIRTY OBJECTS\t DIRTY SIZE\n" + bucketsSummariesDirtyBody
+	BucketsSummariesDirtyTmpl     = "NAME\t OBJECTS\t SIZE \t USED %\t DIRTY OBJECTS\t DIRTY SIZE\n" + bucketsSummariesDirtyBody
+	bucketsSummariesDirtyBody     = "{{range $k, $v := . }}" +
+		"{{$v.Bck}}\t {{$v.ObjCount}}\t {{FormatBytesUnsigned $v.Size 2}}\t {{FormatFloat $v.UsedPct}}%\t " +
+		"{{$v.DirtyCount}}\t {{FormatBytesUnsigned $v.DirtySize 2}}\n" +
+		"{{end}}"
+
+	// Archived object (tar, tgz, zip) members
+	ArchEntriesTmpl = "NAME\t SIZE\n" +
+		"{{range $e := . }}" +
+		"{{$e.Name}}\t {{FormatBytesSigned $e.Size 2}}\n" +
+		"{{end}}"
+
 	// For `object put` mass uploader. A caller adds to the template
 	// total count and size. That is why the template ends with \t
 	ExtensionTmpl = "Files to upload:\nEXTENSION\t COUNT\t SIZE\n" +
@@ -337,6 +367,7 @@ var (
 		"status":     "{{FormatObjStatus $obj}}",
 		"copies":     "{{$obj.Copies}}",
 		"cached":     "{{FormatObjIsCached $obj}}",
+		"user_md":    "{{FormatUserMD $obj.UserMD}}",
 	}
 
 	ObjStatMap = map[string]string{
@@ -348,6 +379,7 @@ var (
 		"copies":   "{{if .NumCopies}}{{.NumCopies}}{{else}}-{{end}}",
 		"checksum": "{{if .Checksum.Value}}{{.Checksum.Value}}{{else}}-{{end}}",
 		"ec":       "{{if (eq .DataSlices 0)}}-{{else}}{{FormatEC .DataSlices .ParitySlices .IsECCopy}}{{end}}",
+		"user_md":  "{{FormatUserMD .UserMD}}",
 	}
 
 	funcMap = template.FuncMap{
@@ -369,6 +401,7 @@ var (
 		"JoinList":            fmtStringList,
 		"JoinListNL":          func(lst []string) string { return fmtStringListGeneric(lst, "\n") },
 		"FormatFeatureFlags":  fmtFeatureFlags,
+		"FormatUserMD":        fmtUserMD,
 	}
 
 	HelpTemplateFuncMap = template.FuncMap{
@@ -584,6 +617,19 @@ func fmtStringList(lst []string) string {
 	return fmtStringListGeneric(lst, ",")
 }
 
+// "key1=value1,key2=value2" sorted by key
+func fmtUserMD(md cmn.SimpleKVs) string {
+	if len(md) == 0 {
+		return "-"
+	}
+	lst := make([]string, 0, len(md))
+	for k, v := range md {
+		lst = append(lst, k+"="+v)
+	}
+	sort.Strings(lst)
+	return fmtStringListGeneric(lst, ",")
+}
+
 func fmtStringListGeneric(lst []string, sep string) string {
 	var s strings.Builder
 	for idx, url := range lst {
@@ -5,6 +5,7 @@
 package cmn
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -55,6 +56,8 @@ type (
 		Size           uint64  `json:"size,string"`
 		TotalDisksSize uint64  `json:"disks_size,string"`
 		UsedPct        float64 `json:"used_pct"`
+		DirtyCount     uint64  `json:"dirty_count,string"` // not yet written to the cloud (see WritePolicyConf)
+		DirtySize      uint64  `json:"dirty_size,string"`
 	}
 	// BucketSummaryMsg represents options that can be set when asking for bucket summary.
 	BucketSummaryMsg struct {
@@ -73,6 +76,42 @@ type (
 		Template string `json:"template"`
 	}
 
+	// GetBatchMsg selects objects to be returned as a single archive (see ActGetBatch).
+	// Exactly one of ObjNames, Template, Prefix, or Manifest must be specified.
+	GetBatchMsg struct {
+		ObjNames    []string `json:"objnames"`     // explicit list of object names
+		Template    string   `json:"template"`     // bash-style range template, e.g. "shard-{0..99}.jpg"
+		Prefix      string   `json:"prefix"`       // all objects with a given prefix
+		Format      string   `json:"format"`       // ExtTar (default), ExtZip, or ExtMsgpack
+		Ordered     bool     `json:"ordered"`      // true: request order; false: completion order
+		SkipMissing bool     `json:"skip_missing"` // true: skip missing objects; false: fail the request
+		// dSort virtual shuffle: assemble the shard `Shard` of the epoch `Epoch`
+		// from the files of the input shards, as defined by the `Manifest` object
+		Manifest string `json:"manifest,omitempty"`
+		Epoch    int    `json:"epoch,omitempty"`
+		Shard    int    `json:"shard,omitempty"`
+	}
+
+	// ComposeMsg lists (in order) source objects to be concatenated into the
+	// destination object (see ActCompose). With WrapTar each source becomes a
+	// separate file named after the source object in the resulting tar archive.
+	ComposeMsg struct {
+		Sources []ComposeSrc `json:"sources"`
+		WrapTar bool         `json:"wrap_tar"`
+	}
+	ComposeSrc struct {
+		Bck     Bck    `json:"bck"`
+		ObjName string `json:"objname"`
+	}
+
+	// ObjUserMDMsg updates user-defined metadata of an existing object in place
+	// (see ActUpdateObjMD): the entries are merged into the existing ones (an
+	// empty value removes the key) or, with Replace, replace them altogether.
+	ObjUserMDMsg struct {
+		MD      SimpleKVs `json:"md"`
+		Replace bool      `json:"replace"`
+	}
+
 	// MountpathList contains two lists:
 	// * Available - list of local mountpaths available to the storage target
 	// * Disabled  - list of disabled mountpaths, the mountpaths that generated
@@ -116,6 +155,12 @@ type (
 		// EC defines erasure coding setting for the bucket
 		EC ECConf `json:"ec"`
 
+		// Replication defines asynchronous replication of the bucket to remote AIS cluster
+		Replication ReplicConf `json:"replication"`
+
+		// WritePolicy defines when the objects PUT into the bucket get written to the cloud
+		WritePolicy WritePolicyConf `json:"write_policy"`
+
 		// Bucket access attributes - see Allow* above
 		Access AccessAttrs `json:"access,string"`
 
@@ -138,18 +183,52 @@ type (
 		Renamed string `list:"omit"`
 	}
 	BucketPropsToUpdate struct {
-		BackendBck *BckToUpdate         `json:"backend_bck"`
-		Versioning *VersionConfToUpdate `json:"versioning"`
-		Cksum      *CksumConfToUpdate   `json:"checksum"`
-		LRU        *LRUConfToUpdate     `json:"lru"`
-		Mirror     *MirrorConfToUpdate  `json:"mirror"`
-		EC         *ECConfToUpdate      `json:"ec"`
-		Access     *AccessAttrs         `json:"access,string"`
+		BackendBck  *BckToUpdate         `json:"backend_bck"`
+		Versioning  *VersionConfToUpdate `json:"versioning"`
+		Cksum       *CksumConfToUpdate   `json:"checksum"`
+		LRU         *LRUConfToUpdate     `json:"lru"`
+		Mirror      *MirrorConfToUpdate  `json:"mirror"`
+		EC          *ECConfToUpdate      `json:"ec"`
+		Replication *ReplicConfToUpdate  `json:"replication"`
+		WritePolicy *WritePolicyToUpdate `json:"write_policy"`
+		Access      *AccessAttrs         `json:"access,string"`
 	}
 	BckToUpdate struct {
 		Name     *string `json:"name"`
 		Provider *string `json:"provider"`
 	}
+
+	// ReplicConf defines asynchronous replication of the AIS bucket to the bucket
+	// of remote AIS cluster: the targets forward PUTs and DELETEs of the objects
+	// (see mirror.XactReplic).
+	ReplicConf struct {
+		Bck     Bck  `json:"bck"`     // destination: the bucket of remote AIS cluster, e.g. ais://@uuid/name
+		Enabled bool `json:"enabled"` // forward PUTs and DELETEs when set to true
+	}
+	ReplicConfToUpdate struct {
+		Bck     *ReplicBckToUpdate `json:"bck"`
+		Enabled *bool              `json:"enabled"`
+	}
+	ReplicBckToUpdate struct {
+		Name     *string     `json:"name"`
+		Provider *string     `json:"provider"`
+		Ns       *NsToUpdate `json:"namespace"`
+	}
+	NsToUpdate struct {
+		UUID *string `json:"uuid"`
+		Name *string `json:"name"`
+	}
+
+	// WritePolicyConf applies to cloud buckets and AIS buckets with cloud backend:
+	// with write-back and write-never policies the objects are written to the cloud
+	// asynchronously (see mirror.XactWriteBack) or upon request (see ActFlush),
+	// respectively. Until then, the objects are "dirty".
+	WritePolicyConf struct {
+		Cloud string `json:"cloud"` // one of: WriteThrough (default), WriteBack, WriteNever
+	}
+	WritePolicyToUpdate struct {
+		Cloud *string `json:"cloud"`
+	}
 )
 
 // object properties
@@ -166,6 +245,7 @@ type (
 		ParitySlices int              `list:"omit"`
 		IsECCopy     bool             `list:"omit"`
 		Present      bool             `json:"present"`
+		UserMD       SimpleKVs        `list:"omit"`
 	}
 	ObjectCksumProps struct {
 		Type  string `json:"type"`
@@ -182,7 +262,7 @@ var GetPropsDefault = []string{
 // GetPropsAll is a list of all `GetProps*` options.
 // NOTE: do **NOT** forget update this array when a prop is added/removed.
 var GetPropsAll = append(GetPropsDefault,
-	GetPropsVersion, GetPropsCached, GetTargetURL, GetPropsStatus, GetPropsCopies, GetPropsEC,
+	GetPropsVersion, GetPropsCached, GetTargetURL, GetPropsStatus, GetPropsCopies, GetPropsEC, GetPropsUserMD,
 )
 
 ///////////////
@@ -195,7 +275,8 @@ func (msg *SelectMsg) NeedLocalData() bool {
 	return msg.WantProp(GetPropsAtime) ||
 		msg.WantProp(GetPropsStatus) ||
 		msg.WantProp(GetPropsCopies) ||
-		msg.WantProp(GetPropsCached)
+		msg.WantProp(GetPropsCached) ||
+		msg.WantProp(GetPropsUserMD)
 }
 
 // WantProp returns true if msg request requires to return propName property.
@@ -242,6 +323,8 @@ func (bs *BucketSummary) Aggregate(bckSummary BucketSummary) {
 	bs.ObjCount += bckSummary.ObjCount
 	bs.Size += bckSummary.Size
 	bs.TotalDisksSize += bckSummary.TotalDisksSize
+	bs.DirtyCount += bckSummary.DirtyCount
+	bs.DirtySize += bckSummary.DirtySize
 	bs.UsedPct = float64(bs.Size) * 100 / float64(bs.TotalDisksSize)
 }
 
@@ -333,6 +416,50 @@ func (c *MirrorConf) String() string {
 	return fmt.Sprintf("%d copies", c.Copies)
 }
 
+func (c *ReplicConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "To " + c.Bck.String()
+}
+
+func (c *ReplicConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Bck.Name == "" {
+		return errors.New("replication destination bucket (replication.bck.name) is empty")
+	}
+	if !c.Bck.IsRemoteAIS() {
+		return fmt.Errorf("replication destination (%q) must be a bucket of remote AIS cluster", c.Bck)
+	}
+	return nil
+}
+
+func (c *WritePolicyConf) Policy() string {
+	if c.Cloud == "" {
+		return WriteThrough
+	}
+	return c.Cloud
+}
+
+// IsDelayed returns true if PUT does not write the object to the cloud.
+func (c *WritePolicyConf) IsDelayed() bool {
+	return c.Cloud == WriteBack || c.Cloud == WriteNever
+}
+
+func (c *WritePolicyConf) String() string { return c.Policy() }
+
+func (c *WritePolicyConf) ValidateAsProps(_ *ValidationArgs) error {
+	switch c.Cloud {
+	case "", WriteThrough, WriteBack, WriteNever:
+		return nil
+	default:
+		return fmt.Errorf("invalid write policy %q, expecting one of: %q, %q, %q",
+			c.Cloud, WriteThrough, WriteBack, WriteNever)
+	}
+}
+
 func (c *RebalanceConf) String() string {
 	if c.Enabled {
 		return "Enabled"
@@ -446,7 +573,20 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	}
 
 	validationArgs := &ValidationArgs{TargetCnt: targetCnt}
-	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC}
+	if bp.Replication.Enabled {
+		if bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty() {
+			return errors.New("replication can only be enabled for AIS buckets (with no backend)")
+		}
+	}
+
+	if bp.WritePolicy.IsDelayed() {
+		if bck := (Bck{Provider: bp.Provider, Props: bp}); !bck.IsCloud() {
+			return fmt.Errorf("%s policy can only be set for cloud buckets (or AIS buckets with cloud backend)",
+				bp.WritePolicy.Cloud)
+		}
+	}
+
+	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC, &bp.Replication, &bp.WritePolicy}
 	for _, validator := range validators {
 		if err := validator.ValidateAsProps(validationArgs); err != nil {
 			return err
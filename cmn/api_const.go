@@ -25,8 +25,16 @@ const (
 )
 
 const (
-	AppendOp = "append"
-	FlushOp  = "flush"
+	AppendOp     = "append"
+	FlushOp      = "flush"
+	AppendArchOp = "arch" // append files to an existing .tar object
+)
+
+// write policies (see WritePolicyConf)
+const (
+	WriteThrough = "write-through" // PUT writes the object to the cloud
+	WriteBack    = "write-back"    // PUT writes the object locally, ActWriteBack writes it to the cloud
+	WriteNever   = "write-never"   // PUT writes the object locally, ActFlush writes it to the cloud
 )
 
 // ActionMsg.Action
@@ -48,11 +56,14 @@ const (
 	ActResetBprops    = "resetbprops"
 	ActResyncBprops   = "resyncbprops"
 	ActListObjects    = "listobj"
+	ActGetBatch       = "getbatch"
 	ActQueryObjects   = "queryobj"
 	ActInvalListCache = "invallistobjcache"
 	ActSummaryBucket  = "summarybck"
 	ActRenameObject   = "renameobj"
 	ActPromote        = "promote"
+	ActCompose        = "compose"
+	ActUpdateObjMD    = "updateobjmd"
 	ActEvictObjects   = "evictobj"
 	ActDelete         = "delete"
 	ActPrefetch       = "prefetch"
@@ -67,10 +78,14 @@ const (
 	ActPutCopies      = "putcopies"
 	ActMakeNCopies    = "makencopies"
 	ActLoadLomCache   = "loadlomcache"
-	ActECGet          = "ecget"    // erasure decode objects
-	ActECPut          = "ecput"    // erasure encode objects
-	ActECRespond      = "ecresp"   // respond to other targets' EC requests
-	ActECEncode       = "ecencode" // erasure code a bucket
+	ActECGet          = "ecget"      // erasure decode objects
+	ActECPut          = "ecput"      // erasure encode objects
+	ActECRespond      = "ecresp"     // respond to other targets' EC requests
+	ActECEncode       = "ecencode"   // erasure code a bucket
+	ActReplicate      = "replicate"  // forward PUTs and DELETEs to remote AIS cluster
+	ActReplicSync     = "replicsync" // replicate the entire bucket to remote AIS cluster
+	ActWriteBack      = "writeback"  // write dirty objects to the cloud (write-back policy)
+	ActFlush          = "flush"      // write all dirty objects of the bucket to the cloud now
 	ActStartGFN       = "metasync-start-gfn"
 	ActRecoverBck     = "recoverbck"
 	ActAttach         = "attach"
@@ -112,6 +127,12 @@ const (
 	HeaderBackendBckName     = HeaderBackendBck + ".name"
 	HeaderBackendBckProvider = HeaderBackendBck + "." + HeaderCloudProvider
 
+	HeaderReplicBck         = "replication.bck"
+	HeaderReplicBckName     = HeaderReplicBck + ".name"
+	HeaderReplicBckProvider = HeaderReplicBck + "." + HeaderCloudProvider
+	HeaderReplicBckNsUUID   = HeaderReplicBck + ".namespace.uuid"
+	HeaderReplicBckNsName   = HeaderReplicBck + ".namespace.name"
+
 	HeaderOrigURLBck  = "orig_url_bck"     // see BucketProps.OrigURLBck
 	HeaderCloudRegion = "cloud_bck_region" // see BucketProps.CloudRegion
 
@@ -130,6 +151,7 @@ const (
 	HeaderObjCksumVal  = "checksum.value" // Checksum Value
 	HeaderObjAtime     = "atime"          // Object access time
 	HeaderObjCustomMD  = "custom_md"      // Object custom metadata
+	HeaderObjUserMD    = "user_md"        // User-defined object metadata: "key=value"
 	HeaderObjSize      = "size"           // Object size (bytes)
 	HeaderObjVersion   = "version"        // Object version/generation - ais or Cloud
 	HeaderObjECMeta    = "ec_meta"        // Info about EC object/slice/replica
@@ -151,6 +173,7 @@ const (
 	HeaderCompress = "compress" // LZ4Compression, etc.
 
 	HeaderHandle = "handle"
+	HeaderError  = "Error" // trailer: error that occurred after the response had started
 )
 
 // supported compressions (alg-s)
@@ -166,8 +189,9 @@ const (
 	URLParamCheckExists = "check_cached" // true: check if object exists
 	URLParamProvider    = "provider"     // cloud provider
 	URLParamNamespace   = "namespace"
-	URLParamPrefix      = "prefix" // prefix for list objects in a bucket
-	URLParamRegex       = "regex"  // dsort/downloader regex
+	URLParamPrefix      = "prefix"   // prefix for list objects in a bucket
+	URLParamRegex       = "regex"    // dsort/downloader regex
+	URLParamArchpath    = "archpath" // GET a file (member) from within an archive (tar, tgz, zip)
 	// internal use
 	URLParamCheckExistsAny   = "cea" // true: lookup object in all mountpaths (NOTE: compare with URLParamCheckExists)
 	URLParamProxyID          = "pid" // ID of the redirecting proxy
@@ -187,6 +211,7 @@ const (
 
 	URLParamAppendType   = "appendty"
 	URLParamAppendHandle = "handle"
+	URLParamCreate       = "create" // true: create the object if it doesn't exist (see AppendArchOp)
 
 	// action (operation, transaction, task) UUID
 	URLParamUUID = "uuid"
@@ -232,6 +257,7 @@ const (
 	GetWhatStatus       = "status"    // JTX status by uuid
 	GetWhatICBundle     = "ic-bundle"
 	GetWhatTargetIPs    = "target_ips"
+	GetWhatArchIndex    = "archindex" // list archive (tar, tgz, zip) members
 )
 
 // SelectMsg.TimeFormat enum
@@ -252,6 +278,7 @@ const (
 	GetPropsStatus   = "status"
 	GetPropsCopies   = "copies"
 	GetPropsEC       = "ec"
+	GetPropsUserMD   = "user_md"
 )
 
 // BucketEntry.Status
@@ -326,11 +353,18 @@ const (
 	Records     = "records"
 	Shards      = "shards"
 	FinishedAck = "finished-ack"
+	Checkpoint  = "checkpoint"
+	Resume      = "resume"
+	Pause       = "pause"
+	Priority    = "priority"
 	List        = "list"
 	Remove      = "remove"
+	Schedule    = "schedule"
 	Next        = "next"
 	Peek        = "peek"
 	Discard     = "discard"
+	Aggregate   = "aggregate"
+	Select      = "select"
 	WorkerOwner = "worker" // TODO: it should be removed once get-next-bytes endpoint is ready
 
 	// CLI
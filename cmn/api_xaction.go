@@ -64,6 +64,10 @@ var XactsDtor = map[string]XactDescriptor{
 	ActRenameLB:      {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false},
 	ActCopyBucket:    {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false},
 	ActECEncode:      {Type: XactTypeBck, Startable: true, Metasync: true, Owned: false},
+	ActReplicate:     {Type: XactTypeBck, Startable: false},
+	ActReplicSync:    {Type: XactTypeBck, Startable: true},
+	ActWriteBack:     {Type: XactTypeBck, Startable: false},
+	ActFlush:         {Type: XactTypeBck, Startable: true},
 	ActEvictObjects:  {Type: XactTypeBck, Startable: false},
 	ActDelete:        {Type: XactTypeBck, Startable: false},
 	ActLoadLomCache:  {Type: XactTypeBck, Startable: false},
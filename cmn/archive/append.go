@@ -0,0 +1,69 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Appending to tar: a tar archive ends with (at least) two zero-filled 512-byte
+// blocks that must be overwritten by the appended files and then rewritten at
+// the new end of the archive.
+
+const tarBlockSize = 512
+
+// TarEnd scans the tar archive and returns the offset of its end-of-archive
+// marker - the offset at which new files are to be written. Zero-length
+// input is a valid (empty) archive.
+func TarEnd(r io.Reader) (end int64, err error) {
+	var (
+		hdr *tar.Header
+		cr  = &countingReader{r: r}
+		tr  = tar.NewReader(cr)
+	)
+	for {
+		if hdr, err = tr.Next(); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+		end = cr.off + cmn.DivCeil(hdr.Size, tarBlockSize)*tarBlockSize
+	}
+}
+
+// CopyTar reads tar stream `r` and writes all its files to `w` followed by
+// the end-of-archive marker. Returns the number of copied files.
+func CopyTar(w io.Writer, r io.Reader) (cnt int, err error) {
+	var (
+		hdr *tar.Header
+		tr  = tar.NewReader(r)
+		tw  = tar.NewWriter(w)
+	)
+	for {
+		if hdr, err = tr.Next(); err != nil {
+			break
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return
+		}
+		if _, err = io.Copy(tw, tr); err != nil {
+			return
+		}
+		cnt++
+	}
+	if err != io.EOF {
+		return
+	}
+	if cnt == 0 {
+		return 0, errors.New("no files to append")
+	}
+	return cnt, tw.Close()
+}
@@ -0,0 +1,62 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestTarAppend(t *testing.T) {
+	orig := writeTestArchive(t, cmn.ExtTar).Bytes()
+	// extra zero padding (as in: tar records) must be overwritten as well
+	padded := append(append([]byte{}, orig...), make([]byte, 10*512)...)
+	for _, b := range [][]byte{orig, padded} {
+		end, err := archive.TarEnd(bytes.NewReader(b))
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, end == int64(len(orig))-1024, "expected end %d, got %d", len(orig)-1024, end)
+
+		var (
+			result = bytes.NewBuffer(append([]byte{}, b[:end]...))
+			toAdd  = &bytes.Buffer{}
+			aw     = archive.NewWriter(cmn.ExtTar, toAdd)
+		)
+		tassert.CheckFatal(t, aw.Write("added.txt", 5, bytes.NewBufferString("added")))
+		tassert.CheckFatal(t, aw.Close())
+		cnt, err := archive.CopyTar(result, toAdd)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, cnt == 1, "expected 1 file appended, got %d", cnt)
+
+		tr := tar.NewReader(result)
+		expected := append(testEntries, struct{ name, data string }{"added.txt", "added"})
+		for _, e := range expected {
+			hdr, err := tr.Next()
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, hdr.Name == e.name, "expected %q, got %q", e.name, hdr.Name)
+			data, err := ioutil.ReadAll(tr)
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, string(data) == e.data, "%s: expected %q, got %q", e.name, e.data, data)
+		}
+		_, err = tr.Next()
+		tassert.Errorf(t, err == io.EOF, "expected EOF, got %v", err)
+	}
+}
+
+func TestTarAppendEmpty(t *testing.T) {
+	end, err := archive.TarEnd(bytes.NewReader(nil))
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, end == 0, "expected zero end, got %d", end)
+
+	_, err = archive.CopyTar(ioutil.Discard, bytes.NewReader(nil))
+	tassert.Errorf(t, err != nil, "expected error when there is nothing to append")
+}
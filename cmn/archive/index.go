@@ -0,0 +1,214 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Index is a (read-only) table of contents of a single archive. It is built
+// once by scanning the archive and is then used to locate and read its members
+// without rescanning.
+
+type (
+	// Entry describes a single archived file (member).
+	Entry struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"` // uncompressed size
+
+		// internal
+		offset int64  // data offset: in the archive (tar, zip) or in the decompressed stream (tgz)
+		csize  int64  // compressed size (zip only)
+		method uint16 // compression method (zip only)
+	}
+	Index struct {
+		Format  string   `json:"format"`
+		Entries []*Entry `json:"entries"`
+		byName  map[string]*Entry
+	}
+
+	countingReader struct {
+		r   io.Reader
+		off int64
+	}
+	readCloser struct {
+		io.Reader
+		io.Closer
+	}
+)
+
+// FormatFromName returns archive format by (object) name extension.
+func FormatFromName(name string) (string, error) {
+	switch {
+	case strings.HasSuffix(name, cmn.ExtTar):
+		return cmn.ExtTar, nil
+	case strings.HasSuffix(name, cmn.ExtTgz), strings.HasSuffix(name, cmn.ExtTarTgz):
+		return cmn.ExtTgz, nil
+	case strings.HasSuffix(name, cmn.ExtZip):
+		return cmn.ExtZip, nil
+	}
+	return "", fmt.Errorf("%q is not an archive (expecting one of %s, %s, %s, %s extensions)",
+		name, cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip)
+}
+
+// TrimExt returns the name without its archive extension (see FormatFromName),
+// if any.
+func TrimExt(name string) string {
+	format, err := FormatFromName(name)
+	if err != nil {
+		return name
+	}
+	if format == cmn.ExtTgz && strings.HasSuffix(name, cmn.ExtTarTgz) {
+		format = cmn.ExtTarTgz
+	}
+	return strings.TrimSuffix(name, format)
+}
+
+// BuildIndex scans the archive of a given format (as per FormatFromName)
+// and returns its index.
+func BuildIndex(format string, r io.ReaderAt, size int64) (idx *Index, err error) {
+	idx = &Index{Format: format}
+	switch format {
+	case cmn.ExtTar:
+		err = idx.scanTar(io.NewSectionReader(r, 0, size))
+	case cmn.ExtTgz:
+		var gzr *gzip.Reader
+		if gzr, err = gzip.NewReader(io.NewSectionReader(r, 0, size)); err != nil {
+			return nil, err
+		}
+		err = idx.scanTar(gzr)
+		gzr.Close()
+	case cmn.ExtZip:
+		err = idx.scanZip(r, size)
+	default:
+		err = fmt.Errorf("unsupported archive format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx.byName = make(map[string]*Entry, len(idx.Entries))
+	for _, e := range idx.Entries {
+		idx.byName[e.Name] = e
+	}
+	return idx, nil
+}
+
+func (idx *Index) scanTar(r io.Reader) error {
+	cr := &countingReader{r: r}
+	tr := tar.NewReader(cr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		// NOTE: tar reader consumes the header (and only the header) upon Next()
+		idx.Entries = append(idx.Entries, &Entry{Name: hdr.Name, Size: hdr.Size, offset: cr.off})
+	}
+}
+
+func (idx *Index) scanZip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Method != zip.Store && f.Method != zip.Deflate {
+			return fmt.Errorf("%s: unsupported zip compression method %d", f.Name, f.Method)
+		}
+		offset, err := f.DataOffset()
+		if err != nil {
+			return err
+		}
+		idx.Entries = append(idx.Entries, &Entry{
+			Name:   f.Name,
+			Size:   int64(f.UncompressedSize64),
+			offset: offset,
+			csize:  int64(f.CompressedSize64),
+			method: f.Method,
+		})
+	}
+	return nil
+}
+
+// Find returns the entry by its name, or nil if not present.
+func (idx *Index) Find(name string) *Entry { return idx.byName[name] }
+
+// Names returns sorted names of all archived files.
+func (idx *Index) Names() []string {
+	names := make([]string, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		names = append(names, e.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open returns a reader of the archived file's content; `r` and `size`
+// must refer to the same archive the index was built from.
+func (idx *Index) Open(r io.ReaderAt, size int64, e *Entry) (io.ReadCloser, error) {
+	switch idx.Format {
+	case cmn.ExtTar:
+		return ioutil.NopCloser(io.NewSectionReader(r, e.offset, e.Size)), nil
+	case cmn.ExtTgz:
+		gzr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.CopyN(ioutil.Discard, gzr, e.offset); err != nil {
+			gzr.Close()
+			return nil, err
+		}
+		return &readCloser{Reader: io.LimitReader(gzr, e.Size), Closer: gzr}, nil
+	case cmn.ExtZip:
+		sr := io.NewSectionReader(r, e.offset, e.csize)
+		if e.method == zip.Store {
+			return ioutil.NopCloser(sr), nil
+		}
+		return flate.NewReader(sr), nil
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", idx.Format)
+	}
+}
+
+func (cr *countingReader) Read(b []byte) (n int, err error) {
+	n, err = cr.r.Read(b)
+	cr.off += int64(n)
+	return
+}
+
+// Seek allows tar reader to skip over archived files' content instead of
+// reading it, as long as the underlying reader is seekable (e.g., plain tar).
+func (cr *countingReader) Seek(offset int64, whence int) (int64, error) {
+	sk, ok := cr.r.(io.Seeker)
+	if !ok || whence != io.SeekCurrent {
+		return -1, errors.New("seek not supported")
+	}
+	pos, err := sk.Seek(offset, whence)
+	if err == nil {
+		cr.off += offset
+	}
+	return pos, err
+}
@@ -0,0 +1,83 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func checkIndex(t *testing.T, format string, b []byte) {
+	r := bytes.NewReader(b)
+	idx, err := archive.BuildIndex(format, r, int64(len(b)))
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(idx.Entries) == len(testEntries), "expected %d entries, got %d", len(testEntries), len(idx.Entries))
+	for _, te := range testEntries {
+		e := idx.Find(te.name)
+		tassert.Fatalf(t, e != nil, "%s: not found", te.name)
+		tassert.Errorf(t, e.Size == int64(len(te.data)), "%s: expected size %d, got %d", te.name, len(te.data), e.Size)
+		rc, err := idx.Open(r, int64(len(b)), e)
+		tassert.CheckFatal(t, err)
+		data, err := ioutil.ReadAll(rc)
+		tassert.CheckFatal(t, err)
+		rc.Close()
+		tassert.Errorf(t, string(data) == te.data, "%s: expected %q, got %q", te.name, te.data, data)
+	}
+	tassert.Errorf(t, idx.Find("nonexistent") == nil, "expected nil for nonexistent entry")
+}
+
+func TestFormatFromName(t *testing.T) {
+	for in, out := range map[string]string{
+		"a.tar": cmn.ExtTar, "dir/b.tgz": cmn.ExtTgz, "c.tar.gz": cmn.ExtTgz, "d.zip": cmn.ExtZip,
+	} {
+		format, err := archive.FormatFromName(in)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, format == out, "%s: expected %q, got %q", in, out, format)
+	}
+	_, err := archive.FormatFromName("e.txt")
+	tassert.Errorf(t, err != nil, "expected error for non-archive")
+}
+
+func TestIndexTar(t *testing.T) {
+	checkIndex(t, cmn.ExtTar, writeTestArchive(t, cmn.ExtTar).Bytes())
+}
+
+func TestIndexTgz(t *testing.T) {
+	var (
+		buf = &bytes.Buffer{}
+		gzw = gzip.NewWriter(buf)
+	)
+	_, err := gzw.Write(writeTestArchive(t, cmn.ExtTar).Bytes())
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, gzw.Close())
+	checkIndex(t, cmn.ExtTgz, buf.Bytes())
+}
+
+func TestIndexZip(t *testing.T) {
+	// stored (uncompressed)
+	checkIndex(t, cmn.ExtZip, writeTestArchive(t, cmn.ExtZip).Bytes())
+
+	// deflated
+	var (
+		buf = &bytes.Buffer{}
+		zw  = zip.NewWriter(buf)
+	)
+	for _, e := range testEntries {
+		w, err := zw.Create(e.name)
+		tassert.CheckFatal(t, err)
+		_, err = w.Write([]byte(e.data))
+		tassert.CheckFatal(t, err)
+	}
+	tassert.CheckFatal(t, zw.Close())
+	checkIndex(t, cmn.ExtZip, buf.Bytes())
+}
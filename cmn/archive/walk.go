@@ -0,0 +1,83 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Walking the archive hands its regular files one by one to the callback.
+// Unlike Index, it reads the archive only once and sequentially - tarballs
+// can be walked while being received.
+
+// WalkFunc is called for each regular file of the archive. The reader is valid
+// only until the function returns.
+type WalkFunc func(name string, size int64, r io.Reader) error
+
+// WalkTar walks the tarball (cmn.ExtTar) or the gzipped tarball (cmn.ExtTgz)
+// read from the stream.
+func WalkTar(format string, r io.Reader, fn WalkFunc) error {
+	switch format {
+	case cmn.ExtTar:
+	case cmn.ExtTgz:
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gzr.Close()
+		r = gzr
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if err := fn(hdr.Name, hdr.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// WalkZip walks the zip archive. Unlike the tarball, the zip archive cannot be
+// read from the stream: its directory is stored at the end.
+// NOTE: the CRC-32 of each file is validated - the error is returned by the
+// reader once the whole file is read.
+func WalkZip(r io.ReaderAt, size int64, fn WalkFunc) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %q: %v", f.Name, err)
+		}
+		err = fn(f.Name, int64(f.UncompressedSize64), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func checkWalk(t *testing.T, walk func(fn archive.WalkFunc) error) {
+	walked := make(map[string]string, len(testEntries))
+	err := walk(func(name string, size int64, r io.Reader) error {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		tassert.Errorf(t, int64(len(data)) == size, "%s: expected size %d, got %d", name, size, len(data))
+		walked[name] = string(data)
+		return nil
+	})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(walked) == len(testEntries), "expected %d files, got %d", len(testEntries), len(walked))
+	for _, te := range testEntries {
+		tassert.Errorf(t, walked[te.name] == te.data, "%s: expected %q, got %q", te.name, te.data, walked[te.name])
+	}
+}
+
+func TestWalkTar(t *testing.T) {
+	// directories are skipped
+	var (
+		buf = &bytes.Buffer{}
+		tw  = tar.NewWriter(buf)
+	)
+	tassert.CheckFatal(t, tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}))
+	tassert.CheckFatal(t, tw.Flush())
+	b := append(buf.Bytes(), writeTestArchive(t, cmn.ExtTar).Bytes()...)
+
+	checkWalk(t, func(fn archive.WalkFunc) error {
+		return archive.WalkTar(cmn.ExtTar, bytes.NewReader(b), fn)
+	})
+}
+
+func TestWalkTgz(t *testing.T) {
+	var (
+		buf = &bytes.Buffer{}
+		gzw = gzip.NewWriter(buf)
+	)
+	_, err := gzw.Write(writeTestArchive(t, cmn.ExtTar).Bytes())
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, gzw.Close())
+	checkWalk(t, func(fn archive.WalkFunc) error {
+		return archive.WalkTar(cmn.ExtTgz, buf, fn)
+	})
+
+	err = archive.WalkTar(cmn.ExtZip, bytes.NewReader(nil), nil)
+	tassert.Errorf(t, err != nil, "expected error: zip archive cannot be read from the stream")
+}
+
+func TestWalkZip(t *testing.T) {
+	b := writeTestArchive(t, cmn.ExtZip).Bytes()
+	checkWalk(t, func(fn archive.WalkFunc) error {
+		return archive.WalkZip(bytes.NewReader(b), int64(len(b)), fn)
+	})
+
+	// corrupt the content of the first file: CRC-32 mismatch
+	i := bytes.Index(b, []byte(testEntries[0].data))
+	tassert.Fatalf(t, i > 0, "content of %s not found", testEntries[0].name)
+	b[i] = 'A'
+	err := archive.WalkZip(bytes.NewReader(b), int64(len(b)), func(_ string, _ int64, r io.Reader) error {
+		_, err := ioutil.ReadAll(r)
+		return err
+	})
+	tassert.Errorf(t, err == zip.ErrChecksum, "expected %v, got %v", zip.ErrChecksum, err)
+}
+
+func TestWalkStop(t *testing.T) {
+	var (
+		b   = writeTestArchive(t, cmn.ExtTar).Bytes()
+		cnt int
+	)
+	err := archive.WalkTar(cmn.ExtTar, bytes.NewReader(b), func(string, int64, io.Reader) error {
+		cnt++
+		return io.ErrUnexpectedEOF
+	})
+	tassert.Errorf(t, err == io.ErrUnexpectedEOF, "expected %v, got %v", io.ErrUnexpectedEOF, err)
+	tassert.Errorf(t, cnt == 1, "expected walking to stop after the first file, got %d", cnt)
+}
+
+func TestTrimExt(t *testing.T) {
+	for in, out := range map[string]string{
+		"a.tar": "a", "dir/b.tgz": "dir/b", "c.tar.gz": "c", "d.zip": "d", "e.txt": "e.txt", "download": "download",
+	} {
+		trimmed := archive.TrimExt(in)
+		tassert.Errorf(t, trimmed == out, "%s: expected %q, got %q", in, out, trimmed)
+	}
+}
@@ -0,0 +1,146 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/tinylib/msgp/msgp"
+)
+
+type (
+	// Writer serializes a sequence of named entries into a single archive stream.
+	// The size of each entry must be known in advance (tar requires it in the header).
+	Writer interface {
+		Write(name string, size int64, r io.Reader) error
+		Close() error
+	}
+
+	tarWriter struct {
+		tw *tar.Writer
+	}
+	zipWriter struct {
+		zw *zip.Writer
+	}
+	// msgpackWriter writes entries as a stream of 2-element arrays: [name, bytes].
+	// The number of entries is not known upfront, so the stream is not a map.
+	msgpackWriter struct {
+		mw  *msgp.Writer
+		buf []byte
+	}
+)
+
+// interface guard
+var (
+	_ Writer = (*tarWriter)(nil)
+	_ Writer = (*zipWriter)(nil)
+	_ Writer = (*msgpackWriter)(nil)
+)
+
+// SupportedFormats returns archive formats that can be written.
+func SupportedFormats() []string {
+	return []string{cmn.ExtTar, cmn.ExtZip, cmn.ExtMsgpack}
+}
+
+// ValidateFormat returns normalized format: an empty string defaults to tar.
+func ValidateFormat(format string) (string, error) {
+	if format == "" {
+		return cmn.ExtTar, nil
+	}
+	if format[0] != '.' {
+		format = "." + format
+	}
+	for _, f := range SupportedFormats() {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported archive format %q (expecting one of %v)", format, SupportedFormats())
+}
+
+// NewWriter returns a Writer for a given (validated) format.
+func NewWriter(format string, w io.Writer) Writer {
+	switch format {
+	case cmn.ExtZip:
+		return &zipWriter{zw: zip.NewWriter(w)}
+	case cmn.ExtMsgpack:
+		return &msgpackWriter{mw: msgp.NewWriter(w)}
+	default:
+		cmn.Assert(format == cmn.ExtTar)
+		return &tarWriter{tw: tar.NewWriter(w)}
+	}
+}
+
+///////////////
+// tarWriter //
+///////////////
+
+func (tw *tarWriter) Write(name string, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  time.Now(),
+		Format:   tar.FormatUnknown,
+	}
+	if err := tw.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.CopyN(tw.tw, r, size)
+	return err
+}
+
+func (tw *tarWriter) Close() error { return tw.tw.Close() }
+
+///////////////
+// zipWriter //
+///////////////
+
+func (zw *zipWriter) Write(name string, size int64, r io.Reader) error {
+	hdr := &zip.FileHeader{
+		Name:               name,
+		Method:             zip.Store,
+		Modified:           time.Now(),
+		UncompressedSize64: uint64(size),
+	}
+	w, err := zw.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyN(w, r, size)
+	return err
+}
+
+func (zw *zipWriter) Close() error { return zw.zw.Close() }
+
+///////////////////
+// msgpackWriter //
+///////////////////
+
+func (mw *msgpackWriter) Write(name string, size int64, r io.Reader) (err error) {
+	if int64(cap(mw.buf)) < size {
+		mw.buf = make([]byte, size)
+	}
+	buf := mw.buf[:size]
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return
+	}
+	if err = mw.mw.WriteArrayHeader(2); err != nil {
+		return
+	}
+	if err = mw.mw.WriteString(name); err != nil {
+		return
+	}
+	return mw.mw.WriteBytes(buf)
+}
+
+func (mw *msgpackWriter) Close() error { return mw.mw.Flush() }
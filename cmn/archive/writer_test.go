@@ -0,0 +1,96 @@
+// Package archive provides common low-level utilities for reading and writing
+// archives (tar, zip, msgpack) that contain user objects.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var testEntries = []struct {
+	name string
+	data string
+}{
+	{"a.jpg", "aaaaaaaaaa"},
+	{"dir/b.cls", "1"},
+	{"dir/c.json", `{"label": 3}`},
+	{"empty", ""},
+}
+
+func writeTestArchive(t *testing.T, format string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	aw := archive.NewWriter(format, buf)
+	for _, e := range testEntries {
+		err := aw.Write(e.name, int64(len(e.data)), bytes.NewBufferString(e.data))
+		tassert.CheckFatal(t, err)
+	}
+	tassert.CheckFatal(t, aw.Close())
+	return buf
+}
+
+func TestValidateFormat(t *testing.T) {
+	for in, out := range map[string]string{"": cmn.ExtTar, "tar": cmn.ExtTar, ".zip": cmn.ExtZip, "msgpack": cmn.ExtMsgpack} {
+		format, err := archive.ValidateFormat(in)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, format == out, "expected %q, got %q", out, format)
+	}
+	_, err := archive.ValidateFormat(".rar")
+	tassert.Errorf(t, err != nil, "expected error for unsupported format")
+}
+
+func TestWriterTar(t *testing.T) {
+	tr := tar.NewReader(writeTestArchive(t, cmn.ExtTar))
+	for _, e := range testEntries {
+		hdr, err := tr.Next()
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, hdr.Name == e.name, "expected %q, got %q", e.name, hdr.Name)
+		data, err := ioutil.ReadAll(tr)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, string(data) == e.data, "%s: expected %q, got %q", e.name, e.data, data)
+	}
+	_, err := tr.Next()
+	tassert.Errorf(t, err == io.EOF, "expected EOF, got %v", err)
+}
+
+func TestWriterZip(t *testing.T) {
+	buf := writeTestArchive(t, cmn.ExtZip)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(zr.File) == len(testEntries), "expected %d files, got %d", len(testEntries), len(zr.File))
+	for i, f := range zr.File {
+		tassert.Errorf(t, f.Name == testEntries[i].name, "expected %q, got %q", testEntries[i].name, f.Name)
+		r, err := f.Open()
+		tassert.CheckFatal(t, err)
+		data, err := ioutil.ReadAll(r)
+		tassert.CheckFatal(t, err)
+		r.Close()
+		tassert.Errorf(t, string(data) == testEntries[i].data, "%s: expected %q, got %q", f.Name, testEntries[i].data, data)
+	}
+}
+
+func TestWriterMsgpack(t *testing.T) {
+	mr := msgp.NewReader(writeTestArchive(t, cmn.ExtMsgpack))
+	for _, e := range testEntries {
+		sz, err := mr.ReadArrayHeader()
+		tassert.CheckFatal(t, err)
+		tassert.Fatalf(t, sz == 2, "expected array of 2, got %d", sz)
+		name, err := mr.ReadString()
+		tassert.CheckFatal(t, err)
+		data, err := mr.ReadBytes(nil)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, name == e.name, "expected %q, got %q", e.name, name)
+		tassert.Errorf(t, string(data) == e.data, "%s: expected %q, got %q", name, e.data, data)
+	}
+}
@@ -15,14 +15,15 @@ package cmn
 //      to 8 different statuses. Now only OK=0, Moved=1, Deleted=2 are supported
 // 3:   CheckExists (for cloud bucket it shows if the object in local cache)
 type BucketEntry struct {
-	Name      string `json:"name" msg:"n"`                            // name of the object - note: does not include the bucket name
-	Size      int64  `json:"size,string,omitempty" msg:"s,omitempty"` // size in bytes
-	Checksum  string `json:"checksum,omitempty" msg:"cs,omitempty"`   // checksum
-	Atime     string `json:"atime,omitempty" msg:"a,omitempty"`       // formatted as per SelectMsg.TimeFormat
-	Version   string `json:"version,omitempty" msg:"v,omitempty"`     // version/generation ID. In GCP it is int64, in AWS it is a string
-	TargetURL string `json:"target_url,omitempty" msg:"t,omitempty"`  // URL of target which has the entry
-	Copies    int16  `json:"copies,omitempty" msg:"c,omitempty"`      // ## copies (non-replicated = 1)
-	Flags     uint16 `json:"flags,omitempty" msg:"f,omitempty"`       // object flags, like CheckExists, IsMoved etc
+	Name      string    `json:"name" msg:"n"`                            // name of the object - note: does not include the bucket name
+	Size      int64     `json:"size,string,omitempty" msg:"s,omitempty"` // size in bytes
+	Checksum  string    `json:"checksum,omitempty" msg:"cs,omitempty"`   // checksum
+	Atime     string    `json:"atime,omitempty" msg:"a,omitempty"`       // formatted as per SelectMsg.TimeFormat
+	Version   string    `json:"version,omitempty" msg:"v,omitempty"`     // version/generation ID. In GCP it is int64, in AWS it is a string
+	TargetURL string    `json:"target_url,omitempty" msg:"t,omitempty"`  // URL of target which has the entry
+	Copies    int16     `json:"copies,omitempty" msg:"c,omitempty"`      // ## copies (non-replicated = 1)
+	Flags     uint16    `json:"flags,omitempty" msg:"f,omitempty"`       // object flags, like CheckExists, IsMoved etc
+	UserMD    SimpleKVs `json:"user_md,omitempty" msg:"u,omitempty"`     // user-defined metadata
 }
 
 func (be *BucketEntry) CheckExists() bool {
@@ -58,6 +59,9 @@ func (be *BucketEntry) CopyWithProps(propsSet StringSet) (ne *BucketEntry) {
 	if propsSet.Contains(GetPropsCopies) {
 		ne.Copies = be.Copies
 	}
+	if propsSet.Contains(GetPropsUserMD) {
+		ne.UserMD = be.UserMD
+	}
 	return
 }
 
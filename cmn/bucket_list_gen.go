@@ -76,6 +76,36 @@ func (z *BucketEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "Flags")
 				return
 			}
+		case "u":
+			var zb0002 uint32
+			zb0002, err = dc.ReadMapHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "UserMD")
+				return
+			}
+			if z.UserMD == nil {
+				z.UserMD = make(SimpleKVs, zb0002)
+			} else if len(z.UserMD) > 0 {
+				for key := range z.UserMD {
+					delete(z.UserMD, key)
+				}
+			}
+			for zb0002 > 0 {
+				zb0002--
+				var za0001 string
+				var za0002 string
+				za0001, err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "UserMD")
+					return
+				}
+				za0002, err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "UserMD", za0001)
+					return
+				}
+				z.UserMD[za0001] = za0002
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -90,8 +120,8 @@ func (z *BucketEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 // EncodeMsg implements msgp.Encodable
 func (z *BucketEntry) EncodeMsg(en *msgp.Writer) (err error) {
 	// omitempty: check for empty values
-	zb0001Len := uint32(8)
-	var zb0001Mask uint8 /* 8 bits */
+	zb0001Len := uint32(9)
+	var zb0001Mask uint16 /* 9 bits */
 	if z.Size == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x2
@@ -120,6 +150,10 @@ func (z *BucketEntry) EncodeMsg(en *msgp.Writer) (err error) {
 		zb0001Len--
 		zb0001Mask |= 0x80
 	}
+	if z.UserMD == nil {
+		zb0001Len--
+		zb0001Mask |= 0x100
+	}
 	// variable map header, size zb0001Len
 	err = en.Append(0x80 | uint8(zb0001Len))
 	if err != nil {
@@ -222,12 +256,42 @@ func (z *BucketEntry) EncodeMsg(en *msgp.Writer) (err error) {
 			return
 		}
 	}
+	if (zb0001Mask & 0x100) == 0 { // if not empty
+		// write "u"
+		err = en.Append(0xa1, 0x75)
+		if err != nil {
+			return
+		}
+		err = en.WriteMapHeader(uint32(len(z.UserMD)))
+		if err != nil {
+			err = msgp.WrapError(err, "UserMD")
+			return
+		}
+		for za0001, za0002 := range z.UserMD {
+			err = en.WriteString(za0001)
+			if err != nil {
+				err = msgp.WrapError(err, "UserMD")
+				return
+			}
+			err = en.WriteString(za0002)
+			if err != nil {
+				err = msgp.WrapError(err, "UserMD", za0001)
+				return
+			}
+		}
+	}
 	return
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *BucketEntry) Msgsize() (s int) {
-	s = 1 + 2 + msgp.StringPrefixSize + len(z.Name) + 2 + msgp.Int64Size + 3 + msgp.StringPrefixSize + len(z.Checksum) + 2 + msgp.StringPrefixSize + len(z.Atime) + 2 + msgp.StringPrefixSize + len(z.Version) + 2 + msgp.StringPrefixSize + len(z.TargetURL) + 2 + msgp.Int16Size + 2 + msgp.Uint16Size
+	s = 1 + 2 + msgp.StringPrefixSize + len(z.Name) + 2 + msgp.Int64Size + 3 + msgp.StringPrefixSize + len(z.Checksum) + 2 + msgp.StringPrefixSize + len(z.Atime) + 2 + msgp.StringPrefixSize + len(z.Version) + 2 + msgp.StringPrefixSize + len(z.TargetURL) + 2 + msgp.Int16Size + 2 + msgp.Uint16Size + 2 + msgp.MapHeaderSize
+	if z.UserMD != nil {
+		for za0001, za0002 := range z.UserMD {
+			_ = za0002
+			s += msgp.StringPrefixSize + len(za0001) + msgp.StringPrefixSize + len(za0002)
+		}
+	}
 	return
 }
 
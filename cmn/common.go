@@ -43,6 +43,10 @@ const (
 	ExtTarTgz = ".tar.gz"
 	// ExtZip is zip files extension
 	ExtZip = ".zip"
+	// ExtMsgpack is msgpack files extension
+	ExtMsgpack = ".msgpack"
+	// ExtTFRecord is TFRecord files extension
+	ExtTFRecord = ".tfrecord"
 
 	// misc
 	SizeofI64 = int(unsafe.Sizeof(uint64(0)))
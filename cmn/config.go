@@ -563,6 +563,8 @@ var (
 	_ PropsValidator = &LRUConf{}
 	_ PropsValidator = &MirrorConf{}
 	_ PropsValidator = &ECConf{}
+	_ PropsValidator = &ReplicConf{}
+	_ PropsValidator = &WritePolicyConf{}
 
 	_ json.Marshaler   = &CloudConf{}
 	_ json.Unmarshaler = &CloudConf{}
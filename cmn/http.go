@@ -41,11 +41,13 @@ const (
 	HeaderContentRange          = "Content-Range"
 	HeaderContentRangeValPrefix = "bytes " // Ref: https://tools.ietf.org/html/rfc7233#section-4.2
 	HeaderAcceptRanges          = "Accept-Ranges"
+	HeaderIfRange               = "If-Range" // Ref: https://tools.ietf.org/html/rfc7233#section-3.2
 	HeaderContentType           = "Content-Type"
 	HeaderContentLength         = "Content-Length"
 	HeaderAccept                = "Accept"
 	HeaderLocation              = "Location"
 	HeaderETag                  = "ETag" // Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/ETag
+	HeaderLastModified          = "Last-Modified"
 )
 
 // Ref: https://www.iana.org/assignments/media-types/media-types.xhtml
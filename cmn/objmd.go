@@ -0,0 +1,72 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// User-defined (custom) object metadata: key/value pairs attached to an object
+// upon PUT (or updated in place - see ActUpdateObjMD) and persisted in its xattr
+// along with the system metadata. Keys are case-insensitive (lowercased).
+
+// UserMDMaxSize is the max total size of the user metadata (keys and values) - as in S3.
+const UserMDMaxSize = 2 * KiB
+
+func isUserMDKeyChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.'
+}
+
+// ValidateUserMD lowercases the keys and checks that they are non-empty and
+// consist of letters, digits, '-', '_', and '.' only, that the values contain
+// no control characters, and that the total size does not exceed UserMDMaxSize.
+func ValidateUserMD(md SimpleKVs) (SimpleKVs, error) {
+	var (
+		size  int
+		lower = make(SimpleKVs, len(md))
+	)
+	for k, v := range md {
+		key := strings.ToLower(k)
+		if key == "" || strings.IndexFunc(key, func(c rune) bool { return !isUserMDKeyChar(c) }) >= 0 {
+			return nil, fmt.Errorf("invalid user metadata key %q", k)
+		}
+		if strings.IndexFunc(v, func(c rune) bool { return c < 0x20 || c == 0x7f }) >= 0 {
+			return nil, fmt.Errorf("invalid user metadata value for key %q", k)
+		}
+		size += len(key) + len(v)
+		lower[key] = v
+	}
+	if size > UserMDMaxSize {
+		return nil, fmt.Errorf("user metadata size (%d) exceeds the maximum (%d)", size, UserMDMaxSize)
+	}
+	return lower, nil
+}
+
+// UserMDFromHdr parses and validates user metadata from the `HeaderObjUserMD`
+// request header(s) - one "key=value" pair per header value.
+func UserMDFromHdr(hdr http.Header) (md SimpleKVs, err error) {
+	entries := hdr[http.CanonicalHeaderKey(HeaderObjUserMD)]
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	md = make(SimpleKVs, len(entries))
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid user metadata %q (expecting key=value)", entry)
+		}
+		md[kv[0]] = kv[1]
+	}
+	return ValidateUserMD(md)
+}
+
+// UserMDToHdr adds user metadata to the header (see UserMDFromHdr).
+func UserMDToHdr(hdr http.Header, md SimpleKVs) {
+	for k, v := range md {
+		hdr.Add(HeaderObjUserMD, k+"="+v)
+	}
+}
@@ -89,6 +89,48 @@ func (pt *ParsedTemplate) Iter() func() (string, bool) {
 	}
 }
 
+// Index returns the position of the name in the sequence generated by Iter,
+// or false if the template does not generate the name. Note that the names
+// generated by unpadded templates are not sorted: "a-{1..10}" yields "a-10"
+// last, though "a-10" < "a-9".
+func (pt *ParsedTemplate) Index(name string) (int64, bool) {
+	if !strings.HasPrefix(name, pt.Prefix) {
+		return 0, false
+	}
+	return pt.index(name[len(pt.Prefix):], 0)
+}
+
+func (pt *ParsedTemplate) index(s string, i int) (int64, bool) {
+	if i == len(pt.Ranges) {
+		return 0, s == ""
+	}
+	var (
+		tr    = pt.Ranges[i]
+		count = int64(1) // number of names generated by the remaining ranges
+	)
+	for _, next := range pt.Ranges[i+1:] {
+		count *= (next.End-next.Start)/next.Step + 1
+	}
+	// Unpadded number may be followed by digits (eg. "{1..20}{0..9}"), hence
+	// trying all the lengths.
+	for n := 1; n <= len(s) && unicode.IsDigit(rune(s[n-1])); n++ {
+		v, err := strconv.ParseInt(s[:n], 10, 64)
+		if err != nil {
+			break
+		}
+		if v < tr.Start || v > tr.End || (v-tr.Start)%tr.Step != 0 {
+			continue
+		}
+		if fmt.Sprintf("%0*d", tr.DigitCount, v) != s[:n] || !strings.HasPrefix(s[n:], tr.Gap) {
+			continue
+		}
+		if rest, ok := pt.index(s[n+len(tr.Gap):], i+1); ok {
+			return (v-tr.Start)/tr.Step*count + rest, true
+		}
+	}
+	return 0, false
+}
+
 func ParseFmtTemplate(template string) (pt ParsedTemplate, err error) {
 	// "prefix-%06d-suffix"
 
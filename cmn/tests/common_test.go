@@ -465,6 +465,39 @@ var _ = Describe("Common file", func() {
 		)
 	})
 
+	Context("ParsedTemplate.Index", func() {
+		DescribeTable("index of every generated name is its position",
+			func(template string) {
+				pt, err := cmn.ParseBashTemplate(template)
+				Expect(err).ShouldNot(HaveOccurred())
+				for i, name := range pt.ToSlice() {
+					idx, ok := pt.Index(name)
+					Expect(ok).To(BeTrue(), name)
+					Expect(idx).To(BeEquivalentTo(i), name)
+				}
+			},
+			Entry("padded", "prefix-{0010..0111..2}-suffix"),
+			Entry("unpadded", "obj-{1..100}"),
+			Entry("unpadded adjacent ranges", "obj-{1..20}{0..9}"),
+			Entry("multi-range", "prefix-{0010..0111..2}-gap-{8..12}-gap2-{0040..0099..4}-suffix"),
+		)
+
+		DescribeTable("names not generated by the template",
+			func(template, name string) {
+				pt, err := cmn.ParseBashTemplate(template)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, ok := pt.Index(name)
+				Expect(ok).To(BeFalse())
+			},
+			Entry("other prefix", "obj-{1..100}", "file-1"),
+			Entry("out of range", "obj-{1..100}", "obj-101"),
+			Entry("off step", "obj-{1..100..3}", "obj-3"),
+			Entry("not padded", "obj-{001..100}", "obj-1"),
+			Entry("padded", "obj-{1..100}", "obj-01"),
+			Entry("trailing characters", "obj-{1..100}", "obj-1.tar"),
+		)
+	})
+
 	Context("ParseAtTemplate", func() {
 		DescribeTable("parse at template without error",
 			func(template string, expectedPt cmn.ParsedTemplate) {
@@ -8,8 +8,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,8 +26,27 @@ const (
 	DlTypeRange  DlType = "range"
 	DlTypeMulti  DlType = "multi"
 	DlTypeCloud  DlType = "cloud"
+
+	DlTypeManifest DlType = "manifest"
+	DlTypeCrawl    DlType = "crawl"
+)
+
+// Priority of the download job - the joggers share the bandwidth between the
+// running jobs proportionally to their priorities.
+const (
+	DlPriorityMin     = 1
+	DlPriorityDefault = 5 // used when priority is not specified
+	DlPriorityMax     = 10
 )
 
+const (
+	DlManifestCSV   = "csv"
+	DlManifestJSONL = "jsonl"
+)
+
+// DlCrawlMaxDepth limits the depth of the crawl, see DlCrawlBody.
+const DlCrawlMaxDepth = 64
+
 type (
 	DlType string
 
@@ -50,6 +72,8 @@ type (
 		Total         int       `json:"total"`          // total number of tasks, negative if unknown
 		AllDispatched bool      `json:"all_dispatched"` // if true, dispatcher has already scheduled all tasks for given job
 		Aborted       bool      `json:"aborted"`
+		Paused        bool      `json:"paused,omitempty"`
+		Priority      int       `json:"priority,omitempty"`
 		StartedTime   time.Time `json:"started_time"`
 		FinishedTime  time.Time `json:"finished_time"`
 	}
@@ -72,6 +96,10 @@ func (j *DlJobInfo) Aggregate(rhs *DlJobInfo) {
 	j.Total += rhs.Total
 	j.AllDispatched = j.AllDispatched && rhs.AllDispatched
 	j.Aborted = j.Aborted || rhs.Aborted
+	j.Paused = j.Paused || rhs.Paused
+	if j.Priority < rhs.Priority {
+		j.Priority = rhs.Priority
+	}
 	if j.StartedTime.After(rhs.StartedTime) {
 		j.StartedTime = rhs.StartedTime
 	}
@@ -147,6 +175,9 @@ func (j DlJobInfo) String() string {
 		sb.WriteString("finished")
 	} else {
 		sb.WriteString(fmt.Sprintf("%d files still being downloaded", j.PendingCnt()))
+		if j.Paused {
+			sb.WriteString(" (paused)")
+		}
 	}
 	return sb.String()
 }
@@ -184,10 +215,16 @@ type DlLimits struct {
 }
 
 type DlBase struct {
-	Description string   `json:"description"`
-	Bck         cmn.Bck  `json:"bucket"`
-	Timeout     string   `json:"timeout"`
-	Limits      DlLimits `json:"limits"`
+	Description string      `json:"description"`
+	Bck         cmn.Bck     `json:"bucket"`
+	Timeout     string      `json:"timeout"`
+	Limits      DlLimits    `json:"limits"`
+	Priority    int         `json:"priority,omitempty"` // DlPriorityMin..DlPriorityMax, DlPriorityDefault if not set
+	Schedule    *DlSchedule `json:"schedule,omitempty"` // if set, the job is recurring (see DlSchedule)
+	// Credentials to access the source, see DlCredentials.
+	Credentials *DlCredentials `json:"credentials,omitempty"`
+	// If set, the archives are extracted, see DlExtract.
+	Extract *DlExtract `json:"extract,omitempty"`
 }
 
 func (b *DlBase) Validate() error {
@@ -205,9 +242,219 @@ func (b *DlBase) Validate() error {
 	if b.Limits.BytesPerHour < 0 {
 		return fmt.Errorf("'limit.bytes_per_hour' must be non-negative (got: %d)", b.Limits.BytesPerHour)
 	}
+	if b.Priority != 0 {
+		if err := ValidatePriority(b.Priority); err != nil {
+			return err
+		}
+	}
+	if b.Schedule != nil {
+		if err := b.Schedule.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.Credentials != nil {
+		if err := b.Credentials.Validate(); err != nil {
+			return err
+		}
+	}
+	if b.Extract != nil {
+		if err := b.Extract.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidatePriority(priority int) error {
+	if priority < DlPriorityMin || priority > DlPriorityMax {
+		return fmt.Errorf("'priority' must be in range [%d, %d] (got: %d)", DlPriorityMin, DlPriorityMax, priority)
+	}
+	return nil
+}
+
+// DlCredentials are used to access the private source: they are applied to
+// each request made to the source (the links, not the cloud buckets) but
+// never returned by the status or list requests and stored only encrypted.
+// At most one of basic auth, bearer token, and S3 credentials can be provided.
+type DlCredentials struct {
+	Headers     map[string]string `json:"headers,omitempty"` // custom HTTP headers, eg. "X-Api-Key"
+	Username    string            `json:"username,omitempty"`
+	Password    string            `json:"password,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	S3          *DlS3Credentials  `json:"s3,omitempty"`
+}
+
+// DlS3Credentials make the requests signed with AWS Signature Version 4. The
+// links with "s3://bucket/object" form are then downloaded from the endpoint.
+type DlS3Credentials struct {
+	AccessKey    string `json:"access_key"`
+	SecretKey    string `json:"secret_key"`
+	SessionToken string `json:"session_token,omitempty"`
+	Region       string `json:"region,omitempty"`   // "us-east-1" if not set
+	Endpoint     string `json:"endpoint,omitempty"` // S3-compatible endpoint, eg. "https://minio.local:9000"
+}
+
+func (c *DlCredentials) Validate() error {
+	var auth []string
+	if c.Username != "" || c.Password != "" {
+		if c.Username == "" {
+			return errors.New("missing 'credentials.username'")
+		}
+		auth = append(auth, "basic auth")
+	}
+	if c.BearerToken != "" {
+		auth = append(auth, "bearer token")
+	}
+	if c.S3 != nil {
+		if err := c.S3.Validate(); err != nil {
+			return err
+		}
+		auth = append(auth, "s3")
+	}
+	if len(auth) > 1 {
+		return fmt.Errorf("at most one type of authentication can be provided in 'credentials' (got: %s)",
+			strings.Join(auth, ", "))
+	}
+	for name := range c.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q in 'credentials.headers'", name)
+		}
+		if len(auth) > 0 && http.CanonicalHeaderKey(name) == cmn.HeaderAuthorization {
+			return fmt.Errorf("%q header cannot be provided together with %s", name, auth[0])
+		}
+	}
+	return nil
+}
+
+// String makes sure that the credentials are not logged.
+func (c DlCredentials) String() string { return "<credentials>" }
+
+func (c *DlS3Credentials) Validate() error {
+	if c.AccessKey == "" || c.SecretKey == "" {
+		return errors.New("'credentials.s3.access_key' and 'credentials.s3.secret_key' must be provided")
+	}
+	if c.Endpoint != "" {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid 'credentials.s3.endpoint' %q (expected http(s)://host[:port])", c.Endpoint)
+		}
+	}
+	return nil
+}
+
+// DlExtract makes the downloaded archives (.tar, .tgz, .tar.gz, .zip) to be
+// extracted on the fly: instead of the archive, each file it contains is
+// stored as a separate object named "<prefix>/<name of the file>".
+type DlExtract struct {
+	// Prefix of the extracted objects; if not set, the name of the archive
+	// without extension, eg. "train-0001/" for "train-0001.tar".
+	Prefix string `json:"prefix,omitempty"`
+	// Only the files with names matching the regex are extracted (all if not set).
+	Regex string `json:"regex,omitempty"`
+	// If set, the directories of the files in the archive are omitted.
+	Flatten bool `json:"flatten,omitempty"`
+	// One of: ".tar", ".tgz", ".tar.gz", ".zip" - determined by the
+	// extension of the link if not set.
+	Format string `json:"format,omitempty"`
+}
+
+func (e *DlExtract) Validate() error {
+	if e.Regex != "" {
+		if _, err := regexp.CompilePOSIX(e.Regex); err != nil {
+			return fmt.Errorf("invalid 'extract.regex': %v", err)
+		}
+	}
+	switch e.Format {
+	case "", cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip:
+	default:
+		return fmt.Errorf("invalid 'extract.format' %q, expected one of: %q, %q, %q, %q",
+			e.Format, cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip)
+	}
+	if strings.HasPrefix(e.Prefix, "/") || strings.Contains("/"+e.Prefix+"/", "/../") {
+		return fmt.Errorf("invalid 'extract.prefix' %q", e.Prefix)
+	}
+	return nil
+}
+
+// DlSchedule makes the download job recurring: the job is run either every
+// `Interval` (starting right away) or at the times matching `Cron` spec.
+// Each run is a separate download job which skips the objects that have not
+// changed since the previous runs (see compareObjects).
+type DlSchedule struct {
+	Cron     string `json:"cron,omitempty"`     // "minute hour day-of-month month day-of-week" (UTC), eg. "0 3 * * 1"
+	Interval string `json:"interval,omitempty"` // eg. "12h"
+	// If set, the objects downloaded by the previous runs which are no longer
+	// listed by the job are deleted from the bucket (for cloud download it is
+	// the same as `sync`).
+	Sync bool `json:"sync,omitempty"`
+}
+
+func (s *DlSchedule) Validate() error {
+	if (s.Cron == "") == (s.Interval == "") {
+		return errors.New("exactly one of 'schedule.cron' and 'schedule.interval' must be provided")
+	}
+	if s.Cron != "" {
+		if _, err := parseCron(s.Cron); err != nil {
+			return fmt.Errorf("invalid 'schedule.cron' %q: %v", s.Cron, err)
+		}
+		return nil
+	}
+	interval, err := time.ParseDuration(s.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid 'schedule.interval': %v", err)
+	}
+	if interval < minScheduleInterval {
+		return fmt.Errorf("'schedule.interval' must be at least %v (got: %v)", minScheduleInterval, interval)
+	}
 	return nil
 }
 
+func (s DlSchedule) String() string {
+	if s.Cron != "" {
+		return fmt.Sprintf("cron %q", s.Cron)
+	}
+	return "every " + s.Interval
+}
+
+// Summary info of the scheduled (recurring) download
+type DlScheduleInfo struct {
+	ID          string      `json:"id"`
+	Description string      `json:"description"`
+	Type        DlType      `json:"type"`
+	Schedule    DlSchedule  `json:"schedule"`
+	Created     time.Time   `json:"created"`
+	NextRun     time.Time   `json:"next_run"`
+	Runs        []DlJobInfo `json:"runs,omitempty"` // history of the runs, the most recent last
+}
+
+// Aggregate merges the info reported by another target - the runs with the
+// same ID (runs are started at the same time on all targets) are aggregated.
+func (s *DlScheduleInfo) Aggregate(rhs *DlScheduleInfo) {
+	if s.NextRun.IsZero() || (!rhs.NextRun.IsZero() && rhs.NextRun.Before(s.NextRun)) {
+		s.NextRun = rhs.NextRun
+	}
+	for i := range rhs.Runs {
+		found := false
+		for j := range s.Runs {
+			if s.Runs[j].ID == rhs.Runs[i].ID {
+				s.Runs[j].Aggregate(&rhs.Runs[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.Runs = append(s.Runs, rhs.Runs[i])
+		}
+	}
+	sort.Slice(s.Runs, func(i, j int) bool { return s.Runs[i].StartedTime.Before(s.Runs[j].StartedTime) })
+}
+
+type DlScheduleInfos []*DlScheduleInfo
+
+func (d DlScheduleInfos) Len() int           { return len(d) }
+func (d DlScheduleInfos) Less(i, j int) bool { return d[i].Created.Before(d[j].Created) }
+func (d DlScheduleInfos) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
 type DlSingleObj struct {
 	ObjName   string `json:"object_name"`
 	Link      string `json:"link"`
@@ -233,8 +480,19 @@ func (b *DlSingleObj) Validate() error {
 
 // Internal status/delete request body
 type DlAdminBody struct {
-	ID    string `json:"id"`
-	Regex string `json:"regex"`
+	ID       string `json:"id"`
+	Regex    string `json:"regex"`
+	Priority int    `json:"priority,omitempty"` // new priority of the job (see DlPriorityMin, DlPriorityMax)
+}
+
+func (b *DlAdminBody) String() string {
+	if b.Priority != 0 {
+		return fmt.Sprintf("id: %q, priority: %d", b.ID, b.Priority)
+	}
+	if b.Regex != "" {
+		return fmt.Sprintf("regex: %q", b.Regex)
+	}
+	return fmt.Sprintf("id: %q", b.ID)
 }
 
 func (b *DlAdminBody) Validate(requireID bool) error {
@@ -261,6 +519,8 @@ type TaskDlInfo struct {
 	Name       string    `json:"name"`
 	Downloaded int64     `json:"downloaded,string"`
 	Total      int64     `json:"total,string,omitempty"`
+	Resumed    int       `json:"resumed,omitempty"`   // number of times the download was resumed with range request
+	Extracted  int       `json:"extracted,omitempty"` // number of files extracted from the archive (see DlExtract)
 	StartTime  time.Time `json:"start_time,omitempty"`
 	EndTime    time.Time `json:"end_time,omitempty"`
 	Running    bool      `json:"running"`
@@ -398,6 +658,108 @@ func (b *DlMultiBody) String() string {
 	return fmt.Sprintf("bucket: %q", b.Bck)
 }
 
+// Manifest request - objects to download (along with their metadata) are
+// listed in the manifest, see DlManifestEntry.
+type DlManifestBody struct {
+	DlBase
+	// If set, the manifest is the object stored in this bucket; otherwise,
+	// the manifest is the link to download it from.
+	ManifestBck cmn.Bck `json:"manifest_bucket"`
+	Manifest    string  `json:"manifest"`
+	// Format of the manifest: "csv" or "jsonl" (by default, determined by
+	// the extension of the manifest).
+	Format string `json:"format"`
+}
+
+func (b *DlManifestBody) Validate() error {
+	if err := b.DlBase.Validate(); err != nil {
+		return err
+	}
+	if b.Manifest == "" {
+		return errors.New("missing 'manifest' in the request body")
+	}
+	if b.Format == "" {
+		switch strings.ToLower(path.Ext(strings.SplitN(b.Manifest, "?", 2)[0])) {
+		case ".csv":
+			b.Format = DlManifestCSV
+		case ".jsonl", ".ndjson":
+			b.Format = DlManifestJSONL
+		default:
+			return fmt.Errorf("cannot determine format of the manifest %q, 'format' must be one of: %q, %q",
+				b.Manifest, DlManifestCSV, DlManifestJSONL)
+		}
+	}
+	if b.Format != DlManifestCSV && b.Format != DlManifestJSONL {
+		return fmt.Errorf("invalid 'format' %q, expected one of: %q, %q", b.Format, DlManifestCSV, DlManifestJSONL)
+	}
+	return nil
+}
+
+func (b *DlManifestBody) Describe() string {
+	if b.Description != "" {
+		return b.Description
+	}
+	return fmt.Sprintf("%s -> %s", b.manifestName(), b.Bck)
+}
+
+func (b *DlManifestBody) String() string {
+	return fmt.Sprintf("bucket: %q, manifest: %q", b.Bck, b.manifestName())
+}
+
+func (b *DlManifestBody) manifestName() string {
+	if b.ManifestBck.Name == "" {
+		return b.Manifest
+	}
+	return b.ManifestBck.String() + "/" + b.Manifest
+}
+
+// Crawl request - the objects to download are found by crawling the HTTP
+// directory index (eg. nginx or Apache autoindex) or the sitemap, starting
+// at `Link`. Only the links under the directory of `Link` are followed, and
+// the objects are named after their paths relative to that directory.
+type DlCrawlBody struct {
+	DlBase
+	Link   string `json:"link"`
+	Subdir string `json:"subdir"` // the objects are stored in this subdirectory of the bucket
+	// Maximal depth of the crawl: 1 - only the files listed by `Link`,
+	// 2 - also the ones in its subdirectories, etc. (DlCrawlMaxDepth if not set).
+	Depth int `json:"depth,omitempty"`
+	// Shell patterns (see `path.Match`) of the files to download and of the
+	// files and directories to skip. The pattern with "/" is matched against
+	// the relative path, otherwise against the base name.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+func (b *DlCrawlBody) Validate() error {
+	if err := b.DlBase.Validate(); err != nil {
+		return err
+	}
+	if b.Link == "" {
+		return errors.New("missing 'link' in the request body")
+	}
+	u, err := url.Parse(cmn.PrependProtocol(b.Link))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid 'link' %q (expected http(s) link)", b.Link)
+	}
+	if b.Depth < 0 || b.Depth > DlCrawlMaxDepth {
+		return fmt.Errorf("'depth' must be in range [0, %d] (got: %d)", DlCrawlMaxDepth, b.Depth)
+	}
+	for _, pattern := range append(b.Include, b.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (b *DlCrawlBody) Describe() string {
+	if b.Description != "" {
+		return b.Description
+	}
+	return fmt.Sprintf("crawl %s -> %s", b.Link, b.Bck)
+}
+
 // Cloud request
 type DlCloudBody struct {
 	DlBase
@@ -410,6 +772,9 @@ func (b *DlCloudBody) Validate() error {
 	if err := b.DlBase.Validate(); err != nil {
 		return err
 	}
+	if b.Extract != nil {
+		return errors.New("'extract' is not supported by cloud download")
+	}
 	return nil
 }
 
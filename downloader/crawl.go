@@ -0,0 +1,260 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+)
+
+const crawlMaxPageSize = 32 * cmn.MiB // index pages (and sitemaps) are read up to this size
+
+// Links of the directory index. The index pages (nginx, Apache, and the like)
+// are generated, so there is no need to parse HTML in its full generality.
+var crawlHrefRegex = regexp.MustCompile(`(?i)<a\s[^>]*?href\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+
+type (
+	// crawler finds the files to download by crawling the directory index
+	// (depth-first, in the order of the index) or the sitemap, see DlCrawlBody.
+	crawler struct {
+		payload  *DlCrawlBody
+		root     *url.URL // the directory of the crawled link
+		rootPath string   // escaped path of `root`, always ends with "/"
+		depth    int
+		timeout  time.Duration
+		pending  []crawlPage         // stack of the pages to crawl
+		files    []crawlFile         // files found on the last crawled page, not yet returned
+		visited  map[string]struct{} // crawled pages (not the files, to keep it small)
+	}
+
+	crawlPage struct {
+		u     *url.URL
+		depth int // 1 for the crawled link
+	}
+
+	crawlFile struct {
+		link string
+		rel  string // escaped path relative to the root directory
+	}
+
+	sitemapXML struct {
+		URLs     []sitemapLoc `xml:"url"`
+		Sitemaps []sitemapLoc `xml:"sitemap"` // sitemap index
+	}
+	sitemapLoc struct {
+		Loc string `xml:"loc"`
+	}
+)
+
+func newCrawler(payload *DlCrawlBody, timeout time.Duration) (*crawler, error) {
+	u, err := url.Parse(cmn.PrependProtocol(payload.Link))
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery, u.Fragment = "", ""
+	root := *u
+	// The link is either the directory or the page in it (eg. "index.html" or "sitemap.xml").
+	if !strings.HasSuffix(root.Path, "/") {
+		root.Path = path.Dir(root.Path)
+		if root.Path != "/" {
+			root.Path += "/"
+		}
+		root.RawPath = ""
+	}
+	c := &crawler{
+		payload:  payload,
+		root:     &root,
+		rootPath: root.EscapedPath(),
+		depth:    payload.Depth,
+		timeout:  timeout,
+		pending:  []crawlPage{{u: u, depth: 1}},
+		visited:  map[string]struct{}{u.String(): {}},
+	}
+	if c.rootPath == "" {
+		c.rootPath = "/"
+	}
+	if c.depth == 0 {
+		c.depth = DlCrawlMaxDepth
+	}
+	return c, nil
+}
+
+// next returns the next file to download or io.EOF if the crawl is over.
+func (c *crawler) next() (crawlFile, error) {
+	for len(c.files) == 0 {
+		if len(c.pending) == 0 {
+			return crawlFile{}, io.EOF
+		}
+		page := c.pending[len(c.pending)-1]
+		c.pending = c.pending[:len(c.pending)-1]
+		if err := c.crawl(page); err != nil {
+			return crawlFile{}, err
+		}
+	}
+	file := c.files[0]
+	c.files = c.files[1:]
+	return file, nil
+}
+
+func (c *crawler) crawl(page crawlPage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	req, err := newSourceRequest(ctx, http.MethodGet, page.u.String(), c.payload.Credentials)
+	if err != nil {
+		return err
+	}
+	resp, err := clientForURL(req.URL.String()).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to crawl %q: %v", page.u, err)
+	}
+	defer func() {
+		debug.AssertNoErr(resp.Body.Close())
+	}()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to crawl %q: %d status code (%s)",
+			page.u, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	body := io.LimitReader(resp.Body, crawlMaxPageSize)
+	if strings.Contains(resp.Header.Get(cmn.HeaderContentType), "xml") || strings.HasSuffix(page.u.Path, ".xml") {
+		return c.parseSitemap(page, body)
+	}
+	return c.parseIndex(page, body)
+}
+
+// parseIndex collects the files listed on the index page and schedules
+// crawling of its subdirectories (the links ending with "/").
+func (c *crawler) parseIndex(page crawlPage, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to crawl %q: %v", page.u, err)
+	}
+	var (
+		dirs []crawlPage
+		seen = make(map[string]struct{}) // the same file can be linked more than once
+	)
+	for _, match := range crawlHrefRegex.FindAllSubmatch(b, -1) {
+		href := string(match[1]) + string(match[2]) + string(match[3]) // only one is not empty
+		u, rel, ok := c.resolve(page.u, html.UnescapeString(href))
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(u.Path, "/") {
+			if page.depth < c.depth && !c.excluded(strings.TrimSuffix(rel, "/")) && c.visit(u) {
+				dirs = append(dirs, crawlPage{u: u, depth: page.depth + 1})
+			}
+			continue
+		}
+		if _, ok := seen[u.String()]; ok {
+			continue
+		}
+		seen[u.String()] = struct{}{}
+		c.addFile(u, rel)
+	}
+	// In reverse, so that the subdirectories are crawled in the order of the index.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		c.pending = append(c.pending, dirs[i])
+	}
+	return nil
+}
+
+// parseSitemap collects the files listed in the sitemap and schedules
+// crawling of the sitemaps listed in the sitemap index.
+func (c *crawler) parseSitemap(page crawlPage, r io.Reader) error {
+	var sitemap sitemapXML
+	if err := xml.NewDecoder(r).Decode(&sitemap); err != nil {
+		return fmt.Errorf("failed to parse sitemap %q: %v", page.u, err)
+	}
+	for _, loc := range sitemap.URLs {
+		u, rel, ok := c.resolve(page.u, strings.TrimSpace(loc.Loc))
+		if !ok || strings.HasSuffix(u.Path, "/") {
+			continue
+		}
+		c.addFile(u, rel)
+	}
+	for i := len(sitemap.Sitemaps) - 1; i >= 0; i-- {
+		u, _, ok := c.resolve(page.u, strings.TrimSpace(sitemap.Sitemaps[i].Loc))
+		if ok && page.depth < c.depth && c.visit(u) {
+			c.pending = append(c.pending, crawlPage{u: u, depth: page.depth + 1})
+		}
+	}
+	return nil
+}
+
+// resolve resolves the link found on the page and returns it along with its
+// (unescaped) path relative to the root directory. False is returned if the
+// link is not to be followed: it points outside the root directory (eg. parent
+// directory or another site) or it has a query (eg. sorting the index).
+func (c *crawler) resolve(page *url.URL, href string) (u *url.URL, rel string, ok bool) {
+	ref, err := url.Parse(href)
+	if err != nil || ref.RawQuery != "" {
+		return
+	}
+	u = page.ResolveReference(ref)
+	u.Fragment = ""
+	if u.Scheme != c.root.Scheme || !strings.EqualFold(u.Host, c.root.Host) {
+		return
+	}
+	escaped := u.EscapedPath()
+	if !strings.HasPrefix(escaped, c.rootPath) || len(escaped) == len(c.rootPath) {
+		return
+	}
+	if rel, err = url.PathUnescape(escaped[len(c.rootPath):]); err != nil {
+		return
+	}
+	return u, rel, true
+}
+
+// visit returns false if the page has been already crawled (or scheduled to).
+func (c *crawler) visit(u *url.URL) bool {
+	if _, ok := c.visited[u.String()]; ok {
+		return false
+	}
+	c.visited[u.String()] = struct{}{}
+	return true
+}
+
+func (c *crawler) addFile(u *url.URL, rel string) {
+	if _, ok := c.visited[u.String()]; ok { // eg. link to itself
+		return
+	}
+	if c.excluded(rel) || !c.included(rel) {
+		return
+	}
+	c.files = append(c.files, crawlFile{link: u.String(), rel: u.EscapedPath()[len(c.rootPath):]})
+}
+
+func (c *crawler) included(rel string) bool {
+	return len(c.payload.Include) == 0 || crawlMatch(c.payload.Include, rel)
+}
+
+func (c *crawler) excluded(rel string) bool { return crawlMatch(c.payload.Exclude, rel) }
+
+// crawlMatch matches the pattern with "/" against the relative path,
+// otherwise against the base name.
+func crawlMatch(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		name := rel
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(rel)
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
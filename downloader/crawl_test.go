@@ -0,0 +1,155 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+// Index pages in the style of nginx and Apache autoindex.
+var crawlTestPages = map[string]string{
+	"/data/": `<html><head><title>Index of /data/</title></head><body>
+<a href="?C=N;O=D">Name</a> <a href="?C=M;O=A">Last modified</a>
+<a href="/">Parent Directory</a>
+<a href="../">../</a>
+<a href="train/">train/</a>
+<a href="val/">val/</a>
+<a href="README.md">README.md</a>
+<a href='labels.json'>labels.json</a>
+<a href="http://other.example.com/data/x.tar">mirror</a>
+<a href="a%20b.txt">a b.txt</a>
+</body></html>`,
+	"/data/train/": `<pre><a href="../">../</a>
+<a href="shard-0.tar">shard-0.tar</a>
+<a href="shard-1.tar">shard-1.tar</a>
+<a href="shard-1.tar">shard-1.tar</a>
+<a href="tmp/">tmp/</a>
+<a href="/data/train/">self</a>
+</pre>`,
+	"/data/train/tmp/": `<a href="partial.tar">partial.tar</a>`,
+	"/data/val/":       `<a href="shard-0.tar">shard-0.tar</a><a href="../train/">train</a>`,
+	"/sitemap.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>{{host}}/sitemap-docs.xml</loc></sitemap>
+</sitemapindex>`,
+	"/sitemap-docs.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>{{host}}/docs/intro.html</loc></url>
+  <url><loc>{{host}}/docs/</loc></url>
+  <url><loc> {{host}}/docs/api.html </loc></url>
+  <url><loc>http://other.example.com/docs/x.html</loc></url>
+</urlset>`,
+}
+
+func crawlAll(t *testing.T, payload *DlCrawlBody) []string {
+	c, err := newCrawler(payload, time.Minute)
+	tassert.CheckFatal(t, err)
+	var rels []string
+	for {
+		file, err := c.next()
+		if err == io.EOF {
+			return rels
+		}
+		tassert.CheckFatal(t, err)
+		rels = append(rels, file.rel)
+	}
+}
+
+func TestCrawler(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := crawlTestPages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, strings.ReplaceAll(page, "{{host}}", srv.URL))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		payload  DlCrawlBody
+		expected []string
+	}{
+		{
+			name:    "all",
+			payload: DlCrawlBody{Link: srv.URL + "/data/"},
+			expected: []string{
+				"README.md", "labels.json", "a%20b.txt",
+				"train/shard-0.tar", "train/shard-1.tar", "train/tmp/partial.tar",
+				"val/shard-0.tar",
+			},
+		},
+		{
+			name:     "depth",
+			payload:  DlCrawlBody{Link: srv.URL + "/data/", Depth: 2},
+			expected: []string{"README.md", "labels.json", "a%20b.txt", "train/shard-0.tar", "train/shard-1.tar", "val/shard-0.tar"},
+		},
+		{
+			name:     "include",
+			payload:  DlCrawlBody{Link: srv.URL + "/data/", Include: []string{"*.tar"}, Exclude: []string{"tmp"}},
+			expected: []string{"train/shard-0.tar", "train/shard-1.tar", "val/shard-0.tar"},
+		},
+		{
+			name:     "include path",
+			payload:  DlCrawlBody{Link: srv.URL + "/data/", Include: []string{"train/*"}},
+			expected: []string{"train/shard-0.tar", "train/shard-1.tar"},
+		},
+		{
+			name:     "subdirectory",
+			payload:  DlCrawlBody{Link: srv.URL + "/data/train/", Exclude: []string{"shard-0.tar"}},
+			expected: []string{"shard-1.tar", "tmp/partial.tar"},
+		},
+		{
+			name:     "sitemap",
+			payload:  DlCrawlBody{Link: srv.URL + "/sitemap.xml"},
+			expected: []string{"docs/intro.html", "docs/api.html"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rels := crawlAll(t, &test.payload)
+			tassert.Errorf(t, reflect.DeepEqual(rels, test.expected), "expected %v, got %v", test.expected, rels)
+		})
+	}
+
+	c, err := newCrawler(&DlCrawlBody{Link: srv.URL + "/missing/"}, time.Minute)
+	tassert.CheckFatal(t, err)
+	_, err = c.next()
+	tassert.Errorf(t, err != nil && err != io.EOF, "expected crawl of missing page to fail")
+}
+
+func TestCrawlValidate(t *testing.T) {
+	tests := []struct {
+		body  DlCrawlBody
+		valid bool
+	}{
+		{body: DlCrawlBody{Link: "http://example.com/data/"}, valid: true},
+		{body: DlCrawlBody{Link: "example.com/data/", Depth: 3, Include: []string{"*.tar"}}, valid: true},
+		{body: DlCrawlBody{}, valid: false},
+		{body: DlCrawlBody{Link: "ftp://example.com/data/"}, valid: false},
+		{body: DlCrawlBody{Link: "http://example.com/data/", Depth: -1}, valid: false},
+		{body: DlCrawlBody{Link: "http://example.com/data/", Depth: DlCrawlMaxDepth + 1}, valid: false},
+		{body: DlCrawlBody{Link: "http://example.com/data/", Exclude: []string{"[a-"}}, valid: false},
+	}
+	for _, test := range tests {
+		test.body.Bck.Name = "bck"
+		err := test.body.Validate()
+		if test.valid {
+			tassert.Errorf(t, err == nil, "expected %+v to be valid, got: %v", test.body, err)
+		} else {
+			tassert.Errorf(t, err != nil, "expected %+v to be invalid", test.body)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+const (
+	// The key which encrypts the credentials stored in the downloader DB, kept
+	// next to the DB (in `confdir`) and readable only by the owner.
+	credsKeyFname = "downloader.key"
+	credsKeySize  = 32 // AES-256
+
+	s3DefaultRegion = "us-east-1"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3EmptyHash     = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" // SHA-256 of empty payload
+)
+
+var (
+	credsKey     []byte
+	credsKeyErr  error
+	credsKeyOnce sync.Once
+)
+
+// newSourceRequest creates the request to the source - the link is resolved
+// and the credentials (if any) are applied.
+func newSourceRequest(ctx context.Context, method, link string, creds *DlCredentials) (*http.Request, error) {
+	if creds != nil && creds.S3 != nil {
+		link = creds.S3.resolveLink(link)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil {
+		return req, nil
+	}
+	for name, value := range creds.Headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case creds.Username != "":
+		req.SetBasicAuth(creds.Username, creds.Password)
+	case creds.BearerToken != "":
+		req.Header.Set(cmn.HeaderAuthorization, cmn.HeaderBearer+" "+creds.BearerToken)
+	case creds.S3 != nil:
+		creds.S3.sign(req, time.Now())
+	}
+	return req, nil
+}
+
+/////////////////////
+// DlS3Credentials //
+/////////////////////
+
+// resolveLink translates "s3://bucket/object" into the (path-style) link to the endpoint.
+func (c *DlS3Credentials) resolveLink(link string) string {
+	const prefix = cmn.S3Scheme + "://"
+	if !strings.HasPrefix(link, prefix) {
+		return link
+	}
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + c.region() + ".amazonaws.com"
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/" + strings.TrimPrefix(link, prefix)
+}
+
+func (c *DlS3Credentials) region() string {
+	if c.Region == "" {
+		return s3DefaultRegion
+	}
+	return c.Region
+}
+
+// sign signs the request (without body) with AWS Signature Version 4, see:
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+// Signed are `Host` and all `X-Amz-*` headers.
+func (c *DlS3Credentials) sign(req *http.Request, now time.Time) {
+	var (
+		amzDate = now.UTC().Format("20060102T150405Z")
+		scope   = strings.Join([]string{amzDate[:8], c.region(), "s3", "aws4_request"}, "/")
+		host    = req.Host
+	)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3EmptyHash)
+	if c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	uri := req.URL.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, uri, s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(), signedHeaders, s3EmptyHash,
+	}, "\n")
+	reqHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hex.EncodeToString(reqHash[:])}, "\n")
+
+	key := []byte("AWS4" + c.SecretKey)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set(cmn.HeaderAuthorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, c.AccessKey, scope, signedHeaders, signature))
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, s3Escape(name)+"="+s3Escape(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func s3Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+////////////////////////
+// encryption at rest //
+////////////////////////
+
+// sealBody removes the credentials from the request body returning them
+// encrypted (empty if the request has no credentials).
+func sealBody(dlb DlBody) (DlBody, string, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(dlb.RawMessage, &fields); err != nil {
+		return dlb, "", err
+	}
+	creds, ok := fields["credentials"]
+	if !ok {
+		return dlb, "", nil
+	}
+	sealed, err := seal(creds)
+	if err != nil {
+		return dlb, "", err
+	}
+	delete(fields, "credentials")
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return dlb, "", err
+	}
+	return DlBody{Type: dlb.Type, RawMessage: b}, sealed, nil
+}
+
+// unsealBody puts the decrypted credentials back into the request body.
+func unsealBody(dlb DlBody, sealed string) (DlBody, error) {
+	creds, err := unseal(sealed)
+	if err != nil {
+		return dlb, fmt.Errorf("failed to decrypt credentials: %v", err)
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(dlb.RawMessage, &fields); err != nil {
+		return dlb, err
+	}
+	fields["credentials"] = creds
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return dlb, err
+	}
+	return DlBody{Type: dlb.Type, RawMessage: b}, nil
+}
+
+func seal(plaintext []byte) (string, error) {
+	aead, err := credsCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func unseal(sealed string) ([]byte, error) {
+	aead, err := credsCipher()
+	if err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < aead.NonceSize() {
+		return nil, errors.New("sealed credentials too short")
+	}
+	return aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+}
+
+func credsCipher() (cipher.AEAD, error) {
+	credsKeyOnce.Do(func() {
+		credsKey, credsKeyErr = loadCredsKey(filepath.Join(cmn.GCO.Get().Confdir, credsKeyFname))
+	})
+	if credsKeyErr != nil {
+		return nil, credsKeyErr
+	}
+	block, err := aes.NewCipher(credsKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadCredsKey reads the key, generates new one on first use.
+func loadCredsKey(fqn string) ([]byte, error) {
+	key, err := ioutil.ReadFile(fqn)
+	if err == nil {
+		if len(key) != credsKeySize {
+			return nil, fmt.Errorf("invalid size of %q: %d (expected %d)", fqn, len(key), credsKeySize)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key = make([]byte, credsKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(fqn, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
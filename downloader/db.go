@@ -11,11 +11,13 @@ import (
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/dbdriver"
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
 	downloaderErrors     = "errors"
 	downloaderTasks      = "tasks"
+	downloaderSchedules  = "schedules"
 	downloaderCollection = "downloads"
 
 	// Number of errors stored in memory. When the number of errors exceeds
@@ -184,3 +186,47 @@ func (db *downloaderDB) delete(id string) {
 	db.driver.Delete(downloaderCollection, key)
 	db.mtx.Unlock()
 }
+
+func (db *downloaderDB) setSchedule(rec *dlScheduleRecord) (err error) {
+	// Never persist the credentials in plain text.
+	persisted := *rec
+	if persisted.Body, persisted.SealedCreds, err = sealBody(rec.Body); err != nil {
+		return err
+	}
+	key := path.Join(downloaderSchedules, rec.ID)
+	return db.driver.Set(downloaderCollection, key, &persisted)
+}
+
+func (db *downloaderDB) deleteSchedule(id string) {
+	key := path.Join(downloaderSchedules, id)
+	if err := db.driver.Delete(downloaderCollection, key); err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Error(err)
+	}
+}
+
+func (db *downloaderDB) schedules() ([]*dlScheduleRecord, error) {
+	records, err := db.driver.GetAll(downloaderCollection, downloaderSchedules)
+	if err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	schedules := make([]*dlScheduleRecord, 0, len(records))
+	for _, r := range records {
+		rec := &dlScheduleRecord{}
+		if err := jsoniter.Unmarshal([]byte(r), rec); err != nil {
+			glog.Error(err)
+			continue
+		}
+		if rec.SealedCreds != "" {
+			if rec.Body, err = unsealBody(rec.Body, rec.SealedCreds); err != nil {
+				glog.Errorf("download schedule %q: %v", rec.ID, err)
+				continue
+			}
+			rec.SealedCreds = ""
+		}
+		schedules = append(schedules, rec)
+	}
+	return schedules, nil
+}
@@ -48,12 +48,16 @@ type (
 	WebResource struct {
 		ObjName string
 		Link    string
+		meta    *dlObjMeta
+		creds   *DlCredentials
 	}
 
 	DstElement struct {
 		ObjName string
 		Version string
 		Link    string
+		meta    *dlObjMeta
+		creds   *DlCredentials
 	}
 
 	DiffResolverResult struct {
@@ -169,6 +173,8 @@ func (dr *DiffResolver) PushDst(v interface{}) {
 		d = &DstElement{
 			ObjName: x.ObjName,
 			Link:    x.Link,
+			meta:    x.meta,
+			creds:   x.creds,
 		}
 	default:
 		cmn.AssertMsg(false, fmt.Sprintf("%T", x))
@@ -141,6 +141,7 @@ func (d *dispatcher) ScheduleForDownload(job DlJob) {
 func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 	defer func() {
 		d.waitFor(job)
+		d.dropJob(job.ID())
 		job.cleanup()
 		d.cleanUpAborted(job.ID())
 	}()
@@ -233,6 +234,8 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 					diffResolver.PushDst(&WebResource{
 						ObjName: obj.objName,
 						Link:    obj.link,
+						meta:    obj.meta,
+						creds:   job.credentials(),
 					})
 				} else {
 					diffResolver.PushDst(&CloudResource{
@@ -256,6 +259,7 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 					objName:   dst.ObjName,
 					link:      dst.Link,
 					fromCloud: dst.Link == "",
+					meta:      dst.meta,
 				}
 			} else {
 				src := result.Src
@@ -371,11 +375,15 @@ func (d *dispatcher) blockingDispatchDownloadSingle(task *singleObjectTask) (err
 		return nil, true
 	}
 
-	// Secondly, try to push the new task into queue.
+	// Secondly, try to push the new task into queue. The jogger decides which
+	// job's task goes next (see `queue`) so we only wait for the free slot.
 	select {
 	// FIXME: if this particular jogger is full, but others are available, dispatcher
 	//  will wait with dispatching all of the requests anyway
-	case jogger.putCh(task) <- task:
+	case jogger.slotCh(task) <- struct{}{}:
+		if !jogger.put(task) {
+			task.job.throttler().release()
+		}
 		return nil, true
 	case <-d.jobAbortedCh(task.job.ID()).Listen():
 		task.job.throttler().release()
@@ -419,6 +427,57 @@ func (d *dispatcher) dispatchAbort(req *request) {
 	req.writeResp(nil)
 }
 
+// dispatchPause makes the joggers stop taking the tasks of the job - the tasks
+// which are already being downloaded are finished.
+func (d *dispatcher) dispatchPause(req *request) {
+	jInfo, err := d.checkJobRunning(req)
+	if err != nil {
+		return
+	}
+	jInfo.Paused.Store(true)
+	req.writeResp(nil)
+}
+
+func (d *dispatcher) dispatchResume(req *request) {
+	jInfo, err := d.checkJobRunning(req)
+	if err != nil {
+		return
+	}
+	jInfo.Paused.Store(false)
+	d.RLock()
+	for _, j := range d.joggers {
+		j.q.wakeup()
+	}
+	d.RUnlock()
+	req.writeResp(nil)
+}
+
+func (d *dispatcher) dispatchPriority(req *request) {
+	if err := ValidatePriority(req.priority); err != nil {
+		req.writeErrResp(err, http.StatusBadRequest)
+		return
+	}
+	jInfo, err := d.checkJobRunning(req)
+	if err != nil {
+		return
+	}
+	jInfo.Priority.Store(int32(req.priority))
+	req.writeResp(nil)
+}
+
+func (d *dispatcher) checkJobRunning(req *request) (*downloadJobInfo, error) {
+	jInfo, err := d.parent.checkJob(req)
+	if err != nil {
+		return nil, err
+	}
+	if dlInfo := jInfo.ToDlJobInfo(); dlInfo.JobFinished() || dlInfo.Aborted {
+		err := fmt.Errorf("download job %q is not running", jInfo.ID)
+		req.writeErrResp(err, http.StatusBadRequest)
+		return nil, err
+	}
+	return jInfo, nil
+}
+
 func (d *dispatcher) dispatchStatus(req *request) {
 	jInfo, err := d.parent.checkJob(req)
 	if err != nil || jInfo == nil {
@@ -485,6 +544,15 @@ func (d *dispatcher) pending(reqID string) bool {
 	return false
 }
 
+// dropJob removes the (finished) job from joggers' queues.
+func (d *dispatcher) dropJob(jobID string) {
+	d.RLock()
+	for _, j := range d.joggers {
+		j.q.dropJob(jobID)
+	}
+	d.RUnlock()
+}
+
 func (d *dispatcher) waitFor(job DlJob) {
 	// PRECONDITION: all tasks should be dispatched.
 	ticker := time.NewTicker(500 * time.Millisecond)
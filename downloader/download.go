@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -37,16 +38,21 @@ import (
 //   * Download    - to download a new object from a URL
 //   * Abort       - to abort a previously requested download (currently queued or currently downloading)
 //   * Status      - to request the status of a previously requested download
+//   * Pause       - to pause (and Resume) a download without aborting it
+//   * SetPriority - to change the priority of a download
+//   * Schedule    - to register a recurring download (see DlSchedule)
 // The Download, Abort and Status requests are encapsulated into an internal
 // request object, added to a dispatcher's request queue and then are dispatched by dispatcher
 // to the correct jogger. The remaining operations are private to the Downloader and
 // are used only internally. Dispatcher is implemented as goroutine listening for
 // incoming requests from Downloader
 //
-// Each jogger, which corresponds to one mountpath, has a download channel
-// (downloadCh) where download requests, that are dispatched from Dispatcher, are
-// queued. Thus, downloads occur on a per-mountpath basis and are handled one at
-// a time by jogger as they arrive.
+// Each jogger, which corresponds to one mountpath, has a queue where download
+// requests, that are dispatched from Dispatcher, are queued per job. Thus,
+// downloads occur on a per-mountpath basis and are handled one at a time by
+// jogger. The jogger takes the requests of the concurrently running jobs in
+// proportion to the jobs' priorities (weighted fair queueing), skipping the
+// paused jobs.
 //
 // ====== Downloading ======
 //
@@ -55,6 +61,8 @@ import (
 // from job in batches. When joggers queues have available space for new objects
 // to download, dispatcher puts objects to download in these queues. If joggers
 // are currently full, dispatcher waits with dispatching next batch until they aren't.
+// Each job has a limited number of slots in jogger's queue so a job with lots of
+// objects to download does not block dispatching of the other jobs.
 //
 // Single object's download is represented as object of `task` type, and there is at
 // most one active task assigned to any jogger at any given time. The
@@ -107,10 +115,13 @@ import (
 // ================================ Summary ====================================
 
 const (
-	actRemove = "REMOVE"
-	actAbort  = "ABORT"
-	actStatus = "STATUS"
-	actList   = "LIST"
+	actRemove   = "REMOVE"
+	actAbort    = "ABORT"
+	actStatus   = "STATUS"
+	actList     = "LIST"
+	actPause    = "PAUSE"
+	actResume   = "RESUME"
+	actPriority = "PRIORITY"
 
 	jobsChSize = 1000
 )
@@ -165,6 +176,7 @@ type (
 		action     string         // one of: adminAbort, adminList, adminStatus, adminRemove
 		id         string         // id of the job task
 		regex      *regexp.Regexp // regex of descriptions to return if id is empty
+		priority   int            // new priority of the job (actPriority)
 		responseCh chan *response // where the outcome of the request is written
 	}
 
@@ -270,6 +282,12 @@ Loop:
 				d.dispatcher.dispatchRemove(req)
 			case actList:
 				d.dispatcher.dispatchList(req)
+			case actPause:
+				d.dispatcher.dispatchPause(req)
+			case actResume:
+				d.dispatcher.dispatchResume(req)
+			case actPriority:
+				d.dispatcher.dispatchPriority(req)
 			default:
 				cmn.AssertFmt(false, req, req.action)
 			}
@@ -378,6 +396,89 @@ func (d *Downloader) ListJobs(regex *regexp.Regexp) (resp interface{}, err error
 	return r.resp, r.err, r.statusCode
 }
 
+// PauseJob stops downloading of the job's objects without aborting the job,
+// the objects which are being downloaded are finished.
+func (d *Downloader) PauseJob(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	req := &request{
+		action:     actPause,
+		id:         id,
+		responseCh: make(chan *response, 1),
+	}
+	d.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	d.DecPending()
+	return r.resp, r.err, r.statusCode
+}
+
+func (d *Downloader) ResumeJob(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	req := &request{
+		action:     actResume,
+		id:         id,
+		responseCh: make(chan *response, 1),
+	}
+	d.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	d.DecPending()
+	return r.resp, r.err, r.statusCode
+}
+
+func (d *Downloader) SetJobPriority(id string, priority int) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	req := &request{
+		action:     actPriority,
+		id:         id,
+		priority:   priority,
+		responseCh: make(chan *response, 1),
+	}
+	d.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	d.DecPending()
+	return r.resp, r.err, r.statusCode
+}
+
+// Schedule registers the recurring download - the job described by `dlb` is
+// then started (by the scheduler, see InitScheduler) whenever it is due.
+func (d *Downloader) Schedule(id string, created time.Time, dlb DlBody) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	defer d.DecPending()
+	if err := dlScheduler.add(id, created, dlb); err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+	return nil, nil, http.StatusOK
+}
+
+func (d *Downloader) ListSchedules(id string, regex *regexp.Regexp) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	defer d.DecPending()
+	schedules := dlScheduler.list(id, regex)
+	if id != "" && len(schedules) == 0 {
+		return nil, fmt.Errorf("download schedule %q not found", id), http.StatusNotFound
+	}
+	return schedules, nil, http.StatusOK
+}
+
+// RemoveSchedule stops further runs of the recurring download. The run in
+// progress, if any, is not aborted.
+func (d *Downloader) RemoveSchedule(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	defer d.DecPending()
+	if err := dlScheduler.remove(id); err != nil {
+		if err == errScheduleNotFound {
+			return nil, fmt.Errorf("download schedule %q not found", id), http.StatusNotFound
+		}
+		return nil, err, http.StatusInternalServerError
+	}
+	return nil, nil, http.StatusOK
+}
+
 func (d *Downloader) checkJob(req *request) (*downloadJobInfo, error) {
 	jInfo, err := dlStore.getJob(req.id)
 	if err != nil {
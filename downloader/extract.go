@@ -0,0 +1,213 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// archiveExtractor is DlExtract of the job with precompiled regex.
+type archiveExtractor struct {
+	DlExtract
+	regex *regexp.Regexp
+}
+
+func newArchiveExtractor(e *DlExtract) *archiveExtractor {
+	if e == nil {
+		return nil
+	}
+	ae := &archiveExtractor{DlExtract: *e}
+	if e.Regex != "" {
+		ae.regex = regexp.MustCompilePOSIX(e.Regex) // validated with the request
+	}
+	return ae
+}
+
+// format returns the format of the archive (as per archive.FormatFromName):
+// either provided with the request or determined by the extension of the link
+// or the object name.
+func (e *archiveExtractor) format(link, objName string) (string, error) {
+	if e.Format != "" {
+		return archive.FormatFromName(e.Format) // validated with the request
+	}
+	if u, err := url.Parse(link); err == nil {
+		if format, err := archive.FormatFromName(u.Path); err == nil {
+			return format, nil
+		}
+	}
+	if format, err := archive.FormatFromName(objName); err == nil {
+		return format, nil
+	}
+	return "", fmt.Errorf("cannot determine format of the archive %q, 'extract.format' must be provided", link)
+}
+
+func (e *archiveExtractor) prefix(objName string) string {
+	if e.Prefix != "" {
+		return e.Prefix
+	}
+	return archive.TrimExt(objName)
+}
+
+// objName returns the name of the object the file of the archive is stored
+// as or false if the file is filtered out.
+func (e *archiveExtractor) objName(prefix, name string) (string, bool) {
+	// Cleaned with leading slash so that the name cannot escape the prefix.
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "", false
+	}
+	if e.regex != nil && !e.regex.MatchString(name) {
+		return "", false
+	}
+	if e.Flatten {
+		name = path.Base(name)
+	}
+	return path.Join(prefix, name), true
+}
+
+// extractArchive stores each (matching) file of the downloaded archive as
+// a separate object. The tarballs are extracted while being downloaded,
+// the zip archive is first stored in the workfile (its directory is at the
+// end). If the checksum of the archive is known, it is validated as well -
+// NOTE: the tarball is validated after its files have already been stored.
+func (t *singleObjectTask) extractArchive(lom *cluster.LOM, r io.Reader, roi remoteObjInfo) error {
+	var (
+		e     = t.job.extractor()
+		expct *cmn.Cksum
+		given *cmn.CksumHash
+	)
+	format, err := e.format(t.obj.link, lom.ObjName)
+	if err != nil {
+		return err
+	}
+	if t.obj.meta != nil && t.obj.meta.cksum != nil {
+		expct = t.obj.meta.cksum
+	} else if lom.CksumConf().ValidateColdGet {
+		expct = roi.cksum()
+	}
+	if expct != nil {
+		given = cmn.NewCksumHash(expct.Type())
+		r = io.TeeReader(r, given.H)
+	}
+	validate := func() error {
+		if given == nil {
+			return nil
+		}
+		given.Finalize()
+		if !given.Equal(expct) {
+			return cmn.NewBadDataCksumError(given.Clone(), expct, t.obj.link+" => "+lom.String())
+		}
+		return nil
+	}
+
+	var (
+		prefix = e.prefix(lom.ObjName)
+		fn     = func(name string, _ int64, r io.Reader) error {
+			objName, ok := e.objName(prefix, name)
+			if !ok {
+				return nil
+			}
+			if err := t.putExtracted(lom.Bck(), objName, r); err != nil {
+				return fmt.Errorf("failed to extract %q from %s: %v", name, t.obj.link, err)
+			}
+			t.extractedCnt.Inc()
+			return nil
+		}
+	)
+	if format != cmn.ExtZip {
+		if err := archive.WalkTar(format, r, fn); err != nil {
+			return err
+		}
+		if given != nil {
+			// Read the rest of the archive (padding) to compute the checksum of the whole.
+			if _, err := io.Copy(ioutil.Discard, r); err != nil {
+				return err
+			}
+		}
+		return validate()
+	}
+
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileDownload)
+	defer func() {
+		if err := cmn.RemoveFile(workFQN); err != nil {
+			glog.Errorf("%s: failed to remove %s, err: %v", t, workFQN, err)
+		}
+	}()
+	file, err := lom.CreateFile(workFQN)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		debug.AssertNoErr(file.Close())
+	}()
+	buf, slab := t.parent.t.GetMMSA().Alloc()
+	size, err := io.CopyBuffer(file, r, buf)
+	slab.Free(buf)
+	if err != nil {
+		return err
+	}
+	if err := validate(); err != nil {
+		return err
+	}
+	return archive.WalkZip(file, size, fn)
+}
+
+// putExtracted stores the file extracted from the archive as the object
+// (possibly on another target) with the checksum as configured for the bucket.
+func (t *singleObjectTask) putExtracted(bck *cluster.Bck, objName string, r io.Reader) error {
+	lom := &cluster.LOM{T: t.parent.t, ObjName: objName}
+	if err := lom.Init(bck.Bck); err != nil {
+		return err
+	}
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileExtract)
+	defer func() {
+		// Promoted locally the workfile is renamed, otherwise it is only sent.
+		if err := cmn.RemoveFile(workFQN); err != nil {
+			glog.Errorf("%s: failed to remove %s, err: %v", t, workFQN, err)
+		}
+	}()
+	file, err := lom.CreateFile(workFQN)
+	if err != nil {
+		return err
+	}
+	var (
+		cksumType = lom.CksumConf().Type
+		cksum     = cmn.NewCksumHash(cksumType)
+		w         = io.Writer(file)
+	)
+	if cksumType != cmn.ChecksumNone {
+		w = cmn.NewWriterMulti(file, cksum.H)
+	}
+	buf, slab := t.parent.t.GetMMSA().Alloc()
+	_, err = io.CopyBuffer(w, r, buf)
+	slab.Free(buf)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return err
+	}
+	var computed *cmn.Cksum
+	if cksumType != cmn.ChecksumNone {
+		cksum.Finalize()
+		computed = cksum.Clone()
+	}
+	_, err = t.parent.t.PromoteFile(workFQN, bck, objName, computed,
+		true /*overwrite*/, false /*safe*/, false /*verbose*/)
+	return err
+}
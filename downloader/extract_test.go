@@ -0,0 +1,99 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestExtractValidate(t *testing.T) {
+	tests := []struct {
+		extract DlExtract
+		valid   bool
+	}{
+		{extract: DlExtract{}, valid: true},
+		{extract: DlExtract{Prefix: "data/", Regex: `\.jpg$`, Flatten: true, Format: cmn.ExtTgz}, valid: true},
+		{extract: DlExtract{Regex: "(unclosed"}, valid: false},
+		{extract: DlExtract{Format: ".rar"}, valid: false},
+		{extract: DlExtract{Prefix: "/abs"}, valid: false},
+		{extract: DlExtract{Prefix: "data/../../etc"}, valid: false},
+	}
+	for _, test := range tests {
+		err := test.extract.Validate()
+		if test.valid {
+			tassert.Errorf(t, err == nil, "expected %+v to be valid, got: %v", test.extract, err)
+		} else {
+			tassert.Errorf(t, err != nil, "expected %+v to be invalid", test.extract)
+		}
+	}
+
+	body := DlCloudBody{DlBase: DlBase{Bck: cmn.Bck{Name: "bck"}, Extract: &DlExtract{}}}
+	tassert.Errorf(t, body.Validate() != nil, "expected extract to be rejected by cloud download")
+}
+
+func TestExtractFormat(t *testing.T) {
+	e := newArchiveExtractor(&DlExtract{})
+	tests := []struct {
+		link, objName, format string
+	}{
+		{link: "http://example.com/shards/train-0001.tar", objName: "train-0001.tar", format: cmn.ExtTar},
+		{link: "http://example.com/train-0001.tar.gz?token=abc", objName: "train-0001.tar.gz", format: cmn.ExtTgz},
+		{link: "http://example.com/download?id=1", objName: "bundle.zip", format: cmn.ExtZip},
+		{link: "http://example.com/download?id=1", objName: "download", format: ""},
+	}
+	for _, test := range tests {
+		format, err := e.format(test.link, test.objName)
+		if test.format == "" {
+			tassert.Errorf(t, err != nil, "expected format of %q to be undetermined, got: %q", test.link, format)
+			continue
+		}
+		tassert.CheckError(t, err)
+		tassert.Errorf(t, format == test.format, "link %q: expected %q, got %q", test.link, test.format, format)
+	}
+
+	e = newArchiveExtractor(&DlExtract{Format: cmn.ExtZip})
+	format, err := e.format("http://example.com/bundle.tar", "bundle.tar")
+	tassert.CheckError(t, err)
+	tassert.Errorf(t, format == cmn.ExtZip, "expected format provided with the request, got %q", format)
+
+	e = newArchiveExtractor(&DlExtract{Format: cmn.ExtTarTgz})
+	format, err = e.format("http://example.com/download?id=1", "download")
+	tassert.CheckError(t, err)
+	tassert.Errorf(t, format == cmn.ExtTgz, "expected %q, got %q", cmn.ExtTgz, format)
+}
+
+func TestExtractObjName(t *testing.T) {
+	tests := []struct {
+		extract  DlExtract
+		archive  string
+		name     string
+		expected string // empty if filtered out
+	}{
+		{archive: "shards/train-0001.tar", name: "img/0001.jpg", expected: "shards/train-0001/img/0001.jpg"},
+		{archive: "train.tar.gz", name: "./img/0001.jpg", expected: "train/img/0001.jpg"},
+		{archive: "train.tar", name: "../../etc/passwd", expected: "train/etc/passwd"},
+		{archive: "train.tar", name: "/abs/file", expected: "train/abs/file"},
+		{extract: DlExtract{Prefix: "imgs/"}, archive: "train.tar", name: "img/0001.jpg", expected: "imgs/img/0001.jpg"},
+		{extract: DlExtract{Flatten: true}, archive: "train.zip", name: "a/b/c.jpg", expected: "train/c.jpg"},
+		{extract: DlExtract{Regex: `\.jpg$`}, archive: "train.tar", name: "a/c.jpg", expected: "train/a/c.jpg"},
+		{extract: DlExtract{Regex: `\.jpg$`}, archive: "train.tar", name: "a/c.cls", expected: ""},
+	}
+	for _, test := range tests {
+		var (
+			e      = newArchiveExtractor(&test.extract)
+			prefix = e.prefix(test.archive)
+		)
+		objName, ok := e.objName(prefix, test.name)
+		if test.expected == "" {
+			tassert.Errorf(t, !ok, "expected %q to be filtered out, got %q", test.name, objName)
+			continue
+		}
+		tassert.Errorf(t, ok && objName == test.expected, "%q from %q: expected %q, got %q",
+			test.name, test.archive, test.expected, objName)
+	}
+}
@@ -78,6 +78,7 @@ func (is *infoStore) setJob(id string, job DlJob) {
 		Description: job.Description(),
 		StartedTime: time.Now(),
 	}
+	jInfo.Priority.Store(int32(job.Priority()))
 
 	is.Lock()
 	is.jobInfo[id] = jInfo
@@ -7,6 +7,7 @@ package downloader
 import (
 	"context"
 	"errors"
+	"io"
 	"path"
 	"strings"
 	"time"
@@ -25,6 +26,8 @@ var (
 	_ DlJob = &sliceDlJob{}
 	_ DlJob = &cloudBucketDlJob{}
 	_ DlJob = &rangeDlJob{}
+	_ DlJob = &manifestDlJob{}
+	_ DlJob = &crawlDlJob{}
 )
 
 var (
@@ -36,6 +39,15 @@ type (
 		objName   string
 		link      string
 		fromCloud bool
+		meta      *dlObjMeta // set only if provided with the request (see DlManifestEntry)
+	}
+
+	// dlObjMeta is the metadata of the object provided with the request - the
+	// downloaded object is validated against it.
+	dlObjMeta struct {
+		size  int64
+		cksum *cmn.Cksum
+		md    cmn.SimpleKVs // user-defined metadata
 	}
 
 	DlJob interface {
@@ -44,6 +56,10 @@ type (
 		Description() string
 		Timeout() time.Duration
 
+		// Initial priority of the job, can be changed while the job is running
+		// (see downloadJobInfo.Priority).
+		Priority() int
+
 		// If total length (size) of download job is not known, -1 should be returned.
 		Len() int
 
@@ -59,6 +75,14 @@ type (
 
 		throttler() *throttler
 
+		// Returns ID of the schedule if the job is its run (see scheduledDlJob).
+		schedule() string
+
+		// Returns credentials to access the source, if any.
+		credentials() *DlCredentials
+		// Returns extractor of the archives, if the archives are to be extracted.
+		extractor() *archiveExtractor
+
 		cleanup()
 	}
 
@@ -67,6 +91,9 @@ type (
 		bck         *cluster.Bck
 		timeout     time.Duration
 		description string
+		priority    int
+		creds       *DlCredentials
+		extract     *archiveExtractor
 		t           *throttler
 	}
 
@@ -94,6 +121,25 @@ type (
 		done  bool                  // true = the iterator is exhausted, nothing left to read
 	}
 
+	manifestDlJob struct {
+		baseDlJob
+		t       cluster.Target
+		payload *DlManifestBody
+		objs    []dlObj         // objects' metas which are ready to be downloaded
+		rc      io.ReadCloser   // manifest, opened with the first batch
+		mr      *manifestReader // parses `rc`
+		done    bool            // true = the manifest is exhausted, nothing left to read
+	}
+
+	crawlDlJob struct {
+		baseDlJob
+		t       cluster.Target
+		payload *DlCrawlBody
+		objs    []dlObj  // objects' metas which are ready to be downloaded
+		crawler *crawler // finds the objects to download
+		done    bool     // true = the crawl is over
+	}
+
 	cloudBucketDlJob struct {
 		baseDlJob
 		t   cluster.Target
@@ -121,37 +167,54 @@ type (
 		Aborted       atomic.Bool `json:"aborted"`
 		AllDispatched atomic.Bool `json:"all_dispatched"`
 
+		// Joggers take the tasks of the job proportionally to its priority,
+		// none when the job is paused (see queue).
+		Priority atomic.Int32 `json:"priority"`
+		Paused   atomic.Bool  `json:"paused"`
+
 		StartedTime  time.Time   `json:"started_time"`
 		FinishedTime atomic.Time `json:"finished_time"`
 	}
 )
 
-func (j *baseDlJob) ID() string             { return j.id }
-func (j *baseDlJob) Bck() cmn.Bck           { return j.bck.Bck }
-func (j *baseDlJob) Timeout() time.Duration { return j.timeout }
-func (j *baseDlJob) Description() string    { return j.description }
-func (j *baseDlJob) Sync() bool             { return false }
-func (j *baseDlJob) checkObj(string) bool   { cmn.Assert(false); return false }
-func (j *baseDlJob) throttler() *throttler  { return j.t }
+func (j *baseDlJob) ID() string                   { return j.id }
+func (j *baseDlJob) Bck() cmn.Bck                 { return j.bck.Bck }
+func (j *baseDlJob) Timeout() time.Duration       { return j.timeout }
+func (j *baseDlJob) Description() string          { return j.description }
+func (j *baseDlJob) Priority() int                { return j.priority }
+func (j *baseDlJob) Sync() bool                   { return false }
+func (j *baseDlJob) checkObj(string) bool         { cmn.Assert(false); return false }
+func (j *baseDlJob) throttler() *throttler        { return j.t }
+func (j *baseDlJob) schedule() string             { return "" }
+func (j *baseDlJob) credentials() *DlCredentials  { return j.creds }
+func (j *baseDlJob) extractor() *archiveExtractor { return j.extract }
 func (j *baseDlJob) cleanup() {
 	dlStore.markFinished(j.ID())
 	dlStore.flush(j.ID())
 	j.throttler().stop()
 }
 
-func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, timeout, desc string, limits DlLimits) *baseDlJob {
+func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, desc string, base *DlBase) *baseDlJob {
 	// TODO: this might be inaccurate if we download 1 or 2 objects because then
 	//  other targets will have limits but will not use them.
+	limits := base.Limits
 	if limits.BytesPerHour > 0 {
 		limits.BytesPerHour /= t.GetSowner().Get().CountTargets()
 	}
 
-	td, _ := time.ParseDuration(timeout)
+	priority := base.Priority
+	if priority == 0 {
+		priority = DlPriorityDefault
+	}
+	td, _ := time.ParseDuration(base.Timeout)
 	return &baseDlJob{
 		id:          id,
 		bck:         bck,
 		timeout:     td,
 		description: desc,
+		priority:    priority,
+		creds:       base.Credentials,
+		extract:     newArchiveExtractor(base.Extract),
 		t:           newThrottler(limits),
 	}
 }
@@ -181,7 +244,7 @@ func newMultiDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlMul
 		objs cmn.SimpleKVs
 		err  error
 	)
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits)
+	base := newBaseDlJob(t, id, bck, payload.Describe(), &payload.DlBase)
 	if objs, err = payload.ExtractPayload(); err != nil {
 		return nil, err
 	}
@@ -201,7 +264,7 @@ func newSingleDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlSi
 		objs cmn.SimpleKVs
 		err  error
 	)
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits)
+	base := newBaseDlJob(t, id, bck, payload.Describe(), &payload.DlBase)
 	if objs, err = payload.ExtractPayload(); err != nil {
 		return nil, err
 	}
@@ -321,12 +384,12 @@ func newCloudBucketDlJob(ctx context.Context, t cluster.Target, id string, bck *
 	if !bck.IsCloud() {
 		return nil, errors.New("bucket download requires a cloud bucket")
 	}
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits)
+	base := newBaseDlJob(t, id, bck, payload.Describe(), &payload.DlBase)
 	job := &cloudBucketDlJob{
 		baseDlJob: *base,
 		t:         t,
 		ctx:       ctx,
-		sync:      payload.Sync,
+		sync:      payload.Sync || (payload.Schedule != nil && payload.Schedule.Sync),
 		prefix:    payload.Prefix,
 		suffix:    payload.Suffix,
 	}
@@ -374,7 +437,7 @@ func newRangeDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlRan
 		return nil, err
 	}
 
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits)
+	base := newBaseDlJob(t, id, bck, payload.Describe(), &payload.DlBase)
 	cnt, err := countObjects(t, pt, payload.Subdir, base.bck)
 	if err != nil {
 		return nil, err
@@ -389,6 +452,126 @@ func newRangeDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlRan
 	return job, nil
 }
 
+func newManifestDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlManifestBody) (*manifestDlJob, error) {
+	if !bck.IsAIS() {
+		return nil, errAISBckReq
+	}
+	base := newBaseDlJob(t, id, bck, payload.Describe(), &payload.DlBase)
+	return &manifestDlJob{baseDlJob: *base, t: t, payload: payload}, nil
+}
+
+func (j *manifestDlJob) Len() int { return -1 }
+func (j *manifestDlJob) genNext() ([]dlObj, bool, error) {
+	if j.done {
+		return nil, false, nil
+	}
+	if err := j.getNextObjs(); err != nil {
+		return nil, false, err
+	}
+	return j.objs, true, nil
+}
+
+// Parses the manifest (streamed) until the batch of objects to download by
+// the target is collected or the manifest is over.
+func (j *manifestDlJob) getNextObjs() (err error) {
+	if j.mr == nil {
+		if j.rc, err = openManifest(j.t, j.payload); err != nil {
+			return
+		}
+		if j.mr, err = newManifestReader(j.rc, j.payload.Format); err != nil {
+			return
+		}
+	}
+	var (
+		smap = j.t.GetSowner().Get()
+		sid  = j.t.Snode().ID()
+	)
+	j.objs = j.objs[:0]
+	for len(j.objs) < downloadBatchSize {
+		entry, err := j.mr.next()
+		if err == io.EOF {
+			j.done = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+		obj, err := makeDlObj(smap, sid, j.bck, entry.ObjName, entry.Link)
+		if err != nil {
+			if err == errInvalidTarget {
+				continue
+			}
+			return err
+		}
+		obj.meta = entry.meta()
+		j.objs = append(j.objs, obj)
+	}
+	return nil
+}
+
+func (j *manifestDlJob) cleanup() {
+	if j.rc != nil {
+		j.rc.Close()
+	}
+	j.baseDlJob.cleanup()
+}
+
+func newCrawlDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlCrawlBody) (*crawlDlJob, error) {
+	if !bck.IsAIS() {
+		return nil, errAISBckReq
+	}
+	base := newBaseDlJob(t, id, bck, payload.Describe(), &payload.DlBase)
+	timeout := base.timeout
+	if timeout == 0 {
+		timeout = cmn.GCO.Get().Downloader.Timeout
+	}
+	c, err := newCrawler(payload, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &crawlDlJob{baseDlJob: *base, t: t, payload: payload, crawler: c}, nil
+}
+
+func (j *crawlDlJob) Len() int { return -1 }
+func (j *crawlDlJob) genNext() ([]dlObj, bool, error) {
+	if j.done {
+		return nil, false, nil
+	}
+	if err := j.getNextObjs(); err != nil {
+		return nil, false, err
+	}
+	return j.objs, true, nil
+}
+
+// Crawls until the batch of objects to download by the target is collected
+// or the crawl is over.
+func (j *crawlDlJob) getNextObjs() error {
+	var (
+		smap = j.t.GetSowner().Get()
+		sid  = j.t.Snode().ID()
+	)
+	j.objs = j.objs[:0]
+	for len(j.objs) < downloadBatchSize {
+		file, err := j.crawler.next()
+		if err == io.EOF {
+			j.done = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+		obj, err := makeDlObj(smap, sid, j.bck, path.Join(j.payload.Subdir, file.rel), file.link)
+		if err != nil {
+			if err == errInvalidTarget {
+				continue
+			}
+			return err
+		}
+		j.objs = append(j.objs, obj)
+	}
+	return nil
+}
+
 func (d *downloadJobInfo) ToDlJobInfo() DlJobInfo {
 	return DlJobInfo{
 		ID:            d.ID,
@@ -400,6 +583,8 @@ func (d *downloadJobInfo) ToDlJobInfo() DlJobInfo {
 		Total:         d.Total,
 		AllDispatched: d.AllDispatched.Load(),
 		Aborted:       d.Aborted.Load(),
+		Paused:        d.Paused.Load(),
+		Priority:      int(d.Priority.Load()),
 		StartedTime:   d.StartedTime,
 		FinishedTime:  d.FinishedTime.Load(),
 	}
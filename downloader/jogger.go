@@ -12,15 +12,34 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 )
 
-const queueChSize = 1000
+// Maximal number of tasks of a single job waiting in the jogger's queue. The
+// limit is per job so that a job with lots of tasks does not prevent the other
+// jobs from queueing theirs.
+const queueJobSize = 100
 
 type (
 	queueEntry = map[string]struct{}
 
+	// jobQueue holds the tasks of a single job.
+	jobQueue struct {
+		info    *downloadJobInfo    // priority and paused state of the job
+		tasks   []*singleObjectTask // pending downloads (FIFO)
+		uids    queueEntry          // set of request uid of pending and running tasks
+		slots   chan struct{}       // token per pending task, see `queueJobSize`
+		pass    float64             // virtual time, advanced by 1/priority with each task taken
+		removed bool                // the job has been aborted, no more tasks are accepted
+	}
+
+	// queue implements weighted fair queueing (stride scheduling) between the
+	// jobs: the jogger always takes the task of the job with the lowest virtual
+	// time so the jobs share the jogger proportionally to their priorities and
+	// a huge job cannot starve the small ones.
 	queue struct {
 		sync.RWMutex
-		ch chan *singleObjectTask // for pending downloads
-		m  map[string]queueEntry  // jobID -> set of request uid
+		jobs    map[string]*jobQueue // jobID -> tasks of the job
+		vtime   float64              // virtual time of the most recently taken task
+		workCh  chan struct{}        // wakes up the jogger waiting for a task
+		stopped bool
 	}
 
 	// Each jogger corresponds to an mpath. All types of download requests
@@ -53,14 +72,10 @@ func newJogger(d *dispatcher, mpath string) *jogger {
 func (j *jogger) jog() {
 	glog.Infof("Starting jogger for mpath %q.", j.mpath)
 	for {
-		t, skip := j.q.get()
+		t := j.q.get()
 		if t == nil {
 			break
 		}
-		if skip {
-			t.job.throttler().release()
-			continue
-		}
 
 		j.mtx.Lock()
 		if j.stopAgent {
@@ -83,7 +98,7 @@ func (j *jogger) jog() {
 		}
 	}
 
-	j.q.cleanup()
+	j.q.stop()
 	j.terminateCh.Close()
 }
 
@@ -98,16 +113,23 @@ func (j *jogger) stop() {
 	}
 	j.mtx.Unlock()
 
+	j.q.stop()
 	<-j.terminateCh.Listen()
 }
 
-// Returns channel which task should be put into.
-func (j *jogger) putCh(t *singleObjectTask) chan<- *singleObjectTask {
-	ok, ch := j.q.putCh(t)
-	if ok {
-		j.parent.parent.IncPending()
+// Returns channel which accepts (a token) when the task can be put into the queue.
+func (j *jogger) slotCh(t *singleObjectTask) chan<- struct{} {
+	return j.q.slotCh(t.id())
+}
+
+// Puts the task into the queue, returns `false` if the task was omitted.
+// PRECONDITION: the token has been sent to the `slotCh`.
+func (j *jogger) put(t *singleObjectTask) bool {
+	if ok := j.q.put(t); !ok {
+		return false
 	}
-	return ch
+	j.parent.parent.IncPending()
+	return true
 }
 
 func (j *jogger) getTask() (t *singleObjectTask) {
@@ -140,123 +162,178 @@ func (j *jogger) pending(id string) bool {
 
 func newQueue() *queue {
 	return &queue{
-		ch: make(chan *singleObjectTask, queueChSize),
-		m:  make(map[string]queueEntry),
+		jobs:   make(map[string]*jobQueue),
+		workCh: make(chan struct{}, 1),
 	}
 }
 
-func (q *queue) putCh(t *singleObjectTask) (ok bool, ch chan<- *singleObjectTask) {
+func (q *queue) slotCh(jobID string) chan<- struct{} {
 	q.Lock()
-	if q.stopped() || q.exists(t.id(), t.uid()) {
-		// If task already exists or the queue was stopped we should just omit it
-		// hence return channel which immediately accepts and omits the task.
+	defer q.Unlock()
+	if q.stopped {
+		// Return channel which immediately accepts - the task is then omitted (see `put`).
+		return make(chan struct{}, 1)
+	}
+	jq, ok := q.jobs[jobID]
+	if !ok {
+		info, err := dlStore.getJob(jobID)
+		if err != nil {
+			return make(chan struct{}, 1)
+		}
+		jq = &jobQueue{
+			info:  info,
+			uids:  make(queueEntry),
+			slots: make(chan struct{}, queueJobSize),
+		}
+		q.jobs[jobID] = jq
+	}
+	return jq.slots
+}
+
+func (q *queue) put(t *singleObjectTask) bool {
+	q.Lock()
+	jq, ok := q.jobs[t.id()]
+	if !ok {
+		q.Unlock()
+		return false
+	}
+	if _, exists := jq.uids[t.uid()]; q.stopped || exists || jq.removed {
+		// If task already exists, the job was aborted or the queue was stopped
+		// we should just omit it.
+		jq.releaseSlot()
 		q.Unlock()
-		return false, make(chan *singleObjectTask, 1)
+		return false
 	}
-	q.putToSet(t.id(), t.uid())
+	jq.uids[t.uid()] = struct{}{}
+	jq.tasks = append(jq.tasks, t)
 	q.Unlock()
 
-	return true, q.ch
+	q.wakeup()
+	return true
 }
 
-// Get tries to find first task which was not yet Aborted
-func (q *queue) get() (foundTask *singleObjectTask, skip bool) {
-	t, ok := <-q.ch
-	if !ok {
-		return nil, false
+// Get blocks until there is a task to download, returns `nil` when the queue
+// has been stopped.
+func (q *queue) get() *singleObjectTask {
+	for {
+		q.Lock()
+		if q.stopped {
+			q.Unlock()
+			return nil
+		}
+		if t := q.next(); t != nil {
+			// NOTE: We do not delete task here but postpone it until the task
+			//  has `Finished` to prevent situation where we put task which is
+			//  being downloaded.
+			ctx, cancel := context.WithCancel(context.Background())
+			t.downloadCtx = ctx
+			t.cancelFunc = cancel
+			q.Unlock()
+			return t
+		}
+		q.Unlock()
+		<-q.workCh
 	}
+}
 
-	q.RLock()
-	defer q.RUnlock()
-	if !q.exists(t.id(), t.uid()) {
-		// The job was removed so we must skip tasks which no longer exist.
-		return t, true
+// Takes the first task of the (not paused) job with the lowest virtual time.
+// NOTE: Should be called under `q.Lock()`.
+func (q *queue) next() *singleObjectTask {
+	var jq *jobQueue
+	for _, candidate := range q.jobs {
+		if len(candidate.tasks) == 0 || candidate.info.Paused.Load() {
+			continue
+		}
+		// Job which was idle (or paused) must not be compensated for the time
+		// it has not been competing.
+		if candidate.pass < q.vtime {
+			candidate.pass = q.vtime
+		}
+		if jq == nil || candidate.pass < jq.pass {
+			jq = candidate
+		}
+	}
+	if jq == nil {
+		return nil
 	}
 
-	// NOTE: We do not delete task here but postpone it until the task
-	//  has `Finished` to prevent situation where we put task which is
-	//  being downloaded.
+	t := jq.tasks[0]
+	jq.tasks[0] = nil
+	jq.tasks = jq.tasks[1:]
+	jq.releaseSlot()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	t.downloadCtx = ctx
-	t.cancelFunc = cancel
-	return t, false
+	q.vtime = jq.pass
+	priority := cmn.MaxI32(jq.info.Priority.Load(), DlPriorityMin)
+	jq.pass += 1 / float64(priority)
+	return t
 }
 
-func (q *queue) delete(t *singleObjectTask) bool {
-	q.Lock()
-	exists := q.exists(t.id(), t.uid())
-	q.removeFromSet(t.id(), t.uid())
-	q.Unlock()
-	return exists
+// Wakes up the jogger if it waits for a task - to be called when a new task
+// is added or paused job has been resumed.
+func (q *queue) wakeup() {
+	select {
+	case q.workCh <- struct{}{}:
+	default:
+	}
 }
 
-func (q *queue) cleanup() {
+func (q *queue) delete(t *singleObjectTask) bool {
 	q.Lock()
-	q.ch = nil
-	q.m = nil
-	q.Unlock()
-}
-
-// NOTE: Should be called under `q.RLock()`.
-func (q *queue) stopped() bool {
-	return q.m == nil || q.ch == nil
-}
-
-// NOTE: Should be called under `q.RLock()`.
-func (q *queue) exists(jobID, requestUID string) bool {
-	jobM, ok := q.m[jobID]
-
+	defer q.Unlock()
+	jq, ok := q.jobs[t.id()]
 	if !ok {
 		return false
 	}
+	_, exists := jq.uids[t.uid()]
+	delete(jq.uids, t.uid())
+	return exists
+}
 
-	_, ok = jobM[requestUID]
-	return ok
+func (q *queue) stop() {
+	q.Lock()
+	q.stopped = true
+	q.Unlock()
+	q.wakeup()
 }
 
 func (q *queue) pending(jobID string) bool {
 	q.RLock()
 	defer q.RUnlock()
-	_, exists := q.m[jobID]
-	return exists
-}
-
-// NOTE: Should be called under `q.Lock()`.
-func (q *queue) putToSet(jobID, requestUID string) {
-	if _, ok := q.m[jobID]; !ok {
-		q.m[jobID] = make(queueEntry)
-	}
-
-	q.m[jobID][requestUID] = struct{}{}
+	jq, ok := q.jobs[jobID]
+	return ok && len(jq.uids) > 0
 }
 
-// NOTE: Should be called under `q.Lock()`.
-func (q *queue) removeFromSet(jobID, requestUID string) {
-	jobM, ok := q.m[jobID]
+// Removes pending tasks of the job and makes the queue reject the next ones,
+// returns the number of removed (pending and running) tasks.
+func (q *queue) removeJob(id string) int {
+	q.Lock()
+	defer q.Unlock()
+	jq, ok := q.jobs[id]
 	if !ok {
-		return
+		return 0
 	}
-
-	if _, ok := jobM[requestUID]; ok {
-		delete(jobM, requestUID)
-
-		if len(jobM) == 0 {
-			delete(q.m, jobID)
-		}
+	for _, t := range jq.tasks {
+		t.job.throttler().release()
+		jq.releaseSlot()
 	}
+	cnt := len(jq.uids)
+	jq.tasks = nil
+	jq.uids = make(queueEntry)
+	jq.removed = true
+	return cnt
 }
 
-func (q *queue) removeJob(id string) int {
+// Forgets the job - must be called once all tasks of the job have been
+// dispatched and processed.
+func (q *queue) dropJob(id string) {
 	q.Lock()
-	defer q.Unlock()
-	if q.stopped() {
-		return 0
-	}
-	jobM, ok := q.m[id]
-	if !ok {
-		return 0
+	delete(q.jobs, id)
+	q.Unlock()
+}
+
+func (jq *jobQueue) releaseSlot() {
+	select {
+	case <-jq.slots:
+	default:
 	}
-	delete(q.m, id)
-	return len(jobM)
 }
@@ -0,0 +1,73 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func newTestQueueJob(t *testing.T, q *queue, id string, priority, cnt int) DlJob {
+	job := &sliceDlJob{baseDlJob: baseDlJob{
+		id:       id,
+		bck:      cluster.NewBck("bck", cmn.ProviderAIS, cmn.NsGlobal),
+		priority: priority,
+		t:        newThrottler(DlLimits{}),
+	}}
+	dlStore.setJob(id, job)
+	for i := 0; i < cnt; i++ {
+		task := &singleObjectTask{job: job, obj: dlObj{objName: fmt.Sprintf("obj-%d", i), link: "http://example.com"}}
+		q.slotCh(id) <- struct{}{}
+		tassert.Fatalf(t, q.put(task), "expected task %q of job %q to be put", task.obj.objName, id)
+	}
+	return job
+}
+
+func TestQueueFairness(t *testing.T) {
+	dlStore = &infoStore{jobInfo: make(map[string]*downloadJobInfo)}
+	var (
+		q    = newQueue()
+		low  = newTestQueueJob(t, q, "low", DlPriorityMin, 60)
+		high = newTestQueueJob(t, q, "high", DlPriorityMin*5, 60)
+		cnt  = make(map[string]int)
+	)
+
+	// The jobs should be served proportionally to their priorities.
+	for i := 0; i < 36; i++ {
+		cnt[q.get().id()]++
+	}
+	tassert.Errorf(t, cnt["high"] >= 29 && cnt["high"] <= 31, "expected ~30 tasks of high priority job, got: %v", cnt)
+
+	// Paused job should not be served at all.
+	info, err := dlStore.getJob("high")
+	tassert.CheckFatal(t, err)
+	info.Paused.Store(true)
+	for i := 0; i < 10; i++ {
+		task := q.get()
+		tassert.Fatalf(t, task.id() == low.ID(), "expected only tasks of not paused job, got: %q", task.id())
+	}
+	info.Paused.Store(false)
+	task := q.get()
+	tassert.Errorf(t, task.id() == "high", "expected task of resumed job, got: %q", task.id())
+
+	// Aborted job should not be served and should not accept more tasks.
+	removed := q.removeJob("high")
+	tassert.Errorf(t, removed == 60, "expected 60 tasks of aborted job to be removed, got: %d", removed)
+	tassert.Errorf(t, !q.pending("high"), "expected no pending tasks of aborted job")
+	for i := 0; i < 5; i++ {
+		task := q.get()
+		tassert.Fatalf(t, task.id() == low.ID(), "expected only tasks of not aborted job, got: %q", task.id())
+	}
+	q.slotCh(high.ID()) <- struct{}{}
+	task = &singleObjectTask{job: high, obj: dlObj{objName: "new", link: "http://example.com"}}
+	tassert.Errorf(t, !q.put(task), "expected task of aborted job to be omitted")
+
+	q.stop()
+	tassert.Errorf(t, q.get() == nil, "expected no task from stopped queue")
+}
@@ -0,0 +1,212 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// names of the CSV manifest columns (and JSON fields) - see DlManifestEntry
+const (
+	manifestColLink       = "url"
+	manifestColObjName    = "name"
+	manifestColSize       = "size"
+	manifestColCksumType  = "cksum_type"
+	manifestColCksumValue = "cksum_value"
+)
+
+type (
+	// DlManifestEntry is a single object listed in the manifest (see
+	// DlManifestBody). The manifest is either CSV, with the first row naming
+	// the columns, or JSON lines - one JSON object per line. In CSV, the
+	// columns other than the ones below are the custom metadata of the object.
+	DlManifestEntry struct {
+		Link       string        `json:"url"`
+		ObjName    string        `json:"name,omitempty"`        // by default, the base of the link
+		Size       int64         `json:"size,omitempty"`        // expected size (if known)
+		CksumType  string        `json:"cksum_type,omitempty"`  // expected checksum (if known)
+		CksumValue string        `json:"cksum_value,omitempty"` // ditto
+		Custom     cmn.SimpleKVs `json:"custom,omitempty"`      // stored as user-defined metadata of the object
+	}
+
+	// manifestReader parses the manifest entry by entry.
+	manifestReader struct {
+		csv  *csv.Reader
+		json *jsoniter.Decoder
+		cols []string // names of the CSV columns
+		cnt  int      // number of entries read so far
+	}
+)
+
+func (e *DlManifestEntry) validate() error {
+	if e.Link == "" {
+		return fmt.Errorf("missing %q", manifestColLink)
+	}
+	obj := DlSingleObj{ObjName: e.ObjName, Link: e.Link}
+	if err := obj.Validate(); err != nil {
+		return err
+	}
+	e.ObjName = obj.ObjName
+	if e.Size < 0 {
+		return fmt.Errorf("%q must be non-negative (got: %d)", manifestColSize, e.Size)
+	}
+	if (e.CksumType == "") != (e.CksumValue == "") {
+		return fmt.Errorf("both %q and %q must be provided", manifestColCksumType, manifestColCksumValue)
+	}
+	if e.CksumType != "" {
+		if err := cmn.ValidateCksumType(e.CksumType); err != nil {
+			return err
+		}
+		if e.CksumType == cmn.ChecksumNone {
+			return fmt.Errorf("invalid %q %q", manifestColCksumType, e.CksumType)
+		}
+	}
+	return nil
+}
+
+func (e *DlManifestEntry) meta() *dlObjMeta {
+	meta := &dlObjMeta{size: e.Size, md: e.Custom}
+	if e.CksumType != "" {
+		meta.cksum = cmn.NewCksum(e.CksumType, strings.ToLower(e.CksumValue))
+	}
+	return meta
+}
+
+////////////////////
+// manifestReader //
+////////////////////
+
+func newManifestReader(r io.Reader, format string) (*manifestReader, error) {
+	mr := &manifestReader{}
+	switch format {
+	case DlManifestCSV:
+		mr.csv = csv.NewReader(r)
+		mr.csv.TrimLeadingSpace = true
+		cols, err := mr.csv.Read()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("missing header (names of the columns)")
+			}
+			return nil, fmt.Errorf("invalid manifest: %v", err)
+		}
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		if !cmn.StringInSlice(manifestColLink, cols) {
+			return nil, fmt.Errorf("invalid manifest: missing %q column", manifestColLink)
+		}
+		mr.cols = cols
+	case DlManifestJSONL:
+		mr.json = jsoniter.NewDecoder(r)
+	default:
+		cmn.AssertMsg(false, format)
+	}
+	return mr, nil
+}
+
+// next returns the next entry of the manifest or io.EOF if there are no more.
+func (mr *manifestReader) next() (entry DlManifestEntry, err error) {
+	mr.cnt++
+	if mr.csv != nil {
+		err = mr.readCSV(&entry)
+	} else if mr.json.More() {
+		err = mr.json.Decode(&entry)
+	} else {
+		err = io.EOF // (trailing whitespace is not an entry)
+	}
+	if err == io.EOF {
+		return
+	}
+	if err == nil {
+		err = entry.validate()
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid manifest entry #%d: %v", mr.cnt, err)
+	}
+	return
+}
+
+func (mr *manifestReader) readCSV(entry *DlManifestEntry) (err error) {
+	record, err := mr.csv.Read()
+	if err != nil {
+		return err
+	}
+	for i, v := range record {
+		v = strings.TrimSpace(v)
+		switch col := mr.cols[i]; col {
+		case manifestColLink:
+			entry.Link = v
+		case manifestColObjName:
+			entry.ObjName = v
+		case manifestColSize:
+			if v == "" {
+				continue
+			}
+			if entry.Size, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return fmt.Errorf("invalid %q %q", col, v)
+			}
+		case manifestColCksumType:
+			entry.CksumType = v
+		case manifestColCksumValue:
+			entry.CksumValue = v
+		default:
+			if v == "" {
+				continue
+			}
+			if entry.Custom == nil {
+				entry.Custom = make(cmn.SimpleKVs, len(record)-i)
+			}
+			entry.Custom[col] = v
+		}
+	}
+	return nil
+}
+
+// openManifest starts reading the manifest - either the object stored in
+// the cluster (requested via primary proxy) or the one pointed to by the link.
+func openManifest(t cluster.Target, payload *DlManifestBody) (io.ReadCloser, error) {
+	var (
+		link  = cmn.PrependProtocol(payload.Manifest)
+		creds = payload.Credentials
+	)
+	if payload.ManifestBck.Name != "" {
+		creds = nil // credentials are meant for the source, not for the cluster
+		u, err := url.Parse(t.GetSowner().Get().Primary.URL(cmn.NetworkPublic))
+		if err != nil {
+			return nil, err
+		}
+		u.Path = cmn.URLPath(cmn.Version, cmn.Objects, payload.ManifestBck.Name, payload.Manifest)
+		u.RawQuery = cmn.AddBckToQuery(nil, payload.ManifestBck).Encode()
+		link = u.String()
+	}
+	req, err := newSourceRequest(context.Background(), http.MethodGet, link, creds)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := clientForURL(req.URL.String()).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to get manifest %q: %d status code (%s)",
+			payload.manifestName(), resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,129 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func readManifest(t *testing.T, manifest, format string) ([]DlManifestEntry, error) {
+	mr, err := newManifestReader(strings.NewReader(manifest), format)
+	if err != nil {
+		return nil, err
+	}
+	var entries []DlManifestEntry
+	for {
+		entry, err := mr.next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func TestManifestReader(t *testing.T) {
+	expected := []DlManifestEntry{
+		{Link: "http://example.com/a/obj1.tar", ObjName: "obj1.tar"},
+		{
+			Link: "http://example.com/a/obj2.tar", ObjName: "b/obj2.tar", Size: 1024,
+			CksumType: cmn.ChecksumXXHash, CksumValue: "0123abcd",
+			Custom: cmn.SimpleKVs{"label": "cat"},
+		},
+	}
+	tests := []struct {
+		format   string
+		manifest string
+	}{
+		{
+			format: DlManifestCSV,
+			manifest: "url, name, size, cksum_type, cksum_value, label\n" +
+				"http://example.com/a/obj1.tar,,,,,\n" +
+				"http://example.com/a/obj2.tar, b/obj2.tar, 1024, xxhash, 0123abcd, cat\n",
+		},
+		{
+			format: DlManifestJSONL,
+			manifest: `{"url": "http://example.com/a/obj1.tar"}` + "\n" +
+				`{"url": "http://example.com/a/obj2.tar", "name": "b/obj2.tar", "size": 1024, ` +
+				`"cksum_type": "xxhash", "cksum_value": "0123abcd", "custom": {"label": "cat"}}` + "\n",
+		},
+	}
+	for _, test := range tests {
+		entries, err := readManifest(t, test.manifest, test.format)
+		tassert.CheckFatal(t, err)
+		tassert.Fatalf(t, len(entries) == len(expected), "[%s] expected %d entries, got: %d",
+			test.format, len(expected), len(entries))
+		for i, entry := range entries {
+			exp := expected[i]
+			tassert.Errorf(t,
+				entry.Link == exp.Link && entry.ObjName == exp.ObjName && entry.Size == exp.Size &&
+					entry.CksumType == exp.CksumType && entry.CksumValue == exp.CksumValue &&
+					len(entry.Custom) == len(exp.Custom) && entry.Custom["label"] == exp.Custom["label"],
+				"[%s] entry #%d expected: %+v, got: %+v", test.format, i, exp, entry)
+		}
+	}
+}
+
+func TestManifestReaderInvalid(t *testing.T) {
+	tests := []struct {
+		format   string
+		manifest string
+	}{
+		{format: DlManifestCSV, manifest: ""},
+		{format: DlManifestCSV, manifest: "name,size\nobj1,10\n"},
+		{format: DlManifestCSV, manifest: "url,size\nhttp://example.com/obj1,ten\n"},
+		{format: DlManifestCSV, manifest: "url,size\nhttp://example.com/obj1,-1\n"},
+		{format: DlManifestCSV, manifest: "url,cksum_type\nhttp://example.com/obj1,md5\n"},
+		{format: DlManifestCSV, manifest: "url,cksum_type,cksum_value\nhttp://example.com/obj1,none,abc\n"},
+		{format: DlManifestCSV, manifest: "url,cksum_type,cksum_value\nhttp://example.com/obj1,sha7,abc\n"},
+		{format: DlManifestCSV, manifest: "url,name\nhttp://example.com/obj1,a,b\n"},
+		{format: DlManifestJSONL, manifest: `{"name": "obj1"}`},
+		{format: DlManifestJSONL, manifest: `{"url": "http://example.com/obj1"`},
+		{format: DlManifestJSONL, manifest: `{"url": "http://example.com/obj1", "size": "ten"}`},
+	}
+	for _, test := range tests {
+		_, err := readManifest(t, test.manifest, test.format)
+		tassert.Errorf(t, err != nil, "[%s] expected manifest %q to be invalid", test.format, test.manifest)
+	}
+}
+
+func TestDlManifestBodyFormat(t *testing.T) {
+	tests := []struct {
+		manifest string
+		format   string
+		expected string
+	}{
+		{manifest: "manifest.csv", expected: DlManifestCSV},
+		{manifest: "dir/manifest.CSV", expected: DlManifestCSV},
+		{manifest: "http://example.com/manifest.jsonl?token=abc", expected: DlManifestJSONL},
+		{manifest: "manifest.ndjson", expected: DlManifestJSONL},
+		{manifest: "manifest.txt", format: DlManifestCSV, expected: DlManifestCSV},
+		{manifest: "manifest.txt"},
+		{manifest: "manifest.csv", format: "xml"},
+		{manifest: ""},
+	}
+	for _, test := range tests {
+		body := &DlManifestBody{
+			DlBase:   DlBase{Bck: cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}},
+			Manifest: test.manifest,
+			Format:   test.format,
+		}
+		err := body.Validate()
+		if test.expected == "" {
+			tassert.Errorf(t, err != nil, "expected manifest %q (format: %q) to be invalid", test.manifest, test.format)
+			continue
+		}
+		tassert.CheckError(t, err)
+		tassert.Errorf(t, body.Format == test.expected, "manifest %q expected format: %q, got: %q",
+			test.manifest, test.expected, body.Format)
+	}
+}
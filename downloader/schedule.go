@@ -0,0 +1,498 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ============================== Scheduling ==================================
+//
+// The download job with `schedule` (see DlSchedule) is not started right away
+// but registered with the scheduler of each target, which persists it (see
+// downloaderDB) and starts a new download job - the run - whenever it is due.
+// The ID of the run is derived from the schedule ID and the scheduled time so
+// that the runs started independently by the targets share the same ID and
+// can be monitored as any other download job.
+//
+// Each run skips the objects that have not changed since they were downloaded
+// (see compareObjects) and, with `schedule.sync`, deletes the objects that
+// were downloaded by the previous runs but are no longer listed by the job.
+// The summary of each run is kept in the history of the schedule.
+//
+// ============================================================================
+
+const (
+	minScheduleInterval = time.Minute
+	maxScheduleRuns     = 100 // number of runs kept in the history of the schedule
+	scheduleTickMax     = time.Minute
+	cronSearchYears     = 5 // the cron spec that does not match within that many years never matches
+)
+
+var (
+	// global downloader scheduler
+	dlScheduler     *scheduler
+	dlSchedulerOnce sync.Once
+
+	errScheduleNotFound = errors.New("schedule not found")
+)
+
+type (
+	scheduler struct {
+		t     cluster.Target
+		renew func() (*Downloader, error) // returns running downloader
+
+		schedules map[string]*dlScheduleRecord
+		mtx       sync.Mutex
+	}
+
+	// dlScheduleRecord is the persisted state of the schedule.
+	dlScheduleRecord struct {
+		DlScheduleInfo
+		Body DlBody `json:"body"`
+		// Credentials removed from the body and encrypted, see `sealBody`.
+		SealedCreds string `json:"sealed_credentials,omitempty"`
+
+		running string // ID of the run in progress, if any
+	}
+
+	// scheduledDlJob is the run of the schedule - the download job which
+	// additionally tags the downloaded objects with the schedule ID and
+	// deletes the ones that are no longer listed.
+	scheduledDlJob struct {
+		DlJob
+		scheduleID string
+		listed     map[string]struct{} // names of the objects listed by the run (only if sweeping)
+	}
+
+	// cronSpec is the parsed cron-like spec - the bitmasks of the allowed values.
+	cronSpec struct {
+		minute, hour, dom, month, dow uint64
+		anyDom, anyDow                bool
+	}
+)
+
+// InitScheduler starts the scheduler of the recurring downloads and resumes
+// the persisted ones. `renew` is used to (re)start the downloader to run them.
+func InitScheduler(t cluster.Target, renew func() (*Downloader, error)) {
+	dlSchedulerOnce.Do(func() {
+		initInfoStore(t.GetDB())
+		dlScheduler = &scheduler{
+			t:         t,
+			renew:     renew,
+			schedules: make(map[string]*dlScheduleRecord),
+		}
+		records, err := dlStore.schedules()
+		if err != nil {
+			glog.Error(err)
+		}
+		for _, rec := range records {
+			dlScheduler.schedules[rec.ID] = rec
+		}
+		hk.Reg("downloader-scheduler", dlScheduler.housekeep, scheduleTickMax)
+	})
+}
+
+func (s *scheduler) add(id string, created time.Time, dlb DlBody) error {
+	base := DlBase{}
+	if err := jsoniter.Unmarshal(dlb.RawMessage, &base); err != nil {
+		return err
+	}
+	if err := base.Validate(); err != nil {
+		return err
+	}
+	payload, err := parseDlBody(dlb)
+	if err != nil {
+		return err
+	}
+	cmn.Assert(base.Schedule != nil)
+	rec := &dlScheduleRecord{
+		DlScheduleInfo: DlScheduleInfo{
+			ID:          id,
+			Description: payload.Describe(),
+			Type:        dlb.Type,
+			Schedule:    *base.Schedule,
+			Created:     created,
+			NextRun:     base.Schedule.next(created, created.Add(-time.Nanosecond)),
+		},
+		Body: dlb,
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err := dlStore.setSchedule(rec); err != nil {
+		return err
+	}
+	s.schedules[id] = rec
+	glog.Infof("download schedule %q (%s) added, next run: %v", id, rec.Schedule.String(), rec.NextRun)
+	return nil
+}
+
+func (s *scheduler) remove(id string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.schedules[id]; !ok {
+		return errScheduleNotFound
+	}
+	delete(s.schedules, id)
+	dlStore.deleteSchedule(id)
+	return nil
+}
+
+func (s *scheduler) list(id string, descRegex *regexp.Regexp) map[string]DlScheduleInfo {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	infos := make(map[string]DlScheduleInfo, len(s.schedules))
+	for _, rec := range s.schedules {
+		if id != "" && rec.ID != id {
+			continue
+		}
+		if descRegex != nil && !descRegex.MatchString(rec.Description) {
+			continue
+		}
+		info := rec.DlScheduleInfo
+		info.Runs = append([]DlJobInfo(nil), rec.Runs...)
+		infos[rec.ID] = info
+	}
+	return infos
+}
+
+// housekeep starts the runs that are due and returns the time until the next one.
+func (s *scheduler) housekeep() time.Duration {
+	var (
+		now  = time.Now()
+		wait = scheduleTickMax
+	)
+	s.mtx.Lock()
+	for _, rec := range s.schedules {
+		if !rec.NextRun.IsZero() && !rec.NextRun.After(now) {
+			s.start(rec, rec.NextRun)
+			// NOTE: the runs missed in the meantime (eg. target was down) are skipped.
+			rec.NextRun = rec.Schedule.next(rec.Created, now)
+			if err := dlStore.setSchedule(rec); err != nil {
+				glog.Error(err)
+			}
+		}
+		if rec.NextRun.IsZero() {
+			continue
+		}
+		if d := rec.NextRun.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	s.mtx.Unlock()
+	return cmn.MaxDuration(wait, time.Second)
+}
+
+// PRECONDITION: `s.mtx` is locked.
+func (s *scheduler) start(rec *dlScheduleRecord, scheduled time.Time) {
+	runID := rec.ID + "-" + scheduled.UTC().Format("20060102T1504")
+	if rec.running != "" {
+		glog.Warningf("download schedule %q: skipping run %q - the previous run %q is still in progress",
+			rec.ID, runID, rec.running)
+		return
+	}
+	rec.running = runID
+	go s.run(rec.ID, runID, rec.Body)
+}
+
+func (s *scheduler) run(id, runID string, dlb DlBody) {
+	if err := s._run(id, runID, dlb); err != nil {
+		glog.Errorf("download schedule %q: failed to start run %q: %v", id, runID, err)
+		now := time.Now()
+		s.finished(id, DlJobInfo{ID: runID, Aborted: true, StartedTime: now, FinishedTime: now})
+	}
+}
+
+func (s *scheduler) _run(id, runID string, dlb DlBody) error {
+	base := DlBase{}
+	if err := jsoniter.Unmarshal(dlb.RawMessage, &base); err != nil {
+		return err
+	}
+	bck := cluster.NewBckEmbed(base.Bck)
+	if err := bck.Init(s.t.GetBowner(), s.t.Snode()); err != nil {
+		return err
+	}
+	job, err := ParseStartDownloadRequest(context.Background(), s.t, bck, runID, dlb)
+	if err != nil {
+		return err
+	}
+	d, err := s.renew()
+	if err != nil {
+		return err
+	}
+	sjob := &scheduledDlJob{DlJob: job, scheduleID: id}
+	if base.Schedule.Sync && !job.Sync() {
+		sjob.listed = make(map[string]struct{})
+	}
+	glog.Infof("download schedule %q: starting run %q", id, runID)
+	if resp, err, statusCode := d.Download(sjob); statusCode >= http.StatusBadRequest {
+		if err == nil {
+			err = fmt.Errorf("%v", resp)
+		}
+		dlStore.Lock()
+		dlStore.delJob(runID)
+		dlStore.Unlock()
+		return err
+	}
+	return nil
+}
+
+// finished records the summary of the run in the history of the schedule.
+func (s *scheduler) finished(id string, info DlJobInfo) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	rec, ok := s.schedules[id]
+	if !ok { // removed in the meantime
+		return
+	}
+	rec.running = ""
+	rec.Runs = append(rec.Runs, info)
+	if len(rec.Runs) > maxScheduleRuns {
+		rec.Runs = rec.Runs[len(rec.Runs)-maxScheduleRuns:]
+	}
+	if err := dlStore.setSchedule(rec); err != nil {
+		glog.Error(err)
+	}
+}
+
+////////////////////
+// scheduledDlJob //
+////////////////////
+
+func (j *scheduledDlJob) schedule() string { return j.scheduleID }
+
+func (j *scheduledDlJob) genNext() (objs []dlObj, ok bool, err error) {
+	objs, ok, err = j.DlJob.genNext()
+	if j.listed != nil {
+		for _, obj := range objs {
+			j.listed[obj.objName] = struct{}{}
+		}
+	}
+	return
+}
+
+func (j *scheduledDlJob) cleanup() {
+	jInfo, err := dlStore.getJob(j.ID())
+	cmn.AssertNoErr(err)
+	// Sweep only if all the objects have been listed - otherwise we could
+	// delete the ones that are still there.
+	if j.listed != nil && jInfo.AllDispatched.Load() && !jInfo.Aborted.Load() {
+		j.sweep()
+	}
+	j.DlJob.cleanup()
+	dlScheduler.finished(j.scheduleID, jInfo.ToDlJobInfo())
+}
+
+// sweep deletes the objects downloaded by the previous runs of the schedule
+// which have not been listed by this run.
+func (j *scheduledDlJob) sweep() {
+	var (
+		t       = dlScheduler.t
+		deleted int
+	)
+	err := fs.WalkBck(&fs.WalkBckOptions{
+		Options: fs.Options{
+			Bck: j.Bck(),
+			CTs: []string{fs.ObjectType},
+			Callback: func(fqn string, de fs.DirEntry) error {
+				lom := &cluster.LOM{T: t, FQN: fqn}
+				if err := lom.Init(j.Bck()); err != nil {
+					return err
+				}
+				if _, ok := j.listed[lom.ObjName]; ok {
+					return nil
+				}
+				if err := lom.Load(); err != nil {
+					return nil
+				}
+				if id, ok := lom.GetCustomMD(cluster.DlScheduleObjMD); !ok || id != j.scheduleID {
+					return nil
+				}
+				lom.Lock(true)
+				err := lom.Remove()
+				lom.Unlock(true)
+				if err != nil {
+					glog.Errorf("%s: failed to delete %s: %v", j, lom, err)
+					return nil
+				}
+				deleted++
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		glog.Errorf("%s: failed to delete the objects which are no longer listed: %v", j, err)
+	}
+	if deleted > 0 {
+		glog.Infof("%s: deleted %d object(s) which are no longer listed", j, deleted)
+	}
+}
+
+func (j *scheduledDlJob) String() string {
+	return fmt.Sprintf("download schedule %q run %q", j.scheduleID, j.ID())
+}
+
+////////////////
+// DlSchedule //
+////////////////
+
+// next returns the time of the first run after `after` of the schedule
+// created at `created` (zero time if there is none).
+func (s *DlSchedule) next(created, after time.Time) time.Time {
+	if s.Cron != "" {
+		spec, err := parseCron(s.Cron)
+		cmn.AssertNoErr(err) // validated
+		return spec.next(after)
+	}
+	interval, err := time.ParseDuration(s.Interval)
+	cmn.AssertNoErr(err) // validated
+	if after.Before(created) {
+		return created
+	}
+	n := after.Sub(created)/interval + 1
+	return created.Add(n * interval)
+}
+
+//////////////
+// cronSpec //
+//////////////
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // both 0 and 7 are Sunday
+}
+
+// parseCron parses the spec in the standard cron format: 5 space-separated
+// fields (minute, hour, day of month, month, day of week), each being a
+// comma-separated list of "*", "N" or "N-M", optionally followed by "/STEP".
+func parseCron(spec string) (*cronSpec, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(cronFields), len(fields))
+	}
+	masks := make([]uint64, len(fields))
+	for i, field := range fields {
+		mask, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", cronFields[i].name, field, err)
+		}
+		masks[i] = mask
+	}
+	if masks[4]&(1<<7) != 0 {
+		masks[4] = masks[4]&^(1<<7) | 1
+	}
+	c := &cronSpec{
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		anyDom: strings.HasPrefix(fields[2], "*"),
+		anyDow: strings.HasPrefix(fields[4], "*"),
+	}
+	if c.next(time.Now()).IsZero() {
+		return nil, errors.New("never matches")
+	}
+	return c, nil
+}
+
+func parseCronField(field string, min, max int) (mask uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		var (
+			rng, step = part, 1
+			lo, hi    int
+		)
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			rng = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+		}
+		switch {
+		case rng == "*":
+			lo, hi = min, max
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			if lo, err = strconv.Atoi(rng); err != nil {
+				return 0, fmt.Errorf("invalid value %q", rng)
+			}
+			hi = lo
+			if step > 1 { // "N/STEP" means every STEP starting at N
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range [%d, %d]", rng, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+// next returns the first time (with minute precision) strictly after `t`
+// that matches the spec or zero time if there is none.
+func (c *cronSpec) next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchDay(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, time.UTC)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay follows cron: if both day of month and day of week are restricted,
+// the day matches if either of them matches.
+func (c *cronSpec) matchDay(t time.Time) bool {
+	var (
+		dom = c.dom&(1<<uint(t.Day())) != 0
+		dow = c.dow&(1<<uint(t.Weekday())) != 0
+	)
+	if c.anyDom || c.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,144 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec  string
+		valid bool
+	}{
+		{spec: "* * * * *", valid: true},
+		{spec: "0 3 * * 1", valid: true},
+		{spec: "*/15 0-6,22 1,15 */2 1-5", valid: true},
+		{spec: "5/10 * * * 7", valid: true},
+		{spec: "* * * *"},
+		{spec: "* * * * * *"},
+		{spec: "60 * * * *"},
+		{spec: "* 24 * * *"},
+		{spec: "* * 0 * *"},
+		{spec: "* * * 13 *"},
+		{spec: "* * * * 8"},
+		{spec: "5-1 * * * *"},
+		{spec: "*/0 * * * *"},
+		{spec: "a * * * *"},
+		{spec: "0 0 31 2 *"}, // never matches
+	}
+	for _, test := range tests {
+		_, err := parseCron(test.spec)
+		if test.valid {
+			tassert.Errorf(t, err == nil, "expected %q to be valid, err: %v", test.spec, err)
+		} else {
+			tassert.Errorf(t, err != nil, "expected %q to be invalid", test.spec)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Wednesday
+	now := time.Date(2020, time.July, 15, 10, 30, 45, 0, time.UTC)
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{spec: "* * * * *", expected: time.Date(2020, time.July, 15, 10, 31, 0, 0, time.UTC)},
+		{spec: "30 10 * * *", expected: time.Date(2020, time.July, 16, 10, 30, 0, 0, time.UTC)},
+		{spec: "*/20 * * * *", expected: time.Date(2020, time.July, 15, 10, 40, 0, 0, time.UTC)},
+		{spec: "0 3 * * 1", expected: time.Date(2020, time.July, 20, 3, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 * *", expected: time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * 1 *", expected: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 29 2 *", expected: time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 0", expected: time.Date(2020, time.July, 19, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", expected: time.Date(2020, time.July, 19, 0, 0, 0, 0, time.UTC)},
+		// both day of month and day of week restricted - either matches
+		{spec: "0 0 20 * 5", expected: time.Date(2020, time.July, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		spec, err := parseCron(test.spec)
+		tassert.CheckFatal(t, err)
+		next := spec.next(now)
+		tassert.Errorf(t, next.Equal(test.expected), "%q: expected next run at %v, got: %v",
+			test.spec, test.expected, next)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	var (
+		created  = time.Date(2020, time.July, 15, 10, 30, 0, 0, time.UTC)
+		schedule = &DlSchedule{Interval: "6h"}
+	)
+	tests := []struct {
+		after    time.Time
+		expected time.Time
+	}{
+		{after: created.Add(-time.Nanosecond), expected: created},
+		{after: created, expected: created.Add(6 * time.Hour)},
+		{after: created.Add(time.Hour), expected: created.Add(6 * time.Hour)},
+		{after: created.Add(6 * time.Hour), expected: created.Add(12 * time.Hour)},
+		{after: created.Add(100 * time.Hour), expected: created.Add(102 * time.Hour)},
+	}
+	for _, test := range tests {
+		next := schedule.next(created, test.after)
+		tassert.Errorf(t, next.Equal(test.expected), "after %v expected next run at %v, got: %v",
+			test.after, test.expected, next)
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	tests := []struct {
+		schedule DlSchedule
+		valid    bool
+	}{
+		{schedule: DlSchedule{Cron: "0 3 * * *"}, valid: true},
+		{schedule: DlSchedule{Interval: "24h", Sync: true}, valid: true},
+		{schedule: DlSchedule{}},
+		{schedule: DlSchedule{Cron: "0 3 * * *", Interval: "24h"}},
+		{schedule: DlSchedule{Interval: "30s"}},
+		{schedule: DlSchedule{Interval: "1day"}},
+		{schedule: DlSchedule{Cron: "0 3 * *"}},
+	}
+	for _, test := range tests {
+		err := test.schedule.Validate()
+		if test.valid {
+			tassert.Errorf(t, err == nil, "expected %+v to be valid, err: %v", test.schedule, err)
+		} else {
+			tassert.Errorf(t, err != nil, "expected %+v to be invalid", test.schedule)
+		}
+	}
+}
+
+func TestScheduleInfoAggregate(t *testing.T) {
+	var (
+		now = time.Now()
+		lhs = &DlScheduleInfo{
+			ID:      "sched",
+			NextRun: now.Add(time.Hour),
+			Runs: []DlJobInfo{
+				{ID: "sched-1", FinishedCnt: 1, StartedTime: now.Add(-2 * time.Hour)},
+			},
+		}
+		rhs = &DlScheduleInfo{
+			ID:      "sched",
+			NextRun: now.Add(time.Minute),
+			Runs: []DlJobInfo{
+				{ID: "sched-2", FinishedCnt: 3, StartedTime: now.Add(-time.Hour)},
+				{ID: "sched-1", FinishedCnt: 2, ErrorCnt: 1, StartedTime: now.Add(-2 * time.Hour)},
+			},
+		}
+	)
+	lhs.Aggregate(rhs)
+	tassert.Errorf(t, lhs.NextRun.Equal(rhs.NextRun), "expected next run %v, got: %v", rhs.NextRun, lhs.NextRun)
+	tassert.Fatalf(t, len(lhs.Runs) == 2, "expected 2 runs, got: %d", len(lhs.Runs))
+	tassert.Errorf(t, lhs.Runs[0].ID == "sched-1" && lhs.Runs[0].FinishedCnt == 3 && lhs.Runs[0].ErrorCnt == 1,
+		"expected aggregated run sched-1, got: %+v", lhs.Runs[0])
+	tassert.Errorf(t, lhs.Runs[1].ID == "sched-2" && lhs.Runs[1].FinishedCnt == 3,
+		"expected run sched-2, got: %+v", lhs.Runs[1])
+}
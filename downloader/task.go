@@ -53,12 +53,26 @@ type (
 		started atomic.Time
 		ended   atomic.Time
 
-		currentSize atomic.Int64 // the current size of the file (updated as the download progresses)
-		totalSize   atomic.Int64 // the total size of the file (nonzero only if Content-Length header was provided by the source of the file)
+		currentSize  atomic.Int64 // the current size of the file (updated as the download progresses)
+		totalSize    atomic.Int64 // the total size of the file (nonzero only if Content-Length header was provided by the source of the file)
+		resumedCnt   atomic.Int32 // number of times the download was resumed from the partial workfile
+		extractedCnt atomic.Int32 // number of files extracted from the archive (see DlExtract)
+
+		// Set only if the source supports range requests (see resumeValidator)
+		// - the content downloaded so far is then kept in the partial workfile
+		// and the retries continue from where the previous attempt stopped.
+		partialFQN string
+		validator  string // sent with If-Range so that changed source is downloaded from scratch
 
 		downloadCtx context.Context    // context with cancel function
 		cancelFunc  context.CancelFunc // used to cancel the download after the request commences
 	}
+	// checksums of the partial workfile computed as the content gets written
+	partialCksum struct {
+		cksum *cmn.CksumHash // as configured for the bucket
+		given *cmn.CksumHash // to validate against `expct`, if any
+		expct *cmn.Cksum
+	}
 )
 
 func (t *singleObjectTask) download() {
@@ -86,6 +100,7 @@ func (t *singleObjectTask) download() {
 	t.ended.Store(time.Now())
 
 	if err != nil {
+		t.removePartial()
 		t.markFailed(err.Error())
 		return
 	}
@@ -103,12 +118,13 @@ func (t *singleObjectTask) download() {
 func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Duration) error {
 	var (
 		workFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+		offset  = t.partialSize()
 	)
 
 	ctx, cancel := context.WithTimeout(t.downloadCtx, timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.obj.link, nil)
+	req, err := newSourceRequest(ctx, http.MethodGet, t.obj.link, t.job.credentials())
 	if err != nil {
 		return err
 	}
@@ -118,8 +134,12 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	if cmn.IsGoogleStorageURL(req.URL) {
 		req.Header.Add("User-Agent", cmn.GcsUA)
 	}
+	if offset > 0 {
+		req.Header.Set(cmn.HeaderRange, fmt.Sprintf("%s%d-", cmn.HeaderRangeValPrefix, offset))
+		req.Header.Set(cmn.HeaderIfRange, t.validator)
+	}
 
-	resp, err := clientForURL(t.obj.link).Do(req)
+	resp, err := clientForURL(req.URL.String()).Do(req)
 	if err != nil {
 		return err
 	}
@@ -128,6 +148,9 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	}()
 
 	if resp.StatusCode >= http.StatusBadRequest {
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			t.removePartial() // start from scratch with the next retry
+		}
 		return fmt.Errorf("request failed with %d status code (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
 
@@ -135,8 +158,44 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 		r   = t.wrapReader(ctx, resp.Body)
 		roi = roiFromLink(t.obj.link, resp)
 	)
+	if id := t.job.schedule(); id != "" {
+		roi.md[cluster.DlScheduleObjMD] = id
+	}
+
+	if resp.StatusCode == http.StatusPartialContent && offset > 0 {
+		if roi.size, err = resumedSize(resp, offset); err != nil {
+			t.removePartial()
+			return err
+		}
+		t.resumedCnt.Inc()
+		glog.Infof("%s: resuming download at offset %d", t, offset)
+	} else {
+		// Either the first attempt or the source has changed (or ignored the
+		// range) - the partial content, if any, is no longer valid.
+		t.removePartial()
+		offset = 0
+		t.validator = resumeValidator(resp)
+	}
 
+	if meta := t.obj.meta; meta != nil && meta.size > 0 {
+		if roi.size > 0 && roi.size != meta.size {
+			return fmt.Errorf("%s: size mismatch (expected %d, source reports %d)", t, meta.size, roi.size)
+		}
+		roi.size = meta.size
+	}
 	t.setTotalSize(roi.size)
+	t.currentSize.Store(offset)
+
+	// The archive itself is not stored, so there is nothing to resume from.
+	if t.job.extractor() != nil {
+		return t.extractArchive(lom, r, roi)
+	}
+
+	// NOTE: the objects with metadata provided with the request are downloaded
+	// the same way as resumable ones, to be validated before put into the bucket.
+	if t.validator != "" || t.obj.meta != nil {
+		return t.downloadPartial(lom, r, roi, offset)
+	}
 
 	lom.SetCustomMD(roi.md)
 	err = t.parent.t.PutObject(cluster.PutObjectParams{
@@ -144,6 +203,7 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 		Reader:       r,
 		WorkFQN:      workFQN,
 		RecvType:     cluster.ColdGet,
+		Cksum:        roi.cksum(),
 		Started:      t.started.Load(),
 		WithFinalize: true,
 	})
@@ -156,6 +216,142 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	return nil
 }
 
+// downloadPartial appends the content to the partial workfile which is kept
+// in case of failure, so that the next retry can resume the download.
+func (t *singleObjectTask) downloadPartial(lom *cluster.LOM, r io.Reader, roi remoteObjInfo, offset int64) (err error) {
+	var (
+		file      *os.File
+		pc        = t.newPartialCksum(lom, roi)
+		buf, slab = t.parent.t.GetMMSA().Alloc()
+	)
+	defer slab.Free(buf)
+	if offset == 0 {
+		t.partialFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileDownload)
+		file, err = lom.CreateFile(t.partialFQN)
+	} else if err = pc.resume(t.partialFQN, offset, buf); err == nil {
+		file, err = os.OpenFile(t.partialFQN, os.O_WRONLY|os.O_APPEND, 0)
+	}
+	if err != nil {
+		return err
+	}
+	written, err := io.CopyBuffer(cmn.NewWriterMulti(append([]io.Writer{file}, pc.writers()...)...), r, buf)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return err
+	}
+	if roi.size > 0 && offset+written != roi.size {
+		t.removePartial()
+		return fmt.Errorf("%s: downloaded %d bytes, expected %d", t, offset+written, roi.size)
+	}
+	return t.promotePartial(lom, roi, pc)
+}
+
+// promotePartial verifies the complete partial workfile and promotes it to the object.
+func (t *singleObjectTask) promotePartial(lom *cluster.LOM, roi remoteObjInfo, pc *partialCksum) error {
+	defer t.removePartial()
+
+	cksum, err := pc.validate(t.obj.link + " => " + lom.String())
+	if err != nil {
+		return err
+	}
+	nlom, err := t.parent.t.PromoteFile(t.partialFQN, lom.Bck(), lom.ObjName, cksum,
+		true /*overwrite*/, false /*safe*/, false /*verbose*/)
+	if err != nil {
+		return err
+	}
+	if nlom.Bck().IsAIS() {
+		nlom.Lock(true)
+		nlom.SetCustomMD(roi.md)
+		if t.obj.meta != nil && len(t.obj.meta.md) > 0 {
+			nlom.SetUserMD(t.obj.meta.md)
+		}
+		err = nlom.Persist()
+		nlom.Unlock(true)
+		if err != nil {
+			return err
+		}
+	}
+	return lom.Load()
+}
+
+func (t *singleObjectTask) partialSize() int64 {
+	if t.partialFQN == "" || t.validator == "" {
+		return 0
+	}
+	fi, err := os.Stat(t.partialFQN)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+func (t *singleObjectTask) removePartial() {
+	if t.partialFQN == "" {
+		return
+	}
+	if err := cmn.RemoveFile(t.partialFQN); err != nil {
+		glog.Errorf("%s: failed to remove %s, err: %v", t, t.partialFQN, err)
+	}
+	t.partialFQN = ""
+}
+
+//////////////////
+// partialCksum //
+//////////////////
+
+// newPartialCksum prepares the checksum (as configured for the bucket) of the
+// partial workfile and, to validate the content, the checksum provided with the
+// request or, same as cold GET, provided by the source, if any.
+func (t *singleObjectTask) newPartialCksum(lom *cluster.LOM, roi remoteObjInfo) *partialCksum {
+	conf := lom.CksumConf()
+	pc := &partialCksum{cksum: cmn.NewCksumHash(conf.Type)}
+	if t.obj.meta != nil && t.obj.meta.cksum != nil {
+		pc.expct = t.obj.meta.cksum
+	} else if conf.ValidateColdGet {
+		pc.expct = roi.cksum()
+	}
+	if pc.expct != nil {
+		pc.given = cmn.NewCksumHash(pc.expct.Type())
+	}
+	return pc
+}
+
+func (pc *partialCksum) writers() []io.Writer {
+	writers := []io.Writer{pc.cksum.H}
+	if pc.given != nil {
+		writers = append(writers, pc.given.H)
+	}
+	return writers
+}
+
+// resume hashes the content downloaded by the previous attempts.
+func (pc *partialCksum) resume(fqn string, offset int64, buf []byte) error {
+	file, err := os.Open(fqn)
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyBuffer(cmn.NewWriterMulti(pc.writers()...), io.LimitReader(file, offset), buf)
+	debug.AssertNoErr(file.Close())
+	return err
+}
+
+// validate returns the checksum to be stored with the object.
+func (pc *partialCksum) validate(what string) (*cmn.Cksum, error) {
+	if pc.given != nil {
+		pc.given.Finalize()
+		if !pc.given.Equal(pc.expct) {
+			return nil, cmn.NewBadDataCksumError(pc.given.Clone(), pc.expct, what)
+		}
+	}
+	if pc.cksum.Type() == cmn.ChecksumNone {
+		return nil, nil
+	}
+	pc.cksum.Finalize()
+	return pc.cksum.Clone(), nil
+}
+
 func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 	var (
 		httpErr = &cmn.HTTPError{}
@@ -211,6 +407,7 @@ func (t *singleObjectTask) setTotalSize(size int64) {
 func (t *singleObjectTask) reset() {
 	t.totalSize.Store(0)
 	t.currentSize.Store(0)
+	t.extractedCnt.Store(0)
 }
 
 func (t *singleObjectTask) downloadCloud(lom *cluster.LOM) error {
@@ -265,6 +462,8 @@ func (t *singleObjectTask) ToTaskDlInfo() TaskDlInfo {
 		Name:       t.obj.objName,
 		Downloaded: t.currentSize.Load(),
 		Total:      t.totalSize.Load(),
+		Resumed:    int(t.resumedCnt.Load()),
+		Extracted:  int(t.extractedCnt.Load()),
 
 		StartTime: t.started.Load(),
 		EndTime:   ended,
@@ -7,6 +7,7 @@ package downloader
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -21,6 +22,7 @@ import (
 
 var (
 	errInvalidTarget = errors.New("invalid target")
+	errInvalidDlType = errors.New("input does not match any of the supported formats (single, range, multi, cloud, manifest)")
 )
 
 // buildDlObjs returns list of objects that must be downloaded by target.
@@ -127,11 +129,60 @@ func ParseStartDownloadRequest(ctx context.Context, t cluster.Target, bck *clust
 		}
 		return newSingleDlJob(t, id, bck, dp)
 
+	case DlTypeManifest:
+		dp := &DlManifestBody{}
+		err := jsoniter.Unmarshal(dlb.RawMessage, dp)
+		if err != nil {
+			return nil, err
+		}
+		if err := dp.Validate(); err != nil {
+			return nil, err
+		}
+		return newManifestDlJob(t, id, bck, dp)
+
+	case DlTypeCrawl:
+		dp := &DlCrawlBody{}
+		err := jsoniter.Unmarshal(dlb.RawMessage, dp)
+		if err != nil {
+			return nil, err
+		}
+		if err := dp.Validate(); err != nil {
+			return nil, err
+		}
+		return newCrawlDlJob(t, id, bck, dp)
+
 	default:
-		return nil, errors.New("input does not match any of the supported formats (single, range, multi, cloud)")
+		return nil, errInvalidDlType
 	}
 }
 
+// parseDlBody parses and validates the request without starting the job.
+func parseDlBody(dlb DlBody) (payload interface {
+	Validate() error
+	Describe() string
+}, err error) {
+	switch dlb.Type {
+	case DlTypeCloud:
+		payload = &DlCloudBody{}
+	case DlTypeMulti:
+		payload = &DlMultiBody{}
+	case DlTypeRange:
+		payload = &DlRangeBody{}
+	case DlTypeSingle:
+		payload = &DlSingleBody{}
+	case DlTypeManifest:
+		payload = &DlManifestBody{}
+	case DlTypeCrawl:
+		payload = &DlCrawlBody{}
+	default:
+		return nil, errInvalidDlType
+	}
+	if err := jsoniter.Unmarshal(dlb.RawMessage, payload); err != nil {
+		return nil, err
+	}
+	return payload, payload.Validate()
+}
+
 //
 // Checksum and version validation helpers
 //
@@ -203,13 +254,68 @@ func roiFromLink(link string, resp *http.Response) (roi remoteObjInfo) {
 			roi.md[cluster.MD5ObjMD] = v
 		}
 	} else {
-		roi.md = make(cmn.SimpleKVs, 1)
+		roi.md = make(cmn.SimpleKVs, 3)
 		roi.md[cluster.SourceObjMD] = cluster.SourceWebObjMD
+		// Validators, if provided, allow to detect that the source has changed.
+		if v := resp.Header.Get(cmn.HeaderETag); v != "" {
+			roi.md[cluster.ETagObjMD] = v
+		}
+		if v := resp.Header.Get(cmn.HeaderLastModified); v != "" {
+			roi.md[cluster.LastModifiedObjMD] = v
+		}
 	}
 	roi.size = resp.ContentLength
 	return
 }
 
+// cksum returns the checksum of the object provided by the source, if any.
+func (roi *remoteObjInfo) cksum() *cmn.Cksum {
+	if v, ok := roi.md[cluster.MD5ObjMD]; ok {
+		return cmn.NewCksum(cmn.ChecksumMD5, v)
+	}
+	if v, ok := roi.md[cluster.CRC32CObjMD]; ok {
+		return cmn.NewCksum(cmn.ChecksumCRC32C, v)
+	}
+	return nil
+}
+
+// resumeValidator returns the validator (strong ETag) to be sent with If-Range
+// when resuming the download or empty string if the source does not support
+// range requests.
+func resumeValidator(resp *http.Response) string {
+	if resp.Header.Get(cmn.HeaderAcceptRanges) != "bytes" {
+		return ""
+	}
+	etag := resp.Header.Get(cmn.HeaderETag)
+	if strings.HasPrefix(etag, "W/") { // If-Range requires strong validator
+		return ""
+	}
+	return etag
+}
+
+// resumedSize validates Content-Range of the response to the range request
+// starting at `offset` and returns the total size of the object (-1 if unknown).
+func resumedSize(resp *http.Response, offset int64) (size int64, err error) {
+	var (
+		start, end int64
+		cr         = resp.Header.Get(cmn.HeaderContentRange)
+		parts      = strings.SplitN(strings.TrimPrefix(cr, cmn.HeaderContentRangeValPrefix), "/", 2)
+	)
+	if !strings.HasPrefix(cr, cmn.HeaderContentRangeValPrefix) || len(parts) != 2 {
+		return 0, fmt.Errorf("invalid %s: %q", cmn.HeaderContentRange, cr)
+	}
+	if _, err := fmt.Sscanf(parts[0], "%d-%d", &start, &end); err != nil || start != offset || end < start {
+		return 0, fmt.Errorf("invalid %s: %q (expected range starting at %d)", cmn.HeaderContentRange, cr, offset)
+	}
+	if parts[1] == "*" {
+		return -1, nil
+	}
+	if size, err = strconv.ParseInt(parts[1], 10, 64); err != nil || size <= end {
+		return 0, fmt.Errorf("invalid %s: %q", cmn.HeaderContentRange, cr)
+	}
+	return size, nil
+}
+
 func parseGoogleCksumHeader(hdr []string) cmn.SimpleKVs {
 	var (
 		h      = cmn.CloudHelpers.Google
@@ -226,14 +332,14 @@ func parseGoogleCksumHeader(hdr []string) cmn.SimpleKVs {
 	return cksums
 }
 
-func headLink(link string) (*http.Response, error) {
+func headLink(link string, creds *DlCredentials) (*http.Response, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), headReqTimeout)
 	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, link, nil)
+	req, err := newSourceRequest(ctx, http.MethodHead, link, creds)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := clientForURL(link).Do(req)
+	resp, err := clientForURL(req.URL.String()).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -270,8 +376,17 @@ func roiFromObjMeta(objMeta cmn.SimpleKVs) (roi remoteObjInfo) {
 
 func compareObjects(src *cluster.LOM, dst *DstElement) (equal bool, err error) {
 	var roi remoteObjInfo
+	if meta := dst.meta; meta != nil {
+		// The metadata provided with the request takes precedence.
+		if meta.size != 0 && meta.size != src.Size() {
+			return false, nil
+		}
+		if meta.cksum != nil && src.Cksum() != nil && src.Cksum().Type() == meta.cksum.Type() {
+			return src.Cksum().Equal(meta.cksum), nil
+		}
+	}
 	if dst.Link != "" {
-		resp, err := headLink(dst.Link)
+		resp, err := headLink(dst.Link, dst.creds)
 		if err != nil {
 			return false, err
 		}
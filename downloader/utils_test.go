@@ -5,9 +5,13 @@
 package downloader
 
 import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"testing"
 
 	"github.com/NVIDIA/aistore/cluster"
@@ -104,3 +108,96 @@ func downloadObject(link string) (string, error) {
 	_, err = io.Copy(f, resp.Body)
 	return f.Name(), err
 }
+
+func TestResumeValidator(t *testing.T) {
+	tests := []struct {
+		acceptRanges string
+		etag         string
+		expected     string
+	}{
+		{acceptRanges: "bytes", etag: `"abc"`, expected: `"abc"`},
+		{acceptRanges: "bytes", etag: `W/"abc"`, expected: ""},
+		{acceptRanges: "bytes", etag: "", expected: ""},
+		{acceptRanges: "none", etag: `"abc"`, expected: ""},
+		{acceptRanges: "", etag: `"abc"`, expected: ""},
+	}
+	for _, test := range tests {
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set(cmn.HeaderAcceptRanges, test.acceptRanges)
+		resp.Header.Set(cmn.HeaderETag, test.etag)
+		validator := resumeValidator(resp)
+		tassert.Errorf(t, validator == test.expected, "resumeValidator(%q, %q) expected: %q, got: %q",
+			test.acceptRanges, test.etag, test.expected, validator)
+	}
+}
+
+func TestResumedSize(t *testing.T) {
+	tests := []struct {
+		contentRange string
+		offset       int64
+		size         int64
+		valid        bool
+	}{
+		{contentRange: "bytes 100-199/200", offset: 100, size: 200, valid: true},
+		{contentRange: "bytes 100-149/*", offset: 100, size: -1, valid: true},
+		{contentRange: "bytes 0-199/200", offset: 100},
+		{contentRange: "bytes 100-199/150", offset: 100},
+		{contentRange: "bytes 100-99/200", offset: 100},
+		{contentRange: "bytes */200", offset: 100},
+		{contentRange: "100-199/200", offset: 100},
+		{contentRange: "", offset: 100},
+	}
+	for _, test := range tests {
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set(cmn.HeaderContentRange, test.contentRange)
+		size, err := resumedSize(resp, test.offset)
+		if !test.valid {
+			tassert.Errorf(t, err != nil, "resumedSize(%q, %d) expected to fail", test.contentRange, test.offset)
+			continue
+		}
+		tassert.CheckError(t, err)
+		tassert.Errorf(t, size == test.size, "resumedSize(%q, %d) expected: %d, got: %d",
+			test.contentRange, test.offset, test.size, size)
+	}
+}
+
+func TestPartialCksum(t *testing.T) {
+	var (
+		content = bytes.Repeat([]byte("0123456789"), 1000)
+		sum     = md5.Sum(content)
+		buf     = make([]byte, 256)
+	)
+	whole := cmn.NewCksumHash(cmn.ChecksumXXHash)
+	whole.H.Write(content)
+	whole.Finalize()
+
+	file, err := ioutil.TempFile("", "partial")
+	tassert.CheckFatal(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.Write(content)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, file.Close())
+
+	for _, offset := range []int64{0, 1, 4096, int64(len(content)) - 1} {
+		for _, expected := range []string{hex.EncodeToString(sum[:]), "bad"} {
+			pc := &partialCksum{
+				cksum: cmn.NewCksumHash(cmn.ChecksumXXHash),
+				given: cmn.NewCksumHash(cmn.ChecksumMD5),
+				expct: cmn.NewCksum(cmn.ChecksumMD5, expected),
+			}
+			if offset > 0 {
+				// NOTE: the file is longer than the prefix - must read no more than `offset` bytes
+				tassert.CheckFatal(t, pc.resume(file.Name(), offset, buf))
+			}
+			_, err = cmn.NewWriterMulti(pc.writers()...).Write(content[offset:])
+			tassert.CheckFatal(t, err)
+			cksum, err := pc.validate("test")
+			if expected == "bad" {
+				tassert.Errorf(t, err != nil, "offset %d: expected checksum mismatch", offset)
+				continue
+			}
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, cksum.Equal(whole.Clone()), "offset %d: expected %s, got %s", offset, whole.Clone(), cksum)
+		}
+	}
+}
@@ -0,0 +1,608 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// Checkpoints make dSort jobs resumable after a target failure. Each target
+// persists the progress of the job in the dSort database:
+//
+//   checkpoints/<uuid>        - request spec, last completed phase and the part
+//                               of the sort result assigned to the target
+//   created/<uuid>/<shard>    - marker of an output shard created by the target
+//
+// Contents of the extracted records live in memory (or in temporary work
+// files) and do not survive the restart, therefore the resumed job always
+// extracts the input shards again - there is no "extracted" phase to resume
+// from. However, the resumed job reuses the checkpointed sort result instead of
+// recomputing - so that the output shards have the same content as in the
+// interrupted job - and skips the shards that have already been created. Only
+// the records which are not covered by the sort result (eg. it was held by the
+// targets which did not rejoin the cluster) are assigned to new shards.
+//
+// The checkpoints are removed once the job finishes successfully, is aborted
+// by the user, or is removed from the history.
+
+const (
+	checkpointsKey = "checkpoints"
+	createdKey     = "created"
+
+	// Last completed phase of the job on the target.
+	CheckpointSorted  = "sorted"  // target received its part of the sort result
+	CheckpointCreated = "created" // target created all of its output shards
+
+	// resumeRetryInterval is the interval after which the resumer checks again
+	// the jobs which are still being aborted.
+	resumeRetryInterval = 10 * time.Second
+)
+
+var (
+	// interface guard
+	_ cluster.Slistener = &resumer{}
+)
+
+type (
+	// Checkpoint is the persisted progress of the dSort job on a single target.
+	Checkpoint struct {
+		ManagerUUID string             `json:"manager_uuid"`
+		RS          *ParsedRequestSpec `json:"request_spec"`
+		// IDs of the targets which participated in the job.
+		Targets []string `json:"targets"`
+		// Phase is the last completed phase (see Checkpoint* constants).
+		Phase string `json:"phase,omitempty"`
+		// Interrupted is set when the job was aborted due to the target
+		// failure (change of the number of targets or the restart of the
+		// target) - such jobs are resumed automatically.
+		Interrupted bool `json:"interrupted,omitempty"`
+		// TotalShards is the number of all output shards in the sort result,
+		// it is set only by the final target.
+		TotalShards int `json:"total_shards,omitempty"`
+		// Shards is the part of the sort result assigned to the target.
+		Shards  []*CheckpointShard `json:"shards,omitempty"`
+		Updated time.Time          `json:"updated"`
+
+		// Not persisted, filled when the checkpoint is requested by other node.
+		Created []string `json:"created,omitempty"` // output shards created by the target
+		Running bool     `json:"running,omitempty"` // job is still running on the target
+
+		daemonID string // target which returned the checkpoint (set by the proxy)
+	}
+
+	// CheckpointShard describes a single output shard of the sort result.
+	CheckpointShard struct {
+		Name    string   `json:"name"`
+		Records []string `json:"records"` // names of the records, in order
+	}
+
+	// sortResult is the sort result of the interrupted job, merged from the
+	// checkpoints of all the targets.
+	sortResult struct {
+		shards  map[string][]string // shard name => records
+		created cmn.StringSet
+		total   int
+	}
+
+	// resumer resumes the interrupted jobs with the targets which are
+	// currently in the cluster. It is registered only on the proxies and acts
+	// only when the proxy is primary.
+	resumer struct {
+		mtx     sync.Mutex
+		smapVer int64
+		retry   *time.Timer
+	}
+)
+
+func checkpointKey(managerUUID string) string { return path.Join(checkpointsKey, managerUUID) }
+func createdPrefix(managerUUID string) string { return path.Join(createdKey, managerUUID) + "/" }
+
+////////////////
+// Checkpoint //
+////////////////
+
+// complete returns true if every output shard of the sort result is known.
+func (sr *sortResult) complete() bool {
+	return sr.total > 0 && len(sr.shards) == sr.total
+}
+
+func (sr *sortResult) merge(cp *Checkpoint) {
+	sr.total = cmn.Max(sr.total, cp.TotalShards)
+	for _, s := range cp.Shards {
+		sr.shards[s.Name] = s.Records
+	}
+	for _, name := range cp.Created {
+		sr.created.Add(name)
+	}
+}
+
+// initCheckpoint creates the checkpoint of the job. If the job resumes other
+// (interrupted) job, the sort result and the created shards are taken over
+// from the checkpoint of the interrupted job which is then removed.
+//
+// NOTE: should be done under lock.
+func (m *Manager) initCheckpoint() error {
+	if m.mg == nil {
+		return nil // standalone manager, nowhere to persist the checkpoint
+	}
+	cp := &Checkpoint{
+		ManagerUUID: m.ManagerUUID,
+		RS:          m.rs,
+		Targets:     make([]string, 0, len(m.smap.Tmap)),
+		Updated:     time.Now(),
+	}
+	for sid := range m.smap.Tmap {
+		cp.Targets = append(cp.Targets, sid)
+	}
+	sort.Strings(cp.Targets)
+
+	if m.rs.Resume != "" {
+		prev, err := m.mg.loadCheckpoint(m.rs.Resume, true /*withCreated*/)
+		if err != nil {
+			return err
+		}
+		// The target may not have participated in the interrupted job.
+		if prev != nil {
+			cp.TotalShards, cp.Shards = prev.TotalShards, prev.Shards
+			for _, name := range prev.Created {
+				if err := m.mg.db.SetString(dsortCollection, createdPrefix(m.ManagerUUID)+name, ""); err != nil {
+					return err
+				}
+			}
+			m.mg.removeCheckpoint(m.rs.Resume)
+		}
+	}
+
+	m.checkpoint.cp = cp
+	return m.mg.db.Set(dsortCollection, checkpointKey(m.ManagerUUID), cp)
+}
+
+// updateCheckpoint applies the update to the checkpoint and persists it.
+// Checkpointing is best-effort: errors are logged but do not fail the job.
+func (m *Manager) updateCheckpoint(update func(cp *Checkpoint)) {
+	m.checkpoint.mu.Lock()
+	defer m.checkpoint.mu.Unlock()
+	cp := m.checkpoint.cp
+	if cp == nil {
+		return // removed (or never created)
+	}
+	update(cp)
+	cp.Updated = time.Now()
+	if err := m.mg.db.Set(dsortCollection, checkpointKey(m.ManagerUUID), cp); err != nil {
+		glog.Errorf("%s %s: failed to update checkpoint, err: %v", cmn.DSortName, m.ManagerUUID, err)
+	}
+}
+
+// checkpointShards records the part of the sort result assigned to the target.
+func (m *Manager) checkpointShards(shards []*extract.Shard) {
+	m.updateCheckpoint(func(cp *Checkpoint) {
+		idx := make(map[string]int, len(cp.Shards))
+		for i, s := range cp.Shards {
+			idx[s.Name] = i
+		}
+		for _, s := range shards {
+			cs := &CheckpointShard{Name: s.Name, Records: make([]string, 0, s.Records.Len())}
+			for _, r := range s.Records.All() {
+				cs.Records = append(cs.Records, r.Name)
+			}
+			if i, ok := idx[s.Name]; ok {
+				cp.Shards[i] = cs
+			} else {
+				cp.Shards = append(cp.Shards, cs)
+			}
+		}
+		cp.Phase = CheckpointSorted
+	})
+}
+
+func (m *Manager) checkpointShardCreated(shardName string) {
+	m.checkpoint.mu.Lock()
+	defer m.checkpoint.mu.Unlock()
+	if m.checkpoint.cp == nil {
+		return
+	}
+	if err := m.mg.db.SetString(dsortCollection, createdPrefix(m.ManagerUUID)+shardName, ""); err != nil {
+		glog.Errorf("%s %s: failed to checkpoint shard %q, err: %v", cmn.DSortName, m.ManagerUUID, shardName, err)
+	}
+}
+
+// discardCheckpoint removes the checkpoint - the job will not be resumable.
+func (m *Manager) discardCheckpoint() {
+	m.checkpoint.mu.Lock()
+	if m.checkpoint.cp != nil {
+		m.checkpoint.cp = nil
+		m.mg.removeCheckpoint(m.ManagerUUID)
+	}
+	m.checkpoint.mu.Unlock()
+}
+
+// loadSortResult merges the sort result checkpointed by all the targets.
+func (m *Manager) loadSortResult() (*sortResult, error) {
+	sr := &sortResult{shards: make(map[string][]string), created: make(cmn.StringSet)}
+	path := cmn.URLPath(cmn.Version, cmn.Sort, cmn.Checkpoint, m.ManagerUUID)
+	responses := broadcast(http.MethodGet, path, nil, nil, m.smap.Tmap)
+	for _, resp := range responses {
+		if resp.statusCode == http.StatusNotFound {
+			continue // new target, did not participate in the interrupted job
+		}
+		if resp.err != nil {
+			return nil, errors.Errorf("failed to get checkpoint from %s, err: %v", resp.si, resp.err)
+		}
+		cp := &Checkpoint{}
+		if err := jsoniter.Unmarshal(resp.res, cp); err != nil {
+			return nil, err
+		}
+		sr.merge(cp)
+	}
+	return sr, nil
+}
+
+// generateShardsFromCheckpoint recreates the output shards from the sort
+// result of the interrupted job. The shards which have already been created
+// are skipped - skipped maps daemon IDs to the number of objects which will
+// not be requested from given target.
+//
+// If the sort result is incomplete (the targets which held the rest of it did
+// not rejoin the cluster), the records which it does not cover are assigned to
+// new shards, the same way as in the fresh job, and sr.total is updated.
+func (m *Manager) generateShardsFromCheckpoint(sr *sortResult, maxSize int64) (shards []*extract.Shard,
+	skipped map[string]int64, err error) {
+	var (
+		names    = make([]string, 0, len(sr.shards))
+		records  = m.recManager.Records
+		used     = make(cmn.StringSet, records.Len())
+		complete = sr.complete()
+	)
+	for name := range sr.shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	skipped = make(map[string]int64, m.smap.CountTargets())
+	records.RLock()
+	for _, name := range names {
+		var (
+			created = sr.created.Contains(name)
+			shard   = &extract.Shard{Name: name, Records: extract.NewRecords(len(sr.shards[name]))}
+		)
+		for _, recordName := range sr.shards[name] {
+			used.Add(recordName)
+			r, ok := records.Find(recordName)
+			if !ok {
+				if created {
+					continue // not needed anymore
+				}
+				records.RUnlock()
+				return nil, nil, errors.Errorf("record %q of shard %q is missing (has the input changed?)", recordName, name)
+			}
+			if created {
+				skipped[r.DaemonID] += int64(len(r.Objects))
+				continue
+			}
+			shard.Size += r.TotalSize()
+			shard.Records.Insert(r)
+		}
+		if !created {
+			shards = append(shards, shard)
+		}
+	}
+	leftover := extract.NewRecords(records.Len() - len(used))
+	for _, r := range records.All() {
+		if !used.Contains(r.Name) {
+			leftover.Insert(r)
+		}
+	}
+	records.RUnlock()
+
+	if leftover.Len() > 0 {
+		if complete {
+			return nil, nil, errors.Errorf("extracted %d record(s) which are not in the sort result (has the input changed?)",
+				leftover.Len())
+		}
+		taken := make(cmn.StringSet, len(names)+len(sr.created))
+		taken.Add(names...)
+		for name := range sr.created {
+			taken.Add(name)
+		}
+		var rest []*extract.Shard
+		if m.rs.OrderFileURL != "" {
+			rest, err = m.generateShardsWithOrderingFile(maxSize, leftover, taken)
+		} else {
+			rest, err = m.generateShardsWithTemplate(maxSize, leftover, taken)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		glog.Infof("%s %s: sort result of %s is incomplete, assigned %d record(s) to %d new shard(s)",
+			cmn.DSortName, m.ManagerUUID, m.rs.Resume, leftover.Len(), len(rest))
+		shards = append(shards, rest...)
+		sr.total = len(names) + len(rest)
+	} else if !complete {
+		sr.total = len(names)
+	}
+
+	metrics := m.Metrics.Creation
+	metrics.Lock()
+	metrics.SkippedCnt = int64(sr.total - len(shards))
+	metrics.Unlock()
+	return shards, skipped, nil
+}
+
+//////////////////
+// ManagerGroup //
+//////////////////
+
+// loadCheckpoint returns the checkpoint of the job or nil if there is none.
+func (mg *ManagerGroup) loadCheckpoint(managerUUID string, withCreated bool) (*Checkpoint, error) {
+	cp := &Checkpoint{}
+	if err := mg.db.Get(dsortCollection, checkpointKey(managerUUID), cp); err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if withCreated {
+		created, err := mg.db.GetAll(dsortCollection, createdPrefix(managerUUID))
+		if err != nil && !dbdriver.IsErrNotFound(err) {
+			return nil, err
+		}
+		for key := range created {
+			cp.Created = append(cp.Created, strings.TrimPrefix(key, createdPrefix(managerUUID)))
+		}
+		sort.Strings(cp.Created)
+	}
+	return cp, nil
+}
+
+// listCheckpoints returns the checkpoints of all the jobs, without the sort
+// results and the created shards.
+func (mg *ManagerGroup) listCheckpoints() ([]*Checkpoint, error) {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	values, err := mg.db.GetAll(dsortCollection, checkpointsKey+"/")
+	if err != nil && !dbdriver.IsErrNotFound(err) {
+		return nil, err
+	}
+	cps := make([]*Checkpoint, 0, len(values))
+	for _, v := range values {
+		cp := &Checkpoint{}
+		if err := jsoniter.Unmarshal([]byte(v), cp); err != nil {
+			glog.Error(err)
+			continue
+		}
+		if m, ok := mg.managers[cp.ManagerUUID]; ok {
+			cp.Running = !m.aborted() && !m.Metrics.Archived.Load()
+		}
+		cp.Shards = nil
+		cps = append(cps, cp)
+	}
+	return cps, nil
+}
+
+func (mg *ManagerGroup) removeCheckpoint(managerUUID string) {
+	created, _ := mg.db.GetAll(dsortCollection, createdPrefix(managerUUID))
+	for key := range created {
+		_ = mg.db.Delete(dsortCollection, key)
+	}
+	_ = mg.db.Delete(dsortCollection, checkpointKey(managerUUID)) // checkpoint may not exist
+}
+
+// interruptCheckpoints marks all the checkpoints as interrupted. It is called
+// when the target starts - the jobs which left the checkpoints behind were
+// interrupted by the restart of the target.
+func (mg *ManagerGroup) interruptCheckpoints() {
+	values, err := mg.db.GetAll(dsortCollection, checkpointsKey+"/")
+	if err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Error(err)
+		}
+		return
+	}
+	for key, v := range values {
+		cp := &Checkpoint{}
+		if err := jsoniter.Unmarshal([]byte(v), cp); err != nil {
+			glog.Error(err)
+			continue
+		}
+		cp.Interrupted = true
+		if err := mg.db.Set(dsortCollection, key, cp); err != nil {
+			glog.Error(err)
+		}
+	}
+}
+
+// housekeepCheckpoints removes the checkpoints of the jobs which have not
+// been resumed for too long.
+//
+// NOTE: should be done under lock.
+func (mg *ManagerGroup) housekeepCheckpoints(maxAge time.Duration) {
+	values, err := mg.db.GetAll(dsortCollection, checkpointsKey+"/")
+	if err != nil {
+		return
+	}
+	for _, v := range values {
+		cp := &Checkpoint{}
+		if err := jsoniter.Unmarshal([]byte(v), cp); err != nil {
+			glog.Error(err)
+			continue
+		}
+		if _, running := mg.managers[cp.ManagerUUID]; !running && time.Since(cp.Updated) > maxAge {
+			mg.removeCheckpoint(cp.ManagerUUID)
+		}
+	}
+}
+
+/////////////
+// resumer //
+/////////////
+
+func (*resumer) String() string { return cmn.DSortNameLowercase + "-resumer" }
+
+func (r *resumer) ListenSmapChanged() {
+	smap := ctx.smapOwner.Get()
+	if smap.Version <= r.smapVer {
+		return
+	}
+	r.smapVer = smap.Version
+	if smap.Primary == nil || !smap.Primary.Equals(ctx.node) {
+		return
+	}
+	go r.resumeInterrupted(smap)
+}
+
+// resumeInterrupted resumes the interrupted jobs with the current targets -
+// the surviving ones and those which have (re)joined the cluster.
+//
+// The job is still running on the targets until they notice the change of the
+// Smap and abort it; since no other Smap change may follow (eg. the target
+// is gone for good), such jobs are checked again after resumeRetryInterval.
+func (r *resumer) resumeInterrupted(smap *cluster.Smap) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	jobs, err := collectCheckpoints(smap)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	var retry bool
+outer:
+	for managerUUID, cps := range jobs {
+		var interrupted bool
+		for _, cp := range cps {
+			if cp.Running {
+				retry = retry || !sameTargets(cp.Targets, smap)
+				continue outer
+			}
+			interrupted = interrupted || cp.Interrupted
+		}
+		if !interrupted {
+			continue
+		}
+		if stale := staleCheckpoint(cps, smap); stale {
+			// Resumed (or aborted) while some of the targets were away - the
+			// checkpoints are leftovers of the targets which have rejoined.
+			glog.Infof("removing stale checkpoint(s) of %s %s", cmn.DSortName, managerUUID)
+			removeCheckpoints(managerUUID, cps, smap)
+			continue
+		}
+		newUUID, err := resumeJob(cps[0])
+		if err != nil {
+			glog.Errorf("failed to resume %s %s, err: %v", cmn.DSortName, managerUUID, err)
+			continue
+		}
+		glog.Infof("resumed interrupted %s %s as %s", cmn.DSortName, managerUUID, newUUID)
+	}
+	if retry && r.retry == nil {
+		r.retry = time.AfterFunc(resumeRetryInterval, r.resumeAgain)
+	}
+}
+
+func (r *resumer) resumeAgain() {
+	r.mtx.Lock()
+	r.retry = nil
+	r.mtx.Unlock()
+	smap := ctx.smapOwner.Get()
+	if smap.Primary == nil || !smap.Primary.Equals(ctx.node) {
+		return
+	}
+	r.resumeInterrupted(smap)
+}
+
+// sameTargets returns true if the job has been started with the targets which
+// are currently in the cluster.
+func sameTargets(targets []string, smap *cluster.Smap) bool {
+	if len(targets) != smap.CountTargets() {
+		return false
+	}
+	for _, sid := range targets {
+		if smap.GetTarget(sid) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// staleCheckpoint returns true if some of the targets which took part in the
+// job are in the cluster but no longer have its checkpoint. The checkpoint is
+// removed when the job is resumed, finishes, or is aborted by the user, so the
+// remaining checkpoints must have been left behind by the targets which were
+// away at the time.
+func staleCheckpoint(cps []*Checkpoint, smap *cluster.Smap) bool {
+	have := make(cmn.StringSet, len(cps))
+	for _, cp := range cps {
+		have.Add(cp.daemonID)
+	}
+	for _, sid := range cps[0].Targets {
+		if smap.GetTarget(sid) != nil && !have.Contains(sid) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeCheckpoints removes the checkpoints of the job from the targets which have it.
+func removeCheckpoints(managerUUID string, cps []*Checkpoint, smap *cluster.Smap) {
+	var (
+		nodes = make(cluster.NodeMap, len(cps))
+		path  = cmn.URLPath(cmn.Version, cmn.Sort, cmn.Checkpoint, managerUUID)
+	)
+	for _, cp := range cps {
+		if si := smap.GetTarget(cp.daemonID); si != nil {
+			nodes[si.ID()] = si
+		}
+	}
+	for _, resp := range broadcast(http.MethodDelete, path, nil, nil, nodes) {
+		if resp.err != nil {
+			glog.Errorf("failed to remove checkpoint of %s %s from %s, err: %v",
+				cmn.DSortName, managerUUID, resp.si, resp.err)
+		}
+	}
+}
+
+// collectCheckpoints gathers the checkpoints from all the targets, grouped by
+// the job.
+func collectCheckpoints(smap *cluster.Smap) (map[string][]*Checkpoint, error) {
+	var (
+		jobs      = make(map[string][]*Checkpoint)
+		path      = cmn.URLPath(cmn.Version, cmn.Sort, cmn.Checkpoint)
+		responses = broadcast(http.MethodGet, path, nil, nil, smap.Tmap)
+	)
+	for _, resp := range responses {
+		if resp.err != nil {
+			return nil, fmt.Errorf("failed to list checkpoints of %s, err: %v", resp.si, resp.err)
+		}
+		var cps []*Checkpoint
+		if err := jsoniter.Unmarshal(resp.res, &cps); err != nil {
+			return nil, err
+		}
+		for _, cp := range cps {
+			cp.daemonID = resp.si.ID()
+			jobs[cp.ManagerUUID] = append(jobs[cp.ManagerUUID], cp)
+		}
+	}
+	return jobs, nil
+}
+
+// resumeJob starts a new job which resumes the one described by the checkpoint.
+func resumeJob(cp *Checkpoint) (string, error) {
+	rs := cp.RS
+	rs.Resume = cp.ManagerUUID
+	rs.TargetOrderSalt = []byte(time.Now().Format("15:04:05.000000"))
+	return startJob(rs)
+}
@@ -0,0 +1,247 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"os"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checkpoint", func() {
+	var (
+		mgrp *ManagerGroup
+		db   dbdriver.Driver
+	)
+
+	newRS := func() *ParsedRequestSpec {
+		return &ParsedRequestSpec{
+			Extension:   cmn.ExtTar,
+			Algorithm:   &SortAlgorithm{Kind: SortKindNone},
+			MaxMemUsage: cmn.ParsedQuantity{Type: cmn.QuantityPercent, Value: 0},
+			DSorterType: DSorterGeneralType,
+		}
+	}
+
+	newManager := func(uuid string, rs *ParsedRequestSpec) *Manager {
+		m, err := mgrp.Add(uuid)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(rs)).NotTo(HaveOccurred())
+		m.unlock()
+		return m
+	}
+
+	newShard := func(name string, records ...string) *extract.Shard {
+		shard := &extract.Shard{Name: name, Records: extract.NewRecords(len(records))}
+		for _, r := range records {
+			shard.Records.Insert(&extract.Record{Name: r})
+		}
+		return shard
+	}
+
+	BeforeEach(func() {
+		err := cmn.CreateDir(testingConfigDir)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ctx.smapOwner = newTestSmap("target1", "target2")
+		ctx.node = ctx.smapOwner.Get().Tmap["target1"]
+		ctx.node.DaemonID = "target1"
+
+		db = dbdriver.NewDBMock()
+		mgrp = NewManagerGroup(db)
+
+		fs.Init()
+		fs.Add(testingConfigDir)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(testingConfigDir)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should checkpoint progress of the job", func() {
+		m := newManager("uuid", newRS())
+
+		cp, err := mgrp.loadCheckpoint("uuid", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cp).ToNot(BeNil())
+		Expect(cp.Targets).To(Equal([]string{"target1", "target2"}))
+		Expect(cp.Phase).To(BeEmpty())
+
+		m.checkpointShards([]*extract.Shard{newShard("shard-1.tar", "a|1", "a|2"), newShard("shard-2.tar", "b|1")})
+		m.checkpointShards([]*extract.Shard{newShard("shard-1.tar", "a|1"), newShard("shard-3.tar", "c|1")})
+		m.checkpointShardCreated("shard-1.tar")
+		m.checkpointShardCreated("dir/shard-3.tar")
+
+		cp, err = mgrp.loadCheckpoint("uuid", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cp.Phase).To(Equal(CheckpointSorted))
+		Expect(cp.Shards).To(Equal([]*CheckpointShard{
+			{Name: "shard-1.tar", Records: []string{"a|1"}},
+			{Name: "shard-2.tar", Records: []string{"b|1"}},
+			{Name: "shard-3.tar", Records: []string{"c|1"}},
+		}))
+		Expect(cp.Created).To(Equal([]string{"dir/shard-3.tar", "shard-1.tar"}))
+
+		m.discardCheckpoint()
+		cp, err = mgrp.loadCheckpoint("uuid", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cp).To(BeNil())
+		all, _ := db.GetAll(dsortCollection, "")
+		Expect(all).To(BeEmpty())
+
+		// No longer checkpointed.
+		m.checkpointShardCreated("shard-2.tar")
+		all, _ = db.GetAll(dsortCollection, "")
+		Expect(all).To(BeEmpty())
+	})
+
+	It("should take over checkpoint of the interrupted job", func() {
+		m := newManager("uuid1", newRS())
+		m.checkpointShards([]*extract.Shard{newShard("shard-1.tar", "a|1")})
+		m.updateCheckpoint(func(cp *Checkpoint) { cp.TotalShards = 2 })
+		m.checkpointShardCreated("shard-1.tar")
+
+		rs := newRS()
+		rs.Resume = "uuid1"
+		m = newManager("uuid2", rs)
+		Expect(m.Metrics.ResumedFrom).To(Equal("uuid1"))
+
+		cp, err := mgrp.loadCheckpoint("uuid1", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cp).To(BeNil())
+
+		cp, err = mgrp.loadCheckpoint("uuid2", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cp.RS.Resume).To(Equal("uuid1"))
+		Expect(cp.TotalShards).To(Equal(2))
+		Expect(cp.Shards).To(HaveLen(1))
+		Expect(cp.Created).To(Equal([]string{"shard-1.tar"}))
+	})
+
+	It("should list and interrupt checkpoints", func() {
+		newManager("uuid1", newRS())
+		m := newManager("uuid2", newRS())
+		m.checkpointShards([]*extract.Shard{newShard("shard-1.tar", "a|1")})
+		m.lock()
+		m.setInProgressTo(false)
+		m.setAbortedTo(true)
+		m.unlock()
+
+		mgrp.interruptCheckpoints()
+		cps, err := mgrp.listCheckpoints()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cps).To(HaveLen(2))
+		for _, cp := range cps {
+			Expect(cp.Interrupted).To(BeTrue())
+			Expect(cp.Shards).To(BeNil())
+			Expect(cp.Running).To(Equal(cp.ManagerUUID == "uuid1"))
+		}
+	})
+
+	It("should detect stale checkpoints", func() {
+		smap := newTestSmap("target1", "target2").Get()
+		cp := func(sid string) *Checkpoint {
+			return &Checkpoint{Targets: []string{"target1", "target2", "target3"}, daemonID: sid}
+		}
+		Expect(sameTargets(cp("").Targets, smap)).To(BeFalse())
+		Expect(sameTargets([]string{"target1", "target2"}, smap)).To(BeTrue())
+
+		// target3 is gone - resume with the survivors
+		Expect(staleCheckpoint([]*Checkpoint{cp("target1"), cp("target2")}, smap)).To(BeFalse())
+		// resumed while target2 was away
+		Expect(staleCheckpoint([]*Checkpoint{cp("target2")}, smap)).To(BeTrue())
+	})
+
+	Describe("generateShardsFromCheckpoint", func() {
+		var m *Manager
+
+		BeforeEach(func() {
+			m = newManager("uuid", newRS())
+			m.recManager.Records.Insert(
+				&extract.Record{Name: "a|1", DaemonID: "target1", Objects: []*extract.RecordObj{{Size: 1}, {Size: 2}}},
+				&extract.Record{Name: "a|2", DaemonID: "target2", Objects: []*extract.RecordObj{{Size: 3}}},
+				&extract.Record{Name: "b|1", DaemonID: "target2", Objects: []*extract.RecordObj{{Size: 4}}},
+			)
+		})
+
+		It("should skip already created shards", func() {
+			sr := &sortResult{
+				shards: map[string][]string{
+					"shard-1.tar": {"b|1", "a|1"},
+					"shard-2.tar": {"a|2"},
+				},
+				created: cmn.NewStringSet("shard-2.tar"),
+				total:   2,
+			}
+			Expect(sr.complete()).To(BeTrue())
+
+			shards, skipped, err := m.generateShardsFromCheckpoint(sr, 0)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(shards).To(HaveLen(1))
+			Expect(shards[0].Name).To(Equal("shard-1.tar"))
+			Expect(shards[0].Size).To(Equal(int64(7)))
+			Expect(shards[0].Records.All()[0].Name).To(Equal("b|1"))
+			Expect(shards[0].Records.All()[1].Name).To(Equal("a|1"))
+			Expect(skipped).To(Equal(map[string]int64{"target2": 1}))
+			Expect(m.Metrics.Creation.SkippedCnt).To(Equal(int64(1)))
+		})
+
+		It("should fail when the records do not match the sort result", func() {
+			sr := &sortResult{
+				shards:  map[string][]string{"shard-1.tar": {"a|1", "a|2"}},
+				created: cmn.NewStringSet(),
+				total:   1,
+			}
+			_, _, err := m.generateShardsFromCheckpoint(sr, 0)
+			Expect(err).Should(HaveOccurred())
+
+			sr.shards["shard-1.tar"] = []string{"a|1", "a|2", "b|1", "c|1"}
+			_, _, err = m.generateShardsFromCheckpoint(sr, 0)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should assign the records missing from incomplete sort result to new shards", func() {
+			var err error
+			m.rs.OutputFormat, err = parseOutputFormat("shard-{1..4}")
+			Expect(err).ShouldNot(HaveOccurred())
+			m.rs.OutputExtension = cmn.ExtTar
+			m.recManager.Records.Insert(
+				&extract.Record{Name: "c|1", DaemonID: "target1", Objects: []*extract.RecordObj{{Size: 5}}},
+			)
+			// The final target (and its part of the sort result) is gone.
+			sr := &sortResult{
+				shards:  map[string][]string{"shard-2.tar": {"a|1", "a|2"}},
+				created: cmn.NewStringSet("shard-2.tar", "shard-1.tar"),
+			}
+			Expect(sr.complete()).To(BeFalse())
+
+			shards, skipped, err := m.generateShardsFromCheckpoint(sr, 6)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(skipped).To(Equal(map[string]int64{"target1": 2, "target2": 1}))
+			Expect(shards).To(HaveLen(1))
+			Expect(shards[0].Name).To(Equal("shard-3.tar"))
+			Expect(shards[0].Records.Len()).To(Equal(2))
+			Expect(shards[0].Records.All()[0].Name).To(Equal("b|1"))
+			Expect(shards[0].Records.All()[1].Name).To(Equal("c|1"))
+			Expect(sr.total).To(Equal(2))
+			Expect(m.Metrics.Creation.SkippedCnt).To(Equal(int64(1)))
+		})
+
+		It("should report incomplete sort result", func() {
+			sr := &sortResult{shards: make(map[string][]string), created: cmn.NewStringSet()}
+			sr.merge(&Checkpoint{TotalShards: 2, Shards: []*CheckpointShard{{Name: "shard-1.tar"}}})
+			Expect(sr.complete()).To(BeFalse())
+			sr.merge(&Checkpoint{Shards: []*CheckpointShard{{Name: "shard-2.tar"}}, Created: []string{"shard-2.tar"}})
+			Expect(sr.complete()).To(BeTrue())
+			Expect(sr.created.Contains("shard-2.tar")).To(BeTrue())
+		})
+	})
+})
@@ -134,11 +134,17 @@ func (m *Manager) start() (err error) {
 		return newDsortAbortedError(m.ManagerUUID)
 	}
 
+	m.checkpointShards(m.creationPhase.metadata.Shards)
+	// Contents of the records from the already created shards (see
+	// Checkpoint) will not be requested.
+	m.decrementRef(m.creationPhase.metadata.SkippedObjCnt)
+
 	// After each target participates in the cluster-wide record distribution,
 	// start listening for the signal to start creating shards locally.
 	if err := m.dsorter.createShardsLocally(); err != nil {
 		return err
 	}
+	m.updateCheckpoint(func(cp *Checkpoint) { cp.Phase = CheckpointCreated })
 
 	glog.Infof("finished %s %s successfully", cmn.DSortName, m.ManagerUUID)
 	return nil
@@ -202,6 +208,10 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 
 		expectedUncompressedSize := uint64(float64(lom.Size()) / m.avgCompressionRatio())
 		toDisk := m.dsorter.preShardExtraction(expectedUncompressedSize)
+		if m.rs.Manifest != "" && m.extractCreator.SupportsOffset() {
+			// Virtual shuffle does not need the contents of the records.
+			toDisk = true
+		}
 
 		beforeExtraction := mono.NanoTime()
 		reader := io.NewSectionReader(f, 0, lom.Size())
@@ -301,13 +311,60 @@ ExtractAllShards:
 	// We will no longer reserve any memory
 	m.dsorter.postExtraction()
 
+	droppedCount, err := m.checkSamples()
+	if err != nil {
+		return err
+	}
+
 	metrics.Lock()
-	totalExtractedCount := metrics.ExtractedRecordCnt
+	totalExtractedCount := metrics.ExtractedRecordCnt - droppedCount
 	metrics.Unlock()
 	m.incrementRef(totalExtractedCount)
 	return nil
 }
 
+// checkSamples verifies that all locally extracted records are complete
+// samples, ie. that each of them has the objects with all the extensions
+// listed in SampleSpec. Since a record never spans multiple shards, this can
+// be done before the records are distributed. Returns the number of objects
+// which were removed together with the dropped samples.
+func (m *Manager) checkSamples() (droppedCount int64, err error) {
+	samples := m.rs.Samples
+	if samples == nil {
+		return 0, nil
+	}
+
+	if samples.MissingMembers == MissingMembersDrop {
+		recordCnt, objectCnt := m.recManager.Records.DeleteIncomplete(samples.Extensions)
+		if recordCnt > 0 {
+			glog.Infof("%s %s: dropped %d incomplete sample(s)", cmn.DSortName, m.ManagerUUID, recordCnt)
+		}
+		metrics := m.Metrics.Extraction
+		metrics.Lock()
+		metrics.DroppedSampleCnt += int64(recordCnt)
+		metrics.Unlock()
+		return int64(objectCnt), nil
+	}
+
+	var (
+		incompleteCnt int
+		example       string
+	)
+	for _, record := range m.recManager.Records.All() {
+		if missing := record.Missing(samples.Extensions); len(missing) > 0 {
+			if incompleteCnt == 0 {
+				example = fmt.Sprintf("%q is missing %v", record.Name, missing)
+			}
+			incompleteCnt++
+		}
+	}
+	if incompleteCnt == 0 {
+		return 0, nil
+	}
+	msg := fmt.Sprintf("found %d incomplete sample(s), eg. %s", incompleteCnt, example)
+	return 0, m.react(samples.MissingMembers, msg)
+}
+
 func (m *Manager) createShard(s *extract.Shard) (err error) {
 	var (
 		loadContent = m.dsorter.loadContent()
@@ -410,46 +467,15 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	if si.DaemonID != m.ctx.node.DaemonID && !m.rs.DryRun {
 		lom.Lock(false)
 		defer lom.Unlock(false)
-
-		file, err := cmn.NewFileHandle(lom.FQN)
-		if err != nil {
+		if err := m.sendShard(lom, si); err != nil {
 			return err
 		}
+	}
 
-		if lom.Size() <= 0 {
-			goto exit
-		}
-
-		cksumType, cksumValue := lom.Cksum().Get()
-		hdr := transport.Header{
-			Bck:     lom.Bck().Bck,
-			ObjName: shardName,
-			ObjAttrs: transport.ObjectAttrs{
-				Size:       lom.Size(),
-				CksumType:  cksumType,
-				CksumValue: cksumValue,
-			},
-		}
-
-		// Make send synchronous
-		streamWg := &sync.WaitGroup{}
-		errCh := make(chan error, 1)
-		cb := func(_ transport.Header, _ io.ReadCloser, _ unsafe.Pointer, err error) {
-			errCh <- err
-			streamWg.Done()
-		}
-		streamWg.Add(1)
-		err = m.streams.shards.Send(transport.Obj{Hdr: hdr, Callback: cb}, file, si)
-		if err != nil {
-			return err
-		}
-		streamWg.Wait()
-		if err := <-errCh; err != nil {
-			return err
-		}
+	if !m.rs.DryRun {
+		m.checkpointShardCreated(shardName)
 	}
 
-exit:
 	metrics.Lock()
 	metrics.CreatedCnt++
 	if si.DaemonID != m.ctx.node.DaemonID {
@@ -465,6 +491,47 @@ exit:
 	return nil
 }
 
+// sendShard synchronously sends the locally created object (shard) to the
+// target it belongs to according to HRW.
+//
+// NOTE: Should be used under the object's (read) lock.
+func (m *Manager) sendShard(lom *cluster.LOM, si *cluster.Snode) error {
+	file, err := cmn.NewFileHandle(lom.FQN)
+	if err != nil {
+		return err
+	}
+
+	if lom.Size() <= 0 {
+		return nil
+	}
+
+	cksumType, cksumValue := lom.Cksum().Get()
+	hdr := transport.Header{
+		Bck:     lom.Bck().Bck,
+		ObjName: lom.ObjName,
+		ObjAttrs: transport.ObjectAttrs{
+			Size:       lom.Size(),
+			CksumType:  cksumType,
+			CksumValue: cksumValue,
+		},
+	}
+
+	// Make send synchronous
+	streamWg := &sync.WaitGroup{}
+	errCh := make(chan error, 1)
+	cb := func(_ transport.Header, _ io.ReadCloser, _ unsafe.Pointer, err error) {
+		errCh <- err
+		streamWg.Done()
+	}
+	streamWg.Add(1)
+	err = m.streams.shards.Send(transport.Obj{Hdr: hdr, Callback: cb}, file, si)
+	if err != nil {
+		return err
+	}
+	streamWg.Wait()
+	return <-errCh
+}
+
 // participateInRecordDistribution coordinates the distributed merging and
 // sorting of each target's SortedRecords based on the order defined by
 // targetOrder. It returns a bool, currentTargetIsFinal, which is true iff the
@@ -604,11 +671,15 @@ func (m *Manager) participateInRecordDistribution(targetOrder cluster.Nodes) (cu
 	return true, err
 }
 
-func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, error) {
+// generateShardsWithTemplate assigns the records to the output shards named
+// after the output template. The names in taken (already assigned by the
+// interrupted job) are skipped.
+func (m *Manager) generateShardsWithTemplate(maxSize int64, records *extract.Records,
+	taken cmn.StringSet) ([]*extract.Shard, error) {
 	var (
-		n               = m.recManager.Records.Len()
+		n               = records.Len()
 		names           = m.rs.OutputFormat.Template.Iter()
-		shardCount      = m.rs.OutputFormat.Template.Count()
+		shardCount      = m.rs.OutputFormat.Template.Count() - int64(len(taken))
 		start           int
 		curShardSize    int64
 		shards          = make([]*extract.Shard, 0)
@@ -616,11 +687,22 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 	)
 
 	if maxSize <= 0 {
+		if shardCount <= 0 {
+			return nil, errors.Errorf("number of shards to be created exceeds expected number of shards (%d)",
+				m.rs.OutputFormat.Template.Count())
+		}
 		// Heuristic: to count desired size of shard in case when maxSize is not specified.
-		maxSize = int64(math.Ceil(float64(m.totalUncompressedSize()) / float64(shardCount)))
+		totalSize := m.totalUncompressedSize()
+		if len(taken) > 0 {
+			totalSize = 0
+			for _, r := range records.All() {
+				totalSize += r.TotalSize()
+			}
+		}
+		maxSize = int64(math.Ceil(float64(totalSize) / float64(shardCount)))
 	}
 
-	for i, r := range m.recManager.Records.All() {
+	for i, r := range records.All() {
 		numLocalRecords[r.DaemonID]++
 		curShardSize += r.TotalSize()
 		if curShardSize < maxSize && i < n-1 {
@@ -628,16 +710,20 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 		}
 
 		name, hasNext := names()
+		for hasNext && taken.Contains(name+m.rs.OutputExtension) {
+			name, hasNext = names()
+		}
 		if !hasNext {
 			// no more shard names are available
-			return nil, errors.Errorf("number of shards to be created exceeds expected number of shards (%d)", shardCount)
+			return nil, errors.Errorf("number of shards to be created exceeds expected number of shards (%d)",
+				m.rs.OutputFormat.Template.Count())
 		}
 		shard := &extract.Shard{
-			Name: name + m.rs.Extension,
+			Name: name + m.rs.OutputExtension,
 		}
 
 		shard.Size = curShardSize
-		shard.Records = m.recManager.Records.Slice(start, i+1)
+		shard.Records = records.Slice(start, i+1)
 		shards = append(shards, shard)
 
 		start = i + 1
@@ -650,11 +736,16 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 	return shards, nil
 }
 
-func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shard, error) {
+// generateShardsWithOrderingFile assigns the records to the output shards
+// according to the external key map. The names in taken (already assigned by
+// the interrupted job) are skipped.
+func (m *Manager) generateShardsWithOrderingFile(maxSize int64, records *extract.Records,
+	taken cmn.StringSet) ([]*extract.Shard, error) {
 	var (
 		shards         = make([]*extract.Shard, 0)
 		externalKeyMap = make(map[string]string)
 		shardsBuilder  = make(map[string][]*extract.Shard)
+		nextShardIdx   = make(map[string]int)
 	)
 
 	if maxSize <= 0 {
@@ -705,7 +796,7 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 		externalKeyMap[recordKey] = shardNameFmt
 	}
 
-	for _, r := range m.recManager.Records.All() {
+	for _, r := range records.All() {
 		key := fmt.Sprintf("%v", r.Key)
 		shardNameFmt, ok := externalKeyMap[key]
 		if !ok {
@@ -719,8 +810,13 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 		recordSize := r.TotalSize() + m.extractCreator.MetadataSize()*int64(len(r.Objects))
 		shardCount := len(shards)
 		if shardCount == 0 || shards[shardCount-1].Size > maxSize {
+			idx := nextShardIdx[shardNameFmt]
+			for taken.Contains(fmt.Sprintf(shardNameFmt, idx)) {
+				idx++
+			}
+			nextShardIdx[shardNameFmt] = idx + 1
 			shard := &extract.Shard{
-				Name:    fmt.Sprintf(shardNameFmt, shardCount),
+				Name:    fmt.Sprintf(shardNameFmt, idx),
 				Size:    recordSize,
 				Records: extract.NewRecords(1),
 			}
@@ -758,8 +854,9 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 //      sent to it already).
 func (m *Manager) distributeShardRecords(maxSize int64) error {
 	var (
-		shards []*extract.Shard
-		err    error
+		shards  []*extract.Shard
+		skipped map[string]int64
+		err     error
 
 		wg             = &sync.WaitGroup{}
 		shardsToTarget = make(map[*cluster.Snode][]*extract.Shard, m.smap.CountTargets())
@@ -775,15 +872,58 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 		}
 	}
 
-	if m.rs.OrderFileURL != "" {
-		shards, err = m.generateShardsWithOrderingFile(maxSize)
+	// When resuming the interrupted job, reuse its sort result so that the
+	// shards which have already been created stay consistent. The sort result
+	// may be incomplete (eg. some of the targets did not rejoin) - the records
+	// which it does not cover are assigned to new shards.
+	var sr *sortResult
+	if m.rs.Resume != "" {
+		if sr, err = m.loadSortResult(); err != nil {
+			return err
+		}
+		if len(sr.shards) == 0 {
+			glog.Warningf("%s %s: sort result of %s is missing, recomputing (%d created shard(s) will be overwritten)",
+				cmn.DSortName, m.ManagerUUID, m.rs.Resume, len(sr.created))
+			sr = nil
+		}
+	}
+
+	if sr != nil {
+		shards, skipped, err = m.generateShardsFromCheckpoint(sr, maxSize)
+	} else if m.rs.OrderFileURL != "" {
+		shards, err = m.generateShardsWithOrderingFile(maxSize, m.recManager.Records, nil)
 	} else {
-		shards, err = m.generateShardsWithTemplate(maxSize)
+		shards, err = m.generateShardsWithTemplate(maxSize, m.recManager.Records, nil)
 	}
 
 	if err != nil {
 		return err
 	}
+	if sr != nil {
+		glog.Infof("%s %s: resuming shard creation, %d out of %d shard(s) already created",
+			cmn.DSortName, m.ManagerUUID, sr.total-len(shards), sr.total)
+		m.updateCheckpoint(func(cp *Checkpoint) { cp.TotalShards = sr.total })
+	} else {
+		m.updateCheckpoint(func(cp *Checkpoint) { cp.TotalShards = len(shards) })
+	}
+
+	// Virtual shuffle: store the manifest instead of creating the shards - the
+	// contents of the records will not be requested.
+	if m.rs.Manifest != "" {
+		if err := m.storeManifest(shards); err != nil {
+			return err
+		}
+		if skipped == nil {
+			skipped = make(map[string]int64, m.smap.CountTargets())
+		}
+		for _, s := range shards {
+			for _, r := range s.Records.All() {
+				skipped[r.DaemonID] += int64(len(r.Objects))
+			}
+		}
+		glog.Infof("%s %s: stored manifest %q (%d shard(s))", cmn.DSortName, m.ManagerUUID, m.rs.Manifest, len(shards))
+		shards = nil
+	}
 
 	// TODO: Following heuristic doesn't seem to be working correctly in
 	// all cases. When there is not much shards at each disk (like 1-5)
@@ -849,8 +989,9 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 			group.Go(func() error {
 				msgpw := msgp.NewWriterSize(w, serializationBufSize)
 				md := &CreationPhaseMetadata{
-					Shards:    s,
-					SendOrder: order,
+					Shards:        s,
+					SendOrder:     order,
+					SkippedObjCnt: skipped[si.DaemonID],
 				}
 				if err := md.EncodeMsg(msgpw); err != nil {
 					w.CloseWithError(err)
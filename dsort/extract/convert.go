@@ -0,0 +1,58 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"io"
+
+	"github.com/NVIDIA/aistore/cluster"
+)
+
+var (
+	_ ExtractCreator  = &convertExtractCreator{}
+	_ RecordExtractor = &convertRecordExtractor{}
+)
+
+type (
+	// convertExtractCreator extracts the shards with one extract creator and
+	// creates them with the other one, effectively converting the shards from
+	// one format to another (eg. tar to TFRecord).
+	//
+	// Record metadata (eg. tar header) is specific to the input format, so it
+	// is dropped during the extraction - output creators generate default
+	// metadata for the objects which do not have one. For the same reason the
+	// objects cannot be stored as offsets in the input shards.
+	convertExtractCreator struct {
+		in, out ExtractCreator
+	}
+
+	convertRecordExtractor struct {
+		RecordExtractor
+	}
+)
+
+func NewConvertExtractCreator(in, out ExtractCreator) ExtractCreator {
+	return &convertExtractCreator{in: in, out: out}
+}
+
+func (c *convertExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader, extractor RecordExtractor,
+	toDisk bool) (int64, int, error) {
+	return c.in.ExtractShard(lom, r, &convertRecordExtractor{extractor}, toDisk)
+}
+
+func (c *convertExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (int64, error) {
+	return c.out.CreateShard(s, w, loadContent)
+}
+
+// UsingCompression refers to the input shards - it is used to estimate the
+// size of extracted records.
+func (c *convertExtractCreator) UsingCompression() bool { return c.in.UsingCompression() }
+func (c *convertExtractCreator) SupportsOffset() bool   { return false }
+func (c *convertExtractCreator) MetadataSize() int64    { return 0 }
+
+func (e *convertRecordExtractor) ExtractRecordWithBuffer(args extractRecordArgs) (int64, error) {
+	args.metadata = nil
+	return e.RecordExtractor.ExtractRecordWithBuffer(args)
+}
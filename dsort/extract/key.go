@@ -11,9 +11,12 @@ import (
 	"hash"
 	"io"
 	"io/ioutil"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 )
 
@@ -35,6 +38,10 @@ type (
 		buf  *bytes.Buffer
 	}
 
+	// ContentTransformer transforms the content of the record object (eg. by
+	// ETL) - the transformed content must be closed by the caller.
+	ContentTransformer func(r io.Reader, size int64) (io.ReadCloser, error)
+
 	KeyExtractor interface {
 		PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool)
 
@@ -50,6 +57,16 @@ type (
 	contentKeyExtractor struct {
 		ty  string // type of key extracted, supported: supportedFormatTypes
 		ext string // extension of object record whose content will be read
+		// If set, the content is expected to be JSON and the key is the value
+		// of the field under given (dot separated) path, eg. "meta.id".
+		field []interface{}
+		// If set, the content is transformed first and the key is the result
+		// of the transformation (eg. computed by ETL).
+		transform ContentTransformer
+	}
+	regexKeyExtractor struct {
+		ty string         // type of key extracted, supported: supportedFormatTypes
+		re *regexp.Regexp // the key is the first capturing group
 	}
 )
 
@@ -79,12 +96,28 @@ func (ke *nameKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, er
 	return ske.name, nil
 }
 
-func NewContentKeyExtractor(ty, ext string) (KeyExtractor, error) {
+func NewContentKeyExtractor(ty, ext, field string) (KeyExtractor, error) {
 	if err := ValidateAlgorithmFormatType(ty); err != nil {
 		return nil, err
 	}
 
-	return &contentKeyExtractor{ty: ty, ext: ext}, nil
+	ke := &contentKeyExtractor{ty: ty, ext: ext}
+	if field != "" {
+		for _, name := range strings.Split(field, ".") {
+			ke.field = append(ke.field, name)
+		}
+	}
+	return ke, nil
+}
+
+// NewETLKeyExtractor creates key extractor which sends the content of the
+// object record with given extension to the ETL (transform) and interprets
+// the result as the key.
+func NewETLKeyExtractor(transform ContentTransformer, ty, ext string) (KeyExtractor, error) {
+	if err := ValidateAlgorithmFormatType(ty); err != nil {
+		return nil, err
+	}
+	return &contentKeyExtractor{ty: ty, ext: ext, transform: transform}, nil
 }
 
 func (ke *contentKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
@@ -108,8 +141,64 @@ func (ke *contentKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{},
 		return nil, err
 	}
 
+	if ke.transform != nil {
+		if b, err = ke.transformContent(b); err != nil {
+			return nil, errors.Errorf("failed to extract key from %q, err: %v", ske.name, err)
+		}
+	}
+
 	key := string(b)
-	switch ke.ty {
+	if ke.field != nil {
+		v := jsoniter.Get(b, ke.field...)
+		if err := v.LastError(); err != nil {
+			return nil, errors.Errorf("failed to extract key from %q, err: %v", ske.name, err)
+		}
+		key = v.ToString()
+	}
+	return parseKey(key, ke.ty)
+}
+
+func (ke *contentKeyExtractor) transformContent(b []byte) ([]byte, error) {
+	rc, err := ke.transform(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	b, err = ioutil.ReadAll(rc)
+	rc.Close()
+	return bytes.TrimSpace(b), err
+}
+
+// NewRegexKeyExtractor creates key extractor which matches the regex against
+// the name of the object record (eg. "video-1/frame-000123.jpg") - the key is
+// the first capturing group.
+func NewRegexKeyExtractor(ty, regex string) (KeyExtractor, error) {
+	if err := ValidateAlgorithmFormatType(ty); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() == 0 {
+		return nil, errors.Errorf("regex %q has no capturing group", regex)
+	}
+	return &regexKeyExtractor{ty: ty, re: re}, nil
+}
+
+func (ke *regexKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+	return r, &SingleKeyExtractor{name: name}, false
+}
+
+func (ke *regexKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, error) {
+	match := ke.re.FindStringSubmatch(ske.name)
+	if match == nil {
+		return nil, errors.Errorf("failed to extract key from %q, err: no match for %q", ske.name, ke.re)
+	}
+	return parseKey(match[1], ke.ty)
+}
+
+func parseKey(key, ty string) (interface{}, error) {
+	switch ty {
 	case FormatTypeInt:
 		return strconv.ParseInt(key, 10, 64)
 	case FormatTypeFloat:
@@ -117,7 +206,7 @@ func (ke *contentKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{},
 	case FormatTypeString:
 		return key, nil
 	default:
-		return nil, errors.Errorf("not implemented extractor type: %s", ke.ty)
+		return nil, errors.Errorf("not implemented extractor type: %s", ty)
 	}
 }
 
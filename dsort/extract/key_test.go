@@ -0,0 +1,119 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeyExtractor", func() {
+	extractKey := func(ke KeyExtractor, name, content string) (interface{}, error) {
+		r, ske, _ := ke.PrepareExtractor(name, cmn.NewSizedReader(strings.NewReader(content), int64(len(content))), Ext(name))
+		_, err := ioutil.ReadAll(r) // drain the reader as the record manager would
+		Expect(err).NotTo(HaveOccurred())
+		return ke.ExtractKey(ske)
+	}
+
+	It("should extract key from the content", func() {
+		ke, err := NewContentKeyExtractor(FormatTypeInt, ".cls", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		key, err := extractKey(ke, "sample.cls", "42")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(BeEquivalentTo(42))
+
+		key, err = extractKey(ke, "sample.jpg", "not a key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(BeNil())
+	})
+
+	It("should extract key from the JSON field", func() {
+		content := `{"meta": {"id": 17, "label": "cat", "score": 0.25}}`
+		tests := []struct {
+			ty       string
+			field    string
+			expected interface{}
+		}{
+			{ty: FormatTypeInt, field: "meta.id", expected: int64(17)},
+			{ty: FormatTypeFloat, field: "meta.score", expected: 0.25},
+			{ty: FormatTypeString, field: "meta.label", expected: "cat"},
+			{ty: FormatTypeString, field: "meta.id", expected: "17"},
+		}
+		for _, test := range tests {
+			ke, err := NewContentKeyExtractor(test.ty, ".json", test.field)
+			Expect(err).NotTo(HaveOccurred())
+			key, err := extractKey(ke, "sample.json", content)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(test.expected))
+		}
+
+		ke, err := NewContentKeyExtractor(FormatTypeInt, ".json", "meta.missing")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = extractKey(ke, "sample.json", content)
+		Expect(err).To(HaveOccurred())
+
+		ke, err = NewContentKeyExtractor(FormatTypeInt, ".json", "meta.label")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = extractKey(ke, "sample.json", content)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should extract key from the name with regex", func() {
+		ke, err := NewRegexKeyExtractor(FormatTypeInt, `frame-(\d+)`)
+		Expect(err).NotTo(HaveOccurred())
+
+		key, err := extractKey(ke, "video-1/frame-000123.jpg", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(Equal(int64(123)))
+
+		_, err = extractKey(ke, "video-1/thumbnail.jpg", "")
+		Expect(err).To(HaveOccurred())
+
+		ke, err = NewRegexKeyExtractor(FormatTypeString, `^(video-\d+)/`)
+		Expect(err).NotTo(HaveOccurred())
+		key, err = extractKey(ke, "video-1/frame-000123.jpg", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(Equal("video-1"))
+	})
+
+	It("should fail to create regex extractor without capturing group", func() {
+		_, err := NewRegexKeyExtractor(FormatTypeInt, `frame-\d+`)
+		Expect(err).To(HaveOccurred())
+		_, err = NewRegexKeyExtractor(FormatTypeInt, `frame-(\d+`)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should extract key from the transformed content", func() {
+		transform := func(r io.Reader, size int64) (io.ReadCloser, error) {
+			b, err := ioutil.ReadAll(r)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(BeEquivalentTo(len(b)))
+			if string(b) == "fail" {
+				return nil, errors.New("transform failed")
+			}
+			return ioutil.NopCloser(strings.NewReader(" " + strings.ToUpper(string(b)) + "\n")), nil
+		}
+		ke, err := NewETLKeyExtractor(transform, FormatTypeString, ".txt")
+		Expect(err).NotTo(HaveOccurred())
+
+		key, err := extractKey(ke, "sample.txt", "label")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(Equal("LABEL"))
+
+		key, err = extractKey(ke, "sample.jpg", "label")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).To(BeNil())
+
+		_, err = extractKey(ke, "sample.txt", "fail")
+		Expect(err).To(HaveOccurred())
+	})
+})
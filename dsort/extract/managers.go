@@ -179,7 +179,11 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 		if f, err = cmn.CreateFile(fullContentPath); err != nil {
 			return size, errors.WithStack(err)
 		}
-		if size, err = copyMetadataAndData(f, r, args.metadata, args.buf); err != nil {
+		var src io.Reader = r
+		if args.extractMethod.Has(ExtractToWriter) {
+			src = io.TeeReader(r, args.w)
+		}
+		if size, err = copyMetadataAndData(f, src, args.metadata, args.buf); err != nil {
 			debug.AssertNoErr(f.Close())
 			return size, errors.WithStack(err)
 		}
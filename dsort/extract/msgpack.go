@@ -0,0 +1,183 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Msgpack shard is a stream of maps, each of them representing a single
+// sample: SampleKeyFeature entry contains the name of the sample and the
+// remaining entries contain the contents of the sample objects (str or bin),
+// keyed by the objects' extensions (without the leading dot), eg.:
+//
+//   {"__key__": "sample-01", "jpg": <image>, "cls": "3"}
+
+var (
+	// interface guard
+	_ ExtractCreator = &msgpackExtractCreator{}
+)
+
+type (
+	msgpackExtractCreator struct {
+		t cluster.Target
+	}
+
+	// msgpackSample is a single map of the msgpack shard.
+	msgpackSample struct {
+		key    string
+		fields []sampleField
+	}
+)
+
+// read reads next map from the stream; returns io.EOF when there are no more.
+func (sample *msgpackSample) read(mr *msgp.Reader) error {
+	if _, err := mr.R.Peek(1); err != nil {
+		return err // including io.EOF
+	}
+	sz, err := mr.ReadMapHeader()
+	if err != nil {
+		return err
+	}
+
+	var hasKey bool
+	sample.fields = sample.fields[:0]
+	for i := uint32(0); i < sz; i++ {
+		name, err := mr.ReadString()
+		if err != nil {
+			return err
+		}
+		typ, err := mr.NextType()
+		if err != nil {
+			return err
+		}
+		var value []byte
+		switch typ {
+		case msgp.BinType:
+			value, err = mr.ReadBytes(nil)
+		case msgp.StrType:
+			value, err = mr.ReadStringAsBytes(nil)
+		default:
+			return errors.Errorf("field %q: unsupported type %s (expected str or bin)", name, typ)
+		}
+		if err != nil {
+			return err
+		}
+		if name == SampleKeyFeature {
+			sample.key, hasKey = string(value), true
+			continue
+		}
+		sample.fields = append(sample.fields, sampleField{name: name, value: value})
+	}
+	if !hasKey {
+		return errors.Errorf("missing %q field", SampleKeyFeature)
+	}
+	return nil
+}
+
+///////////////////////////
+// msgpackExtractCreator //
+///////////////////////////
+
+func NewMsgpackExtractCreator(t cluster.Target) ExtractCreator {
+	return &msgpackExtractCreator{t: t}
+}
+
+// ExtractShard reads the msgpack file and extracts the fields of all samples
+// as separate objects of the records.
+func (m *msgpackExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader, extractor RecordExtractor,
+	toDisk bool) (extractedSize int64, extractedCount int, err error) {
+	var (
+		size   int64
+		sample msgpackSample
+		fqn    = lom.ParsedFQN
+		mr     = msgp.NewReader(r)
+	)
+
+	buf, slab := m.t.GetMMSA().Alloc(r.Size())
+	defer slab.Free(buf)
+
+	extractMethod := ExtractToMem
+	if toDisk {
+		extractMethod = ExtractToDisk
+	}
+
+	for idx := 0; ; idx++ {
+		if err = sample.read(mr); err == io.EOF {
+			return extractedSize, extractedCount, nil
+		} else if err != nil {
+			return extractedSize, extractedCount, errors.Errorf("sample %d: %v", idx, err)
+		}
+		for _, field := range sample.fields {
+			args := extractRecordArgs{
+				shardName:     fqn.ObjName,
+				fileType:      fqn.ContentType,
+				recordName:    sample.key + "." + field.name,
+				r:             cmn.NewSizedReader(bytes.NewReader(field.value), int64(len(field.value))),
+				extractMethod: extractMethod,
+				buf:           buf,
+			}
+			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
+				return extractedSize, extractedCount, err
+			}
+			extractedSize += size
+			extractedCount++
+		}
+	}
+}
+
+// CreateShard creates msgpack file in which each record of the shard is
+// written as a single map.
+func (m *msgpackExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (written int64, err error) {
+	var (
+		n  int64
+		mw = msgp.NewWriter(w)
+	)
+	for _, rec := range s.Records.All() {
+		if err = mw.WriteMapHeader(uint32(len(rec.Objects) + 1)); err != nil {
+			return written, err
+		}
+		if err = mw.WriteString(SampleKeyFeature); err != nil {
+			return written, err
+		}
+		if err = mw.WriteString(rec.SampleKey()); err != nil {
+			return written, err
+		}
+		for _, obj := range rec.Objects {
+			if err = mw.WriteString(sampleFieldName(obj)); err != nil {
+				return written, err
+			}
+			if err = mw.WriteBytesHeader(uint32(obj.Size)); err != nil {
+				return written, err
+			}
+			if n, err = loadContent(mw, rec, obj); err != nil {
+				return written + n, err
+			}
+			if n != obj.Size {
+				return written + n, errors.Errorf("loaded %d bytes of %q, expected %d", n, rec.MakeObjName(obj), obj.Size)
+			}
+			written += n
+		}
+	}
+	return written, mw.Flush()
+}
+
+func (m *msgpackExtractCreator) UsingCompression() bool {
+	return false
+}
+
+func (m *msgpackExtractCreator) SupportsOffset() bool {
+	return false
+}
+
+func (m *msgpackExtractCreator) MetadataSize() int64 {
+	return 0 // records are not stored with any metadata
+}
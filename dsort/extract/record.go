@@ -7,6 +7,7 @@ package extract
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -111,6 +112,17 @@ func (r *Record) delete(ext string) {
 	}
 }
 
+// Missing returns the extensions, out of the provided ones, for which the
+// record has no objects.
+func (r *Record) Missing(exts []string) (missing []string) {
+	for _, ext := range exts {
+		if !r.exists(ext) {
+			missing = append(missing, ext)
+		}
+	}
+	return
+}
+
 func (r *Record) TotalSize() int64 {
 	size := int64(0)
 	for _, obj := range r.Objects {
@@ -123,6 +135,20 @@ func (r *Record) MakeUniqueName(obj *RecordObj) string {
 	return r.Name + obj.Extension
 }
 
+// MakeObjName returns the name which the object had inside the original shard.
+func (r *Record) MakeObjName(obj *RecordObj) string {
+	return r.SampleKey() + obj.Extension
+}
+
+// SampleKey returns the name of the record without the name of the original
+// shard - the name shared by all the objects of the record.
+func (r *Record) SampleKey() string {
+	if idx := strings.IndexByte(r.Name, '|'); idx >= 0 {
+		return r.Name[idx+1:]
+	}
+	return r.Name
+}
+
 // NewRecords creates new instance of Records struct and allocates n places for
 // the actual Record's
 func NewRecords(n int) *Records {
@@ -170,6 +196,31 @@ func (r *Records) DeleteDup(name, ext string) {
 	r.Unlock()
 }
 
+// DeleteIncomplete removes all records which are missing an object with any of
+// the provided extensions. It returns the number of removed records and objects.
+//
+// NOTE: Contents of the removed objects are not freed - it is done in cleanup.
+func (r *Records) DeleteIncomplete(exts []string) (records, objects int) {
+	r.Lock()
+	arr := r.arr[:0]
+	for _, record := range r.arr {
+		if len(record.Missing(exts)) == 0 {
+			arr = append(arr, record)
+			continue
+		}
+		delete(r.m, record.Name)
+		records++
+		objects += len(record.Objects)
+	}
+	for i := len(arr); i < len(r.arr); i++ {
+		r.arr[i] = nil
+	}
+	r.arr = arr
+	r.totalObjectCount -= objects
+	r.Unlock()
+	return
+}
+
 // NOTE: must be done under lock
 func (r *Records) Find(name string) (record *Record, exists bool) {
 	record, exists = r.m[name]
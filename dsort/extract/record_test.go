@@ -98,4 +98,44 @@ var _ = Describe("Records", func() {
 			Expect(r.TotalSize()).To(BeEquivalentTo(len(r.Objects) * objectSize))
 		})
 	})
+
+	Context("samples", func() {
+		newRecord := func(name string, exts ...string) *Record {
+			record := &Record{Key: name, Name: name}
+			for _, ext := range exts {
+				record.Objects = append(record.Objects, &RecordObj{Size: objectSize, Extension: ext})
+			}
+			return record
+		}
+
+		It("should report missing extensions", func() {
+			record := newRecord("sample", ".jpg", ".json")
+			Expect(record.Missing([]string{".jpg", ".json"})).To(BeEmpty())
+			Expect(record.Missing([]string{".jpg", ".cls", ".json", ".txt"})).To(Equal([]string{".cls", ".txt"}))
+		})
+
+		It("should delete incomplete records", func() {
+			records := NewRecords(0)
+			records.Insert(
+				newRecord("sample1", ".jpg", ".cls", ".json"),
+				newRecord("sample2", ".jpg", ".json"),
+				newRecord("sample3", ".cls", ".json", ".jpg", ".txt"),
+				newRecord("sample4", ".txt"),
+			)
+			records.Insert(newRecord("sample2", ".cls"))
+			Expect(records.objectCount()).To(Equal(11))
+
+			recordCnt, objectCnt := records.DeleteIncomplete([]string{".jpg", ".cls", ".json"})
+			Expect(recordCnt).To(Equal(1))
+			Expect(objectCnt).To(Equal(1))
+			Expect(records.Len()).To(Equal(3))
+			Expect(records.objectCount()).To(Equal(10))
+			for _, name := range []string{"sample1", "sample2", "sample3"} {
+				_, exists := records.Find(name)
+				Expect(exists).To(BeTrue())
+			}
+			_, exists := records.Find("sample4")
+			Expect(exists).To(BeFalse())
+		})
+	})
 })
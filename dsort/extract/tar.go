@@ -77,6 +77,11 @@ func newTarFileHeader(header *tar.Header) tarFileHeader {
 	}
 }
 
+func newDefaultTarHeader(name string, size int64) *tar.Header {
+	h := tarFileHeader{Typeflag: tar.TypeReg, Name: name, Mode: 0644}
+	return h.toTarHeader(size)
+}
+
 func (h *tarFileHeader) toTarHeader(size int64) *tar.Header {
 	return &tar.Header{
 		Size:     size,
@@ -254,6 +259,13 @@ func (t *tarExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadContent
 				}
 				debug.Assert(diff >= 0 && diff < 512)
 			case SGLStoreType, DiskStoreType:
+				if obj.MetadataSize == 0 {
+					// Object has no metadata (eg. it was extracted from shard
+					// in different format) so we need to create the header.
+					if err := tw.WriteHeader(newDefaultTarHeader(rec.MakeObjName(obj), obj.Size)); err != nil {
+						return written, err
+					}
+				}
 				rdReader.reinit(tw, obj.Size, obj.MetadataSize)
 				if n, err = loadContent(rdReader, rec, obj); err != nil {
 					return written + n, err
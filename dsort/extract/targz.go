@@ -166,6 +166,13 @@ func (t *targzExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadConte
 				}
 				debug.Assert(diff >= 0 && diff < 512)
 			case SGLStoreType, DiskStoreType:
+				if obj.MetadataSize == 0 {
+					// Object has no metadata (eg. it was extracted from shard
+					// in different format) so we need to create the header.
+					if err := tw.WriteHeader(newDefaultTarHeader(rec.MakeObjName(obj), obj.Size)); err != nil {
+						return written, err
+					}
+				}
 				rdReader.reinit(tw, obj.Size, obj.MetadataSize)
 				if n, err = loadContent(rdReader, rec, obj); err != nil {
 					return written + n, err
@@ -0,0 +1,445 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/pkg/errors"
+)
+
+// TFRecord file is a sequence of records, each of them framed as follows:
+//
+//   uint64 length
+//   uint32 masked crc32c of length
+//   byte   data[length]
+//   uint32 masked crc32c of data
+//
+// All integers are little-endian. dSort expects the data of each record to be
+// tf.train.Example protobuf message which represents a single sample: it has
+// SampleKeyFeature bytes feature with the name of the sample and single-value
+// bytes features with the contents of the sample objects, named after
+// the objects' extensions (without the leading dot), eg.:
+//
+//   {"__key__": "sample-01", "jpg": <image>, "cls": "3"}
+//
+// This is the format produced by dSort when creating TFRecord shards.
+
+const (
+	// SampleKeyFeature is the name of the feature/field which contains the name
+	// of the sample in TFRecord and msgpack shards.
+	SampleKeyFeature = "__key__"
+
+	tfRecordHeaderSize = 12 // length + masked crc32c of length
+	tfRecordFooterSize = 4  // masked crc32c of data
+	tfRecordMaskDelta  = 0xa282ead8
+
+	// protobuf wire types
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+
+	// tag of the first (and in case of map entry, Feature.value: second)
+	// length-delimited field of the message
+	protoTag1 = 1<<3 | protoBytes
+	protoTag2 = 2<<3 | protoBytes
+)
+
+var (
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+	// interface guard
+	_ ExtractCreator = &tfRecordExtractCreator{}
+)
+
+type (
+	tfRecordExtractCreator struct {
+		t cluster.Target
+	}
+
+	// tfRecordReader reads consecutive records and validates their checksums.
+	tfRecordReader struct {
+		r      io.Reader
+		size   int64 // remaining size of the file (upper bound of record length)
+		header [tfRecordHeaderSize]byte
+		buf    []byte
+	}
+
+	// tfRecordWriter writes the data of a single record and calculates its
+	// checksum.
+	tfRecordWriter struct {
+		w   io.Writer
+		crc hash.Hash32
+	}
+
+	// sampleField is a named value of the sample: single-value bytes feature
+	// of tf.train.Example or an entry of msgpack map.
+	sampleField struct {
+		name  string
+		value []byte
+	}
+)
+
+func maskCRC(crc uint32) uint32 {
+	return ((crc >> 15) | (crc << 17)) + tfRecordMaskDelta
+}
+
+////////////////////
+// tfRecordReader //
+////////////////////
+
+func newTFRecordReader(r io.Reader, size int64) *tfRecordReader {
+	return &tfRecordReader{r: r, size: size}
+}
+
+// next returns the data of the next record; the returned slice is valid only
+// until the next call. Returns io.EOF when there are no more records.
+func (tr *tfRecordReader) next() ([]byte, error) {
+	if _, err := io.ReadFull(tr.r, tr.header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("truncated TFRecord header")
+		}
+		return nil, err // including io.EOF
+	}
+	var (
+		length    = binary.LittleEndian.Uint64(tr.header[:8])
+		lengthCRC = binary.LittleEndian.Uint32(tr.header[8:])
+	)
+	if maskCRC(crc32.Checksum(tr.header[:8], crc32c)) != lengthCRC {
+		return nil, errors.New("TFRecord length checksum mismatch")
+	}
+	tr.size -= tfRecordHeaderSize
+	if tr.size < tfRecordFooterSize || length > uint64(tr.size-tfRecordFooterSize) {
+		return nil, errors.Errorf("invalid TFRecord length %d (exceeds the size of the file)", length)
+	}
+	size := int(length) + tfRecordFooterSize
+	if cap(tr.buf) < size {
+		tr.buf = make([]byte, size)
+	}
+	buf := tr.buf[:size]
+	if _, err := io.ReadFull(tr.r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errors.Errorf("failed to read TFRecord, err: %v", err)
+	}
+	tr.size -= int64(size)
+	data := buf[:length]
+	if maskCRC(crc32.Checksum(data, crc32c)) != binary.LittleEndian.Uint32(buf[length:]) {
+		return nil, errors.New("TFRecord data checksum mismatch")
+	}
+	return data, nil
+}
+
+////////////////////
+// tfRecordWriter //
+////////////////////
+
+// writeTFRecordHeader writes the length of the record and its checksum, and
+// returns writer for the data of the record.
+func writeTFRecordHeader(w io.Writer, length int64) (*tfRecordWriter, error) {
+	var header [tfRecordHeaderSize]byte
+	binary.LittleEndian.PutUint64(header[:8], uint64(length))
+	binary.LittleEndian.PutUint32(header[8:], maskCRC(crc32.Checksum(header[:8], crc32c)))
+	if _, err := w.Write(header[:]); err != nil {
+		return nil, err
+	}
+	return &tfRecordWriter{w: w, crc: crc32.New(crc32c)}, nil
+}
+
+func (rw *tfRecordWriter) Write(p []byte) (int, error) {
+	n, err := rw.w.Write(p)
+	rw.crc.Write(p[:n])
+	return n, err
+}
+
+// finish writes the checksum of the data.
+func (rw *tfRecordWriter) finish() error {
+	var footer [tfRecordFooterSize]byte
+	binary.LittleEndian.PutUint32(footer[:], maskCRC(rw.crc.Sum32()))
+	_, err := rw.w.Write(footer[:])
+	return err
+}
+
+//////////////////////
+// tf.train.Example //
+//////////////////////
+
+// Only the subset of tf.train.Example which is required to represent
+// a sample is supported:
+//
+//   message Example   { Features features = 1; }
+//   message Features  { map<string, Feature> feature = 1; }
+//   message Feature   { oneof kind { BytesList bytes_list = 1; FloatList float_list = 2; Int64List int64_list = 3; } }
+//   message BytesList { repeated bytes value = 1; }
+//
+// The sizes of all the fields are known upfront, so the message can be
+// streamed without buffering the contents of the features.
+
+func uvarintSize(x uint64) (n int) {
+	for n = 1; x >= 0x80; n++ {
+		x >>= 7
+	}
+	return
+}
+
+// size of length-delimited field (tag, length and the data)
+func protoFieldSize(size int64) int64 { return 1 + int64(uvarintSize(uint64(size))) + size }
+
+func tfFeatureEntrySize(name string, size int64) int64 {
+	featureSize := protoFieldSize(protoFieldSize(size)) // Feature{bytes_list: BytesList{value: [...]}}
+	return protoFieldSize(int64(len(name))) + protoFieldSize(featureSize)
+}
+
+func tfFeaturesSize(key string, rec *Record) (size int64) {
+	size = protoFieldSize(tfFeatureEntrySize(SampleKeyFeature, int64(len(key))))
+	for _, obj := range rec.Objects {
+		size += protoFieldSize(tfFeatureEntrySize(sampleFieldName(obj), obj.Size))
+	}
+	return
+}
+
+func sampleFieldName(obj *RecordObj) string { return strings.TrimPrefix(obj.Extension, ".") }
+
+func appendProtoHeader(b []byte, tag byte, size int64) []byte {
+	b = append(b, tag)
+	return appendUvarint(b, uint64(size))
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
+
+// appendTFFeatureHeader appends everything that precedes the value of the
+// feature in the encoded Features message.
+func appendTFFeatureHeader(b []byte, name string, size int64) []byte {
+	bytesListSize := protoFieldSize(size)
+	b = appendProtoHeader(b, protoTag1, tfFeatureEntrySize(name, size)) // Features.feature
+	b = appendProtoHeader(b, protoTag1, int64(len(name)))               // key
+	b = append(b, name...)
+	b = appendProtoHeader(b, protoTag2, protoFieldSize(bytesListSize)) // value: Feature
+	b = appendProtoHeader(b, protoTag1, bytesListSize)                 // Feature.bytes_list
+	return appendProtoHeader(b, protoTag1, size)                       // BytesList.value
+}
+
+// protoFields iterates over the fields of encoded protobuf message, calling
+// the callback for each length-delimited one; other fields are skipped.
+func protoFields(b []byte, cb func(num uint64, value []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errors.New("invalid protobuf tag")
+		}
+		b = b[n:]
+		switch tag & 0x7 {
+		case protoVarint:
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return errors.New("invalid protobuf varint")
+			}
+			b = b[n:]
+		case protoFixed64, protoFixed32:
+			size := 8
+			if tag&0x7 == protoFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return errors.New("truncated protobuf message")
+			}
+			b = b[size:]
+		case protoBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return errors.New("invalid protobuf field length")
+			}
+			if err := cb(tag>>3, b[n:n+int(size)]); err != nil {
+				return err
+			}
+			b = b[n+int(size):]
+		default:
+			return errors.Errorf("unsupported protobuf wire type %d", tag&0x7)
+		}
+	}
+	return nil
+}
+
+// parseTFExample returns the name of the sample and its features. The
+// returned values reference the provided buffer.
+func parseTFExample(b []byte) (key string, features []sampleField, err error) {
+	var hasKey bool
+	err = protoFields(b, func(num uint64, msg []byte) error {
+		if num != 1 { // Example.features
+			return nil
+		}
+		return protoFields(msg, func(num uint64, entry []byte) error {
+			if num != 1 { // Features.feature
+				return nil
+			}
+			feature, err := parseTFFeature(entry)
+			if err != nil {
+				return err
+			}
+			if feature.name == SampleKeyFeature {
+				key, hasKey = string(feature.value), true
+			} else {
+				features = append(features, feature)
+			}
+			return nil
+		})
+	})
+	if err == nil && !hasKey {
+		err = fmt.Errorf("missing %q feature", SampleKeyFeature)
+	}
+	return
+}
+
+func parseTFFeature(entry []byte) (feature sampleField, err error) {
+	var values int
+	err = protoFields(entry, func(num uint64, b []byte) error {
+		switch num {
+		case 1: // key
+			feature.name = string(b)
+			return nil
+		case 2: // value
+			return protoFields(b, func(kind uint64, list []byte) error {
+				if kind != 1 {
+					return fmt.Errorf("feature %q: only bytes features are supported", feature.name)
+				}
+				return protoFields(list, func(num uint64, value []byte) error {
+					if num == 1 {
+						feature.value = value
+						values++
+					}
+					return nil
+				})
+			})
+		default:
+			return nil
+		}
+	})
+	if err == nil && values != 1 {
+		err = fmt.Errorf("feature %q: expected single value, got %d", feature.name, values)
+	}
+	return
+}
+
+////////////////////////////
+// tfRecordExtractCreator //
+////////////////////////////
+
+func NewTFRecordExtractCreator(t cluster.Target) ExtractCreator {
+	return &tfRecordExtractCreator{t: t}
+}
+
+// ExtractShard reads the TFRecord file and extracts the features of all
+// examples as separate objects of the records.
+func (t *tfRecordExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader, extractor RecordExtractor,
+	toDisk bool) (extractedSize int64, extractedCount int, err error) {
+	var (
+		size int64
+		fqn  = lom.ParsedFQN
+		tr   = newTFRecordReader(r, r.Size())
+	)
+
+	buf, slab := t.t.GetMMSA().Alloc(r.Size())
+	defer slab.Free(buf)
+
+	extractMethod := ExtractToMem
+	if toDisk {
+		extractMethod = ExtractToDisk
+	}
+
+	for idx := 0; ; idx++ {
+		data, err := tr.next()
+		if err == io.EOF {
+			return extractedSize, extractedCount, nil
+		} else if err != nil {
+			return extractedSize, extractedCount, errors.Errorf("record %d: %v", idx, err)
+		}
+
+		key, features, err := parseTFExample(data)
+		if err != nil {
+			return extractedSize, extractedCount, errors.Errorf("record %d: %v", idx, err)
+		}
+		for _, feature := range features {
+			args := extractRecordArgs{
+				shardName:     fqn.ObjName,
+				fileType:      fqn.ContentType,
+				recordName:    key + "." + feature.name,
+				r:             cmn.NewSizedReader(bytes.NewReader(feature.value), int64(len(feature.value))),
+				extractMethod: extractMethod,
+				buf:           buf,
+			}
+			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
+				return extractedSize, extractedCount, err
+			}
+			extractedSize += size
+			extractedCount++
+		}
+	}
+}
+
+// CreateShard creates TFRecord file in which each record of the shard is
+// written as a single tf.train.Example.
+func (t *tfRecordExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (written int64, err error) {
+	var (
+		n   int64
+		hdr []byte
+		rw  *tfRecordWriter
+	)
+	for _, rec := range s.Records.All() {
+		key := rec.SampleKey()
+		featuresSize := tfFeaturesSize(key, rec)
+		if rw, err = writeTFRecordHeader(w, protoFieldSize(featuresSize)); err != nil {
+			return written, err
+		}
+
+		hdr = appendProtoHeader(hdr[:0], protoTag1, featuresSize) // Example.features
+		hdr = appendTFFeatureHeader(hdr, SampleKeyFeature, int64(len(key)))
+		hdr = append(hdr, key...)
+		if _, err = rw.Write(hdr); err != nil {
+			return written, err
+		}
+		for _, obj := range rec.Objects {
+			hdr = appendTFFeatureHeader(hdr[:0], sampleFieldName(obj), obj.Size)
+			if _, err = rw.Write(hdr); err != nil {
+				return written, err
+			}
+			if n, err = loadContent(rw, rec, obj); err != nil {
+				return written + n, err
+			}
+			if n != obj.Size {
+				return written + n, errors.Errorf("loaded %d bytes of %q, expected %d", n, rec.MakeObjName(obj), obj.Size)
+			}
+			written += n
+		}
+		if err = rw.finish(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (t *tfRecordExtractCreator) UsingCompression() bool {
+	return false
+}
+
+func (t *tfRecordExtractCreator) SupportsOffset() bool {
+	return false
+}
+
+func (t *tfRecordExtractCreator) MetadataSize() int64 {
+	return 0 // records are not stored with any metadata
+}
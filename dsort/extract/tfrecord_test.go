@@ -0,0 +1,129 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"hash/crc32"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// newTestShard creates shard with the records composed of the objects with
+// given contents; returned function loads the contents.
+func newTestShard(samples map[string]map[string]string, order ...string) (*Shard, LoadContentFunc) {
+	shard := &Shard{Name: "output", Records: NewRecords(len(order))}
+	for _, key := range order {
+		record := &Record{Name: "input|" + key}
+		for ext, content := range samples[key] {
+			record.Objects = append(record.Objects, &RecordObj{Extension: ext, Size: int64(len(content))})
+		}
+		shard.Records.Insert(record)
+	}
+	loadContent := func(w io.Writer, rec *Record, obj *RecordObj) (int64, error) {
+		return io.Copy(w, strings.NewReader(samples[rec.SampleKey()][obj.Extension]))
+	}
+	return shard, loadContent
+}
+
+var _ = Describe("TFRecord", func() {
+	samples := map[string]map[string]string{
+		"sample-1":     {".jpg": "image-1", ".cls": "3"},
+		"dir/sample-2": {".jpg": strings.Repeat("x", 300), ".json": `{"id": 2}`},
+		"sample-3":     {".cls": ""},
+	}
+	order := []string{"sample-1", "dir/sample-2", "sample-3"}
+
+	It("should use crc32c", func() {
+		Expect(crc32.Checksum([]byte("123456789"), crc32c)).To(BeEquivalentTo(0xe3069283))
+	})
+
+	It("should create and read the shard", func() {
+		shard, loadContent := newTestShard(samples, order...)
+		buf := &bytes.Buffer{}
+		_, err := (&tfRecordExtractCreator{}).CreateShard(shard, buf, loadContent)
+		Expect(err).NotTo(HaveOccurred())
+
+		tr := newTFRecordReader(buf, int64(buf.Len()))
+		for _, key := range order {
+			data, err := tr.next()
+			Expect(err).NotTo(HaveOccurred())
+			sampleKey, features, err := parseTFExample(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sampleKey).To(Equal(key))
+			Expect(features).To(HaveLen(len(samples[key])))
+			for _, feature := range features {
+				Expect(string(feature.value)).To(Equal(samples[key]["."+feature.name]))
+			}
+		}
+		_, err = tr.next()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should detect corrupted records", func() {
+		shard, loadContent := newTestShard(samples, order[:1]...)
+		buf := &bytes.Buffer{}
+		_, err := (&tfRecordExtractCreator{}).CreateShard(shard, buf, loadContent)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, idx := range []int{0, 9, tfRecordHeaderSize + 5, buf.Len() - 1} {
+			b := append([]byte(nil), buf.Bytes()...)
+			b[idx] ^= 0xff
+			_, err := newTFRecordReader(bytes.NewReader(b), int64(len(b))).next()
+			Expect(err).To(HaveOccurred())
+		}
+
+		b := buf.Bytes()[:buf.Len()-2]
+		_, err = newTFRecordReader(bytes.NewReader(b), int64(len(b))).next()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject unsupported examples", func() {
+		b := appendProtoHeader(nil, protoTag1, protoFieldSize(tfFeatureEntrySize("jpg", 0)))
+		b = appendTFFeatureHeader(b, "jpg", 0)
+		_, features, err := parseTFExample(b)
+		Expect(features).To(HaveLen(1)) // well-formed...
+		Expect(err).To(HaveOccurred())  // ...but missing the key
+
+		// Example{features: {"cls": Feature{int64_list: [3]}}}
+		entry := []byte{protoTag1, 3, 'c', 'l', 's', protoTag2, 4, 3<<3 | protoBytes, 2, 1<<3 | protoVarint, 3}
+		b = appendProtoHeader(nil, protoTag1, int64(len(entry)+2))
+		b = appendProtoHeader(b, protoTag1, int64(len(entry)))
+		b = append(b, entry...)
+		_, _, err = parseTFExample(b)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Msgpack", func() {
+	It("should create and read the shard", func() {
+		samples := map[string]map[string]string{
+			"sample-1": {".jpg": "image-1", ".cls": "3"},
+			"sample-2": {".jpg": "image-2", ".meta.json": "{}"},
+		}
+		shard, loadContent := newTestShard(samples, "sample-1", "sample-2")
+		buf := &bytes.Buffer{}
+		_, err := (&msgpackExtractCreator{}).CreateShard(shard, buf, loadContent)
+		Expect(err).NotTo(HaveOccurred())
+
+		var (
+			sample msgpackSample
+			mr     = msgp.NewReader(buf)
+		)
+		for _, key := range []string{"sample-1", "sample-2"} {
+			Expect(sample.read(mr)).NotTo(HaveOccurred())
+			Expect(sample.key).To(Equal(key))
+			Expect(sample.fields).To(HaveLen(2))
+			for _, field := range sample.fields {
+				Expect(string(field.value)).To(Equal(samples[key]["."+field.name]))
+			}
+		}
+		Expect(sample.read(mr)).To(Equal(io.EOF))
+	})
+})
@@ -182,6 +182,13 @@ func (z *zipExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadC
 	for _, rec := range s.Records.All() {
 		for _, obj := range rec.Objects {
 			rdReader.reinit(zw, obj.Size, obj.MetadataSize)
+			if obj.MetadataSize == 0 {
+				// Object has no metadata (eg. it was extracted from shard in
+				// different format) so we need to create the file ourselves.
+				if rdReader.writer, err = zw.Create(rec.MakeObjName(obj)); err != nil {
+					return written, err
+				}
+			}
 			if n, err = loadContent(rdReader, rec, obj); err != nil {
 				return written + n, err
 			}
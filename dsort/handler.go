@@ -48,7 +48,11 @@ func ProxySortHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodPost:
-		proxyStartSortHandler(w, r)
+		if len(apiItems) == 1 && apiItems[0] == cmn.Resume {
+			proxyResumeSortHandler(w, r)
+		} else {
+			proxyStartSortHandler(w, r)
+		}
 	case http.MethodGet:
 		proxyGetHandler(w, r)
 	case http.MethodDelete:
@@ -115,12 +119,54 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	b, err := js.Marshal(parsedRS)
+	managerUUID, err := startJob(parsedRS)
 	if err != nil {
-		s := fmt.Sprintf("unable to marshal RequestSpec: %+v, err: %v", parsedRS, err)
-		cmn.InvalidHandlerWithMsg(w, r, s, http.StatusInternalServerError)
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	w.Write([]byte(managerUUID))
+}
+
+// POST /v1/sort/resume?uuid=...
+func proxyResumeSortHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		smap        = ctx.smapOwner.Get()
+		managerUUID = r.URL.Query().Get(cmn.URLParamUUID)
+	)
+	jobs, err := collectCheckpoints(smap)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cps, ok := jobs[managerUUID]
+	if !ok {
+		msg := fmt.Sprintf("%s job %q not found or cannot be resumed", cmn.DSortName, managerUUID)
+		cmn.InvalidHandlerWithMsg(w, r, msg, http.StatusNotFound)
+		return
+	}
+	for _, cp := range cps {
+		if cp.Running {
+			msg := fmt.Sprintf("%s job %q is still running", cmn.DSortName, managerUUID)
+			cmn.InvalidHandlerWithMsg(w, r, msg, http.StatusConflict)
+			return
+		}
+	}
+
+	newUUID, err := resumeJob(cps[0])
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write([]byte(newUUID))
+}
+
+// startJob broadcasts the request spec to all targets and starts the job.
+// Returns the ID of the started job.
+func startJob(parsedRS *ParsedRequestSpec) (string, error) {
+	b, err := js.Marshal(parsedRS)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal RequestSpec: %+v, err: %v", parsedRS, err)
+	}
 
 	managerUUID := cmn.GenUUID()
 	checkResponses := func(responses []response) error {
@@ -134,9 +180,7 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 			path := cmn.URLPath(cmn.Version, cmn.Sort, cmn.Abort, managerUUID)
 			broadcast(http.MethodDelete, path, nil, nil, ctx.smapOwner.Get().Tmap)
 
-			s := fmt.Sprintf("failed to execute start sort, err: %s, status: %d", resp.err.Error(), resp.statusCode)
-			cmn.InvalidHandlerWithMsg(w, r, s, http.StatusInternalServerError)
-			return resp.err
+			return fmt.Errorf("failed to execute start sort, err: %s, status: %d", resp.err.Error(), resp.statusCode)
 		}
 
 		return nil
@@ -157,17 +201,16 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 	path := cmn.URLPath(cmn.Version, cmn.Sort, cmn.Init, managerUUID)
 	responses := broadcast(http.MethodPost, path, nil, b, ctx.smapOwner.Get().Tmap)
 	if err := checkResponses(responses); err != nil {
-		return
+		return "", err
 	}
 
 	glog.V(4).Infof("[%s] broadcasting start request to all targets", managerUUID)
 	path = cmn.URLPath(cmn.Version, cmn.Sort, cmn.Start, managerUUID)
 	responses = broadcast(http.MethodPost, path, nil, nil, ctx.smapOwner.Get().Tmap)
 	if err := checkResponses(responses); err != nil {
-		return
+		return "", err
 	}
-
-	w.Write([]byte(managerUUID))
+	return managerUUID, nil
 }
 
 // GET /v1/sort
@@ -295,7 +338,10 @@ func proxyAbortSortHandler(w http.ResponseWriter, r *http.Request) {
 		query       = r.URL.Query()
 		managerUUID = query.Get(cmn.URLParamUUID)
 		path        = cmn.URLPath(cmn.Version, cmn.Sort, cmn.Abort, managerUUID)
-		responses   = broadcast(http.MethodDelete, path, nil, nil, ctx.smapOwner.Get().Tmap)
+		// Let targets know that the job is aborted by the user (and so it
+		// should not be resumed).
+		params    = url.Values{cmn.URLParamProxyID: []string{ctx.node.DaemonID}}
+		responses = broadcast(http.MethodDelete, path, params, nil, ctx.smapOwner.Get().Tmap)
 	)
 
 	allNotFound := true
@@ -408,6 +454,8 @@ func SortHandler(w http.ResponseWriter, r *http.Request) {
 		metricsHandler(w, r)
 	case cmn.FinishedAck:
 		finishedAckHandler(w, r)
+	case cmn.Checkpoint:
+		checkpointHandler(w, r)
 	default:
 		cmn.InvalidHandlerWithMsg(w, r, "invalid path")
 	}
@@ -643,6 +691,9 @@ func abortSortHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	dsortManager.abort(fmt.Errorf("%s has been aborted via API (remotely)", cmn.DSortName))
+	if r.URL.Query().Get(cmn.URLParamProxyID) != "" {
+		dsortManager.discardCheckpoint()
+	}
 }
 
 func removeSortHandler(w http.ResponseWriter, r *http.Request) {
@@ -715,6 +766,65 @@ func metricsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkpointHandler is the handler called for the HTTP endpoint /v1/sort/checkpoint.
+// A valid GET to this endpoint sends response with the checkpoint of the job
+// with given uuid or, when uuid is not provided, the list of all checkpoints
+// (without sort results). A valid DELETE removes the checkpoint of the job
+// which is not running.
+func checkpointHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		removeCheckpointHandler(w, r)
+		return
+	}
+	if !checkHTTPMethod(w, r, http.MethodGet) {
+		return
+	}
+	apiItems, err := checkRESTItems(w, r, 0, cmn.Version, cmn.Sort, cmn.Checkpoint)
+	if err != nil {
+		return
+	}
+
+	var body []byte
+	if len(apiItems) == 0 {
+		cps, err := Managers.listCheckpoints()
+		if err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body = cmn.MustMarshal(cps)
+	} else {
+		managerUUID := apiItems[0]
+		cp, err := Managers.loadCheckpoint(managerUUID, true /*withCreated*/)
+		if err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if cp == nil {
+			s := fmt.Sprintf("invalid request: checkpoint of %s job %s does not exist", cmn.DSortName, managerUUID)
+			cmn.InvalidHandlerWithMsg(w, r, s, http.StatusNotFound)
+			return
+		}
+		body = cmn.MustMarshal(cp)
+	}
+	if _, err := w.Write(body); err != nil {
+		glog.Error(err)
+	}
+}
+
+func removeCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, cmn.Sort, cmn.Checkpoint)
+	if err != nil {
+		return
+	}
+	managerUUID := apiItems[0]
+	if m, exists := Managers.Get(managerUUID); exists && !m.Metrics.Archived.Load() {
+		s := fmt.Sprintf("invalid request: %s job %s is still running", cmn.DSortName, managerUUID)
+		cmn.InvalidHandlerWithMsg(w, r, s, http.StatusConflict)
+		return
+	}
+	Managers.removeCheckpoint(managerUUID)
+}
+
 // finishedAckHandler is the handler called for the HTTP endpoint /v1/sort/finished-ack.
 // A valid PUT to this endpoint acknowledges that daemonID has finished dSort operation.
 func finishedAckHandler(w http.ResponseWriter, r *http.Request) {
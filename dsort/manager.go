@@ -22,6 +22,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/NVIDIA/aistore/etl"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/stats"
@@ -125,6 +126,10 @@ type (
 		creationPhase struct {
 			metadata CreationPhaseMetadata
 		}
+		checkpoint struct {
+			mu sync.Mutex
+			cp *Checkpoint // nil when the job is not (or no longer) checkpointed
+		}
 		finishedAck struct {
 			mu sync.Mutex
 			m  map[string]struct{} // finished acks: daemonID -> ack
@@ -153,6 +158,9 @@ func RegisterNode(smapOwner cluster.Sowner, bmdOwner cluster.Bowner, snode *clus
 		cmn.AssertNoErr(err)
 		err = fs.CSM.RegisterContentType(filetype.DSortWorkfileType, &filetype.DSortFile{})
 		cmn.AssertNoErr(err)
+	} else {
+		// Primary proxy resumes the jobs interrupted by the target failures.
+		smapOwner.Listeners().Reg(&resumer{})
 	}
 }
 
@@ -168,6 +176,7 @@ func (m *Manager) init(rs *ParsedRequestSpec) error {
 
 	m.rs = rs
 	m.Metrics = newMetrics(rs.Description, rs.ExtendedMetrics)
+	m.Metrics.ResumedFrom = rs.Resume
 	m.startShardCreation = make(chan struct{}, 1)
 
 	m.ctx.smapOwner.Listeners().Reg(m)
@@ -185,6 +194,10 @@ func (m *Manager) init(rs *ParsedRequestSpec) error {
 		return err
 	}
 
+	if err := m.initCheckpoint(); err != nil {
+		return err
+	}
+
 	// NOTE: Total size of the records metadata can sometimes be large
 	// and so this is why we need such a long timeout.
 	config := cmn.GCO.Get()
@@ -371,6 +384,11 @@ func (m *Manager) finalCleanup() {
 
 	m.finishedAck.m = nil
 
+	// The job is resumable only if it did not finish.
+	if !m.aborted() {
+		m.discardCheckpoint()
+	}
+
 	// Update clean state
 	m.state.cleaned = finallyCleanedState
 	m.state.cleanWait.Signal() // if there is another `finalCleanup` waiting it should be woken up to check the state and exit
@@ -434,7 +452,14 @@ func (m *Manager) setExtractCreator() (err error) {
 
 	switch m.rs.Algorithm.Kind {
 	case SortKindContent:
-		keyExtractor, err = extract.NewContentKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension)
+		keyExtractor, err = extract.NewContentKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension, m.rs.Algorithm.Field)
+	case SortKindRegex:
+		keyExtractor, err = extract.NewRegexKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Regex)
+	case SortKindETL:
+		var comm etl.Communicator
+		if comm, err = etl.GetCommunicator(m.rs.Algorithm.ETLID); err == nil {
+			keyExtractor, err = extract.NewETLKeyExtractor(comm.Transform, m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension)
+		}
 	case SortKindMD5:
 		keyExtractor, err = extract.NewMD5KeyExtractor()
 	default:
@@ -449,16 +474,12 @@ func (m *Manager) setExtractCreator() (err error) {
 		return m.react(m.rs.DuplicatedRecords, msg)
 	}
 
-	var extractCreator extract.ExtractCreator
-	switch m.rs.Extension {
-	case cmn.ExtTar:
-		extractCreator = extract.NewTarExtractCreator(m.ctx.t)
-	case cmn.ExtTarTgz, cmn.ExtTgz:
-		extractCreator = extract.NewTargzExtractCreator(m.ctx.t)
-	case cmn.ExtZip:
-		extractCreator = extract.NewZipExtractCreator(m.ctx.t)
-	default:
-		cmn.AssertMsg(false, fmt.Sprintf("unknown extension %s", m.rs.Extension))
+	if m.rs.OutputExtension == "" { // spec was not parsed by `RequestSpec.Parse`
+		m.rs.OutputExtension = m.rs.Extension
+	}
+	extractCreator := newExtractCreator(m.ctx.t, m.rs.Extension)
+	if m.rs.OutputExtension != m.rs.Extension {
+		extractCreator = extract.NewConvertExtractCreator(extractCreator, newExtractCreator(m.ctx.t, m.rs.OutputExtension))
 	}
 
 	if !m.rs.DryRun {
@@ -473,6 +494,24 @@ func (m *Manager) setExtractCreator() (err error) {
 	return nil
 }
 
+func newExtractCreator(t cluster.Target, ext string) extract.ExtractCreator {
+	switch ext {
+	case cmn.ExtTar:
+		return extract.NewTarExtractCreator(t)
+	case cmn.ExtTarTgz, cmn.ExtTgz:
+		return extract.NewTargzExtractCreator(t)
+	case cmn.ExtZip:
+		return extract.NewZipExtractCreator(t)
+	case cmn.ExtTFRecord:
+		return extract.NewTFRecordExtractCreator(t)
+	case cmn.ExtMsgpack:
+		return extract.NewMsgpackExtractCreator(t)
+	default:
+		cmn.AssertMsg(false, fmt.Sprintf("unknown extension %s", ext))
+		return nil
+	}
+}
+
 // updateFinishedAck marks daemonID as finished. If all daemons ack then the
 // finalCleanup is dispatched in separate goroutine.
 func (m *Manager) updateFinishedAck(daemonID string) {
@@ -725,13 +764,17 @@ func (m *Manager) ListenSmapChanged() {
 
 	if newSmap.CountTargets() != m.smap.CountTargets() {
 		// Currently adding new target as well as removing one is not
-		// supported during the run.
+		// supported during the run - the job is interrupted and resumed
+		// from the checkpoint once all the targets are back (see `resumer`).
 		//
 		// TODO: dSort should survive adding new target. For now it is
 		// not possible as rebalance deletes moved object - dSort needs
 		// to use `GetObject` method instead of relaying on simple `os.Open`
-		err := errors.Errorf("number of target has changed during dSort run, aborting due to possible errors")
-		go m.abort(err)
+		err := errors.Errorf("number of target has changed during dSort run, interrupting the job")
+		go func() {
+			m.updateCheckpoint(func(cp *Checkpoint) { cp.Interrupted = true })
+			m.abort(err)
+		}()
 	}
 }
 
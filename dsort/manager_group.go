@@ -38,6 +38,7 @@ type ManagerGroup struct {
 
 func InitManagers(db dbdriver.Driver) {
 	Managers = NewManagerGroup(db)
+	Managers.interruptCheckpoints()
 }
 
 // NewManagerGroup returns new, initialized manager group.
@@ -142,6 +143,7 @@ func (mg *ManagerGroup) Remove(managerUUID string) error {
 
 	key := path.Join(managersKey, managerUUID)
 	_ = mg.db.Delete(dsortCollection, key) // Delete only returns err when record does not exist, which should be ignored
+	mg.removeCheckpoint(managerUUID)
 	return nil
 }
 
@@ -186,6 +188,8 @@ func (mg *ManagerGroup) housekeep() time.Duration {
 	mg.mtx.Lock()
 	defer mg.mtx.Unlock()
 
+	mg.housekeepCheckpoints(regularInterval)
+
 	records, err := mg.db.GetAll(dsortCollection, managersKey)
 	if err != nil {
 		if dbdriver.IsErrNotFound(err) {
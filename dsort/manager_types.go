@@ -12,6 +12,9 @@ type (
 	CreationPhaseMetadata struct {
 		Shards    []*extract.Shard          `msg:"shards"`
 		SendOrder map[string]*extract.Shard `msg:"send_order"`
+		// Number of local objects which will not be requested by any target
+		// because their shards have already been created (see Checkpoint).
+		SkippedObjCnt int64 `msg:"skipped"`
 	}
 
 	RemoteResponse struct {
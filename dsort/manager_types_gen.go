@@ -102,6 +102,12 @@ func (z *CreationPhaseMetadata) DecodeMsg(dc *msgp.Reader) (err error) {
 				}
 				z.SendOrder[za0002] = za0003
 			}
+		case "skipped":
+			z.SkippedObjCnt, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "SkippedObjCnt")
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -115,9 +121,9 @@ func (z *CreationPhaseMetadata) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *CreationPhaseMetadata) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
+	// map header, size 3
 	// write "shards"
-	err = en.Append(0x82, 0xa6, 0x73, 0x68, 0x61, 0x72, 0x64, 0x73)
+	err = en.Append(0x83, 0xa6, 0x73, 0x68, 0x61, 0x72, 0x64, 0x73)
 	if err != nil {
 		return
 	}
@@ -169,6 +175,16 @@ func (z *CreationPhaseMetadata) EncodeMsg(en *msgp.Writer) (err error) {
 			}
 		}
 	}
+	// write "skipped"
+	err = en.Append(0xa7, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.SkippedObjCnt)
+	if err != nil {
+		err = msgp.WrapError(err, "SkippedObjCnt")
+		return
+	}
 	return
 }
 
@@ -194,6 +210,7 @@ func (z *CreationPhaseMetadata) Msgsize() (s int) {
 			}
 		}
 	}
+	s += 8 + msgp.Int64Size
 	return
 }
 
@@ -0,0 +1,189 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xoshiro256"
+)
+
+// Virtual shuffle: instead of creating the output shards, dSort stores the
+// mapping of the records to the output shards as a single manifest object
+// (see RequestSpec.Manifest) in the output bucket. The shards are then
+// assembled on the fly - from the files (members) of the original, input
+// shards - by the multi-object GET (see cmn.GetBatchMsg).
+//
+// The order of the records computed by dSort is the order of epoch 0. Each
+// subsequent epoch N shuffles all the records with the seed derived from the
+// manifest seed and N, and cuts them into the same number of shards, each with
+// the same number of records as in epoch 0. Since the order depends only on
+// the manifest, any shard of any epoch can be requested at any time and from
+// any proxy, without rewriting the data.
+
+type (
+	// Manifest is the result of the virtual shuffle.
+	Manifest struct {
+		ManagerUUID string           `json:"manager_uuid"`
+		Bck         cmn.Bck          `json:"bucket"`  // bucket of the input shards
+		Seed        int64            `json:"seed"`    // base seed of the epochs' shuffles
+		Sources     []string         `json:"sources"` // names of the input shards
+		Shards      []ManifestShard  `json:"shards"`
+		Records     []ManifestRecord `json:"records"` // in the order of epoch 0
+	}
+
+	ManifestShard struct {
+		Name  string `json:"n"` // name of the shard in epoch 0
+		Count int    `json:"c"` // number of records in the shard
+	}
+
+	ManifestRecord struct {
+		Src  int      `json:"s"` // index of the input shard (see Manifest.Sources)
+		Name string   `json:"n"` // name of the record in the input shard (without extension)
+		Exts []string `json:"e"` // extensions of the record's objects
+	}
+
+	// ManifestMember is a single file of the shard assembled from the manifest.
+	ManifestMember struct {
+		ObjName  string // input shard
+		Archpath string // name of the file in the input shard, also in the assembled one
+	}
+)
+
+//////////////
+// Manifest //
+//////////////
+
+// EpochOrder returns the indexes of the records in the order of the given epoch.
+func (mf *Manifest) EpochOrder(epoch int) []int {
+	order := make([]int, len(mf.Records))
+	for i := range order {
+		order[i] = i
+	}
+	if epoch == 0 {
+		return order
+	}
+	seed := xoshiro256.Hash(xoshiro256.Hash(uint64(mf.Seed)) + uint64(epoch))
+	rnd := rand.New(rand.NewSource(int64(seed)))
+	rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	return order
+}
+
+// Members returns, in order, the files of the shard `shardIdx` given the order
+// of the epoch (see EpochOrder).
+func (mf *Manifest) Members(order []int, shardIdx int) ([]ManifestMember, error) {
+	if shardIdx < 0 || shardIdx >= len(mf.Shards) {
+		return nil, fmt.Errorf("shard %d out of range (the manifest defines %d shards)", shardIdx, len(mf.Shards))
+	}
+	var start int
+	for _, shard := range mf.Shards[:shardIdx] {
+		start += shard.Count
+	}
+	end := start + mf.Shards[shardIdx].Count
+	if end > len(order) {
+		return nil, fmt.Errorf("malformed manifest %q: %d records, expected at least %d", mf.ManagerUUID, len(order), end)
+	}
+	members := make([]ManifestMember, 0, end-start)
+	for _, idx := range order[start:end] {
+		rec := &mf.Records[idx]
+		if rec.Src < 0 || rec.Src >= len(mf.Sources) {
+			return nil, fmt.Errorf("malformed manifest %q: invalid source of record %q", mf.ManagerUUID, rec.Name)
+		}
+		for _, ext := range rec.Exts {
+			members = append(members, ManifestMember{ObjName: mf.Sources[rec.Src], Archpath: rec.Name + ext})
+		}
+	}
+	return members, nil
+}
+
+/////////////
+// Manager //
+/////////////
+
+func (m *Manager) buildManifest(shards []*extract.Shard) (*Manifest, error) {
+	mf := &Manifest{
+		ManagerUUID: m.ManagerUUID,
+		Bck:         cmn.Bck{Name: m.rs.Bucket, Provider: m.rs.Provider, Ns: cmn.NsGlobal},
+		Seed:        time.Now().UnixNano(),
+		Shards:      make([]ManifestShard, 0, len(shards)),
+	}
+	if m.rs.Algorithm.Seed != "" {
+		seed, err := strconv.ParseInt(m.rs.Algorithm.Seed, 10, 64)
+		cmn.AssertNoErr(err) // validated when parsing the request spec
+		mf.Seed = seed
+	}
+	sources := make(map[string]int, 64)
+	for _, shard := range shards {
+		records := shard.Records.All()
+		mf.Shards = append(mf.Shards, ManifestShard{Name: shard.Name, Count: len(records)})
+		for _, r := range records {
+			idx := strings.IndexByte(r.Name, '|')
+			if idx < 0 {
+				return nil, fmt.Errorf("record %q: unknown input shard", r.Name)
+			}
+			src, ok := sources[r.Name[:idx]]
+			if !ok {
+				src = len(mf.Sources)
+				sources[r.Name[:idx]] = src
+				mf.Sources = append(mf.Sources, r.Name[:idx]+m.rs.Extension)
+			}
+			rec := ManifestRecord{Src: src, Name: r.SampleKey(), Exts: make([]string, 0, len(r.Objects))}
+			for _, obj := range r.Objects {
+				rec.Exts = append(rec.Exts, obj.Extension)
+			}
+			mf.Records = append(mf.Records, rec)
+		}
+	}
+	return mf, nil
+}
+
+// storeManifest puts the manifest into the output bucket, in place of
+// creating the shards.
+func (m *Manager) storeManifest(shards []*extract.Shard) error {
+	mf, err := m.buildManifest(shards)
+	if err != nil {
+		return err
+	}
+	if m.rs.DryRun {
+		return nil
+	}
+	lom := &cluster.LOM{T: m.ctx.t, ObjName: m.rs.Manifest}
+	if err := lom.Init(cmn.Bck{Name: m.rs.OutputBucket, Provider: m.rs.OutputProvider}); err != nil {
+		return err
+	}
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	err = m.ctx.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       ioutil.NopCloser(bytes.NewReader(cmn.MustMarshal(mf))),
+		WorkFQN:      fs.CSM.GenContentParsedFQN(lom.ParsedFQN, filetype.DSortWorkfileType, filetype.WorkfileCreateShard),
+		RecvType:     cluster.WarmGet,
+		Started:      time.Now(),
+		WithFinalize: true,
+	})
+	if err != nil {
+		return err
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), m.smap)
+	if err != nil {
+		return err
+	}
+	if si.DaemonID != m.ctx.node.DaemonID {
+		lom.Lock(false)
+		defer lom.Unlock(false)
+		return m.sendShard(lom, si)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	newShard := func(name string, records ...*extract.Record) *extract.Shard {
+		shard := &extract.Shard{Name: name, Records: extract.NewRecords(len(records))}
+		shard.Records.Insert(records...)
+		return shard
+	}
+
+	newRecord := func(name string, exts ...string) *extract.Record {
+		r := &extract.Record{Name: name}
+		for _, ext := range exts {
+			r.Objects = append(r.Objects, &extract.RecordObj{Extension: ext})
+		}
+		return r
+	}
+
+	newManifest := func(records, shards int) *Manifest {
+		mf := &Manifest{Seed: 42, Sources: []string{"input.tar"}}
+		for i := 0; i < records; i++ {
+			mf.Records = append(mf.Records, ManifestRecord{Name: fmt.Sprintf("record-%d", i), Exts: []string{".jpg"}})
+		}
+		for i := 0; i < shards; i++ {
+			mf.Shards = append(mf.Shards, ManifestShard{Count: records / shards})
+		}
+		return mf
+	}
+
+	It("should build manifest from shards", func() {
+		m := &Manager{
+			ManagerUUID: "uuid",
+			rs: &ParsedRequestSpec{
+				Bucket:    "bck",
+				Provider:  cmn.ProviderAIS,
+				Extension: cmn.ExtTar,
+				Algorithm: &SortAlgorithm{Kind: SortKindShuffle, Seed: "42"},
+			},
+		}
+		mf, err := m.buildManifest([]*extract.Shard{
+			newShard("out-0.tar", newRecord("shard-1|a", ".jpg", ".cls"), newRecord("shard-0|dir/b", ".jpg")),
+			newShard("out-1.tar", newRecord("shard-1|c", ".cls")),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(mf.Bck).To(Equal(cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}))
+		Expect(mf.Seed).To(Equal(int64(42)))
+		Expect(mf.Sources).To(Equal([]string{"shard-1.tar", "shard-0.tar"}))
+		Expect(mf.Shards).To(Equal([]ManifestShard{{Name: "out-0.tar", Count: 2}, {Name: "out-1.tar", Count: 1}}))
+
+		members, err := mf.Members(mf.EpochOrder(0), 0)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(members).To(Equal([]ManifestMember{
+			{ObjName: "shard-1.tar", Archpath: "a.jpg"},
+			{ObjName: "shard-1.tar", Archpath: "a.cls"},
+			{ObjName: "shard-0.tar", Archpath: "dir/b.jpg"},
+		}))
+		members, err = mf.Members(mf.EpochOrder(0), 1)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(members).To(Equal([]ManifestMember{{ObjName: "shard-1.tar", Archpath: "c.cls"}}))
+
+		_, err = mf.Members(mf.EpochOrder(0), 2)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should shuffle records deterministically in each epoch", func() {
+		mf := newManifest(1000, 10)
+		Expect(mf.EpochOrder(0)).To(Equal(newManifest(1000, 10).EpochOrder(0)))
+		Expect(sort.IntsAreSorted(mf.EpochOrder(0))).To(BeTrue())
+
+		epoch1, epoch2 := mf.EpochOrder(1), mf.EpochOrder(2)
+		Expect(mf.EpochOrder(1)).To(Equal(epoch1))
+		Expect(epoch1).NotTo(Equal(epoch2))
+		Expect(sort.IntsAreSorted(epoch1)).To(BeFalse())
+
+		// Each epoch is a permutation of all the records.
+		sort.Ints(epoch1)
+		Expect(epoch1).To(Equal(mf.EpochOrder(0)))
+
+		mf.Seed = 43
+		Expect(mf.EpochOrder(2)).NotTo(Equal(epoch2))
+	})
+
+	It("should keep the number of records in the shards", func() {
+		mf := newManifest(100, 10)
+		seen := make(map[string]struct{}, 100)
+		for shard := 0; shard < 10; shard++ {
+			members, err := mf.Members(mf.EpochOrder(5), shard)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(members).To(HaveLen(10))
+			for _, member := range members {
+				seen[member.Archpath] = struct{}{}
+			}
+		}
+		Expect(seen).To(HaveLen(100))
+	})
+})
@@ -141,6 +141,9 @@ type LocalExtraction struct {
 	// ExtractedToDiskSize describes uncompressed size of extracted shards to disk
 	// to given moment.
 	ExtractedToDiskSize int64 `json:"extracted_to_disk_size,string"`
+	// DroppedSampleCnt describes number of incomplete samples which were
+	// removed after the extraction (see SampleSpec).
+	DroppedSampleCnt int64 `json:"dropped_sample_count,string"`
 	// ShardExtractionStats describes time statistics about single shard extraction.
 	ShardExtractionStats *DetailedStats `json:"single_shard_stats,omitempty"`
 }
@@ -167,6 +170,10 @@ type ShardCreation struct {
 	// data. Sometimes is faster to create shard on specific target and send it
 	// via network than create shard on destination target.
 	MovedShardCnt int64 `json:"moved_shard_count,string"`
+	// SkippedCnt describes number of shards which were not created because
+	// they had already been created by the interrupted job (see ResumedFrom).
+	// Set only on the final target.
+	SkippedCnt int64 `json:"skipped_count,string"`
 	// RequestStats describes time statistics about request to other target.
 	RequestStats *TimeStats `json:"req_stats,omitempty"`
 	// ResponseStats describes time statistics about response to other target.
@@ -194,6 +201,8 @@ type Metrics struct {
 
 	// Description of the job.
 	Description string `json:"description,omitempty"`
+	// ResumedFrom is the ID of the interrupted job which this job resumes.
+	ResumedFrom string `json:"resumed_from,omitempty"`
 
 	// Warnings which were produced during the job.
 	Warnings []string `json:"warnings,omitempty"`
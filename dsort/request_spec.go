@@ -12,21 +12,28 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
 	"github.com/NVIDIA/aistore/dsort/extract"
 )
 
 const (
 	templBash = "bash"
 	templAt   = "@"
+
+	// MissingMembersDrop removes incomplete samples (see SampleSpec). Other
+	// supported values are reactions: cmn.IgnoreReaction, cmn.WarnReaction
+	// and cmn.AbortReaction - the first two keep incomplete samples.
+	MissingMembersDrop = "drop"
 )
 
 var (
 	errMissingBucket            = errors.New("missing field 'bucket'")
-	errInvalidExtension         = errors.New("extension must be one of '.tar', '.tar.gz', or '.tgz'")
+	errInvalidExtension         = fmt.Errorf("extension must be one of: %+v", supportedExtensions)
 	errNegOutputShardSize       = errors.New("output shard size must be >= 0")
 	errEmptyOutputShardSize     = errors.New("output shard size must be set (cannot be 0)")
 	errNegativeConcurrencyLimit = fmt.Errorf("concurrency max limit must be 0 (limits will be calculated) or > 0")
@@ -39,11 +46,25 @@ var (
 	errInvalidAlgorithmKind      = fmt.Errorf("invalid algorithm kind, should be one of: %+v", supportedAlgorithms)
 	errInvalidSeed               = errors.New("invalid seed provided, should be int")
 	errInvalidAlgorithmExtension = errors.New("invalid extension provided, should be in format: .ext")
+	errInvalidAlgorithmField     = errors.New("invalid field provided, should be a dot separated path to the JSON field: meta.id")
+	errUnexpectedAlgorithmField  = errors.New("field can only be used with content algorithm")
+	errInvalidAlgorithmRegex     = errors.New("invalid regex provided, should contain capturing group: frame-(\\d+)")
+	errMissingAlgorithmETL       = errors.New("ETL ID must be provided")
+
+	errEmptySampleExtensions  = errors.New("sample extensions must be provided")
+	errInvalidSampleExtension = errors.New("invalid sample extension provided, should be in format: .ext")
+	errInvalidMissingMembers  = fmt.Errorf("invalid missing members policy, should be one of: %+v", supportedMissingMembers)
+	errSampleKeyExtension     = errors.New("content (etl) algorithm extension must be one of the sample extensions")
+
+	errManifestExtension = fmt.Errorf("manifest requires one of the archive extensions: %+v", []string{cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip})
+	errManifestOrderFile = errors.New("manifest cannot be used with the order file")
 )
 
 var (
 	// supportedExtensions is a list of supported extensions by dSort
-	supportedExtensions = []string{cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip}
+	supportedExtensions = []string{cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip, cmn.ExtTFRecord, cmn.ExtMsgpack}
+
+	supportedMissingMembers = []string{MissingMembersDrop, cmn.IgnoreReaction, cmn.WarnReaction, cmn.AbortReaction}
 )
 
 // TODO: maybe this struct should be composed of `type` and `template` where
@@ -82,6 +103,8 @@ type RequestSpec struct {
 	Description string `json:"description" yaml:"description"`
 	// Default: same as `bucket` field
 	OutputBucket string `json:"output_bucket" yaml:"output_bucket"`
+	// Default: same as `extension` field
+	OutputExtension string `json:"output_extension" yaml:"output_extension"`
 	// Default: alphanumeric, increasing
 	Algorithm SortAlgorithm `json:"algorithm" yaml:"algorithm"`
 	// Default: ""
@@ -102,6 +125,14 @@ type RequestSpec struct {
 	StreamMultiplier int `json:"stream_multiplier" yaml:"stream_multiplier"`
 	// Default: false
 	ExtendedMetrics bool `json:"extended_metrics" yaml:"extended_metrics"`
+	// Default: nil - records are formed by all objects sharing the same name
+	// and are not required to have any specific objects
+	Samples *SampleSpec `json:"samples" yaml:"samples"`
+	// Default: "" - output shards are created; otherwise, the job is a virtual
+	// shuffle: instead of creating the shards, the mapping of the records to
+	// the shards is stored as the manifest object with the given name in the
+	// output bucket (see Manifest)
+	Manifest string `json:"manifest" yaml:"manifest"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -118,6 +149,7 @@ type ParsedRequestSpec struct {
 	Provider            string                `json:"provider"`
 	OutputProvider      string                `json:"output_provider"`
 	Extension           string                `json:"extension"`
+	OutputExtension     string                `json:"output_extension"`
 	OutputShardSize     int64                 `json:"output_shard_size,string"`
 	InputFormat         *parsedInputTemplate  `json:"input_format"`
 	OutputFormat        *parsedOutputTemplate `json:"output_format"`
@@ -130,6 +162,11 @@ type ParsedRequestSpec struct {
 	CreateConcMaxLimit  int                   `json:"create_concurrency_max_limit"`
 	StreamMultiplier    int                   `json:"stream_multiplier"` // TODO: should be removed
 	ExtendedMetrics     bool                  `json:"extended_metrics"`
+	Samples             *SampleSpec           `json:"samples,omitempty"`
+	// ID of the interrupted job which is resumed by this job, set by the proxy
+	// (see Checkpoint).
+	Resume   string `json:"resume,omitempty"`
+	Manifest string `json:"manifest,omitempty"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -141,15 +178,37 @@ type ParsedRequestSpec struct {
 type SortAlgorithm struct {
 	Kind string `json:"kind"`
 
-	// Kind: alphanumeric, content
+	// Kind: alphanumeric, content, regex, etl
 	Decreasing bool `json:"decreasing"`
 
 	// Kind: shuffle
 	Seed string `json:"seed"` // seed provided to random generator
 
-	// Kind: content
-	Extension  string `json:"extension"`
+	// Kind: content, etl
+	Extension string `json:"extension"`
+	// Kind: content, etl, regex (defaults to string)
 	FormatType string `json:"format_type"`
+	// Kind: content - if set, the content is JSON and the key is the value of
+	// the field under given dot separated path (eg. "meta.id")
+	Field string `json:"field"`
+
+	// Kind: regex - the key is the first capturing group of the regex matched
+	// against the name of the file (eg. "frame-(\d+)")
+	Regex string `json:"regex"`
+	// Kind: etl - ID of the running ETL which computes the key from the content
+	// of the file with given extension
+	ETLID string `json:"etl_id"`
+}
+
+// SampleSpec describes samples (eg. WebDataset) - records composed of the
+// objects which share the same name but differ by extension, eg.: `key.jpg`,
+// `key.cls` and `key.json`.
+type SampleSpec struct {
+	// Extensions of all the objects that a complete sample consists of.
+	Extensions []string `json:"extensions" yaml:"extensions"`
+	// Determines what to do with incomplete samples: "abort" (default),
+	// "warn" or "ignore" to keep them, "drop" to remove them.
+	MissingMembers string `json:"missing_members" yaml:"missing_members"`
 }
 
 // Parse returns a non-nil error if a RequestSpec is invalid. When RequestSpec
@@ -188,6 +247,12 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 		return nil, errInvalidExtension
 	}
 	parsedRS.Extension = rs.Extension
+	parsedRS.OutputExtension = rs.OutputExtension
+	if parsedRS.OutputExtension == "" {
+		parsedRS.OutputExtension = parsedRS.Extension
+	} else if !validateExtension(parsedRS.OutputExtension) {
+		return nil, errInvalidExtension
+	}
 
 	parsedRS.OutputShardSize, err = cmn.S2B(rs.OutputShardSize)
 	if err != nil {
@@ -202,6 +267,12 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 		return nil, errInvalidAlgorithm
 	}
 
+	if rs.Samples != nil {
+		if parsedRS.Samples, err = parseSamples(*rs.Samples, parsedRS.Algorithm); err != nil {
+			return nil, err
+		}
+	}
+
 	if empty, valid := validateOrderFileURL(rs.OrderFileURL); !valid {
 		return nil, errInvalidOrderParam
 	} else if empty {
@@ -228,6 +299,17 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 		}
 	}
 
+	if rs.Manifest != "" {
+		// Assembled shards are read from the input shards via `archpath`.
+		if _, err := archive.FormatFromName(parsedRS.Extension); err != nil {
+			return nil, errManifestExtension
+		}
+		if parsedRS.OrderFileURL != "" {
+			return nil, errManifestOrderFile
+		}
+		parsedRS.Manifest = rs.Manifest
+	}
+
 	if rs.MaxMemUsage == "" {
 		rs.MaxMemUsage = cfg.DefaultMaxMemUsage
 	}
@@ -323,7 +405,12 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 		}
 	}
 
-	if algo.Kind == SortKindContent {
+	if algo.Field != "" && algo.Kind != SortKindContent {
+		return nil, errUnexpectedAlgorithmField
+	}
+
+	switch algo.Kind {
+	case SortKindContent, SortKindETL:
 		algo.Extension = strings.TrimSpace(algo.Extension)
 		if algo.Extension == "" {
 			return nil, errInvalidAlgorithmExtension
@@ -336,13 +423,72 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 		if err := extract.ValidateAlgorithmFormatType(algo.FormatType); err != nil {
 			return nil, err
 		}
-	} else {
+
+		algo.Field = strings.TrimSpace(algo.Field)
+		if algo.Field != "" {
+			for _, name := range strings.Split(algo.Field, ".") {
+				if name == "" {
+					return nil, errInvalidAlgorithmField
+				}
+			}
+		}
+
+		if algo.Kind == SortKindETL {
+			algo.ETLID = strings.TrimSpace(algo.ETLID)
+			if algo.ETLID == "" {
+				return nil, errMissingAlgorithmETL
+			}
+		}
+	case SortKindRegex:
+		re, err := regexp.Compile(algo.Regex)
+		if err != nil || re.NumSubexp() == 0 {
+			return nil, errInvalidAlgorithmRegex
+		}
+
+		if algo.FormatType == "" {
+			algo.FormatType = extract.FormatTypeString
+		}
+		if err := extract.ValidateAlgorithmFormatType(algo.FormatType); err != nil {
+			return nil, err
+		}
+	default:
 		algo.FormatType = extract.FormatTypeString
 	}
 
 	return &algo, nil
 }
 
+func parseSamples(samples SampleSpec, algo *SortAlgorithm) (*SampleSpec, error) {
+	if len(samples.Extensions) == 0 {
+		return nil, errEmptySampleExtensions
+	}
+	exts := make([]string, 0, len(samples.Extensions))
+	for _, ext := range samples.Extensions {
+		ext = strings.TrimSpace(ext)
+		if len(ext) < 2 || ext[0] != '.' {
+			return nil, errInvalidSampleExtension
+		}
+		if !cmn.StringInSlice(ext, exts) {
+			exts = append(exts, ext)
+		}
+	}
+	samples.Extensions = exts
+
+	if samples.MissingMembers == "" {
+		samples.MissingMembers = cmn.AbortReaction
+	}
+	if !cmn.StringInSlice(samples.MissingMembers, supportedMissingMembers) {
+		return nil, errInvalidMissingMembers
+	}
+
+	// The key is extracted from one of the sample members, so it must be
+	// required - otherwise some samples could end up without the key.
+	if (algo.Kind == SortKindContent || algo.Kind == SortKindETL) && !cmn.StringInSlice(algo.Extension, samples.Extensions) {
+		return nil, errSampleKeyExtension
+	}
+	return &samples, nil
+}
+
 func validateOrderFileURL(orderURL string) (empty, valid bool) {
 	if orderURL == "" {
 		return true, true
@@ -11,6 +11,7 @@ import (
 	"math"
 
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -290,6 +291,95 @@ var _ = Describe("RequestSpec", func() {
 			_, err = rs.Parse()
 			Expect(err).ShouldNot(HaveOccurred())
 		})
+		It("should parse spec with output extension", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OutputFormat:    "prefix-{10..111}-suffix",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.OutputExtension).To(Equal(cmn.ExtTar))
+
+			rs.OutputExtension = cmn.ExtTFRecord
+			parsed, err = rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Extension).To(Equal(cmn.ExtTar))
+			Expect(parsed.OutputExtension).To(Equal(cmn.ExtTFRecord))
+
+			rs.OutputExtension = ".tfrecords"
+			_, err = rs.Parse()
+			Expect(err).To(Equal(errInvalidExtension))
+		})
+
+		It("should parse spec with samples", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OutputFormat:    "prefix-{10..111}-suffix",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Algorithm: SortAlgorithm{
+					Kind:       SortKindContent,
+					Extension:  ".json",
+					FormatType: extract.FormatTypeInt,
+					Field:      " meta.id ",
+				},
+				Samples: &SampleSpec{Extensions: []string{".jpg", " .cls", ".json", ".jpg"}},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Algorithm.Field).To(Equal("meta.id"))
+			Expect(parsed.Samples.Extensions).To(Equal([]string{".jpg", ".cls", ".json"}))
+			Expect(parsed.Samples.MissingMembers).To(Equal(cmn.AbortReaction))
+		})
+
+		It("should parse spec with regex and etl algorithms", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OutputFormat:    "prefix-{10..111}-suffix",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Algorithm:       SortAlgorithm{Kind: SortKindRegex, Regex: `frame-(\d+)`},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Algorithm.FormatType).To(Equal(extract.FormatTypeString))
+
+			rs.Algorithm = SortAlgorithm{Kind: SortKindRegex, Regex: `frame-(\d+)`, FormatType: extract.FormatTypeInt}
+			parsed, err = rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Algorithm.FormatType).To(Equal(extract.FormatTypeInt))
+
+			rs.Algorithm = SortAlgorithm{Kind: SortKindETL, ETLID: " etl-key ", Extension: ".json", FormatType: extract.FormatTypeFloat}
+			parsed, err = rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Algorithm.ETLID).To(Equal("etl-key"))
+			Expect(parsed.Algorithm.Extension).To(Equal(".json"))
+		})
+
+		It("should parse spec with manifest", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTgz,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OutputFormat:    "prefix-{10..111}-suffix",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Algorithm:       SortAlgorithm{Kind: SortKindShuffle, Seed: "42"},
+				Manifest:        "epochs.json",
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Manifest).To(Equal("epochs.json"))
+		})
 	})
 
 	Context("request specs which shall NOT pass", func() {
@@ -450,6 +540,115 @@ var _ = Describe("RequestSpec", func() {
 			Expect(err).Should(HaveOccurred())
 		})
 
+		It("should fail due to invalid samples", func() {
+			for _, samples := range []*SampleSpec{
+				{},
+				{Extensions: []string{"jpg"}},
+				{Extensions: []string{".jpg", "."}},
+				{Extensions: []string{".jpg"}, MissingMembers: "skip"},
+			} {
+				rs := RequestSpec{
+					Bucket:          "test",
+					Extension:       cmn.ExtTar,
+					InputFormat:     "prefix-{0010..0111..2}-suffix",
+					OutputFormat:    "prefix-{10..111}-suffix",
+					OutputShardSize: "10KB",
+					MaxMemUsage:     "80%",
+					Algorithm:       SortAlgorithm{Kind: SortKindNone},
+					Samples:         samples,
+				}
+				_, err := rs.Parse()
+				Expect(err).Should(HaveOccurred())
+			}
+		})
+
+		It("should fail when content key extension is not required by samples", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OutputFormat:    "prefix-{10..111}-suffix",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Algorithm:       SortAlgorithm{Kind: SortKindContent, Extension: ".json", FormatType: extract.FormatTypeString},
+				Samples:         &SampleSpec{Extensions: []string{".jpg", ".cls"}, MissingMembers: MissingMembersDrop},
+			}
+			_, err := rs.Parse()
+			Expect(err).To(Equal(errSampleKeyExtension))
+		})
+
+		It("should fail due to invalid algorithm field", func() {
+			for _, algo := range []SortAlgorithm{
+				{Kind: SortKindContent, Extension: ".json", FormatType: extract.FormatTypeString, Field: "meta..id"},
+				{Kind: SortKindShuffle, Field: "meta.id"},
+			} {
+				rs := RequestSpec{
+					Bucket:          "test",
+					Extension:       cmn.ExtTar,
+					InputFormat:     "prefix-{0010..0111..2}-suffix",
+					OutputFormat:    "prefix-{10..111}-suffix",
+					OutputShardSize: "10KB",
+					MaxMemUsage:     "80%",
+					Algorithm:       algo,
+				}
+				_, err := rs.Parse()
+				Expect(err).To(Equal(errInvalidAlgorithm))
+			}
+		})
+
+		It("should fail due to invalid regex or etl algorithm", func() {
+			for _, algo := range []SortAlgorithm{
+				{Kind: SortKindRegex},
+				{Kind: SortKindRegex, Regex: `frame-\d+`},
+				{Kind: SortKindRegex, Regex: `frame-(\d+`},
+				{Kind: SortKindRegex, Regex: `frame-(\d+)`, FormatType: "bool"},
+				{Kind: SortKindRegex, Regex: `frame-(\d+)`, Field: "meta.id"},
+				{Kind: SortKindETL, Extension: ".json", FormatType: extract.FormatTypeString},
+				{Kind: SortKindETL, ETLID: "etl-key", FormatType: extract.FormatTypeString},
+				{Kind: SortKindETL, ETLID: "etl-key", Extension: ".json"},
+			} {
+				rs := RequestSpec{
+					Bucket:          "test",
+					Extension:       cmn.ExtTar,
+					InputFormat:     "prefix-{0010..0111..2}-suffix",
+					OutputFormat:    "prefix-{10..111}-suffix",
+					OutputShardSize: "10KB",
+					MaxMemUsage:     "80%",
+					Algorithm:       algo,
+				}
+				_, err := rs.Parse()
+				Expect(err).To(Equal(errInvalidAlgorithm))
+			}
+		})
+
+		It("should fail when manifest is used with unsupported extension", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTFRecord,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OutputFormat:    "prefix-{10..111}-suffix",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Manifest:        "epochs.json",
+			}
+			_, err := rs.Parse()
+			Expect(err).To(Equal(errManifestExtension))
+		})
+
+		It("should fail when manifest is used with order file", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111..2}-suffix",
+				OrderFileURL:    "http://localhost:8080/ekm.txt",
+				OutputShardSize: "10KB",
+				MaxMemUsage:     "80%",
+				Manifest:        "epochs.json",
+			}
+			_, err := rs.Parse()
+			Expect(err).To(Equal(errManifestOrderFile))
+		})
+
 		It("should fail when output shard size is empty and output format is %06d", func() {
 			rs := RequestSpec{
 				Bucket:       "test",
@@ -21,10 +21,13 @@ const (
 	SortKindMD5          = "md5"
 	SortKindShuffle      = "shuffle" // shuffle randomly, can be used with seed to get reproducible results
 	SortKindContent      = "content" // sort by content of given file
+	SortKindRegex        = "regex"   // sort by the part of the file name captured by regex
+	SortKindETL          = "etl"     // sort by the key computed by ETL from the content of given file
 )
 
 var (
-	supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent, SortKindNone}
+	supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent,
+		SortKindRegex, SortKindETL, SortKindNone}
 )
 
 type (
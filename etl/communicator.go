@@ -5,7 +5,9 @@
 package etl
 
 import (
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -32,6 +34,10 @@ type (
 		// - Method "PUT", Path "/"
 		// - Method "GET", Path "/bucket/object"
 		Do(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string) error
+		// Transform sends arbitrary content (not an object) to the ETL container
+		// and returns the transformed one, which must be closed by the caller.
+		// Supported only by PushCommType.
+		Transform(r io.Reader, size int64) (io.ReadCloser, error)
 	}
 	baseComm struct {
 		cluster.Slistener
@@ -105,6 +111,10 @@ func (c baseComm) PodName() string      { return c.podName }
 func (c baseComm) RemoteAddrIP() string { return c.remoteAddr }
 func (c baseComm) SvcName() string      { return c.podName /*pod name is same as service name*/ }
 
+func (c baseComm) Transform(io.Reader, int64) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("ETL %q: transforming content requires %q communication type", c.name, PushCommType)
+}
+
 //////////////
 // pushComm //
 //////////////
@@ -146,6 +156,25 @@ func (pushc *pushComm) Do(w http.ResponseWriter, _ *http.Request, bck *cluster.B
 	return nil
 }
 
+func (pushc *pushComm) Transform(r io.Reader, size int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodPut, pushc.transformerAddress, r)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set(cmn.HeaderContentType, cmn.ContentBinary)
+	resp, err := pushc.t.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("ETL %q: failed to transform, status %d: %s", pushc.name, resp.StatusCode, b)
+	}
+	return resp.Body, nil
+}
+
 ////////////////////
 //  redirectComm  //
 ////////////////////
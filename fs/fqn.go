@@ -21,11 +21,14 @@ const (
 
 const (
 	// prefixes for workfiles created by various services
-	WorkfileRemote  = "remote" // getting object from neighbor target while rebalance is running
-	WorkfileColdget = "cold"   // object GET: coldget
-	WorkfilePut     = "put"    // object PUT
-	WorkfileAppend  = "append" // object APPEND
-	WorkfileFSHC    = "fshc"   // FSHC test file
+	WorkfileRemote    = "remote"    // getting object from neighbor target while rebalance is running
+	WorkfileColdget   = "cold"      // object GET: coldget
+	WorkfilePut       = "put"       // object PUT
+	WorkfileAppend    = "append"    // object APPEND
+	WorkfileMultipart = "multipart" // S3 multipart upload: part
+	WorkfileFSHC      = "fshc"      // FSHC test file
+	WorkfileDownload  = "download"  // downloader: partially downloaded object (kept between retries)
+	WorkfileExtract   = "extract"   // downloader: file extracted from the downloaded archive
 )
 
 type ParsedFQN struct {
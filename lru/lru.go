@@ -21,6 +21,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/ios"
+	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction/demand"
 )
@@ -352,6 +353,10 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 		j.misplaced = append(j.misplaced, lom)
 		return nil
 	}
+	// never evict objects that are yet to be written to the cloud
+	if mirror.IsDirty(lom) {
+		return nil
+	}
 
 	// do nothing if the heap's curSize >= totalSize and
 	// the file is more recent then the the heap's newest.
@@ -475,6 +480,11 @@ func (j *lruJ) _throttle(usedPct int64) (err error) {
 // remove local copies that "belong" to different LRU joggers; hence, space accounting may be temporarily not precise
 func (j *lruJ) evictObj(lom *cluster.LOM) (ok bool) {
 	lom.Lock(true)
+	// re-check: the object may have been overwritten (and become dirty) since the walk
+	if mirror.IsDirty(lom) {
+		lom.Unlock(true)
+		return
+	}
 	if err := lom.Remove(); err == nil {
 		ok = true
 	} else {
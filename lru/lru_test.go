@@ -16,9 +16,11 @@ import (
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/lru"
+	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/tutils"
 	"github.com/NVIDIA/aistore/xaction"
@@ -45,6 +47,7 @@ const (
 	basePath             = "/tmp/lru-tests"
 	bucketName           = "lru-bck"
 	bucketNameAnother    = bucketName + "-another"
+	bucketNameCloud      = bucketName + "-cloud"
 )
 
 type fileMetadata struct {
@@ -95,6 +98,15 @@ func newTargetLRUMock() *cluster.TargetMock {
 					Access: cmn.AllAccess(),
 				},
 			),
+			cluster.NewBck(
+				bucketNameCloud, cmn.ProviderAmazon, cmn.NsGlobal,
+				&cmn.BucketProps{
+					Cksum:       cmn.CksumConf{Type: cmn.ChecksumNone},
+					LRU:         cmn.LRUConf{Enabled: true},
+					Access:      cmn.AllAccess(),
+					WritePolicy: cmn.WritePolicyConf{Cloud: cmn.WriteNever},
+				},
+			),
 		)
 		tMock = cluster.NewTargetMock(bmdMock)
 	)
@@ -146,7 +158,9 @@ func saveRandomFile(t cluster.Target, filename string, size int64) {
 	err = lom.Init(cmn.Bck{})
 	Expect(err).NotTo(HaveOccurred())
 	lom.SetSize(size)
-	lom.IncVersion()
+	if lom.Bck().IsAIS() {
+		lom.IncVersion()
+	}
 	Expect(lom.Persist()).NotTo(HaveOccurred())
 }
 
@@ -173,7 +187,9 @@ var _ = Describe("LRU tests", func() {
 
 			filesPath  string
 			fpAnother  string
+			fpCloud    string
 			bckAnother cmn.Bck
+			bckCloud   cmn.Bck
 		)
 
 		BeforeEach(func() {
@@ -185,10 +201,13 @@ var _ = Describe("LRU tests", func() {
 			mpaths, _ := fs.Get()
 			bck := cmn.Bck{Name: bucketName, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
 			bckAnother = cmn.Bck{Name: bucketNameAnother, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+			bckCloud = cmn.Bck{Name: bucketNameCloud, Provider: cmn.ProviderAmazon, Ns: cmn.NsGlobal}
 			filesPath = mpaths[basePath].MakePathCT(bck, fs.ObjectType)
 			fpAnother = mpaths[basePath].MakePathCT(bckAnother, fs.ObjectType)
+			fpCloud = mpaths[basePath].MakePathCT(bckCloud, fs.ObjectType)
 			cmn.CreateDir(filesPath)
 			cmn.CreateDir(fpAnother)
+			cmn.CreateDir(fpCloud)
 		})
 
 		AfterEach(func() {
@@ -349,6 +368,36 @@ var _ = Describe("LRU tests", func() {
 				numFilesLeft := len(filesAnother)
 				Expect(numFilesLeft).To(BeNumerically("==", numberOfCreatedFiles))
 			})
+
+			It("should not evict objects that are yet to be written to the cloud", func() {
+				mirror.InitWriteBack(dbdriver.NewDBMock())
+				defer func() { mirror.DirtyQ = nil }()
+
+				saveRandomFiles(t, fpCloud, numberOfCreatedFiles)
+				files, err := ioutil.ReadDir(fpCloud)
+				Expect(err).NotTo(HaveOccurred())
+				dirty := make([]string, 0, len(files)/2)
+				for i, file := range files {
+					if i%2 == 0 {
+						dirty = append(dirty, file.Name())
+						Expect(mirror.DirtyQ.Add(bckCloud, file.Name(), mirror.ReplicPut, fileSize)).NotTo(HaveOccurred())
+					}
+				}
+
+				ini.Buckets = []cmn.Bck{bckCloud}
+				lru.Run(ini)
+
+				filesLeft, err := ioutil.ReadDir(fpCloud)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(filesLeft)).To(BeNumerically("<", numberOfCreatedFiles))
+				names := make([]string, 0, len(filesLeft))
+				for _, file := range filesLeft {
+					names = append(names, file.Name())
+				}
+				for _, name := range dirty {
+					Expect(cmn.StringInSlice(name, names)).To(BeTrue())
+				}
+			})
 		})
 
 		Describe("evict trash directory", func() {
@@ -7,11 +7,15 @@ package mirror
 import (
 	"testing"
 
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
 func TestMirror(t *testing.T) {
 	RegisterFailHandler(Fail)
+	cmn.InitShortID(0)
+	cluster.InitTarget()
 	RunSpecs(t, "Mirror Suite")
 }
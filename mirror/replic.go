@@ -0,0 +1,514 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xaction/demand"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Asynchronous replication of AIS buckets to remote AIS clusters (see cmn.ReplicConf).
+//
+// PUTs and DELETEs of the objects are recorded in the replication queue (one per
+// target, stored in the target's database and thus surviving restarts) and
+// forwarded to the destination bucket by the on-demand XactReplic - one per
+// bucket. Only the latest operation on a given object is kept in the queue.
+// Failed requests remain queued and are retried with exponential backoff.
+
+const (
+	ReplicPut = "put"
+	ReplicDel = "delete"
+
+	replicCollection = "replication"
+	replicWorkers    = 8                // max concurrent requests to the remote cluster (per bucket)
+	replicRetryMin   = 2 * time.Second  // delay before the first retry
+	replicRetryMax   = 5 * time.Minute  // max delay between retries
+	replicScanTime   = 10 * time.Second // rescan the queue for due retries at least this often
+)
+
+type (
+	// RemoteAIS forwards the replicated objects to a remote AIS cluster
+	// (implemented by the ais cloud provider).
+	RemoteAIS interface {
+		PutReplica(remoteBck cmn.Bck, lom *cluster.LOM, r cmn.ReadOpenCloser) error
+		DeleteReplica(remoteBck cmn.Bck, objName string) error
+		HeadReplica(remoteBck cmn.Bck, objName string) (*cmn.ObjectProps, error)
+	}
+
+	// ReplicEntry is the PUT or DELETE of the object that is yet to be replicated.
+	ReplicEntry struct {
+		Bck     cmn.Bck `json:"bck"`
+		ObjName string  `json:"name"`
+		Op      string  `json:"op"`                // ReplicPut or ReplicDel
+		Seq     int64   `json:"seq,string"`        // changes with every operation on the object
+		Queued  int64   `json:"queued,string"`     // when the object (first) got out of sync
+		Retries int     `json:"retries,omitempty"` // failed attempts so far
+		NextTry int64   `json:"next_try,string,omitempty"`
+		Size    int64   `json:"size,string,omitempty"` // object size at the time of the operation
+	}
+
+	// ReplicBacklog summarizes the queued entries of a bucket.
+	ReplicBacklog struct {
+		Bck    cmn.Bck
+		Count  int64
+		Size   int64 // total size of the queued objects
+		Oldest int64 // the earliest ReplicEntry.Queued
+	}
+
+	// ReplicQueue is a durable (database-backed) queue of the per-object
+	// operations - used for both replication and write-back (see writeback.go).
+	ReplicQueue struct {
+		db   dbdriver.Driver
+		coll string
+		mu   sync.Mutex
+		seq  atomic.Int64
+	}
+
+	XactReplic struct {
+		// implements cmn.Xact and cmn.Runner interfaces
+		demand.XactDemandBase
+		t      cluster.Target
+		statsT stats.Tracker
+		remote RemoteAIS
+		queue  *ReplicQueue
+		wakeCh chan struct{}
+		// stats
+		backlog atomic.Int64
+		lag     atomic.Int64
+		deleted atomic.Int64
+		errors  atomic.Int64
+	}
+)
+
+var (
+	// ReplicQ is the target's replication queue (see InitReplic).
+	ReplicQ *ReplicQueue
+
+	_ cmn.XactStats = &stats.ReplicTargetStats{}
+)
+
+func InitReplic(db dbdriver.Driver) { ReplicQ = NewReplicQueue(db, replicCollection) }
+
+/////////////////
+// ReplicQueue //
+/////////////////
+
+func NewReplicQueue(db dbdriver.Driver, coll string) *ReplicQueue {
+	q := &ReplicQueue{db: db, coll: coll}
+	q.seq.Store(time.Now().UnixNano())
+	return q
+}
+
+func replicKey(bck cmn.Bck, objName string) string {
+	return cluster.NewBckEmbed(bck).MakeUname(objName)
+}
+
+// Add queues the operation on the object replacing the one queued before (if any).
+func (q *ReplicQueue) Add(bck cmn.Bck, objName, op string, size int64) error {
+	var (
+		prev ReplicEntry
+		key  = replicKey(bck, objName)
+		e    = &ReplicEntry{
+			Bck: bck, ObjName: objName, Op: op, Seq: q.seq.Inc(), Queued: time.Now().UnixNano(), Size: size,
+		}
+	)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.db.Get(q.coll, key, &prev); err == nil {
+		e.Queued = prev.Queued
+	} else if !dbdriver.IsErrNotFound(err) {
+		return err
+	}
+	return q.db.Set(q.coll, key, e)
+}
+
+// Has returns true if the object has a queued operation.
+func (q *ReplicQueue) Has(bck cmn.Bck, objName string) bool {
+	var e ReplicEntry
+	return q.db.Get(q.coll, replicKey(bck, objName), &e) == nil
+}
+
+// Remove drops the queued operation on the object (if any).
+func (q *ReplicQueue) Remove(bck cmn.Bck, objName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.db.Delete(q.coll, replicKey(bck, objName)); err != nil && !dbdriver.IsErrNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Entries returns the queued entries of the bucket, the oldest first.
+func (q *ReplicQueue) Entries(bck cmn.Bck) ([]*ReplicEntry, error) {
+	entries, err := q.entries(replicKey(bck, ""))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Queued < entries[j].Queued })
+	return entries, nil
+}
+
+// Backlog returns the summary of the queued entries for each bucket that has any.
+func (q *ReplicQueue) Backlog() ([]*ReplicBacklog, error) {
+	entries, err := q.entries("")
+	if err != nil {
+		return nil, err
+	}
+	var (
+		backlog []*ReplicBacklog
+		bcks    = make(map[string]*ReplicBacklog)
+	)
+	for _, e := range entries {
+		uname := replicKey(e.Bck, "")
+		b, ok := bcks[uname]
+		if !ok {
+			b = &ReplicBacklog{Bck: e.Bck, Oldest: e.Queued}
+			bcks[uname] = b
+			backlog = append(backlog, b)
+		}
+		b.Count++
+		b.Size += e.Size
+		if e.Queued < b.Oldest {
+			b.Oldest = e.Queued
+		}
+	}
+	return backlog, nil
+}
+
+// BckBacklog returns the summary of the queued entries of the bucket.
+func (q *ReplicQueue) BckBacklog(bck cmn.Bck) (*ReplicBacklog, error) {
+	entries, err := q.entries(replicKey(bck, ""))
+	if err != nil {
+		return nil, err
+	}
+	b := &ReplicBacklog{Bck: bck}
+	for _, e := range entries {
+		b.Count++
+		b.Size += e.Size
+		if b.Oldest == 0 || e.Queued < b.Oldest {
+			b.Oldest = e.Queued
+		}
+	}
+	return b, nil
+}
+
+// RemoveAll drops the queued entries of the bucket.
+func (q *ReplicQueue) RemoveAll(bck cmn.Bck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	values, err := q.db.GetAll(q.coll, replicKey(bck, ""))
+	if err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			err = nil
+		}
+		return err
+	}
+	for key := range values {
+		if err := q.db.Delete(q.coll, key); err != nil && !dbdriver.IsErrNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *ReplicQueue) entries(prefix string) ([]*ReplicEntry, error) {
+	values, err := q.db.GetAll(q.coll, prefix)
+	if err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			err = nil
+		}
+		return nil, err
+	}
+	entries := make([]*ReplicEntry, 0, len(values))
+	for key, value := range values {
+		e := &ReplicEntry{}
+		if err := jsoniter.UnmarshalFromString(value, e); err != nil {
+			glog.Errorf("failed to unmarshal %s entry %q: %v", q.coll, key, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// done removes the replicated entry unless the object has changed in the meantime.
+func (q *ReplicQueue) done(e *ReplicEntry) error {
+	return q.update(e, func(key string) error { return q.db.Delete(q.coll, key) })
+}
+
+// retry reschedules the failed entry unless the object has changed in the meantime.
+func (q *ReplicQueue) retry(e *ReplicEntry) error {
+	return q.update(e, func(key string) error {
+		delay := replicRetryMin << uint(cmn.Min(e.Retries, 16))
+		if delay > replicRetryMax {
+			delay = replicRetryMax
+		}
+		e.Retries++
+		e.NextTry = time.Now().Add(delay).UnixNano()
+		return q.db.Set(q.coll, key, e)
+	})
+}
+
+func (q *ReplicQueue) update(e *ReplicEntry, f func(key string) error) error {
+	var (
+		curr ReplicEntry
+		key  = replicKey(e.Bck, e.ObjName)
+	)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.db.Get(q.coll, key, &curr); err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			err = nil
+		}
+		return err
+	}
+	if curr.Seq != e.Seq {
+		return nil
+	}
+	return f(key)
+}
+
+////////////////
+// XactReplic //
+////////////////
+
+func NewXactReplic(bck cmn.Bck, t cluster.Target, statsT stats.Tracker, remote RemoteAIS) *XactReplic {
+	r := &XactReplic{
+		XactDemandBase: *demand.NewXactDemandBaseBck(cmn.ActReplicate, bck),
+		t:              t,
+		statsT:         statsT,
+		remote:         remote,
+		queue:          ReplicQ,
+		wakeCh:         make(chan struct{}, 1),
+	}
+	r.InitIdle()
+	return r
+}
+
+func (r *XactReplic) IsMountpathXact() bool { return false }
+
+// Notify wakes up the xaction to replicate newly queued entries.
+func (r *XactReplic) Notify() {
+	r.IncPending() // not idle until (re)scanned
+	select {
+	case r.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *XactReplic) Run() error {
+	glog.Infoln(r.String())
+	ticker := time.NewTicker(replicScanTime)
+	defer ticker.Stop()
+	for {
+		if stop, err := r.replicate(); stop {
+			r.XactDemandBase.Stop()
+			r.Finish(err)
+			return err
+		}
+		select {
+		case <-r.wakeCh:
+		case <-ticker.C:
+		case <-r.IdleTimer():
+			r.XactDemandBase.Stop()
+			r.Finish()
+			return nil
+		case <-r.ChanAbort():
+			r.XactDemandBase.Stop()
+			return cmn.NewAbortedError(r.String())
+		}
+	}
+}
+
+func (r *XactReplic) Stats() cmn.XactStats {
+	baseStats := r.XactBase.Stats().(*cmn.BaseXactStats)
+	st := &stats.ReplicTargetStats{BaseXactStats: *baseStats}
+	bck := cluster.NewBckEmbed(r.Bck())
+	if err := bck.Init(r.t.GetBowner(), r.t.Snode()); err == nil {
+		st.Ext.Dest = bck.Props.Replication.Bck
+	}
+	st.Ext.Backlog = r.backlog.Load()
+	st.Ext.Lag = cmn.DurationJSON(r.lag.Load())
+	st.Ext.Deleted = r.deleted.Load()
+	st.Ext.Errors = r.errors.Load()
+	return st
+}
+
+// replicate forwards the due entries of the bucket; returns true when the
+// xaction must stop: the bucket does not exist or its replication is disabled.
+func (r *XactReplic) replicate() (stop bool, err error) {
+	bck := cluster.NewBckEmbed(r.Bck())
+	if err = bck.Init(r.t.GetBowner(), r.t.Snode()); err != nil {
+		if cmn.IsErrBucketNought(err) {
+			glog.Warningf("%s: %v - dropping the queue", r, err)
+			err = r.queue.RemoveAll(r.Bck())
+		}
+		return true, err
+	}
+	if !bck.Props.Replication.Enabled {
+		glog.Warningf("%s: replication disabled", r)
+		return true, nil
+	}
+	entries, err := r.queue.Entries(r.Bck())
+	if err != nil {
+		glog.Errorf("%s: %v", r, err)
+		return false, nil
+	}
+	var (
+		due []*ReplicEntry
+		now = time.Now().UnixNano()
+	)
+	for _, e := range entries {
+		if e.NextTry <= now {
+			due = append(due, e)
+		}
+	}
+	r.updateBacklog(entries, 0, now)
+	if len(due) == 0 {
+		return
+	}
+
+	dst := bck.Props.Replication.Bck
+	nDone := runWorkers(due, r.ChanAbort(), func(e *ReplicEntry) bool { return r.do(e, dst) })
+	r.updateBacklog(entries, nDone, time.Now().UnixNano())
+	return
+}
+
+// runWorkers executes `do` on the entries using up to replicWorkers goroutines
+// until done or aborted; returns the number of successfully processed entries.
+func runWorkers(entries []*ReplicEntry, abort <-chan struct{}, do func(e *ReplicEntry) bool) int64 {
+	var (
+		wg     = &sync.WaitGroup{}
+		workCh = make(chan *ReplicEntry)
+		nDone  atomic.Int64
+	)
+	for i := 0; i < cmn.Min(replicWorkers, len(entries)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range workCh {
+				if do(e) {
+					nDone.Inc()
+				}
+			}
+		}()
+	}
+loop:
+	for _, e := range entries {
+		select {
+		case workCh <- e:
+		case <-abort:
+			break loop
+		}
+	}
+	close(workCh)
+	wg.Wait()
+	return nDone.Load()
+}
+
+// do replicates a single entry; returns true on success.
+func (r *XactReplic) do(e *ReplicEntry, dst cmn.Bck) bool {
+	var (
+		size int64
+		err  error
+	)
+	switch e.Op {
+	case ReplicPut:
+		size, err = r.put(e, dst)
+	case ReplicDel:
+		err = r.remote.DeleteReplica(dst, e.ObjName)
+	default:
+		err = fmt.Errorf("invalid replication op %q", e.Op)
+	}
+	if err != nil {
+		if n := r.errors.Inc(); n == 1 || n%logNumProcessed == 0 || e.Retries == 0 {
+			glog.Errorf("%s: failed to %s %s/%s (retries: %d, errors: %d): %v",
+				r, e.Op, dst, e.ObjName, e.Retries, n, err)
+		}
+		r.statsT.Add(stats.ErrReplicCount, 1)
+		if err := r.queue.retry(e); err != nil {
+			glog.Errorf("%s: %v", r, err)
+		}
+		return false
+	}
+	if err := r.queue.done(e); err != nil {
+		glog.Errorf("%s: %v", r, err)
+	}
+	if e.Op == ReplicDel {
+		r.deleted.Inc()
+		r.statsT.Add(stats.ReplicDelCount, 1)
+		return true
+	}
+	r.ObjectsInc()
+	r.BytesAdd(size)
+	r.statsT.AddMany(
+		stats.NamedVal64{Name: stats.ReplicPutCount, Value: 1},
+		stats.NamedVal64{Name: stats.ReplicPutSize, Value: size},
+	)
+	return true
+}
+
+func (r *XactReplic) put(e *ReplicEntry, dst cmn.Bck) (size int64, err error) {
+	lom := &cluster.LOM{T: r.t, ObjName: e.ObjName}
+	if err = lom.Init(r.Bck()); err != nil {
+		return
+	}
+	lom.Lock(false)
+	if err = lom.Load(false); err != nil {
+		lom.Unlock(false)
+		if cmn.IsObjNotExist(err) {
+			// deleted in the meantime: the DELETE (if any) is queued separately
+			err = nil
+		}
+		return
+	}
+	file, err := os.Open(lom.FQN)
+	lom.Unlock(false)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	defer file.Close()
+	fh, err := cmn.NewFileSectionHandle(file, 0, lom.Size(), 0)
+	if err != nil {
+		return
+	}
+	if err = r.remote.PutReplica(dst, lom, fh); err != nil {
+		return
+	}
+	return lom.Size(), nil
+}
+
+// updateBacklog sets the backlog (excluding `nDone` just replicated entries)
+// and the replication lag, and keeps the xaction from idling while the backlog
+// is not empty.
+func (r *XactReplic) updateBacklog(entries []*ReplicEntry, nDone, now int64) {
+	var (
+		backlog = int64(len(entries)) - nDone
+		lag     int64
+	)
+	if backlog > 0 && len(entries) > 0 {
+		lag = now - entries[0].Queued
+	}
+	r.backlog.Store(backlog)
+	r.lag.Store(lag)
+	if pending := r.Pending(); pending != backlog {
+		r.SubPending(int(pending - backlog))
+	}
+}
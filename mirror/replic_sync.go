@@ -0,0 +1,147 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"runtime"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// XactReplicSync runs in a background, traverses all local mountpaths, and
+// queues for replication (see XactReplic) the objects that are missing at the
+// destination or differ in size or checksum. Objects that exist only at the
+// destination are not removed.
+
+type (
+	XactReplicSync struct {
+		xactBckBase
+		remote RemoteAIS
+		dst    cmn.Bck
+		notify func() // wakes up the replication of the queued objects
+	}
+	replicSyncJogger struct { // one per mountpath
+		joggerBckBase
+		parent *XactReplicSync
+	}
+)
+
+//
+// public methods
+//
+
+func NewXactReplicSync(bck cmn.Bck, t cluster.Target, id string, dst cmn.Bck, remote RemoteAIS,
+	notify func()) *XactReplicSync {
+	return &XactReplicSync{
+		xactBckBase: *newXactBckBase(id, cmn.ActReplicSync, bck, t),
+		remote:      remote,
+		dst:         dst,
+		notify:      notify,
+	}
+}
+
+func (r *XactReplicSync) Run() (err error) {
+	mpathersCount := r.init()
+	glog.Infoln(r.String(), "to", r.dst)
+	err = r.xactBckBase.run(mpathersCount)
+	if r.ObjCount() > 0 {
+		r.notify()
+	}
+	r.Finish(err)
+	return
+}
+
+//
+// private methods
+//
+
+func (r *XactReplicSync) init() (mpathCount int) {
+	var (
+		availablePaths, _ = fs.Get()
+		config            = cmn.GCO.Get()
+	)
+	mpathCount = len(availablePaths)
+
+	r.xactBckBase.init(mpathCount)
+	for _, mpathInfo := range availablePaths {
+		jogger := newReplicSyncJogger(r, mpathInfo, config)
+		mpathLC := mpathInfo.MakePathCT(r.Bck(), fs.ObjectType)
+		r.mpathers[mpathLC] = jogger
+	}
+	for _, mpather := range r.mpathers {
+		jogger := mpather.(*replicSyncJogger)
+		go jogger.jog()
+	}
+	return
+}
+
+//
+// mpath replicSyncJogger - main
+//
+
+func newReplicSyncJogger(parent *XactReplicSync, mpathInfo *fs.MountpathInfo, config *cmn.Config) *replicSyncJogger {
+	j := &replicSyncJogger{
+		joggerBckBase: joggerBckBase{
+			parent:    &parent.xactBckBase,
+			bck:       parent.Bck(),
+			mpathInfo: mpathInfo,
+			config:    config,
+		},
+		parent: parent,
+	}
+	j.joggerBckBase.callback = j.sync
+	return j
+}
+
+func (j *replicSyncJogger) jog() {
+	glog.Infof("jogger[%s/%s] started", j.mpathInfo, j.parent.Bck())
+	j.joggerBckBase.jog()
+}
+
+func (j *replicSyncJogger) sync(lom *cluster.LOM) error {
+	if j.parent.Aborted() {
+		return cmn.NewAbortedError("replication sync xaction")
+	}
+	props, err := j.parent.remote.HeadReplica(j.parent.dst, lom.ObjName)
+	if err == nil && props != nil && replicInSync(lom, props) {
+		return nil
+	}
+	// NOTE: failing HEAD is not fatal - the object gets queued and retried
+	if err := ReplicQ.Add(lom.Bck().Bck, lom.ObjName, ReplicPut, lom.Size()); err != nil {
+		return err
+	}
+
+	j.num++
+	j.parent.ObjectsInc()
+	j.parent.BytesAdd(lom.Size())
+
+	if (j.num % throttleNumObjects) == 0 {
+		if errstop := j.yieldTerm(); errstop != nil {
+			return errstop
+		}
+		if (j.num % logNumProcessed) == 0 {
+			glog.Infof("jogger[%s/%s] queued %d objects...", j.mpathInfo, j.parent.Bck(), j.num)
+			j.config = cmn.GCO.Get()
+			j.parent.notify()
+		}
+	} else {
+		runtime.Gosched()
+	}
+	return nil
+}
+
+func replicInSync(lom *cluster.LOM, props *cmn.ObjectProps) bool {
+	if props.Size != lom.Size() {
+		return false
+	}
+	cksum := lom.Cksum()
+	if cksum == nil || cksum.Type() == cmn.ChecksumNone || props.Checksum.Value == "" {
+		return true
+	}
+	return cksum.Type() != props.Checksum.Type || cksum.Value() == props.Checksum.Value
+}
@@ -0,0 +1,129 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReplicQueue", func() {
+	var (
+		q    *ReplicQueue
+		bck  = cmn.Bck{Name: "replicated", Provider: cmn.ProviderAIS}
+		bck2 = cmn.Bck{Name: "replicated2", Provider: cmn.ProviderAIS}
+	)
+
+	BeforeEach(func() {
+		q = NewReplicQueue(dbdriver.NewDBMock(), replicCollection)
+	})
+
+	It("should keep only the latest operation on the object", func() {
+		Expect(q.Add(bck, "obj", ReplicPut, 0)).NotTo(HaveOccurred())
+		entries, err := q.Entries(bck)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		first := entries[0]
+
+		Expect(q.Add(bck, "obj", ReplicDel, 0)).NotTo(HaveOccurred())
+		entries, err = q.Entries(bck)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Op).To(Equal(ReplicDel))
+		Expect(entries[0].Seq).NotTo(Equal(first.Seq))
+		// the object has been out of sync since the first operation
+		Expect(entries[0].Queued).To(Equal(first.Queued))
+	})
+
+	It("should not remove the entry replaced while replicating", func() {
+		Expect(q.Add(bck, "obj", ReplicPut, 0)).NotTo(HaveOccurred())
+		entries, _ := q.Entries(bck)
+		Expect(entries).To(HaveLen(1))
+
+		Expect(q.Add(bck, "obj", ReplicPut, 0)).NotTo(HaveOccurred())
+		Expect(q.done(entries[0])).NotTo(HaveOccurred())
+		entries, _ = q.Entries(bck)
+		Expect(entries).To(HaveLen(1))
+
+		Expect(q.done(entries[0])).NotTo(HaveOccurred())
+		entries, _ = q.Entries(bck)
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should reschedule failed entries with increasing delay", func() {
+		Expect(q.Add(bck, "obj", ReplicPut, 0)).NotTo(HaveOccurred())
+		entries, _ := q.Entries(bck)
+		e := entries[0]
+
+		Expect(q.retry(e)).NotTo(HaveOccurred())
+		entries, _ = q.Entries(bck)
+		Expect(entries[0].Retries).To(Equal(1))
+		delay := time.Until(time.Unix(0, entries[0].NextTry))
+		Expect(delay).To(BeNumerically("~", replicRetryMin, time.Second))
+
+		for i := 0; i < 20; i++ {
+			Expect(q.retry(entries[0])).NotTo(HaveOccurred())
+			entries, _ = q.Entries(bck)
+		}
+		delay = time.Until(time.Unix(0, entries[0].NextTry))
+		Expect(delay).To(BeNumerically("~", replicRetryMax, time.Second))
+	})
+
+	It("should summarize and remove the backlog per bucket", func() {
+		Expect(q.Add(bck, "obj1", ReplicPut, 0)).NotTo(HaveOccurred())
+		Expect(q.Add(bck, "obj2", ReplicDel, 0)).NotTo(HaveOccurred())
+		Expect(q.Add(bck2, "obj1", ReplicPut, 0)).NotTo(HaveOccurred())
+
+		backlog, err := q.Backlog()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backlog).To(HaveLen(2))
+		for _, b := range backlog {
+			if b.Bck.Equal(bck) {
+				Expect(b.Count).To(BeEquivalentTo(2))
+			} else {
+				Expect(b.Bck.Equal(bck2)).To(BeTrue())
+				Expect(b.Count).To(BeEquivalentTo(1))
+			}
+		}
+
+		Expect(q.RemoveAll(bck)).NotTo(HaveOccurred())
+		entries, _ := q.Entries(bck)
+		Expect(entries).To(BeEmpty())
+		entries, _ = q.Entries(bck2)
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should track, summarize, and remove dirty objects", func() {
+		var (
+			dq    = NewReplicQueue(dbdriver.NewDBMock(), wbCollection)
+			cloud = cmn.Bck{Name: "cloud", Provider: cmn.ProviderAmazon}
+		)
+		Expect(dq.Add(cloud, "obj1", ReplicPut, 1024)).NotTo(HaveOccurred())
+		Expect(dq.Add(cloud, "obj2", ReplicPut, 512)).NotTo(HaveOccurred())
+		Expect(dq.Add(cloud, "obj2", ReplicPut, 256)).NotTo(HaveOccurred())
+		Expect(dq.Has(cloud, "obj1")).To(BeTrue())
+		Expect(dq.Has(cloud, "obj3")).To(BeFalse())
+
+		b, err := dq.BckBacklog(cloud)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Count).To(BeEquivalentTo(2))
+		Expect(b.Size).To(BeEquivalentTo(1024 + 256))
+
+		Expect(dq.Remove(cloud, "obj1")).NotTo(HaveOccurred())
+		Expect(dq.Remove(cloud, "obj1")).NotTo(HaveOccurred())
+		Expect(dq.Has(cloud, "obj1")).To(BeFalse())
+		b, err = dq.BckBacklog(cloud)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Count).To(BeEquivalentTo(1))
+		Expect(b.Size).To(BeEquivalentTo(256))
+
+		// separate collections do not interfere
+		Expect(q.Has(cloud, "obj2")).To(BeFalse())
+	})
+})
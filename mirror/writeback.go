@@ -0,0 +1,305 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xaction/demand"
+)
+
+// Delayed writing of cloud buckets (see cmn.WritePolicyConf).
+//
+// With write-back and write-never policies PUT is acknowledged as soon as the
+// object is stored locally; the object is "dirty" until written to the cloud.
+// Dirty objects are recorded in the dirty queue (one per target, stored in the
+// target's database) which is processed by the on-demand XactWriteBack
+// (write-back) or by the user-started XactFlush (both policies). Failed
+// uploads remain queued and are retried with exponential backoff.
+
+const wbCollection = "writeback"
+
+type (
+	XactWriteBack struct {
+		// implements cmn.Xact and cmn.Runner interfaces
+		demand.XactDemandBase
+		t      cluster.Target
+		statsT stats.Tracker
+		queue  *ReplicQueue
+		wakeCh chan struct{}
+		errors atomic.Int64
+	}
+	XactFlush struct {
+		// implements cmn.Xact and cmn.Runner interfaces
+		cmn.XactBase
+		t      cluster.Target
+		statsT stats.Tracker
+		queue  *ReplicQueue
+		errors atomic.Int64
+	}
+)
+
+// DirtyQ is the target's queue of dirty objects (see InitWriteBack).
+var DirtyQ *ReplicQueue
+
+func InitWriteBack(db dbdriver.Driver) { DirtyQ = NewReplicQueue(db, wbCollection) }
+
+// IsDirty returns true if the object is yet to be written to the cloud.
+func IsDirty(lom *cluster.LOM) bool {
+	if !lom.Bck().IsCloud() || DirtyQ == nil {
+		return false
+	}
+	return DirtyQ.Has(lom.Bck().Bck, lom.ObjName)
+}
+
+///////////////////
+// XactWriteBack //
+///////////////////
+
+func NewXactWriteBack(bck cmn.Bck, t cluster.Target, statsT stats.Tracker) *XactWriteBack {
+	r := &XactWriteBack{
+		XactDemandBase: *demand.NewXactDemandBaseBck(cmn.ActWriteBack, bck),
+		t:              t,
+		statsT:         statsT,
+		queue:          DirtyQ,
+		wakeCh:         make(chan struct{}, 1),
+	}
+	r.InitIdle()
+	return r
+}
+
+func (r *XactWriteBack) IsMountpathXact() bool { return false }
+
+// Notify wakes up the xaction to write back newly queued objects.
+func (r *XactWriteBack) Notify() {
+	r.IncPending() // not idle until (re)scanned
+	select {
+	case r.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *XactWriteBack) Run() error {
+	glog.Infoln(r.String())
+	ticker := time.NewTicker(replicScanTime)
+	defer ticker.Stop()
+	for {
+		if stop, err := r.writeBack(); stop {
+			r.XactDemandBase.Stop()
+			r.Finish(err)
+			return err
+		}
+		select {
+		case <-r.wakeCh:
+		case <-ticker.C:
+		case <-r.IdleTimer():
+			r.XactDemandBase.Stop()
+			r.Finish()
+			return nil
+		case <-r.ChanAbort():
+			r.XactDemandBase.Stop()
+			return cmn.NewAbortedError(r.String())
+		}
+	}
+}
+
+// writeBack uploads the due dirty objects of the bucket; returns true when the
+// xaction must stop: the bucket does not exist or its policy is not write-back.
+func (r *XactWriteBack) writeBack() (stop bool, err error) {
+	bck := cluster.NewBckEmbed(r.Bck())
+	if err = bck.Init(r.t.GetBowner(), r.t.Snode()); err != nil {
+		if cmn.IsErrBucketNought(err) {
+			glog.Warningf("%s: %v - dropping the queue", r, err)
+			err = r.queue.RemoveAll(r.Bck())
+		}
+		return true, err
+	}
+	if bck.Props.WritePolicy.Policy() != cmn.WriteBack {
+		// NOTE: dirty objects remain queued until flushed
+		glog.Warningf("%s: write policy changed to %s", r, bck.Props.WritePolicy)
+		return true, nil
+	}
+	entries, err := r.queue.Entries(r.Bck())
+	if err != nil {
+		glog.Errorf("%s: %v", r, err)
+		return false, nil
+	}
+	var (
+		due []*ReplicEntry
+		now = time.Now().UnixNano()
+	)
+	for _, e := range entries {
+		if e.NextTry <= now {
+			due = append(due, e)
+		}
+	}
+	r.setPending(int64(len(entries)))
+	if len(due) == 0 {
+		return
+	}
+	nDone := runWorkers(due, r.ChanAbort(), func(e *ReplicEntry) bool {
+		size, err := writeBackObj(r.t, r.statsT, r.queue, e)
+		if err != nil {
+			if n := r.errors.Inc(); n == 1 || n%logNumProcessed == 0 || e.Retries == 0 {
+				glog.Errorf("%s: failed to write back %s (retries: %d, errors: %d): %v",
+					r, e.ObjName, e.Retries, n, err)
+			}
+			return false
+		}
+		r.ObjectsInc()
+		r.BytesAdd(size)
+		return true
+	})
+	r.setPending(int64(len(entries)) - nDone)
+	return
+}
+
+// setPending keeps the xaction from idling while the queue is not empty.
+func (r *XactWriteBack) setPending(backlog int64) {
+	if pending := r.Pending(); pending != backlog {
+		r.SubPending(int(pending - backlog))
+	}
+}
+
+///////////////
+// XactFlush //
+///////////////
+
+// XactFlush writes all dirty objects of the bucket to the cloud, regardless
+// of the write policy and the retry schedule.
+func NewXactFlush(bck cmn.Bck, t cluster.Target, statsT stats.Tracker, id string) *XactFlush {
+	return &XactFlush{
+		XactBase: *cmn.NewXactBaseBck(id, cmn.ActFlush, bck),
+		t:        t,
+		statsT:   statsT,
+		queue:    DirtyQ,
+	}
+}
+
+func (r *XactFlush) IsMountpathXact() bool { return false }
+
+func (r *XactFlush) Run() (err error) {
+	glog.Infoln(r.String())
+	entries, err := r.queue.Entries(r.Bck())
+	if err == nil {
+		runWorkers(entries, r.ChanAbort(), func(e *ReplicEntry) bool {
+			size, err := writeBackObj(r.t, r.statsT, r.queue, e)
+			if err != nil {
+				r.errors.Inc()
+				glog.Errorf("%s: failed to write back %s: %v", r, e.ObjName, err)
+				return false
+			}
+			r.ObjectsInc()
+			r.BytesAdd(size)
+			return true
+		})
+		if n := r.errors.Load(); n > 0 {
+			err = fmt.Errorf("%s: failed to write back %d (out of %d) objects", r, n, len(entries))
+		}
+	}
+	if r.Aborted() {
+		err = cmn.NewAbortedError(r.String())
+	}
+	r.Finish(err)
+	return
+}
+
+//
+// private functions
+//
+
+// writeBackObj uploads the dirty object to the cloud and updates its metadata
+// (unless the object has changed while uploading); returns the uploaded size.
+func writeBackObj(t cluster.Target, statsT stats.Tracker, queue *ReplicQueue, e *ReplicEntry) (size int64, err error) {
+	if size, err = uploadObj(t, e); err != nil {
+		statsT.Add(stats.ErrWriteBackCount, 1)
+		if errRetry := queue.retry(e); errRetry != nil {
+			glog.Errorf("%s: %v", e.ObjName, errRetry)
+		}
+		return
+	}
+	if errDone := queue.done(e); errDone != nil {
+		glog.Errorf("%s: %v", e.ObjName, errDone)
+	}
+	if size > 0 {
+		statsT.AddMany(
+			stats.NamedVal64{Name: stats.WriteBackCount, Value: 1},
+			stats.NamedVal64{Name: stats.WriteBackSize, Value: size},
+		)
+	}
+	return
+}
+
+func uploadObj(t cluster.Target, e *ReplicEntry) (size int64, err error) {
+	lom := &cluster.LOM{T: t, ObjName: e.ObjName}
+	if err = lom.Init(e.Bck); err != nil {
+		return
+	}
+	lom.Lock(false)
+	if err = lom.Load(false); err != nil {
+		lom.Unlock(false)
+		if cmn.IsObjNotExist(err) {
+			err = nil // deleted in the meantime
+		}
+		return
+	}
+	cksum := lom.Cksum()
+	file, err := os.Open(lom.FQN)
+	lom.Unlock(false)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil // removed in the meantime
+		}
+		return
+	}
+	cloud := t.Cloud(lom.Bck())
+	version, err, _ := cloud.PutObj(context.Background(), file, lom)
+	file.Close()
+	if err != nil {
+		return
+	}
+	size = lom.Size()
+
+	// the object is now clean - update its metadata the same way synchronous PUT does
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false); err != nil || !sameObj(lom, size, cksum) {
+		return size, nil // changed (and re-queued) or removed in the meantime
+	}
+	userMD := lom.UserMD()
+	customMD := cmn.SimpleKVs{cluster.SourceObjMD: cloud.Provider()}
+	if version != "" {
+		customMD[cluster.VersionObjMD] = version
+	}
+	lom.SetCustomMD(customMD)
+	lom.SetUserMD(userMD)
+	if lom.VersionConf().Enabled {
+		lom.SetVersion(version)
+	}
+	if err := lom.Persist(); err != nil {
+		glog.Errorf("%s: %v", lom, err)
+	}
+	lom.ReCache()
+	return size, nil
+}
+
+func sameObj(lom *cluster.LOM, size int64, cksum *cmn.Cksum) bool {
+	if lom.Size() != size {
+		return false
+	}
+	if cksum == nil || cksum.Type() == cmn.ChecksumNone {
+		return true
+	}
+	return cksum.Equal(lom.Cksum())
+}
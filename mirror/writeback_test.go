@@ -0,0 +1,281 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type (
+	// wbTargetMock is the target with the (mocked) cloud provider
+	wbTargetMock struct {
+		cluster.TargetMock
+		cloud *wbCloudMock
+	}
+	// wbCloudMock records uploaded objects; `onPut`, if set, runs while uploading
+	wbCloudMock struct {
+		cluster.CloudProvider
+		mtx   sync.Mutex
+		objs  map[string][]byte
+		err   error
+		onPut func(lom *cluster.LOM)
+	}
+)
+
+func (t *wbTargetMock) Cloud(_ *cluster.Bck) cluster.CloudProvider { return t.cloud }
+
+func (m *wbCloudMock) Provider() string { return cmn.ProviderAmazon }
+
+func (m *wbCloudMock) PutObj(_ context.Context, r io.Reader, lom *cluster.LOM) (string, error, int) {
+	if m.err != nil {
+		return "", m.err, http.StatusInternalServerError
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	if m.onPut != nil {
+		m.onPut(lom)
+	}
+	m.mtx.Lock()
+	m.objs[lom.ObjName] = data
+	m.mtx.Unlock()
+	return "v1", nil, 0
+}
+
+func (m *wbCloudMock) uploaded(objName string) (data []byte, ok bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	data, ok = m.objs[objName]
+	return
+}
+
+var _ = Describe("WriteBack", func() {
+	const (
+		wbMpath      = "/tmp/mirror-test_wb/mpath"
+		wbBucketName = "wb-bck"
+		wbObjSize    = 4 * cmn.KiB
+	)
+
+	var (
+		bck   = cmn.Bck{Name: wbBucketName, Provider: cmn.ProviderAmazon, Ns: cmn.NsGlobal}
+		tMock = &wbTargetMock{
+			TargetMock: *cluster.NewTargetMock(cluster.NewBaseBownerMock(
+				cluster.NewBck(wbBucketName, cmn.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{
+					Cksum:       cmn.CksumConf{Type: cmn.ChecksumXXHash},
+					WritePolicy: cmn.WritePolicyConf{Cloud: cmn.WriteBack},
+				}),
+			)),
+		}
+		statsT = stats.NewTrackerMock()
+		queue  *ReplicQueue
+		loms   []*cluster.LOM
+	)
+
+	// putObj stores the object locally and queues it the same way PUT does
+	putObj := func(objName string, data []byte) *cluster.LOM {
+		lom := &cluster.LOM{T: tMock, ObjName: objName}
+		Expect(lom.Init(bck)).NotTo(HaveOccurred())
+		lom.Lock(true)
+		defer lom.Unlock(true)
+		file, err := cmn.CreateFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = file.Write(data)
+		file.Close()
+		Expect(err).NotTo(HaveOccurred())
+		lom.SetSize(int64(len(data)))
+		lom.SetCksum(nil)
+		cksum, err := lom.ComputeCksumIfMissing()
+		Expect(err).NotTo(HaveOccurred())
+		lom.SetCksum(cksum)
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		lom.ReCache()
+		Expect(queue.Add(bck, objName, ReplicPut, lom.Size())).NotTo(HaveOccurred())
+		loms = append(loms, lom)
+		return lom
+	}
+
+	randData := func(size int) []byte {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+
+	entry := func(objName string) *ReplicEntry {
+		entries, err := queue.Entries(bck)
+		Expect(err).NotTo(HaveOccurred())
+		for _, e := range entries {
+			if e.ObjName == objName {
+				return e
+			}
+		}
+		return nil
+	}
+
+	loadLOM := func(objName string) *cluster.LOM {
+		lom := &cluster.LOM{T: tMock, ObjName: objName}
+		Expect(lom.Init(bck)).NotTo(HaveOccurred())
+		lom.Uncache()
+		Expect(lom.Load(false)).NotTo(HaveOccurred())
+		return lom
+	}
+
+	BeforeEach(func() {
+		Expect(cmn.CreateDir(wbMpath)).NotTo(HaveOccurred())
+		fs.DisableFsIDCheck()
+		_ = fs.Add(wbMpath)
+		_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+		_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+
+		tMock.cloud = &wbCloudMock{objs: make(map[string][]byte)}
+		queue = NewReplicQueue(dbdriver.NewDBMock(), wbCollection)
+		DirtyQ = queue
+		loms = loms[:0]
+	})
+
+	AfterEach(func() {
+		for _, lom := range loms {
+			lom.Uncache()
+			_ = os.Remove(lom.FQN)
+		}
+		DirtyQ = nil
+		_ = fs.Remove(wbMpath)
+		_ = os.RemoveAll("/tmp/mirror-test_wb")
+	})
+
+	Describe("writeBackObj", func() {
+		It("should upload the dirty object and mark it clean", func() {
+			data := randData(wbObjSize)
+			lom := putObj("obj", data)
+			lom.SetUserMD(cmn.SimpleKVs{"color": "blue"})
+			Expect(lom.Persist()).NotTo(HaveOccurred())
+			Expect(IsDirty(lom)).To(BeTrue())
+
+			size, err := writeBackObj(tMock, statsT, queue, entry("obj"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(BeEquivalentTo(wbObjSize))
+
+			uploaded, ok := tMock.cloud.uploaded("obj")
+			Expect(ok).To(BeTrue())
+			Expect(uploaded).To(Equal(data))
+			Expect(IsDirty(lom)).To(BeFalse())
+
+			lom = loadLOM("obj")
+			Expect(lom.CustomMD()).To(HaveKeyWithValue(cluster.SourceObjMD, cmn.ProviderAmazon))
+			Expect(lom.CustomMD()).To(HaveKeyWithValue(cluster.VersionObjMD, "v1"))
+			Expect(lom.UserMD()).To(HaveKeyWithValue("color", "blue"))
+		})
+
+		It("should keep the object dirty if it has been overwritten while uploading", func() {
+			putObj("obj", randData(wbObjSize))
+			e := entry("obj")
+			// same size, different content: detected by checksum
+			tMock.cloud.onPut = func(*cluster.LOM) { putObj("obj", randData(wbObjSize)) }
+
+			_, err := writeBackObj(tMock, statsT, queue, e)
+			Expect(err).NotTo(HaveOccurred())
+
+			curr := entry("obj")
+			Expect(curr).NotTo(BeNil())
+			Expect(curr.Seq).NotTo(Equal(e.Seq))
+			Expect(curr.Retries).To(BeZero())
+			lom := loadLOM("obj")
+			Expect(lom.CustomMD()).NotTo(HaveKey(cluster.SourceObjMD))
+		})
+
+		It("should keep the object dirty if it has been resized while uploading", func() {
+			putObj("obj", randData(wbObjSize))
+			tMock.cloud.onPut = func(*cluster.LOM) { putObj("obj", randData(wbObjSize/2)) }
+
+			_, err := writeBackObj(tMock, statsT, queue, entry("obj"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(entry("obj")).NotTo(BeNil())
+			lom := loadLOM("obj")
+			Expect(lom.Size()).To(BeEquivalentTo(wbObjSize / 2))
+			Expect(lom.CustomMD()).NotTo(HaveKey(cluster.SourceObjMD))
+		})
+
+		It("should reschedule the object if failed to upload", func() {
+			putObj("obj", randData(wbObjSize))
+			tMock.cloud.err = errors.New("cloud is down")
+
+			_, err := writeBackObj(tMock, statsT, queue, entry("obj"))
+			Expect(err).To(HaveOccurred())
+
+			e := entry("obj")
+			Expect(e).NotTo(BeNil())
+			Expect(e.Retries).To(Equal(1))
+			Expect(e.NextTry).NotTo(BeZero())
+		})
+
+		It("should drop the entry if the object has been removed", func() {
+			lom := putObj("obj", randData(wbObjSize))
+			e := entry("obj")
+			lom.Lock(true)
+			Expect(lom.Remove()).NotTo(HaveOccurred())
+			lom.Unlock(true)
+
+			size, err := writeBackObj(tMock, statsT, queue, e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(BeZero())
+			Expect(entry("obj")).To(BeNil())
+			_, ok := tMock.cloud.uploaded("obj")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("XactFlush", func() {
+		It("should write back all dirty objects regardless of the retry schedule", func() {
+			const numObjs = 10
+			for i := 0; i < numObjs; i++ {
+				putObj("flush/obj-"+cmn.GenUUID(), randData(wbObjSize))
+			}
+			// failed before and not yet due
+			Expect(queue.retry(entry(loms[0].ObjName))).NotTo(HaveOccurred())
+
+			xact := NewXactFlush(bck, tMock, statsT, cmn.GenUUID())
+			Expect(xact.Run()).NotTo(HaveOccurred())
+			Expect(xact.Finished()).To(BeTrue())
+			Expect(xact.ObjCount()).To(BeEquivalentTo(numObjs))
+			Expect(xact.BytesCount()).To(BeEquivalentTo(numObjs * wbObjSize))
+
+			backlog, err := queue.BckBacklog(bck)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backlog.Count).To(BeZero())
+			for _, lom := range loms {
+				_, ok := tMock.cloud.uploaded(lom.ObjName)
+				Expect(ok).To(BeTrue())
+			}
+		})
+
+		It("should fail and keep the objects that failed to upload", func() {
+			putObj("obj", randData(wbObjSize))
+			tMock.cloud.err = errors.New("cloud is down")
+
+			xact := NewXactFlush(bck, tMock, statsT, cmn.GenUUID())
+			Expect(xact.Run()).To(HaveOccurred())
+			Expect(xact.Finished()).To(BeTrue())
+			Expect(xact.ObjCount()).To(BeZero())
+			Expect(entry("obj")).NotTo(BeNil())
+		})
+	})
+})
@@ -96,6 +96,7 @@ func (w *Walk) CloudObjPage() (*cmn.BucketList, error) {
 		needCksum   = w.msg.WantProp(cmn.GetPropsChecksum)
 		needVersion = w.msg.WantProp(cmn.GetPropsVersion)
 		needCopies  = w.msg.WantProp(cmn.GetPropsCopies)
+		needUserMD  = w.msg.WantProp(cmn.GetPropsUserMD)
 	)
 
 	for _, e := range objList.Entries {
@@ -132,6 +133,9 @@ func (w *Walk) CloudObjPage() (*cmn.BucketList, error) {
 		if needCopies {
 			e.Copies = int16(lom.NumCopies())
 		}
+		if needUserMD {
+			e.UserMD = lom.UserMD()
+		}
 
 		if postCallback != nil {
 			postCallback(lom)
@@ -47,6 +47,7 @@ var (
 		cmn.GetPropsStatus,
 		cmn.GetPropsCopies,
 		cmn.GetTargetURL,
+		cmn.GetPropsUserMD,
 	}
 )
 
@@ -90,6 +91,7 @@ func (wi *WalkInfo) needVersion() bool   { return wi.propNeeded[cmn.GetPropsVers
 func (wi *WalkInfo) needStatus() bool    { return wi.propNeeded[cmn.GetPropsStatus] } //nolint:unused // left for consistency
 func (wi *WalkInfo) needCopies() bool    { return wi.propNeeded[cmn.GetPropsCopies] }
 func (wi *WalkInfo) needTargetURL() bool { return wi.propNeeded[cmn.GetTargetURL] }
+func (wi *WalkInfo) needUserMD() bool    { return wi.propNeeded[cmn.GetPropsUserMD] }
 
 // Checks if the directory should be processed by cache list call
 // Does checks:
@@ -163,6 +165,9 @@ func (wi *WalkInfo) lsObject(lom *cluster.LOM, objStatus uint16) *cmn.BucketEntr
 	if wi.needSize() {
 		fileInfo.Size = lom.Size()
 	}
+	if wi.needUserMD() {
+		fileInfo.UserMD = lom.UserMD()
+	}
 	if wi.postCallback != nil {
 		wi.postCallback(lom)
 	}
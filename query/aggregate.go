@@ -0,0 +1,176 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cluster"
+)
+
+// Aggregation queries: instead of returning the (filtered) objects, each target
+// computes statistics over the objects it stores; the proxy merges the results.
+
+const (
+	GroupByPrefix = "prefix" // group by the first `Depth` components of the object name
+	GroupByExt    = "ext"    // group by the object name extension
+
+	// statistics (columns) to report
+	AggrCount     = "count"
+	AggrSum       = "sum"
+	AggrMin       = "min"
+	AggrMax       = "max"
+	AggrHistogram = "histogram"
+)
+
+type (
+	AggregateMsg struct {
+		GroupBy   string `json:"group_by,omitempty"`   // one of: "" (single group), GroupByPrefix, GroupByExt
+		Depth     int    `json:"depth,omitempty"`      // GroupByPrefix only
+		AtimeStep int64  `json:"atime_step,omitempty"` // atime histogram bucket width (nanoseconds); 0 - no histogram
+		// statistics to report (e.g., AggrCount, AggrSum) - used by clients to present
+		// the result; targets compute all of them regardless
+		Columns []string `json:"columns,omitempty"`
+	}
+
+	AggregateStats struct {
+		Count   int64           `json:"count"`
+		Size    int64           `json:"size"` // total size
+		MinSize int64           `json:"min_size"`
+		MaxSize int64           `json:"max_size"`
+		Atime   map[int64]int64 `json:"atime,omitempty"` // histogram: bucket start (unix nanoseconds) => count
+	}
+
+	AggregateResult struct {
+		Done   bool                       `json:"done"`   // false if the query is still running
+		Groups map[string]*AggregateStats `json:"groups"` // by group key
+	}
+
+	aggregator struct {
+		mtx sync.Mutex
+		msg AggregateMsg
+		res AggregateResult
+	}
+)
+
+func (msg *AggregateMsg) Validate() error {
+	switch msg.GroupBy {
+	case "", GroupByExt:
+		if msg.Depth != 0 {
+			return fmt.Errorf("depth is only valid when grouping by %s", GroupByPrefix)
+		}
+	case GroupByPrefix:
+		if msg.Depth <= 0 {
+			return fmt.Errorf("grouping by %s requires positive depth, got %d", GroupByPrefix, msg.Depth)
+		}
+	default:
+		return fmt.Errorf("invalid group_by %q (expecting %q or %q)", msg.GroupBy, GroupByPrefix, GroupByExt)
+	}
+	if msg.AtimeStep < 0 {
+		return fmt.Errorf("invalid atime histogram step %d", msg.AtimeStep)
+	}
+	return nil
+}
+
+// groupKey returns the group of the object, e.g. "train/" for "train/a.jpg"
+// when grouping by prefix of depth 1, or ".jpg" when grouping by extension
+func (msg *AggregateMsg) groupKey(objName string) string {
+	switch msg.GroupBy {
+	case GroupByPrefix:
+		parts := strings.SplitN(objName, "/", msg.Depth+1)
+		if len(parts) <= msg.Depth { // fewer components: the object's "directory"
+			parts = parts[:len(parts)-1]
+		} else {
+			parts = parts[:msg.Depth]
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		return strings.Join(parts, "/") + "/"
+	case GroupByExt:
+		return path.Ext(objName)
+	default:
+		return ""
+	}
+}
+
+func newAggregator(msg *AggregateMsg) *aggregator {
+	return &aggregator{msg: *msg, res: AggregateResult{Groups: make(map[string]*AggregateStats, 8)}}
+}
+
+func (a *aggregator) add(lom *cluster.LOM) {
+	key := a.msg.groupKey(lom.ObjName)
+	a.mtx.Lock()
+	stats, ok := a.res.Groups[key]
+	if !ok {
+		stats = &AggregateStats{}
+		a.res.Groups[key] = stats
+	}
+	stats.add(lom.Size(), lom.AtimeUnix(), a.msg.AtimeStep)
+	a.mtx.Unlock()
+}
+
+func (a *aggregator) result(done bool) *AggregateResult {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	res := &AggregateResult{Done: done, Groups: make(map[string]*AggregateStats, len(a.res.Groups))}
+	res.Merge(&a.res)
+	return res
+}
+
+func (s *AggregateStats) add(size, atime, step int64) {
+	if s.Count == 0 || size < s.MinSize {
+		s.MinSize = size
+	}
+	if size > s.MaxSize {
+		s.MaxSize = size
+	}
+	s.Count++
+	s.Size += size
+	if step > 0 {
+		if s.Atime == nil {
+			s.Atime = make(map[int64]int64, 4)
+		}
+		s.Atime[atime-atime%step]++
+	}
+}
+
+func (s *AggregateStats) merge(other *AggregateStats) {
+	if other.Count == 0 {
+		return
+	}
+	if s.Count == 0 || other.MinSize < s.MinSize {
+		s.MinSize = other.MinSize
+	}
+	if other.MaxSize > s.MaxSize {
+		s.MaxSize = other.MaxSize
+	}
+	s.Count += other.Count
+	s.Size += other.Size
+	for bucket, cnt := range other.Atime {
+		if s.Atime == nil {
+			s.Atime = make(map[int64]int64, len(other.Atime))
+		}
+		s.Atime[bucket] += cnt
+	}
+}
+
+// Merge adds the other (e.g., another target's) result to this one.
+func (r *AggregateResult) Merge(other *AggregateResult) {
+	if r.Groups == nil {
+		r.Groups = make(map[string]*AggregateStats, len(other.Groups))
+	}
+	for key, stats := range other.Groups {
+		s, ok := r.Groups[key]
+		if !ok {
+			s = &AggregateStats{}
+			r.Groups[key] = s
+		}
+		s.merge(stats)
+	}
+}
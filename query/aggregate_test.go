@@ -0,0 +1,53 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregateGroupKey(t *testing.T) {
+	tests := []struct {
+		msg      AggregateMsg
+		objName  string
+		expected string
+	}{
+		{AggregateMsg{}, "train/a.jpg", ""},
+		{AggregateMsg{GroupBy: GroupByExt}, "train/a.jpg", ".jpg"},
+		{AggregateMsg{GroupBy: GroupByExt}, "train/a", ""},
+		{AggregateMsg{GroupBy: GroupByPrefix, Depth: 1}, "train/a.jpg", "train/"},
+		{AggregateMsg{GroupBy: GroupByPrefix, Depth: 2}, "train/cats/a.jpg", "train/cats/"},
+		{AggregateMsg{GroupBy: GroupByPrefix, Depth: 2}, "train/a.jpg", "train/"},
+		{AggregateMsg{GroupBy: GroupByPrefix, Depth: 1}, "a.jpg", ""},
+	}
+	for _, test := range tests {
+		if key := test.msg.groupKey(test.objName); key != test.expected {
+			t.Errorf("%+v, %q: expected %q, got %q", test.msg, test.objName, test.expected, key)
+		}
+	}
+}
+
+func TestAggregateMerge(t *testing.T) {
+	var (
+		step   = int64(10)
+		s1, s2 AggregateStats
+		res    AggregateResult
+	)
+	s1.add(100, 15, step)
+	s1.add(5, 27, step)
+	s2.add(50, 11, step)
+
+	res.Merge(&AggregateResult{Groups: map[string]*AggregateStats{"a/": &s1}})
+	res.Merge(&AggregateResult{Groups: map[string]*AggregateStats{"a/": &s2, "b/": {}}})
+
+	expected := &AggregateStats{Count: 3, Size: 155, MinSize: 5, MaxSize: 100, Atime: map[int64]int64{10: 2, 20: 1}}
+	if !reflect.DeepEqual(res.Groups["a/"], expected) {
+		t.Errorf("expected %+v, got %+v", expected, res.Groups["a/"])
+	}
+	if res.Groups["b/"].Count != 0 {
+		t.Errorf("expected empty group, got %+v", res.Groups["b/"])
+	}
+}
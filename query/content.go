@@ -0,0 +1,485 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/objwalk/walkinfo"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Content select (a.k.a. S3 Select): instead of (or in addition to) filtering
+// objects by their metadata, look into the contents of CSV and JSON-lines
+// objects and return only the rows (records) that match a given condition,
+// optionally projected onto a subset of columns.
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl" // JSON lines: one JSON object per line
+
+	// how to treat the first line of a CSV object
+	CSVHeaderNone   = ""       // no header: columns are referred to by position (`_1`, `_2`, ...)
+	CSVHeaderUse    = "use"    // header: columns are referred to by name or position
+	CSVHeaderIgnore = "ignore" // skip the first line; columns are referred to by position
+)
+
+type (
+	ContentMsg struct {
+		Format          string   `json:"format"`                     // FormatCSV or FormatJSONL
+		OutputFormat    string   `json:"output_format,omitempty"`    // same as `Format` if empty
+		FileHeader      string   `json:"file_header,omitempty"`      // CSV only, one of: CSVHeaderNone, CSVHeaderUse, CSVHeaderIgnore
+		Delimiter       string   `json:"delimiter,omitempty"`        // CSV field delimiter, "," if empty
+		OutputDelimiter string   `json:"output_delimiter,omitempty"` // CSV output field delimiter, "," if empty
+		Comment         string   `json:"comment,omitempty"`          // CSV lines starting with it are skipped
+		Columns         []string `json:"columns,omitempty"`          // projection; all columns if empty
+		Where           string   `json:"where,omitempty"`            // condition (see ParseRowExpr), e.g.: "age > 30 AND city = 'SF'"
+		Limit           int64    `json:"limit,omitempty"`            // maximum number of rows per scan (object or target); 0 - no limit
+	}
+
+	// ContentFilter is compiled ContentMsg: scans objects and writes out the
+	// matching rows.
+	ContentFilter struct {
+		msg       ContentMsg
+		where     RowExpr
+		delim     rune
+		outDelim  rune
+		comment   rune
+		wholeRows bool
+	}
+
+	// ContentScanner scans one or more objects subject to the common limit.
+	ContentScanner struct {
+		cf   *ContentFilter
+		w    io.Writer
+		csvw *csv.Writer
+		rows int64
+	}
+
+	// row of a CSV or JSON-lines object
+	row interface {
+		get(col string) (interface{}, bool)
+	}
+	csvRow struct {
+		rec    []string
+		header map[string]int
+	}
+	jsonRow map[string]interface{}
+)
+
+func (msg *ContentMsg) Validate() error {
+	switch msg.Format {
+	case FormatCSV, FormatJSONL:
+	default:
+		return fmt.Errorf("invalid content format %q (expecting %q or %q)", msg.Format, FormatCSV, FormatJSONL)
+	}
+	switch msg.OutputFormat {
+	case "", FormatCSV, FormatJSONL:
+	default:
+		return fmt.Errorf("invalid output format %q (expecting %q or %q)", msg.OutputFormat, FormatCSV, FormatJSONL)
+	}
+	switch msg.FileHeader {
+	case CSVHeaderNone, CSVHeaderUse, CSVHeaderIgnore:
+	default:
+		return fmt.Errorf("invalid file header %q (expecting %q or %q)", msg.FileHeader, CSVHeaderUse, CSVHeaderIgnore)
+	}
+	if msg.Limit < 0 {
+		return fmt.Errorf("invalid limit %d", msg.Limit)
+	}
+	return nil
+}
+
+func NewContentFilter(msg *ContentMsg) (cf *ContentFilter, err error) {
+	if err = msg.Validate(); err != nil {
+		return nil, err
+	}
+	cf = &ContentFilter{msg: *msg, delim: ',', outDelim: ','}
+	if cf.msg.OutputFormat == "" {
+		cf.msg.OutputFormat = cf.msg.Format
+	}
+	if cf.delim, err = singleRune(msg.Delimiter, ','); err != nil {
+		return nil, fmt.Errorf("invalid delimiter: %v", err)
+	}
+	if cf.outDelim, err = singleRune(msg.OutputDelimiter, ','); err != nil {
+		return nil, fmt.Errorf("invalid output delimiter: %v", err)
+	}
+	if cf.comment, err = singleRune(msg.Comment, 0); err != nil {
+		return nil, fmt.Errorf("invalid comment character: %v", err)
+	}
+	if msg.Where != "" {
+		if cf.where, err = ParseRowExpr(msg.Where); err != nil {
+			return nil, err
+		}
+	}
+	// "SELECT *" to the same format: rows are written out as they are
+	cf.wholeRows = (len(msg.Columns) == 0 || len(msg.Columns) == 1 && msg.Columns[0] == "*") &&
+		cf.msg.OutputFormat == cf.msg.Format && cf.delim == cf.outDelim
+	if len(msg.Columns) == 1 && msg.Columns[0] == "*" {
+		cf.msg.Columns = nil
+	}
+	return cf, nil
+}
+
+func singleRune(s string, dflt rune) (rune, error) {
+	if s == "" {
+		return dflt, nil
+	}
+	r := []rune(s)
+	if len(r) != 1 {
+		return 0, fmt.Errorf("expecting single character, got %q", s)
+	}
+	return r[0], nil
+}
+
+// NewScanner returns scanner that writes matching rows to `w`; the scanner
+// can be reused to scan multiple objects (e.g., all objects of a bucket).
+func (cf *ContentFilter) NewScanner(w io.Writer) *ContentScanner {
+	cs := &ContentScanner{cf: cf, w: w}
+	if cf.msg.OutputFormat == FormatCSV {
+		cs.csvw = csv.NewWriter(w)
+		cs.csvw.Comma = cf.outDelim
+	}
+	return cs
+}
+
+// Done returns true when the limit is reached.
+func (cs *ContentScanner) Done() bool { return cs.cf.msg.Limit > 0 && cs.rows >= cs.cf.msg.Limit }
+
+// Rows returns the number of rows written so far.
+func (cs *ContentScanner) Rows() int64 { return cs.rows }
+
+// Scan reads CSV or JSON-lines content and writes out the matching rows.
+func (cs *ContentScanner) Scan(r io.Reader) error {
+	if cs.Done() {
+		return nil
+	}
+	var err error
+	if cs.cf.msg.Format == FormatCSV {
+		err = cs.scanCSV(r)
+	} else {
+		err = cs.scanJSONL(r)
+	}
+	if cs.csvw != nil {
+		cs.csvw.Flush()
+		if err == nil {
+			err = cs.csvw.Error()
+		}
+	}
+	return err
+}
+
+func (cs *ContentScanner) scanCSV(r io.Reader) error {
+	var (
+		cf     = cs.cf
+		header map[string]int
+		cr     = csv.NewReader(r)
+	)
+	cr.Comma = cf.delim
+	cr.Comment = cf.comment
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	if cf.msg.FileHeader != CSVHeaderNone {
+		rec, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if cf.msg.FileHeader == CSVHeaderUse {
+			header = make(map[string]int, len(rec))
+			for i, name := range rec {
+				header[name] = i
+			}
+		}
+	}
+	for !cs.Done() {
+		rec, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		row := &csvRow{rec: rec, header: header}
+		if cf.where != nil && !cf.where.Eval(row) {
+			continue
+		}
+		if err := cs.writeRow(row, rec, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cs *ContentScanner) scanJSONL(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for lineno := 1; !cs.Done(); lineno++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var row jsonRow
+			if errJ := jsoniter.Unmarshal(trimmed, &row); errJ != nil {
+				return fmt.Errorf("line %d: invalid JSON object: %v", lineno, errJ)
+			}
+			if cs.cf.where == nil || cs.cf.where.Eval(row) {
+				if errW := cs.writeRow(row, nil, trimmed); errW != nil {
+					return errW
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeRow writes the (projected) row in the output format; `rec` and `raw`
+// are the original CSV record and JSON line, respectively.
+func (cs *ContentScanner) writeRow(row row, rec []string, raw []byte) (err error) {
+	cs.rows++
+	cf := cs.cf
+	if cf.wholeRows {
+		if raw != nil {
+			if _, err = cs.w.Write(raw); err == nil {
+				_, err = cs.w.Write([]byte{'\n'})
+			}
+			return
+		}
+		return cs.csvw.Write(rec)
+	}
+	names := cf.msg.Columns
+	if len(names) == 0 {
+		names = allColumns(row)
+	}
+	if cf.msg.OutputFormat == FormatCSV {
+		out := make([]string, len(names))
+		for i, name := range names {
+			if v, ok := row.get(name); ok {
+				out[i] = valueString(v)
+			}
+		}
+		return cs.csvw.Write(out)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		v, _ := row.get(name)
+		buf.Write(cmn.MustMarshal(name))
+		buf.WriteByte(':')
+		buf.Write(cmn.MustMarshal(v))
+	}
+	buf.WriteString("}\n")
+	_, err = cs.w.Write(buf.Bytes())
+	return
+}
+
+// allColumns returns the column names of the row: header (or positional) names
+// for CSV, sorted keys for JSON
+func allColumns(r row) []string {
+	switch r := r.(type) {
+	case *csvRow:
+		names := make([]string, len(r.rec))
+		if r.header != nil {
+			for name, i := range r.header {
+				if i < len(names) {
+					names[i] = name
+				}
+			}
+		}
+		for i := range names {
+			if names[i] == "" {
+				names[i] = "_" + strconv.Itoa(i+1)
+			}
+		}
+		return names
+	case jsonRow:
+		names := make([]string, 0, len(r))
+		for name := range r {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	default:
+		cmn.AssertMsg(false, fmt.Sprintf("unexpected row type %T", r))
+		return nil
+	}
+}
+
+// column is referred to by name (header) or position: `_1`, `_2`, ...
+func (r *csvRow) get(col string) (interface{}, bool) {
+	if i, ok := r.header[col]; ok {
+		if i < len(r.rec) {
+			return r.rec[i], true
+		}
+		return nil, false
+	}
+	if strings.HasPrefix(col, "_") {
+		if i, err := strconv.Atoi(col[1:]); err == nil && i > 0 && i <= len(r.rec) {
+			return r.rec[i-1], true
+		}
+	}
+	return nil, false
+}
+
+func (r jsonRow) get(col string) (interface{}, bool) {
+	v, ok := r[col]
+	return v, ok && v != nil
+}
+
+func valueString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	default:
+		return string(cmn.MustMarshal(v))
+	}
+}
+
+// likeRegexp converts SQL LIKE pattern ('%' - any sequence, '_' - any single
+// character) into regular expression.
+func likeRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteByte('.')
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteByte('$')
+	return regexp.MustCompile(sb.String())
+}
+
+// SelectContent scans the objects that match the query and are stored on this
+// target, and writes out the matching rows (see ContentMsg).
+func SelectContent(ctx context.Context, t cluster.Target, q *ObjectsQuery, w io.Writer) error {
+	cmn.Assert(q.Content != nil)
+	var (
+		cs     = q.Content.NewScanner(w)
+		bck    = q.BckSource.Bck
+		config = cmn.GCO.Get()
+	)
+	if q.ObjectsSource.Pt != nil {
+		smap := t.GetSowner().Get()
+		iter := q.ObjectsSource.Pt.Iter()
+		for objName, hasNext := iter(); hasNext && !cs.Done(); objName, hasNext = iter() {
+			lom := &cluster.LOM{T: t, ObjName: objName}
+			if err := lom.Init(bck.Bck, config); err != nil {
+				return err
+			}
+			si, err := cluster.HrwTarget(lom.Uname(), smap)
+			if err != nil {
+				return err
+			}
+			if si.ID() != t.Snode().ID() {
+				continue
+			}
+			if err := selectObject(lom, q.Filter(), cs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	smsg := &cmn.SelectMsg{Prefix: q.ObjectsSource.Prefix, Props: cmn.GetPropsName}
+	if q.Misplaced {
+		smsg.Flags = cmn.SelectMisplaced
+	}
+	wi := walkinfo.NewWalkInfo(ctx, t, smsg)
+	wi.SetObjectFilter(q.Filter())
+	cb := func(fqn string, de fs.DirEntry) error {
+		entry, err := wi.Callback(fqn, de)
+		if entry == nil || err != nil {
+			return err
+		}
+		lom := &cluster.LOM{T: t, ObjName: entry.Name}
+		if err := lom.Init(bck.Bck, config); err != nil {
+			return err
+		}
+		if err := selectObject(lom, nil, cs); err != nil {
+			return err
+		}
+		if cs.Done() {
+			return cmn.NewAbortedError("content select: limit reached")
+		}
+		return nil
+	}
+	opts := &fs.WalkBckOptions{
+		Options: fs.Options{
+			Bck:      bck.Bck,
+			CTs:      []string{fs.ObjectType},
+			Callback: cb,
+			Sorted:   true,
+		},
+		ValidateCallback: func(fqn string, de fs.DirEntry) error {
+			if de.IsDir() {
+				return wi.ProcessDir(fqn)
+			}
+			return nil
+		},
+	}
+	if err := fs.WalkBck(opts); err != nil && !cs.Done() {
+		return err
+	}
+	return nil
+}
+
+// SelectObjectContent scans a single object.
+func SelectObjectContent(lom *cluster.LOM, cf *ContentFilter, w io.Writer) error {
+	return selectObject(lom, nil, cf.NewScanner(w))
+}
+
+func selectObject(lom *cluster.LOM, filter cluster.ObjectFilter, cs *ContentScanner) error {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err := lom.Load(); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if filter != nil && !filter(lom) {
+		return nil
+	}
+	fh, err := os.Open(lom.FQN)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	if err := cs.Scan(fh); err != nil {
+		return fmt.Errorf("%s: %v", lom, err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testCSV = `name,age,city
+alice,34,San Francisco
+bob,28,"New York"
+carol,41,San Jose
+dave,,Boston
+`
+	testJSONL = `{"name":"alice","age":34,"city":"San Francisco"}
+{"name":"bob","age":28,"city":"New York"}
+
+{"name":"carol","age":41,"city":"San Jose","tags":["a"]}
+{"name":"dave","city":"Boston"}`
+)
+
+func TestContentScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      ContentMsg
+		input    string
+		expected string
+	}{
+		{
+			name:     "csv: all rows",
+			msg:      ContentMsg{Format: FormatCSV, FileHeader: CSVHeaderIgnore},
+			input:    testCSV,
+			expected: "alice,34,San Francisco\nbob,28,New York\ncarol,41,San Jose\ndave,,Boston\n",
+		},
+		{
+			name:     "csv: numeric comparison and projection",
+			msg:      ContentMsg{Format: FormatCSV, FileHeader: CSVHeaderUse, Columns: []string{"city", "name"}, Where: "age > 30"},
+			input:    testCSV,
+			expected: "San Francisco,alice\nSan Jose,carol\n",
+		},
+		{
+			name:     "csv: positional columns, LIKE, limit",
+			msg:      ContentMsg{Format: FormatCSV, FileHeader: CSVHeaderIgnore, Columns: []string{"_1"}, Where: "_3 LIKE 'San%'", Limit: 1},
+			input:    testCSV,
+			expected: "alice\n",
+		},
+		{
+			name: "csv to jsonl",
+			msg: ContentMsg{
+				Format: FormatCSV, OutputFormat: FormatJSONL, FileHeader: CSVHeaderUse,
+				Columns: []string{"name", "age"}, Where: "NOT (city = 'Boston') AND (age < 30 OR name = 'carol')",
+			},
+			input:    testCSV,
+			expected: "{\"name\":\"bob\",\"age\":\"28\"}\n{\"name\":\"carol\",\"age\":\"41\"}\n",
+		},
+		{
+			name:     "csv: custom delimiters",
+			msg:      ContentMsg{Format: FormatCSV, Delimiter: ";", OutputDelimiter: "|", Comment: "#", Where: "_2 != 'x'"},
+			input:    "# comment\na;x\nb;y\n",
+			expected: "b|y\n",
+		},
+		{
+			name:     "jsonl: rows as they are",
+			msg:      ContentMsg{Format: FormatJSONL, Where: "age >= 34"},
+			input:    testJSONL,
+			expected: "{\"name\":\"alice\",\"age\":34,\"city\":\"San Francisco\"}\n{\"name\":\"carol\",\"age\":41,\"city\":\"San Jose\",\"tags\":[\"a\"]}\n",
+		},
+		{
+			name:     "jsonl: projection, missing values",
+			msg:      ContentMsg{Format: FormatJSONL, Columns: []string{"name", "age"}, Where: "city <> 'New York'"},
+			input:    testJSONL,
+			expected: "{\"name\":\"alice\",\"age\":34}\n{\"name\":\"carol\",\"age\":41}\n{\"name\":\"dave\",\"age\":null}\n",
+		},
+		{
+			name:     "jsonl to csv",
+			msg:      ContentMsg{Format: FormatJSONL, OutputFormat: FormatCSV, Columns: []string{"name", "tags"}, Where: "name > 'b'"},
+			input:    testJSONL,
+			expected: "bob,\ncarol,\"[\"\"a\"\"]\"\ndave,\n",
+		},
+	}
+	for _, test := range tests {
+		cf, err := NewContentFilter(&test.msg)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var buf bytes.Buffer
+		if err := cf.NewScanner(&buf).Scan(strings.NewReader(test.input)); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%s: expected\n%q\ngot\n%q", test.name, test.expected, buf.String())
+		}
+	}
+}
+
+func TestContentScanLimit(t *testing.T) {
+	cf, err := NewContentFilter(&ContentMsg{Format: FormatCSV, FileHeader: CSVHeaderUse, Where: "age > 0", Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		buf bytes.Buffer
+		cs  = cf.NewScanner(&buf)
+	)
+	// the limit applies across all scanned objects
+	for i := 0; i < 3; i++ {
+		if err := cs.Scan(strings.NewReader(testCSV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cs.Rows() != 3 || !cs.Done() {
+		t.Errorf("expected 3 rows, got %d", cs.Rows())
+	}
+}
+
+func TestContentErrors(t *testing.T) {
+	msgs := []ContentMsg{
+		{Format: "parquet"},
+		{Format: FormatCSV, OutputFormat: "xml"},
+		{Format: FormatCSV, FileHeader: "first"},
+		{Format: FormatCSV, Delimiter: "::"},
+		{Format: FormatCSV, Where: "age >"},
+		{Format: FormatCSV, Where: "age > 10KiB"},
+		{Format: FormatJSONL, Limit: -1},
+	}
+	for _, msg := range msgs {
+		if _, err := NewContentFilter(&msg); err == nil {
+			t.Errorf("%+v: expected error", msg)
+		}
+	}
+
+	cf, _ := NewContentFilter(&ContentMsg{Format: FormatJSONL})
+	if err := cf.NewScanner(&bytes.Buffer{}).Scan(strings.NewReader("{\"a\":1}\nnot json\n")); err == nil {
+		t.Error("expected error on invalid JSON line")
+	}
+}
+
+func TestParseSelectExpr(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected *ContentMsg
+	}{
+		{
+			expr:     "SELECT * FROM S3Object",
+			expected: &ContentMsg{},
+		},
+		{
+			expr: "select s.name, s._2 from S3Object s where CAST(s.age AS INT) > 30 and s.city like 'San%' limit 10",
+			expected: &ContentMsg{
+				Columns: []string{"name", "_2"},
+				Where:   "(age > 30) AND (city LIKE 'San%')",
+				Limit:   10,
+			},
+		},
+		{
+			expr: "SELECT name FROM S3Object[*] AS t WHERE NOT t.city = 'O''Hare' OR t.age <= -1.5",
+			expected: &ContentMsg{
+				Columns: []string{"name"},
+				Where:   "(NOT (city = 'O''Hare')) OR (age <= -1.5)",
+			},
+		},
+	}
+	for _, test := range tests {
+		msg, err := ParseSelectExpr(test.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", test.expr, err)
+		}
+		if !reflect.DeepEqual(msg, test.expected) {
+			t.Errorf("%q: expected %+v, got %+v", test.expr, test.expected, msg)
+		}
+		if msg.Where != "" {
+			if _, err := ParseRowExpr(msg.Where); err != nil {
+				t.Errorf("%q: failed to re-parse %q: %v", test.expr, msg.Where, err)
+			}
+		}
+	}
+
+	for _, expr := range []string{
+		"SELECT * FROM table",
+		"SELECT count(*) FROM S3Object",
+		"SELECT * FROM S3Object LIMIT 0",
+		"SELECT * FROM S3Object WHERE name",
+		"SELECT * FROM S3Object s WHERE s.name = 'a' ORDER BY s.name",
+	} {
+		if _, err := ParseSelectExpr(expr); err == nil {
+			t.Errorf("%q: expected error", expr)
+		}
+	}
+}
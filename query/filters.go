@@ -5,14 +5,18 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
 )
 
 type (
@@ -25,6 +29,7 @@ type (
 const (
 	stringArg = iota
 	intArg
+	noArg
 
 	AtimeBeforeF = "atime_before"
 	AtimeAfterF  = "atime_after"
@@ -38,7 +43,26 @@ const (
 	VersionLeF = "version_le"
 	VersionGeF = "version_ge"
 
-	ExtF = "ext"
+	ExtF       = "ext"
+	NameLikeF  = "name_like" // SQL LIKE pattern: '%' matches any sequence, '_' - any single character
+	NameGlobF  = "name_glob" // shell pattern, as in path.Match
+	NameRegexF = "name_regex"
+
+	CustomMDF    = "custom_md"     // custom (e.g., Cloud-provided) metadata: key equals value
+	CustomMDHasF = "custom_md_has" // custom metadata: key exists
+
+	UserMDF    = "user_md"     // user-defined metadata: key equals value
+	UserMDHasF = "user_md_has" // user-defined metadata: key exists
+
+	CksumTypeF  = "cksum_type"
+	CksumValueF = "cksum"
+
+	CopiesF   = "copies" // number of local copies (replicas), including the object itself
+	CopiesGeF = "copies_ge"
+
+	ECEncodedF            = "ec_encoded"             // object is erasure coded (i.e., not replicated)
+	MisplacedF            = "misplaced"              // object is not in its HRW location
+	CloudVersionMismatchF = "cloud_version_mismatch" // object's version differs from its Cloud version
 )
 
 var functionMeta = map[string]filterMeta{
@@ -54,7 +78,26 @@ var functionMeta = map[string]filterMeta{
 	VersionLeF: {1, intArg},
 	VersionGeF: {1, intArg},
 
-	ExtF: {1, stringArg},
+	ExtF:       {1, stringArg},
+	NameLikeF:  {1, stringArg},
+	NameGlobF:  {1, stringArg},
+	NameRegexF: {1, stringArg},
+
+	CustomMDF:    {2, stringArg},
+	CustomMDHasF: {1, stringArg},
+
+	UserMDF:    {2, stringArg},
+	UserMDHasF: {1, stringArg},
+
+	CksumTypeF:  {1, stringArg},
+	CksumValueF: {1, stringArg},
+
+	CopiesF:   {2, intArg},
+	CopiesGeF: {1, intArg},
+
+	ECEncodedF:            {0, noArg},
+	MisplacedF:            {0, noArg},
+	CloudVersionMismatchF: {0, noArg},
 }
 
 func NewFilter(fname string, args []string) *FilterMsg {
@@ -79,6 +122,13 @@ func NewAndFilter(filters ...*FilterMsg) *FilterMsg {
 	}
 }
 
+func NewNotFilter(filter *FilterMsg) *FilterMsg {
+	return &FilterMsg{
+		Type:    NOT,
+		Filters: []*FilterMsg{filter},
+	}
+}
+
 func ObjFilterFromMsg(filter *FilterMsg) (cluster.ObjectFilter, error) {
 	if filter == nil {
 		return nil, nil
@@ -97,6 +147,15 @@ func ObjFilterFromMsg(filter *FilterMsg) (cluster.ObjectFilter, error) {
 			return And(filters...), nil
 		}
 		return Or(filters...), nil
+	case NOT:
+		if len(filter.Filters) != 1 {
+			return nil, fmt.Errorf("expected %s filter to have exactly 1 inner filter, got %d", filter.Type, len(filter.Filters))
+		}
+		f, err := ObjFilterFromMsg(filter.Filters[0])
+		if err != nil {
+			return nil, err
+		}
+		return Not(f), nil
 	case FUNCTION:
 		return functionFilterMsgToObjectFilter(filter)
 	default:
@@ -132,10 +191,41 @@ func functionFilterMsgToObjectFilter(filterMsg *FilterMsg) (cluster.ObjectFilter
 		return nil, fmt.Errorf("expected %d arguments, got %d", functionMeta[filterMsg.FName], len(filterMsg.Args))
 	}
 
+	if fMeta.argsType == noArg {
+		switch filterMsg.FName {
+		case ECEncodedF:
+			return ECEncodedFilter(), nil
+		case MisplacedF:
+			return MisplacedFilter(), nil
+		case CloudVersionMismatchF:
+			return CloudVersionMismatchFilter(), nil
+		default:
+			cmn.Assert(false)
+			return nil, nil
+		}
+	}
 	if fMeta.argsType == stringArg {
 		switch filterMsg.FName {
 		case ExtF:
 			return ExtFilter(filterMsg.Args[0]), nil
+		case NameLikeF:
+			return NameLikeFilter(filterMsg.Args[0]), nil
+		case NameGlobF:
+			return NameGlobFilter(filterMsg.Args[0])
+		case NameRegexF:
+			return NameRegexFilter(filterMsg.Args[0])
+		case CustomMDF:
+			return CustomMDFilter(filterMsg.Args[0], filterMsg.Args[1]), nil
+		case CustomMDHasF:
+			return CustomMDHasFilter(filterMsg.Args[0]), nil
+		case CksumTypeF:
+			return CksumTypeFilter(filterMsg.Args[0]), nil
+		case CksumValueF:
+			return CksumValueFilter(filterMsg.Args[0]), nil
+		case UserMDF:
+			return UserMDFilter(filterMsg.Args[0], filterMsg.Args[1]), nil
+		case UserMDHasF:
+			return UserMDHasFilter(filterMsg.Args[0]), nil
 		default:
 			cmn.Assert(false)
 			return nil, nil
@@ -166,6 +256,10 @@ func functionFilterMsgToObjectFilter(filterMsg *FilterMsg) (cluster.ObjectFilter
 		return VersionLEFilter(int(v[0])), nil
 	case VersionGeF:
 		return VersionGEFilter(int(v[0])), nil
+	case CopiesF:
+		return CopiesFilter(int(v[0]), int(v[1])), nil
+	case CopiesGeF:
+		return CopiesGEFilter(int(v[0])), nil
 	default:
 		cmn.Assert(false)
 		return nil, nil
@@ -299,6 +393,226 @@ func ExtFilter(ext string) cluster.ObjectFilter {
 	}
 }
 
+func NameLikeFilter(pattern string) cluster.ObjectFilter {
+	re := likeRegexp(pattern)
+	return func(lom *cluster.LOM) bool {
+		return re.MatchString(lom.ObjName)
+	}
+}
+
+func NameLikeFilterMsg(pattern string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: NameLikeF,
+		Args:  []string{pattern},
+	}
+}
+
+func NameGlobFilter(pattern string) (cluster.ObjectFilter, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	return func(lom *cluster.LOM) bool {
+		matched, _ := path.Match(pattern, lom.ObjName)
+		return matched
+	}, nil
+}
+
+func NameGlobFilterMsg(pattern string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: NameGlobF,
+		Args:  []string{pattern},
+	}
+}
+
+func NameRegexFilter(expr string) (cluster.ObjectFilter, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %v", expr, err)
+	}
+	return func(lom *cluster.LOM) bool {
+		return re.MatchString(lom.ObjName)
+	}, nil
+}
+
+func NameRegexFilterMsg(expr string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: NameRegexF,
+		Args:  []string{expr},
+	}
+}
+
+func CustomMDFilter(key, value string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		v, ok := lom.GetCustomMD(key)
+		return ok && v == value
+	}
+}
+
+func CustomMDFilterMsg(key, value string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CustomMDF,
+		Args:  []string{key, value},
+	}
+}
+
+func CustomMDHasFilter(key string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		_, ok := lom.GetCustomMD(key)
+		return ok
+	}
+}
+
+func CustomMDHasFilterMsg(key string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CustomMDHasF,
+		Args:  []string{key},
+	}
+}
+
+func UserMDFilter(key, value string) cluster.ObjectFilter {
+	key = strings.ToLower(key)
+	return func(lom *cluster.LOM) bool {
+		v, ok := lom.GetCustomMD(cluster.UserObjMDPrefix + key)
+		return ok && v == value
+	}
+}
+
+func UserMDFilterMsg(key, value string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: UserMDF,
+		Args:  []string{key, value},
+	}
+}
+
+func UserMDHasFilter(key string) cluster.ObjectFilter {
+	key = strings.ToLower(key)
+	return func(lom *cluster.LOM) bool {
+		_, ok := lom.GetCustomMD(cluster.UserObjMDPrefix + key)
+		return ok
+	}
+}
+
+func UserMDHasFilterMsg(key string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: UserMDHasF,
+		Args:  []string{key},
+	}
+}
+
+func CksumTypeFilter(ty string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		cksum := lom.Cksum()
+		return cksum != nil && cksum.Type() == ty
+	}
+}
+
+func CksumTypeFilterMsg(ty string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CksumTypeF,
+		Args:  []string{ty},
+	}
+}
+
+func CksumValueFilter(value string) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		cksum := lom.Cksum()
+		return cksum != nil && cksum.Value() == value
+	}
+}
+
+func CksumValueFilterMsg(value string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CksumValueF,
+		Args:  []string{value},
+	}
+}
+
+func CopiesFilter(min, max int) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		n := lom.NumCopies()
+		return n >= min && n <= max
+	}
+}
+
+func CopiesFilterMsg(min, max int64) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CopiesF,
+		Args:  []string{cmn.I2S(min), cmn.I2S(max)},
+	}
+}
+
+func CopiesGEFilter(n int) cluster.ObjectFilter {
+	return CopiesFilter(n, math.MaxInt32)
+}
+
+func CopiesGEFilterMsg(n int64) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: CopiesGeF,
+		Args:  []string{cmn.I2S(n)},
+	}
+}
+
+func ECEncodedFilter() cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		if !lom.ECEnabled() {
+			return false
+		}
+		md, err := ec.ObjectMetadata(lom.Bck(), lom.ObjName)
+		return err == nil && !md.IsCopy
+	}
+}
+
+func ECEncodedFilterMsg() *FilterMsg {
+	return &FilterMsg{Type: FUNCTION, FName: ECEncodedF}
+}
+
+// misplaced: either on a wrong mountpath or on a wrong target
+func MisplacedFilter() cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		if !lom.IsHRW() {
+			return true
+		}
+		si, err := cluster.HrwTarget(lom.Uname(), lom.T.GetSowner().Get())
+		return err == nil && si.ID() != lom.T.Snode().ID()
+	}
+}
+
+func MisplacedFilterMsg() *FilterMsg {
+	return &FilterMsg{Type: FUNCTION, FName: MisplacedF}
+}
+
+// NOTE: checks the current version with the Cloud provider (one request per object)
+func CloudVersionMismatchFilter() cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		if !lom.Bck().IsCloud() {
+			return false
+		}
+		vchanged, err, _ := lom.T.CheckCloudVersion(context.Background(), lom)
+		return err == nil && vchanged
+	}
+}
+
+func CloudVersionMismatchFilterMsg() *FilterMsg {
+	return &FilterMsg{Type: FUNCTION, FName: CloudVersionMismatchF}
+}
+
+func Not(filter cluster.ObjectFilter) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		return !filter(lom)
+	}
+}
+
 func And(filters ...cluster.ObjectFilter) cluster.ObjectFilter {
 	return func(lom *cluster.LOM) bool {
 		for _, f := range filters {
@@ -10,12 +10,15 @@ const (
 	FUNCTION = "F"
 	AND      = "AND"
 	OR       = "OR"
+	NOT      = "NOT"
 )
 
 type (
 	InitMsg struct {
-		QueryMsg   DefMsg `json:"query"`
-		WorkersCnt uint   `json:"workers"`
+		QueryMsg   DefMsg      `json:"query"`
+		Expr       string      `json:"expr,omitempty"` // SQL-like query (see Parse); when set, `QueryMsg` is ignored
+		WorkersCnt uint        `json:"workers"`
+		Persist    *PersistMsg `json:"persist,omitempty"` // materialize the results into a bucket (see PersistMsg)
 	}
 
 	NextMsg struct {
@@ -30,18 +33,21 @@ type (
 		InnerSelect InnerSelectMsg `json:"inner_select"`
 		From        FromMsg        `json:"from"`
 		Where       WhereMsg       `json:"where"`
+		Aggregate   *AggregateMsg  `json:"aggregate,omitempty"` // compute statistics instead of listing objects
 		Fast        bool           `json:"fast"`
 	}
 
 	// OuterSelect -> Look only on objects' metadata.
-	// In the future we might have InnerSelect, which looks into objects' contents
 	OuterSelectMsg struct {
-		Prefix   string `json:"prefix"`
-		Template string `json:"objects_source"`
+		Prefix     string `json:"prefix"`
+		Template   string `json:"objects_source"`
+		StartAfter string `json:"start_after,omitempty"` // skip objects with names up to and including this one
 	}
 
+	// InnerSelect -> Objects' properties and, optionally, contents (see ContentMsg)
 	InnerSelectMsg struct {
-		Props string `json:"props"`
+		Props   string      `json:"props"`
+		Content *ContentMsg `json:"content,omitempty"`
 	}
 
 	FromMsg struct {
@@ -53,7 +59,7 @@ type (
 	}
 
 	FilterMsg struct {
-		Type string `json:"type"` // one of: FUNCTION, AND, OR
+		Type string `json:"type"` // one of: FUNCTION, AND, OR, NOT
 
 		FName string   `json:"filter_name"`
 		Args  []string `json:"args"`
@@ -61,3 +67,19 @@ type (
 		Filters []*FilterMsg `json:"inner_filters"`
 	}
 )
+
+// Uses returns true if the filter (or any of its inner filters) is the given function.
+func (f *FilterMsg) Uses(fname string) bool {
+	if f == nil {
+		return false
+	}
+	if f.Type == FUNCTION {
+		return f.FName == fname
+	}
+	for _, inner := range f.Filters {
+		if inner.Uses(fname) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,648 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// SQL-like query language, e.g.:
+//
+//   SELECT name,size FROM ais://imgs WHERE size > 1MiB AND atime < '2020-01-01' AND name LIKE 'train/%'
+//
+// Grammar (keywords are case-insensitive):
+//
+//   query     := SELECT props|aggrs FROM bucket [WHERE expr] [GROUP BY group]
+//   props     := '*' | prop [',' prop]...
+//   aggrs     := aggr [',' aggr]...
+//   aggr      := count(*) | sum(size) | min(size) | max(size) | histogram(atime, 'step')
+//   group     := prefix(depth) | ext
+//   expr      := term [OR term]...
+//   term      := factor [AND factor]...
+//   factor    := NOT factor | '(' expr ')' | predicate
+//   predicate := size|version|copies op number | atime op 'date'
+//              | name LIKE|GLOB|REGEXP 'pattern'
+//              | ext|cksum|cksum_type|user_md.KEY|custom_md.KEY ('='|'!=') 'value'
+//              | ec_encoded | misplaced | cloud_version_mismatch
+//   op        := '=' | '!=' | '<' | '<=' | '>' | '>='
+//
+// The bucket is given as [provider://]bucket-name[/prefix] - the optional prefix
+// limits the query to the objects with names starting with it.
+
+const (
+	tokEOF = iota
+	tokErr // lexer error: reported when (and if) the parser gets to it
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokComma
+	tokStar
+	tokLParen
+	tokRParen
+)
+
+const (
+	kwSelect = "SELECT"
+	kwFrom   = "FROM"
+	kwWhere  = "WHERE"
+	kwGroup  = "GROUP"
+	kwBy     = "BY"
+	kwAnd    = "AND"
+	kwOr     = "OR"
+	kwNot    = "NOT"
+	kwLike   = "LIKE"
+	kwGlob   = "GLOB"
+	kwRegexp = "REGEXP"
+
+	fieldName      = "name"
+	fieldSize      = "size"
+	fieldAtime     = "atime"
+	fieldVersion   = "version"
+	fieldExt       = "ext"
+	fieldCopies    = "copies"
+	fieldCksum     = "cksum"
+	fieldCksumType = "cksum_type"
+	fieldUserMD    = "user_md."
+	fieldCustomMD  = "custom_md."
+
+	fieldECEncoded            = "ec_encoded"
+	fieldMisplaced            = "misplaced"
+	fieldCloudVersionMismatch = "cloud_version_mismatch"
+)
+
+var atimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+type (
+	token struct {
+		typ int
+		val string
+		pos int // 1-based position in the query string
+	}
+
+	// ParseError describes a syntax error and its (1-based) position in the query
+	ParseError struct {
+		Pos int
+		Msg string
+	}
+
+	parser struct {
+		src   string
+		toks  []token
+		idx   int
+		alias string // S3 Select only: `FROM S3Object alias`
+	}
+)
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses the query and returns the corresponding query definition.
+func Parse(expr string) (*DefMsg, error) {
+	p := &parser{src: expr}
+	p.tokenize()
+	return p.parseQuery()
+}
+
+///////////
+// lexer //
+///////////
+
+func isIdentRune(r byte) bool {
+	return r == '_' || r == '.' || r == '-' || r < unicode.MaxASCII && (unicode.IsLetter(rune(r)) || unicode.IsDigit(rune(r)))
+}
+
+func (p *parser) tokenize() {
+	src := p.src
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case len(p.toks) > 0 && p.isKeyword(p.toks[len(p.toks)-1], kwFrom):
+			// bucket URI is taken verbatim, up to the next whitespace
+			j := strings.IndexAny(src[i:], " \t\r\n")
+			if j < 0 {
+				j = len(src)
+			} else {
+				j += i
+			}
+			p.toks = append(p.toks, token{typ: tokString, val: src[i:j], pos: i + 1})
+			i = j
+		case c == ',':
+			p.toks = append(p.toks, token{typ: tokComma, val: ",", pos: i + 1})
+			i++
+		case c == '*':
+			p.toks = append(p.toks, token{typ: tokStar, val: "*", pos: i + 1})
+			i++
+		case c == '(':
+			p.toks = append(p.toks, token{typ: tokLParen, val: "(", pos: i + 1})
+			i++
+		case c == ')':
+			p.toks = append(p.toks, token{typ: tokRParen, val: ")", pos: i + 1})
+			i++
+		case c == '=' || c == '<' || c == '>' || c == '!':
+			j := i + 1
+			if j < len(src) && (src[j] == '=' || (c == '<' && src[j] == '>')) {
+				j++
+			}
+			op := src[i:j]
+			if op == "!" {
+				p.toks = append(p.toks, token{typ: tokErr, val: "unexpected character '!'", pos: i + 1})
+				return
+			}
+			p.toks = append(p.toks, token{typ: tokOp, val: op, pos: i + 1})
+			i = j
+		case c == '\'':
+			var (
+				sb strings.Builder
+				j  = i + 1
+			)
+			for {
+				if j >= len(src) {
+					p.toks = append(p.toks, token{typ: tokErr, val: "unterminated string", pos: i + 1})
+					return
+				}
+				if src[j] == '\'' {
+					if j+1 < len(src) && src[j+1] == '\'' { // escaped quote
+						sb.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(src[j])
+				j++
+			}
+			p.toks = append(p.toks, token{typ: tokString, val: sb.String(), pos: i + 1})
+			i = j + 1
+		case isIdentRune(c):
+			j := i
+			for j < len(src) && isIdentRune(src[j]) {
+				j++
+			}
+			typ := tokIdent
+			if c >= '0' && c <= '9' {
+				typ = tokNumber
+			}
+			p.toks = append(p.toks, token{typ: typ, val: src[i:j], pos: i + 1})
+			i = j
+		default:
+			p.toks = append(p.toks, token{typ: tokErr, val: fmt.Sprintf("unexpected character %q", c), pos: i + 1})
+			return
+		}
+	}
+	p.toks = append(p.toks, token{typ: tokEOF, pos: len(src) + 1})
+}
+
+////////////
+// parser //
+////////////
+
+func (p *parser) peek() token { return p.toks[p.idx] }
+
+func (p *parser) next() token {
+	tok := p.toks[p.idx]
+	if tok.typ != tokEOF && tok.typ != tokErr {
+		p.idx++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(tok token, kw string) bool {
+	return tok.typ == tokIdent && strings.EqualFold(tok.val, kw)
+}
+
+func (p *parser) expectKeyword(kw string) (token, error) {
+	tok := p.next()
+	if !p.isKeyword(tok, kw) {
+		return tok, p.unexpected(tok, kw)
+	}
+	return tok, nil
+}
+
+func (p *parser) unexpected(tok token, expected string) error {
+	if tok.typ == tokErr {
+		return &ParseError{tok.pos, tok.val}
+	}
+	if tok.typ == tokEOF {
+		return &ParseError{tok.pos, fmt.Sprintf("expected %s, got end of query", expected)}
+	}
+	return &ParseError{tok.pos, fmt.Sprintf("expected %s, got %q", expected, tok.val)}
+}
+
+func (p *parser) parseQuery() (*DefMsg, error) {
+	msg := &DefMsg{}
+	if _, err := p.expectKeyword(kwSelect); err != nil {
+		return nil, err
+	}
+	if err := p.parseSelect(msg); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectKeyword(kwFrom); err != nil {
+		return nil, err
+	}
+	if err := p.parseFrom(msg); err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); p.isKeyword(tok, kwWhere) {
+		p.next()
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		msg.Where.Filter = filter
+		if msg.OuterSelect.Prefix == "" {
+			msg.OuterSelect.Prefix = namePrefix(filter)
+		}
+	}
+	if tok := p.peek(); p.isKeyword(tok, kwGroup) {
+		if msg.Aggregate == nil {
+			return nil, &ParseError{tok.pos, "GROUP BY requires aggregate functions (e.g., count(*)) in SELECT"}
+		}
+		p.next()
+		if err := p.parseGroupBy(msg.Aggregate); err != nil {
+			return nil, err
+		}
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, p.unexpected(tok, "end of query")
+	}
+	return msg, nil
+}
+
+func (p *parser) parseSelect(msg *DefMsg) error {
+	if p.peek().typ == tokIdent && p.toks[p.idx+1].typ == tokLParen {
+		return p.parseAggregates(msg)
+	}
+	if tok := p.peek(); tok.typ == tokStar {
+		p.next()
+		msg.InnerSelect.Props = strings.Join(cmn.GetPropsAll, ",")
+		return nil
+	}
+	props := make([]string, 0, 4)
+	for {
+		tok := p.next()
+		if tok.typ != tokIdent || p.isKeyword(tok, kwFrom) {
+			return p.unexpected(tok, "property name")
+		}
+		if p.peek().typ == tokLParen {
+			return &ParseError{tok.pos, "cannot mix aggregate functions and object properties"}
+		}
+		prop := strings.ToLower(tok.val)
+		if prop != cmn.GetPropsName && !cmn.StringInSlice(prop, cmn.GetPropsAll) {
+			return &ParseError{tok.pos, fmt.Sprintf("unknown property %q", tok.val)}
+		}
+		if !cmn.StringInSlice(prop, props) {
+			props = append(props, prop)
+		}
+		if p.peek().typ != tokComma {
+			break
+		}
+		p.next()
+	}
+	msg.InnerSelect.Props = strings.Join(props, ",")
+	return nil
+}
+
+// aggregate functions: count(*), sum(size), min(size), max(size), histogram(atime, 'step')
+func (p *parser) parseAggregates(msg *DefMsg) error {
+	aggr := &AggregateMsg{}
+	for {
+		tok := p.next()
+		fn := strings.ToLower(tok.val)
+		if tok.typ != tokIdent || (fn != AggrCount && fn != AggrSum && fn != AggrMin && fn != AggrMax && fn != AggrHistogram) {
+			return p.unexpected(tok, "aggregate function (count, sum, min, max, or histogram)")
+		}
+		if tok := p.next(); tok.typ != tokLParen {
+			return p.unexpected(tok, "'('")
+		}
+		arg := p.next()
+		switch fn {
+		case AggrCount:
+			if arg.typ != tokStar {
+				return p.unexpected(arg, "'*'")
+			}
+		case AggrHistogram:
+			if !strings.EqualFold(arg.val, fieldAtime) || arg.typ != tokIdent {
+				return p.unexpected(arg, fieldAtime)
+			}
+			if tok := p.next(); tok.typ != tokComma {
+				return p.unexpected(tok, "','")
+			}
+			step, err := p.expectString()
+			if err != nil {
+				return err
+			}
+			d, err := time.ParseDuration(step.val)
+			if err != nil || d <= 0 {
+				return &ParseError{step.pos, fmt.Sprintf("invalid histogram step %q", step.val)}
+			}
+			aggr.AtimeStep = int64(d)
+		default:
+			if !strings.EqualFold(arg.val, fieldSize) || arg.typ != tokIdent {
+				return p.unexpected(arg, fieldSize)
+			}
+		}
+		if tok := p.next(); tok.typ != tokRParen {
+			return p.unexpected(tok, "')'")
+		}
+		if !cmn.StringInSlice(fn, aggr.Columns) {
+			aggr.Columns = append(aggr.Columns, fn)
+		}
+		if p.peek().typ != tokComma {
+			break
+		}
+		p.next()
+	}
+	msg.Aggregate = aggr
+	msg.InnerSelect.Props = strings.Join([]string{cmn.GetPropsName, cmn.GetPropsSize, cmn.GetPropsAtime}, ",")
+	return nil
+}
+
+// GROUP BY prefix(depth) | ext
+func (p *parser) parseGroupBy(aggr *AggregateMsg) error {
+	if _, err := p.expectKeyword(kwBy); err != nil {
+		return err
+	}
+	tok := p.next()
+	switch {
+	case p.isKeyword(tok, GroupByExt):
+		aggr.GroupBy = GroupByExt
+	case p.isKeyword(tok, GroupByPrefix):
+		if tok := p.next(); tok.typ != tokLParen {
+			return p.unexpected(tok, "'('")
+		}
+		depth := p.next()
+		n, err := strconv.Atoi(depth.val)
+		if depth.typ != tokNumber || err != nil || n <= 0 {
+			return p.unexpected(depth, "positive number")
+		}
+		if tok := p.next(); tok.typ != tokRParen {
+			return p.unexpected(tok, "')'")
+		}
+		aggr.GroupBy, aggr.Depth = GroupByPrefix, n
+	default:
+		return p.unexpected(tok, "prefix(DEPTH) or ext")
+	}
+	return nil
+}
+
+func (p *parser) parseFrom(msg *DefMsg) error {
+	tok := p.next()
+	if tok.typ == tokEOF {
+		return p.unexpected(tok, "bucket")
+	}
+	bck, prefix, err := cmn.ParseBckObjectURI(tok.val)
+	if err != nil {
+		return &ParseError{tok.pos, err.Error()}
+	}
+	if bck.Name == "" {
+		return &ParseError{tok.pos, fmt.Sprintf("invalid bucket %q", tok.val)}
+	}
+	msg.From.Bck = bck
+	msg.OuterSelect.Prefix = prefix
+	return nil
+}
+
+func (p *parser) parseOr() (*FilterMsg, error) {
+	return p.parseBinary(kwOr, OR, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*FilterMsg, error) {
+	return p.parseBinary(kwAnd, AND, p.parseFactor)
+}
+
+func (p *parser) parseBinary(kw, typ string, parseOperand func() (*FilterMsg, error)) (*FilterMsg, error) {
+	filter, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	filters := []*FilterMsg{filter}
+	for p.isKeyword(p.peek(), kw) {
+		p.next()
+		if filter, err = parseOperand(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return &FilterMsg{Type: typ, Filters: filters}, nil
+}
+
+func (p *parser) parseFactor() (*FilterMsg, error) {
+	tok := p.next()
+	switch {
+	case p.isKeyword(tok, kwNot):
+		filter, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return NewNotFilter(filter), nil
+	case tok.typ == tokLParen:
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.typ != tokRParen {
+			return nil, p.unexpected(tok, "')'")
+		}
+		return filter, nil
+	case tok.typ == tokIdent:
+		return p.parsePredicate(tok)
+	default:
+		return nil, p.unexpected(tok, "condition")
+	}
+}
+
+func (p *parser) parsePredicate(field token) (*FilterMsg, error) {
+	name := strings.ToLower(field.val)
+	switch name {
+	case fieldName:
+		return p.parseName()
+	case fieldECEncoded:
+		return ECEncodedFilterMsg(), nil
+	case fieldMisplaced:
+		return MisplacedFilterMsg(), nil
+	case fieldCloudVersionMismatch:
+		return CloudVersionMismatchFilterMsg(), nil
+	}
+
+	op := p.next()
+	if op.typ != tokOp {
+		return nil, p.unexpected(op, "comparison operator")
+	}
+	switch {
+	case name == fieldSize || name == fieldVersion || name == fieldCopies:
+		tok := p.next()
+		if tok.typ != tokNumber {
+			return nil, p.unexpected(tok, "number")
+		}
+		var (
+			v   int64
+			err error
+		)
+		if name == fieldSize {
+			v, err = cmn.S2B(tok.val)
+		} else {
+			v, err = strconv.ParseInt(tok.val, 10, 64)
+		}
+		if err != nil || v < 0 {
+			return nil, &ParseError{tok.pos, fmt.Sprintf("invalid %s %q", name, tok.val)}
+		}
+		return rangeFilterMsg(name, op, v)
+	case name == fieldAtime:
+		tok, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseAtime(tok.val)
+		if err != nil {
+			return nil, &ParseError{tok.pos, err.Error()}
+		}
+		switch op.val {
+		case "<":
+			return ATimeBeforeFilterMsg(t), nil
+		case "<=":
+			return ATimeBeforeFilterMsg(t.Add(time.Nanosecond)), nil
+		case ">":
+			return ATimeAfterFilterMsg(t), nil
+		case ">=":
+			return ATimeAfterFilterMsg(t.Add(-time.Nanosecond)), nil
+		}
+	default:
+		var eq func(v string) *FilterMsg
+		switch {
+		case name == fieldExt:
+			eq = func(v string) *FilterMsg { return &FilterMsg{Type: FUNCTION, FName: ExtF, Args: []string{v}} }
+		case name == fieldCksum:
+			eq = CksumValueFilterMsg
+		case name == fieldCksumType:
+			eq = CksumTypeFilterMsg
+		case strings.HasPrefix(name, fieldUserMD) && len(name) > len(fieldUserMD):
+			eq = func(v string) *FilterMsg { return UserMDFilterMsg(name[len(fieldUserMD):], v) }
+		case strings.HasPrefix(name, fieldCustomMD) && len(name) > len(fieldCustomMD):
+			// unlike user metadata, custom metadata keys are case-sensitive
+			eq = func(v string) *FilterMsg { return CustomMDFilterMsg(field.val[len(fieldCustomMD):], v) }
+		default:
+			return nil, &ParseError{field.pos, fmt.Sprintf("unknown field %q", field.val)}
+		}
+		tok, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		switch op.val {
+		case "=":
+			return eq(tok.val), nil
+		case "!=", "<>":
+			return NewNotFilter(eq(tok.val)), nil
+		}
+	}
+	return nil, &ParseError{op.pos, fmt.Sprintf("operator %q is not supported for %q", op.val, field.val)}
+}
+
+// name LIKE|GLOB|REGEXP 'pattern'
+func (p *parser) parseName() (*FilterMsg, error) {
+	tok := p.next()
+	var newMsg func(string) *FilterMsg
+	switch {
+	case p.isKeyword(tok, kwLike):
+		newMsg = NameLikeFilterMsg
+	case p.isKeyword(tok, kwGlob):
+		newMsg = NameGlobFilterMsg
+	case p.isKeyword(tok, kwRegexp):
+		newMsg = NameRegexFilterMsg
+	default:
+		return nil, p.unexpected(tok, kwLike+", "+kwGlob+", or "+kwRegexp)
+	}
+	pattern, err := p.expectString()
+	if err != nil {
+		return nil, err
+	}
+	filter := newMsg(pattern.val)
+	// validate right away to report the position
+	if _, err := ObjFilterFromMsg(filter); err != nil {
+		return nil, &ParseError{pattern.pos, err.Error()}
+	}
+	return filter, nil
+}
+
+func (p *parser) expectString() (token, error) {
+	tok := p.next()
+	if tok.typ != tokString {
+		return tok, p.unexpected(tok, "quoted string")
+	}
+	return tok, nil
+}
+
+// size, version, and copies: maps comparison operators onto (inclusive) range filters
+func rangeFilterMsg(name string, op token, v int64) (*FilterMsg, error) {
+	var le, ge, eq func(n int64) *FilterMsg
+	switch name {
+	case fieldSize:
+		le, ge, eq = SizeLEFilterMsg, SizeGEFilterMsg, func(n int64) *FilterMsg { return SizeFilterMsg(n, n) }
+	case fieldVersion:
+		le, ge, eq = VersionLEFilterMsg, VersionGEFilterMsg, func(n int64) *FilterMsg { return VersionFilterMsg(n, n) }
+	default:
+		le = func(n int64) *FilterMsg { return CopiesFilterMsg(0, n) }
+		ge, eq = CopiesGEFilterMsg, func(n int64) *FilterMsg { return CopiesFilterMsg(n, n) }
+	}
+	switch op.val {
+	case "=":
+		return eq(v), nil
+	case "!=", "<>":
+		return NewNotFilter(eq(v)), nil
+	case "<":
+		if v == 0 {
+			return nil, &ParseError{op.pos, fmt.Sprintf("%s cannot be negative", name)}
+		}
+		return le(v - 1), nil
+	case "<=":
+		return le(v), nil
+	case ">":
+		return ge(v + 1), nil
+	case ">=":
+		return ge(v), nil
+	}
+	return nil, &ParseError{op.pos, fmt.Sprintf("operator %q is not supported for %q", op.val, name)}
+}
+
+// name LIKE 'prefix%...' in the top-level conjunction narrows down the objects
+// to iterate over (the filter itself remains in place)
+func namePrefix(filter *FilterMsg) string {
+	filters := []*FilterMsg{filter}
+	if filter.Type == AND {
+		filters = filter.Filters
+	}
+	for _, f := range filters {
+		if f.Type == FUNCTION && f.FName == NameLikeF {
+			if idx := strings.IndexAny(f.Args[0], "%_"); idx != 0 {
+				if idx < 0 {
+					return f.Args[0]
+				}
+				return f.Args[0][:idx]
+			}
+		}
+	}
+	return ""
+}
+
+func parseAtime(s string) (t time.Time, err error) {
+	for _, layout := range atimeLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			return
+		}
+	}
+	return t, fmt.Errorf("invalid time %q (expecting RFC3339, 'YYYY-MM-DD hh:mm:ss', or 'YYYY-MM-DD')", s)
+}
@@ -0,0 +1,192 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestParse(t *testing.T) {
+	atime, _ := time.Parse("2006-01-02", "2020-01-01")
+	tests := []struct {
+		query    string
+		props    string
+		bck      cmn.Bck
+		prefix   string
+		expected *FilterMsg
+	}{
+		{
+			query: "SELECT * FROM ais://imgs",
+			props: strings.Join(cmn.GetPropsAll, ","),
+			bck:   cmn.Bck{Name: "imgs", Provider: cmn.ProviderAIS},
+		},
+		{
+			query:  "select name, size from ais://imgs/train/ where size >= 10",
+			props:  "name,size",
+			bck:    cmn.Bck{Name: "imgs", Provider: cmn.ProviderAIS},
+			prefix: "train/",
+			expected: &FilterMsg{
+				Type: FUNCTION, FName: SizeGeF, Args: []string{"10"},
+			},
+		},
+		{
+			query:  "SELECT name,size FROM ais://imgs WHERE size > 1MiB AND atime < '2020-01-01' AND name LIKE 'train/%'",
+			props:  "name,size",
+			bck:    cmn.Bck{Name: "imgs", Provider: cmn.ProviderAIS},
+			prefix: "train/",
+			expected: NewAndFilter(
+				SizeGEFilterMsg(cmn.MiB+1),
+				ATimeBeforeFilterMsg(atime),
+				NameLikeFilterMsg("train/%"),
+			),
+		},
+		{
+			query: "SELECT name FROM imgs WHERE (ext = 'jpg' OR ext = 'png') AND user_md.Label = 'cat''s' OR version <= 2",
+			props: "name",
+			bck:   cmn.Bck{Name: "imgs"},
+			expected: NewOrFilter(
+				NewAndFilter(
+					NewOrFilter(
+						&FilterMsg{Type: FUNCTION, FName: ExtF, Args: []string{"jpg"}},
+						&FilterMsg{Type: FUNCTION, FName: ExtF, Args: []string{"png"}},
+					),
+					UserMDFilterMsg("label", "cat's"),
+				),
+				VersionLEFilterMsg(2),
+			),
+		},
+		{
+			query: "SELECT name FROM ais://imgs WHERE NOT (name GLOB '*.jpg' OR misplaced) AND copies != 2 AND custom_md.source = 'gcp'",
+			props: "name",
+			bck:   cmn.Bck{Name: "imgs", Provider: cmn.ProviderAIS},
+			expected: NewAndFilter(
+				NewNotFilter(NewOrFilter(NameGlobFilterMsg("*.jpg"), MisplacedFilterMsg())),
+				NewNotFilter(CopiesFilterMsg(2, 2)),
+				CustomMDFilterMsg("source", "gcp"),
+			),
+		},
+	}
+	for _, test := range tests {
+		msg, err := Parse(test.query)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.query, err)
+		}
+		if msg.InnerSelect.Props != test.props {
+			t.Errorf("%q: expected props %q, got %q", test.query, test.props, msg.InnerSelect.Props)
+		}
+		if !msg.From.Bck.Equal(test.bck) {
+			t.Errorf("%q: expected bucket %s, got %s", test.query, test.bck, msg.From.Bck)
+		}
+		if msg.OuterSelect.Prefix != test.prefix {
+			t.Errorf("%q: expected prefix %q, got %q", test.query, test.prefix, msg.OuterSelect.Prefix)
+		}
+		if !reflect.DeepEqual(msg.Where.Filter, test.expected) {
+			t.Errorf("%q: expected filter %s, got %s", test.query, cmn.MustMarshal(test.expected), cmn.MustMarshal(msg.Where.Filter))
+		}
+		if _, err := ObjFilterFromMsg(msg.Where.Filter); err != nil {
+			t.Errorf("%q: invalid filter: %v", test.query, err)
+		}
+	}
+}
+
+func TestParseAggregate(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected *AggregateMsg
+	}{
+		{
+			query:    "SELECT count(*) FROM ais://imgs",
+			expected: &AggregateMsg{Columns: []string{AggrCount}},
+		},
+		{
+			query: "SELECT COUNT(*), sum(size), max(size) FROM ais://imgs WHERE size > 1 GROUP BY prefix(2)",
+			expected: &AggregateMsg{
+				GroupBy: GroupByPrefix, Depth: 2, Columns: []string{AggrCount, AggrSum, AggrMax},
+			},
+		},
+		{
+			query: "SELECT min(size), histogram(atime, '24h') FROM ais://imgs GROUP BY ext",
+			expected: &AggregateMsg{
+				GroupBy: GroupByExt, AtimeStep: int64(24 * time.Hour), Columns: []string{AggrMin, AggrHistogram},
+			},
+		},
+	}
+	for _, test := range tests {
+		msg, err := Parse(test.query)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.query, err)
+		}
+		if !reflect.DeepEqual(msg.Aggregate, test.expected) {
+			t.Errorf("%q: expected %s, got %s", test.query, cmn.MustMarshal(test.expected), cmn.MustMarshal(msg.Aggregate))
+		}
+		if err := msg.Aggregate.Validate(); err != nil {
+			t.Errorf("%q: invalid aggregate: %v", test.query, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"", 1},
+		{"SELECT size ais://imgs", 13},
+		{"SELECT bogus FROM ais://imgs", 8},
+		{"SELECT name FROM", 17},
+		{"SELECT name FROM ais://imgs WHERE size > 'big'", 42},
+		{"SELECT name FROM ais://imgs WHERE atime = '2020-01-01'", 41},
+		{"SELECT name FROM ais://imgs WHERE atime < 'yesterday'", 43},
+		{"SELECT name FROM ais://imgs WHERE (size > 1 OR size < 1", 56},
+		{"SELECT name FROM ais://imgs WHERE name LIKE 'abc", 45},
+		{"SELECT name FROM ais://imgs WHERE size > 1 LIMIT 10", 44},
+		{"SELECT name FROM ais://imgs WHERE color = 'red'", 35},
+		{"SELECT name FROM ais://imgs WHERE name REGEXP 'a(b'", 47},
+		{"SELECT name FROM ais://imgs WHERE NOT", 38},
+		{"SELECT name, count(*) FROM ais://imgs", 14},
+		{"SELECT count(*), name FROM ais://imgs", 18},
+		{"SELECT sum(atime) FROM ais://imgs", 12},
+		{"SELECT histogram(atime, 'daily') FROM ais://imgs", 25},
+		{"SELECT name FROM ais://imgs GROUP BY ext", 29},
+		{"SELECT count(*) FROM ais://imgs GROUP BY prefix(0)", 49},
+		{"SELECT count(*) FROM ais://imgs GROUP BY size", 42},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.query)
+		if err == nil {
+			t.Errorf("%q: expected error", test.query)
+			continue
+		}
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q: expected parse error, got %v", test.query, err)
+			continue
+		}
+		if perr.Pos != test.pos {
+			t.Errorf("%q: expected error at position %d, got %v", test.query, test.pos, err)
+		}
+	}
+}
+
+func TestFilterMsgErrors(t *testing.T) {
+	tests := []*FilterMsg{
+		{Type: NOT, Filters: []*FilterMsg{SizeLEFilterMsg(1), SizeGEFilterMsg(2)}},
+		{Type: AND, Filters: []*FilterMsg{SizeLEFilterMsg(1)}},
+		NameGlobFilterMsg("[a-"),
+		NameRegexFilterMsg("*"),
+		{Type: FUNCTION, FName: CopiesF, Args: []string{"1"}},
+		{Type: FUNCTION, FName: "unknown"},
+	}
+	for _, filter := range tests {
+		if _, err := ObjFilterFromMsg(filter); err == nil {
+			t.Errorf("expected error for filter %s", cmn.MustMarshal(filter))
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Persisted query results: instead of keeping the results in memory until
+// they are fetched (see NextMsg), the proxy materializes them into an AIS
+// bucket as a sequence of pages - JSON-encoded lists of entries - and a manifest:
+//
+//   [prefix/]handle/manifest.json
+//   [prefix/]handle/page-000000.json
+//   [prefix/]handle/page-000001.json
+//   ...
+//
+// Since the results are regular objects, they survive proxy restarts and can
+// be read via any proxy. The manifest is updated after each page is written,
+// and serves as the checkpoint: materialization that did not complete can be
+// resumed (see PersistMsg.Resume). Clients read the pages one by one, starting
+// from the beginning or from a previously returned token (page number).
+
+const (
+	DefaultPersistPageSize = 1000
+
+	manifestObjName = "manifest.json"
+)
+
+// ErrNoMoreResults is returned when all persisted results have been read.
+var ErrNoMoreResults = errors.New("no more query results")
+
+type (
+	PersistMsg struct {
+		Bck      cmn.Bck `json:"bucket"`              // AIS bucket to store the results in
+		Prefix   string  `json:"prefix,omitempty"`    // optional prefix of the results' object names
+		PageSize uint    `json:"page_size,omitempty"` // number of entries per page; DefaultPersistPageSize if 0
+		// when set, resume materialization of the previously persisted query
+		// with the given handle (the query itself is taken from the manifest)
+		Resume string `json:"resume,omitempty"`
+	}
+
+	ResultsManifest struct {
+		Handle  string `json:"handle"`
+		Query   DefMsg `json:"query"`
+		Pages   int    `json:"pages"`          // number of pages written so far
+		Entries int64  `json:"entries"`        // total number of entries in all pages
+		Last    string `json:"last,omitempty"` // name of the last persisted entry (see AddPage)
+		Done    bool   `json:"done"`           // true when all results have been persisted
+		Err     string `json:"error,omitempty"`
+	}
+)
+
+// AddPage accounts for the page of entries that has been persisted. For the
+// query over template, Last is the entry furthest in the template's sequence,
+// which is not necessarily the greatest name (e.g., "obj-9" follows "obj-10"
+// in the sorted page of `obj-{1..100}`).
+func (m *ResultsManifest) AddPage(entries []*cmn.BucketEntry) {
+	m.Pages++
+	m.Entries += int64(len(entries))
+	if m.Query.OuterSelect.Template == "" {
+		m.Last = entries[len(entries)-1].Name
+		return
+	}
+	pt, err := cmn.ParseBashTemplate(m.Query.OuterSelect.Template)
+	if err != nil {
+		m.Last = entries[len(entries)-1].Name
+		return
+	}
+	lastIdx, ok := pt.Index(m.Last)
+	if !ok {
+		lastIdx = -1
+	}
+	for _, entry := range entries {
+		if idx, ok := pt.Index(entry.Name); ok && idx > lastIdx {
+			m.Last, lastIdx = entry.Name, idx
+		}
+	}
+}
+
+func (msg *PersistMsg) Validate() error {
+	if !msg.Bck.IsAIS() {
+		return fmt.Errorf("query results can only be persisted in AIS bucket (got %s)", msg.Bck)
+	}
+	if msg.PageSize == 0 {
+		msg.PageSize = DefaultPersistPageSize
+	}
+	return nil
+}
+
+func (msg *PersistMsg) ManifestName(handle string) string {
+	return path.Join(msg.Prefix, handle, manifestObjName)
+}
+
+func (msg *PersistMsg) PageName(handle string, page int) string {
+	return path.Join(msg.Prefix, handle, fmt.Sprintf("page-%06d.json", page))
+}
+
+// PageToken returns the token that refers to the given page; the tokens are
+// stable and can be used to resume reading the results.
+func PageToken(page int) string { return strconv.Itoa(page) }
+
+// ParsePageToken returns the page number; empty token refers to the first page.
+func ParsePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	page, err := strconv.Atoi(token)
+	if err != nil || page < 0 {
+		return 0, fmt.Errorf("invalid query results token %q", token)
+	}
+	return page, nil
+}
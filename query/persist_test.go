@@ -0,0 +1,90 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestPersistMsg(t *testing.T) {
+	msg := &PersistMsg{Bck: cmn.Bck{Name: "results", Provider: cmn.ProviderAIS}, Prefix: "queries"}
+	if err := msg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if msg.PageSize != DefaultPersistPageSize {
+		t.Errorf("expected default page size, got %d", msg.PageSize)
+	}
+	if name := msg.ManifestName("h1"); name != "queries/h1/manifest.json" {
+		t.Errorf("unexpected manifest name %q", name)
+	}
+	if name := msg.PageName("h1", 12); name != "queries/h1/page-000012.json" {
+		t.Errorf("unexpected page name %q", name)
+	}
+
+	cloud := &PersistMsg{Bck: cmn.Bck{Name: "results", Provider: cmn.ProviderAmazon}}
+	if err := cloud.Validate(); err == nil {
+		t.Error("expected error: results cannot be persisted in Cloud bucket")
+	}
+}
+
+func TestPageToken(t *testing.T) {
+	for _, page := range []int{0, 1, 1000} {
+		if p, err := ParsePageToken(PageToken(page)); err != nil || p != page {
+			t.Errorf("expected page %d, got %d (err: %v)", page, p, err)
+		}
+	}
+	if p, err := ParsePageToken(""); err != nil || p != 0 {
+		t.Errorf("expected first page, got %d (err: %v)", p, err)
+	}
+	for _, token := range []string{"-1", "abc"} {
+		if _, err := ParsePageToken(token); err == nil {
+			t.Errorf("%q: expected error", token)
+		}
+	}
+}
+
+func TestResultsManifestAddPage(t *testing.T) {
+	const template = "obj-{1..20}"
+	pt, err := cmn.ParseBashTemplate(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		names    = pt.ToSlice()
+		manifest = &ResultsManifest{Query: DefMsg{OuterSelect: OuterSelectMsg{Template: template}}}
+	)
+	// pages are sorted by name: obj-10 < obj-6 < obj-9
+	for _, page := range [][]string{names[:5], names[5:10]} {
+		entries := make([]*cmn.BucketEntry, 0, len(page))
+		for _, name := range page {
+			entries = append(entries, &cmn.BucketEntry{Name: name})
+		}
+		cmn.SortBckEntries(entries)
+		manifest.AddPage(entries)
+	}
+	if manifest.Pages != 2 || manifest.Entries != 10 {
+		t.Errorf("expected 2 pages and 10 entries, got %d and %d", manifest.Pages, manifest.Entries)
+	}
+	if manifest.Last != "obj-10" {
+		t.Fatalf("expected last entry %q, got %q", "obj-10", manifest.Last)
+	}
+
+	// resumed query skips up to and including the last entry's position
+	idx, ok := pt.Index(manifest.Last)
+	if !ok {
+		t.Fatalf("%q is not generated by %q", manifest.Last, template)
+	}
+	if rest := names[idx+1:]; len(rest) != 10 || rest[0] != "obj-11" {
+		t.Errorf("expected to resume at %q with 10 names left, got %v", "obj-11", rest)
+	}
+
+	prefix := &ResultsManifest{}
+	prefix.AddPage([]*cmn.BucketEntry{{Name: "a/1"}, {Name: "a/2"}})
+	if prefix.Last != "a/2" {
+		t.Errorf("expected last entry %q, got %q", "a/2", prefix.Last)
+	}
+}
@@ -5,6 +5,7 @@
 package query
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/NVIDIA/aistore/cluster"
@@ -14,8 +15,9 @@ import (
 type (
 	ObjectsSource struct {
 		// regexp *regexp.Regexp // support in the future
-		Pt     *cmn.ParsedTemplate
-		Prefix string
+		Pt         *cmn.ParsedTemplate
+		Prefix     string
+		StartAfter string
 	}
 
 	BucketSource struct {
@@ -33,7 +35,10 @@ type (
 		Select        InnerSelect
 		Fast          bool
 		Cached        bool
+		Misplaced     bool           // include misplaced objects (as in: when filtering by MisplacedF)
+		Content       *ContentFilter // content select (see ContentMsg)
 		filter        cluster.ObjectFilter
+		aggr          *aggregator
 	}
 )
 
@@ -46,12 +51,25 @@ func NewQuery(source *ObjectsSource, bckSource *BucketSource, filter cluster.Obj
 }
 
 func (q *ObjectsQuery) Filter() cluster.ObjectFilter {
-	if q.filter != nil {
-		return q.filter
+	filter := q.filter
+	if filter == nil {
+		filter = func(*cluster.LOM) bool { return true }
+	}
+	if q.aggr == nil {
+		return filter
+	}
+	// aggregation: account for the matching objects and return none
+	return func(lom *cluster.LOM) bool {
+		if filter(lom) {
+			q.aggr.add(lom)
+		}
+		return false
 	}
-	return func(*cluster.LOM) bool { return true }
 }
 
+// IsAggregate returns true for aggregation queries (see AggregateMsg).
+func (q *ObjectsQuery) IsAggregate() bool { return q.aggr != nil }
+
 func TemplateObjSource(template string) (*ObjectsSource, error) {
 	pt, err := cmn.ParseBashTemplate(template)
 	if err != nil {
@@ -85,6 +103,7 @@ func NewQueryFromMsg(node cluster.Node, msg *DefMsg) (q *ObjectsQuery, err error
 	} else {
 		q.ObjectsSource = AllObjSource()
 	}
+	q.ObjectsSource.StartAfter = msg.OuterSelect.StartAfter
 
 	if msg.InnerSelect.Props != "" {
 		q.Select.Props = msg.InnerSelect.Props
@@ -98,5 +117,22 @@ func NewQueryFromMsg(node cluster.Node, msg *DefMsg) (q *ObjectsQuery, err error
 	if q.filter, err = ObjFilterFromMsg(msg.Where.Filter); err != nil {
 		return nil, err
 	}
+	q.Misplaced = msg.Where.Filter.Uses(MisplacedF)
+	if msg.Aggregate != nil {
+		if err = msg.Aggregate.Validate(); err != nil {
+			return nil, err
+		}
+		q.aggr = newAggregator(msg.Aggregate)
+		q.Cached = true // only the objects present in the cluster
+	}
+	if msg.InnerSelect.Content != nil {
+		if q.aggr != nil {
+			return nil, errors.New("aggregation of object contents is not supported")
+		}
+		if q.Content, err = NewContentFilter(msg.InnerSelect.Content); err != nil {
+			return nil, err
+		}
+		q.Cached = true
+	}
 	return q, nil
 }
@@ -0,0 +1,379 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Row conditions and S3 Select expressions, e.g.:
+//
+//   SELECT s.name, s.city FROM S3Object s WHERE CAST(s.age AS INT) > 30 AND s.city LIKE 'San%' LIMIT 10
+//
+// Grammar (keywords are case-insensitive):
+//
+//   select  := SELECT cols FROM S3Object [[AS] alias] [WHERE expr] [LIMIT number]
+//   cols    := '*' | col [',' col]...
+//   expr    := term [OR term]...
+//   term    := factor [AND factor]...
+//   factor  := NOT factor | '(' expr ')' | col op value | col [NOT] LIKE 'pattern'
+//   col     := name | _N | CAST '(' col AS type ')'
+//   op      := '=' | '!=' | '<>' | '<' | '<=' | '>' | '>='
+//
+// Columns are referred to by name (CSV header or JSON key) or by 1-based
+// position (`_1`, `_2`, ...). Values are compared as numbers when both the
+// literal and the column value are numeric, and as strings otherwise.
+
+const (
+	kwLimit    = "LIMIT"
+	kwCast     = "CAST"
+	kwAs       = "AS"
+	kwS3Object = "S3Object"
+)
+
+type (
+	// RowExpr is a condition on the row of a CSV or JSON-lines object.
+	RowExpr interface {
+		Eval(r row) bool
+		String() string
+	}
+
+	rowCmp struct {
+		col   string
+		op    string
+		val   string
+		num   float64
+		isNum bool
+	}
+	rowLike struct {
+		col     string
+		pattern string
+		re      *regexp.Regexp
+	}
+	rowAnd []RowExpr
+	rowOr  []RowExpr
+	rowNot struct{ expr RowExpr }
+)
+
+// ParseRowExpr parses row condition (see ContentMsg.Where).
+func ParseRowExpr(expr string) (RowExpr, error) {
+	p := &parser{src: expr}
+	p.tokenize()
+	e, err := p.parseRowOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, p.unexpected(tok, "end of expression")
+	}
+	return e, nil
+}
+
+// ParseSelectExpr parses S3 Select expression into content select message;
+// the caller is expected to fill in the input and output formats.
+func ParseSelectExpr(expr string) (*ContentMsg, error) {
+	p := &parser{src: expr}
+	p.tokenize()
+	p.alias = p.selectAlias()
+	msg := &ContentMsg{}
+	if _, err := p.expectKeyword(kwSelect); err != nil {
+		return nil, err
+	}
+	if p.peek().typ == tokStar {
+		p.next()
+	} else {
+		for {
+			col, err := p.parseColumn()
+			if err != nil {
+				return nil, err
+			}
+			msg.Columns = append(msg.Columns, col)
+			if p.peek().typ != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if _, err := p.expectKeyword(kwFrom); err != nil {
+		return nil, err
+	}
+	if tok := p.next(); !strings.EqualFold(tok.val, kwS3Object) && !strings.EqualFold(tok.val, kwS3Object+"[*]") {
+		return nil, p.unexpected(tok, kwS3Object)
+	}
+	if p.alias != "" {
+		if p.isKeyword(p.peek(), kwAs) {
+			p.next()
+		}
+		p.next()
+	}
+	if p.isKeyword(p.peek(), kwWhere) {
+		p.next()
+		where, err := p.parseRowOr()
+		if err != nil {
+			return nil, err
+		}
+		msg.Where = where.String()
+	}
+	if p.isKeyword(p.peek(), kwLimit) {
+		p.next()
+		tok := p.next()
+		n, err := strconv.ParseInt(tok.val, 10, 64)
+		if tok.typ != tokNumber || err != nil || n <= 0 {
+			return nil, p.unexpected(tok, "positive number")
+		}
+		msg.Limit = n
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, p.unexpected(tok, "end of query")
+	}
+	return msg, nil
+}
+
+// selectAlias returns the alias that follows `FROM S3Object`, if any
+func (p *parser) selectAlias() string {
+	for i, tok := range p.toks {
+		if !p.isKeyword(tok, kwFrom) || i+2 >= len(p.toks) {
+			continue
+		}
+		alias := p.toks[i+2]
+		if p.isKeyword(alias, kwAs) && i+3 < len(p.toks) {
+			alias = p.toks[i+3]
+		}
+		if alias.typ != tokIdent || p.isKeyword(alias, kwWhere) || p.isKeyword(alias, kwLimit) {
+			return ""
+		}
+		return alias.val
+	}
+	return ""
+}
+
+func (p *parser) parseRowOr() (RowExpr, error) {
+	var exprs rowOr
+	for {
+		e, err := p.parseRowAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if !p.isKeyword(p.peek(), kwOr) {
+			break
+		}
+		p.next()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) parseRowAnd() (RowExpr, error) {
+	var exprs rowAnd
+	for {
+		e, err := p.parseRowFactor()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if !p.isKeyword(p.peek(), kwAnd) {
+			break
+		}
+		p.next()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) parseRowFactor() (RowExpr, error) {
+	tok := p.peek()
+	switch {
+	case p.isKeyword(tok, kwNot):
+		p.next()
+		e, err := p.parseRowFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &rowNot{e}, nil
+	case tok.typ == tokLParen:
+		p.next()
+		e, err := p.parseRowOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.typ != tokRParen {
+			return nil, p.unexpected(tok, "')'")
+		}
+		return e, nil
+	}
+	col, err := p.parseColumn()
+	if err != nil {
+		return nil, err
+	}
+	tok = p.next()
+	if p.isKeyword(tok, kwNot) || p.isKeyword(tok, kwLike) {
+		not := p.isKeyword(tok, kwNot)
+		if not {
+			if _, err := p.expectKeyword(kwLike); err != nil {
+				return nil, err
+			}
+		}
+		pattern, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		var e RowExpr = &rowLike{col: col, pattern: pattern.val, re: likeRegexp(pattern.val)}
+		if not {
+			e = &rowNot{e}
+		}
+		return e, nil
+	}
+	if tok.typ != tokOp {
+		return nil, p.unexpected(tok, "comparison operator or LIKE")
+	}
+	cmp := &rowCmp{col: col, op: tok.val}
+	if cmp.op == "<>" {
+		cmp.op = "!="
+	}
+	val := p.next()
+	switch {
+	case val.typ == tokString:
+		cmp.val = val.val
+	case val.typ == tokNumber || val.typ == tokIdent && strings.HasPrefix(val.val, "-"):
+		n, err := strconv.ParseFloat(val.val, 64)
+		if err != nil {
+			return nil, &ParseError{val.pos, fmt.Sprintf("invalid number %q", val.val)}
+		}
+		cmp.val, cmp.num, cmp.isNum = val.val, n, true
+	default:
+		return nil, p.unexpected(val, "number or string")
+	}
+	return cmp, nil
+}
+
+// parseColumn returns the column name, with the alias (if any) stripped;
+// CAST is accepted and ignored - numeric comparison is automatic
+func (p *parser) parseColumn() (string, error) {
+	tok := p.next()
+	if tok.typ != tokIdent || p.isKeyword(tok, kwFrom) {
+		return "", p.unexpected(tok, "column name")
+	}
+	if p.isKeyword(tok, kwCast) && p.peek().typ == tokLParen {
+		p.next()
+		col, err := p.parseColumn()
+		if err != nil {
+			return "", err
+		}
+		if _, err := p.expectKeyword(kwAs); err != nil {
+			return "", err
+		}
+		if typ := p.next(); typ.typ != tokIdent {
+			return "", p.unexpected(typ, "type name")
+		}
+		if tok := p.next(); tok.typ != tokRParen {
+			return "", p.unexpected(tok, "')'")
+		}
+		return col, nil
+	}
+	col := tok.val
+	if p.alias != "" && strings.HasPrefix(col, p.alias+".") {
+		col = col[len(p.alias)+1:]
+	}
+	return col, nil
+}
+
+/////////////////
+// row filters //
+/////////////////
+
+func (e *rowCmp) Eval(r row) bool {
+	v, ok := r.get(e.col)
+	if !ok {
+		return false
+	}
+	var (
+		res int
+		s   = valueString(v)
+	)
+	if n, err := strconv.ParseFloat(s, 64); e.isNum && err == nil {
+		switch {
+		case n < e.num:
+			res = -1
+		case n > e.num:
+			res = 1
+		}
+	} else {
+		res = strings.Compare(s, e.val)
+	}
+	switch e.op {
+	case "=":
+		return res == 0
+	case "!=":
+		return res != 0
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	case ">":
+		return res > 0
+	case ">=":
+		return res >= 0
+	default:
+		return false
+	}
+}
+
+func (e *rowCmp) String() string {
+	if e.isNum {
+		return e.col + " " + e.op + " " + e.val
+	}
+	return e.col + " " + e.op + " " + quote(e.val)
+}
+
+func (e *rowLike) Eval(r row) bool {
+	v, ok := r.get(e.col)
+	return ok && e.re.MatchString(valueString(v))
+}
+
+func (e *rowLike) String() string { return e.col + " LIKE " + quote(e.pattern) }
+
+func (e rowAnd) Eval(r row) bool {
+	for _, inner := range e {
+		if !inner.Eval(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e rowAnd) String() string { return join(e, " AND ") }
+
+func (e rowOr) Eval(r row) bool {
+	for _, inner := range e {
+		if inner.Eval(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e rowOr) String() string { return join(e, " OR ") }
+
+func (e *rowNot) Eval(r row) bool { return !e.expr.Eval(r) }
+
+func (e *rowNot) String() string { return "NOT (" + e.expr.String() + ")" }
+
+func join(exprs []RowExpr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = "(" + e.String() + ")"
+	}
+	return strings.Join(parts, sep)
+}
+
+func quote(s string) string { return "'" + strings.Replace(s, "'", "''", -1) + "'" }
@@ -6,6 +6,7 @@ package query
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -67,6 +68,11 @@ func (r *ObjectsListingXact) IsMountpathXact() bool { return false } // TODO --
 func (r *ObjectsListingXact) Start() {
 	defer func() {
 		r.fetchingDone = true
+		if r.query.IsAggregate() {
+			// nothing to fetch: keep the result around for the proxy to collect
+			r.Finish()
+			time.AfterFunc(xactionTTL, func() { Registry.Delete(r.ID().String()) })
+		}
 	}()
 
 	cmn.Assert(r.query.ObjectsSource != nil)
@@ -106,15 +112,35 @@ func (r *ObjectsListingXact) startFromTemplate() {
 	}()
 
 	var (
-		iter   = r.query.ObjectsSource.Pt.Iter()
-		bck    = r.query.BckSource.Bck
-		config = cmn.GCO.Get()
-		smap   = r.t.GetSowner().Get()
+		pt         = r.query.ObjectsSource.Pt
+		iter       = pt.Iter()
+		bck        = r.query.BckSource.Bck
+		config     = cmn.GCO.Get()
+		smap       = r.t.GetSowner().Get()
+		startAfter = r.query.ObjectsSource.StartAfter
+		startIdx   = int64(-1)
+		idx        = int64(-1)
 	)
 
 	cmn.Assert(bck.IsAIS())
 
+	// Skip by the position in the template - the names generated by unpadded
+	// template are not sorted (see cmn.ParsedTemplate.Index).
+	if startAfter != "" {
+		if i, ok := pt.Index(startAfter); ok {
+			startIdx = i
+		}
+	}
+
 	for objName, hasNext := iter(); hasNext; objName, hasNext = iter() {
+		idx++
+		if startIdx >= 0 {
+			if idx <= startIdx {
+				continue
+			}
+		} else if startAfter != "" && objName <= startAfter {
+			continue
+		}
 		lom := &cluster.LOM{T: r.t, ObjName: objName}
 		if err := lom.Init(bck.Bck, config); err != nil {
 			r.putResult(&Result{err: err})
@@ -342,6 +368,14 @@ func (r *ObjectsListingXact) ForEach(apply func(entry *cmn.BucketEntry) error) e
 	return nil
 }
 
+// Aggregate returns (partial, if the query is still running) aggregation result.
+func (r *ObjectsListingXact) Aggregate() (*AggregateResult, error) {
+	if !r.query.IsAggregate() {
+		return nil, fmt.Errorf("%s is not an aggregation query", r)
+	}
+	return r.query.aggr.result(r.Finished()), nil
+}
+
 func (r *ObjectsListingXact) TokenFulfilled(token string) bool {
 	// Everything, that target has, has been already fetched.
 	return r.Finished() && !r.Aborted() && r.LastDiscardedResult() != "" && cmn.TokenIncludesObject(token, r.LastDiscardedResult())
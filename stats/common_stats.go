@@ -45,6 +45,7 @@ const (
 	KindLatency    = "latency"
 	KindThroughput = "throughput"
 	KindSpecial    = "special"
+	KindGauge      = "gauge" // the current value: set (not added) by Add()
 )
 
 // number-of-goroutines watermarks expressed as multipliers over the number of available logical CPUs (GOMAXPROCS)
@@ -54,7 +55,7 @@ const (
 )
 
 var (
-	kinds      = []string{KindCounter, KindLatency, KindThroughput, KindSpecial}
+	kinds      = []string{KindCounter, KindLatency, KindThroughput, KindSpecial, KindGauge}
 	goMaxProcs int
 )
 
@@ -77,9 +78,13 @@ const (
 	ErrListCount     = "err.list.n"
 	ErrRangeCount    = "err.range.n"
 	ErrDownloadCount = "err.dl.n"
+	// multi-object GET (see ais/prxbatch.go)
+	GetBatchCount    = "getbatch.n"     // requests
+	GetBatchObjCount = "getbatch.obj.n" // objects returned in all requests
 
 	// KindLatency
 	GetLatency          = "get.µs"
+	GetBatchLatency     = "getbatch.µs"
 	ListLatency         = "lst.µs"
 	KeepAliveMinLatency = "kalive.µs.min"
 	KeepAliveMaxLatency = "kalive.µs.max"
@@ -126,6 +131,19 @@ type (
 		RebID      int64 `json:"glob.id,string"`
 	}
 
+	ReplicTargetStats struct {
+		cmn.BaseXactStats
+		Ext ExtReplicStats `json:"ext"`
+	}
+
+	ExtReplicStats struct {
+		Dest    cmn.Bck          `json:"dest"`           // replication destination
+		Backlog int64            `json:"backlog,string"` // PUTs and DELETEs yet to be replicated
+		Lag     cmn.DurationJSON `json:"lag"`            // age of the oldest of them
+		Deleted int64            `json:"deleted,string"`
+		Errors  int64            `json:"errors,string"`
+	}
+
 	TargetStatus struct {
 		RebalanceStats *RebalanceTargetStats `json:"rebalance_stats,omitempty"`
 	}
@@ -265,6 +283,12 @@ func (s *CoreStats) doAdd(name, nameSuffix string, val int64) {
 			v.Value += val
 			v.Unlock()
 		}
+	case KindGauge:
+		nroot := name[:strings.LastIndexByte(name, '.')]
+		s.statsdC.Send(nroot, 1, metric{Type: statsd.Gauge, Name: "value", Value: val})
+		v.Lock()
+		v.Value = val
+		v.Unlock()
 	default:
 		cmn.AssertMsg(false, v.kind)
 	}
@@ -415,6 +439,9 @@ func (tracker statsTracker) registerCommonStats() {
 	tracker.register(ErrListCount, KindCounter, true)
 	tracker.register(ErrRangeCount, KindCounter, true)
 	tracker.register(ErrDownloadCount, KindCounter, true)
+	tracker.register(GetBatchCount, KindCounter, true)
+	tracker.register(GetBatchObjCount, KindCounter, true)
+	tracker.register(GetBatchLatency, KindLatency, true)
 
 	tracker.register(Uptime, KindSpecial, true)
 }
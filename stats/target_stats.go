@@ -30,6 +30,8 @@ const (
 	LruEvictCount  = "lru.evict.n"
 	VerChangeCount = "vchange.n"
 	VerChangeSize  = "vchange.size"
+	// cold GET
+	GetColdCoalescedCount = "get.cold.coalesced.n" // joined in-flight fetch (see ais/tgtcoldget.go)
 	// rebalance
 	RebTxCount = "reb.tx.n"
 	RebTxSize  = "reb.tx.size"
@@ -59,6 +61,21 @@ const (
 	// Downloader
 	DownloadSize = "dl.size"
 
+	// Replication to remote AIS cluster
+	ReplicPutCount = "replic.put.n"
+	ReplicPutSize  = "replic.put.size"
+	ReplicDelCount = "replic.del.n"
+	ErrReplicCount = "err.replic.n"
+
+	// Write-back to the cloud
+	WriteBackCount    = "wb.put.n"
+	WriteBackSize     = "wb.put.size"
+	ErrWriteBackCount = "err.wb.n"
+
+	// KindGauge
+	ReplicBacklog = "replic.backlog.n" // number of PUTs and DELETEs yet to be replicated
+	ReplicLag     = "replic.lag.µs"    // age of the oldest of them
+
 	// KindThroughput
 	GetThroughput = "get.bps" // bytes per second
 )
@@ -127,6 +144,7 @@ func (r *Trunner) RegisterAll() {
 	r.Register(AppendLatency, KindLatency)
 	r.Register(GetColdCount, KindCounter)
 	r.Register(GetColdSize, KindCounter)
+	r.Register(GetColdCoalescedCount, KindCounter)
 	r.Register(GetThroughput, KindThroughput)
 	r.Register(LruEvictSize, KindCounter)
 	r.Register(LruEvictCount, KindCounter)
@@ -154,6 +172,19 @@ func (r *Trunner) RegisterAll() {
 	r.Register(DownloadSize, KindCounter)
 	r.Register(DownloadLatency, KindLatency)
 
+	// replication
+	r.Register(ReplicPutCount, KindCounter)
+	r.Register(ReplicPutSize, KindCounter)
+	r.Register(ReplicDelCount, KindCounter)
+	r.Register(ErrReplicCount, KindCounter)
+	r.Register(ReplicBacklog, KindGauge)
+	r.Register(ReplicLag, KindGauge)
+
+	// write-back
+	r.Register(WriteBackCount, KindCounter)
+	r.Register(WriteBackSize, KindCounter)
+	r.Register(ErrWriteBackCount, KindCounter)
+
 	// dsort
 	r.Register(DSortCreationReqCount, KindCounter)
 	r.Register(DSortCreationReqLatency, KindLatency)
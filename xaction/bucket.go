@@ -18,6 +18,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/query"
+	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction/demand"
 )
 
@@ -310,6 +311,134 @@ func (r *registry) RenewPutMirror(lom *cluster.LOM) *mirror.XactPut {
 	return res.entry.Get().(*mirror.XactPut)
 }
 
+//
+// replicEntry
+//
+type replicEntry struct {
+	baseBckEntry
+	t      cluster.Target
+	statsT stats.Tracker
+	remote mirror.RemoteAIS
+	xact   *mirror.XactReplic
+}
+
+func (e *replicEntry) Start(bck cmn.Bck) error {
+	x := mirror.NewXactReplic(bck, e.t, e.statsT, e.remote)
+	go x.Run()
+	e.xact = x
+	return nil
+}
+func (*replicEntry) Kind() string    { return cmn.ActReplicate }
+func (e *replicEntry) Get() cmn.Xact { return e.xact }
+
+func (r *registry) RenewReplic(t cluster.Target, statsT stats.Tracker, remote mirror.RemoteAIS, bck *cluster.Bck) *mirror.XactReplic {
+	e := &replicEntry{t: t, statsT: statsT, remote: remote}
+	res := r.renewBucketXaction(e, bck)
+	if res.err != nil {
+		return nil
+	}
+	return res.entry.Get().(*mirror.XactReplic)
+}
+
+//
+// replicSyncEntry
+//
+type replicSyncEntry struct {
+	baseBckEntry
+	t      cluster.Target
+	statsT stats.Tracker
+	remote mirror.RemoteAIS
+	xact   *mirror.XactReplicSync
+}
+
+func (e *replicSyncEntry) Start(bck cmn.Bck) error {
+	var (
+		cbck   = cluster.NewBckEmbed(bck)
+		notify = func() {
+			if x := Registry.RenewReplic(e.t, e.statsT, e.remote, cbck); x != nil {
+				x.Notify()
+			}
+		}
+	)
+	if err := cbck.Init(e.t.GetBowner(), e.t.Snode()); err != nil {
+		return err
+	}
+	e.xact = mirror.NewXactReplicSync(bck, e.t, e.uuid, cbck.Props.Replication.Bck, e.remote, notify)
+	return nil
+}
+func (*replicSyncEntry) Kind() string    { return cmn.ActReplicSync }
+func (e *replicSyncEntry) Get() cmn.Xact { return e.xact }
+
+func (r *registry) RenewReplicSync(t cluster.Target, statsT stats.Tracker, remote mirror.RemoteAIS,
+	bck *cluster.Bck, uuid string) (*mirror.XactReplicSync, error) {
+	e := &replicSyncEntry{baseBckEntry: baseBckEntry{uuid}, t: t, statsT: statsT, remote: remote}
+	res := r.renewBucketXaction(e, bck)
+	if res.err != nil {
+		return nil, res.err
+	}
+	if !res.isNew {
+		return nil, fmt.Errorf("%s xaction already running", e.Kind())
+	}
+	return res.entry.Get().(*mirror.XactReplicSync), nil
+}
+
+//
+// writeBackEntry
+//
+type writeBackEntry struct {
+	baseBckEntry
+	t      cluster.Target
+	statsT stats.Tracker
+	xact   *mirror.XactWriteBack
+}
+
+func (e *writeBackEntry) Start(bck cmn.Bck) error {
+	x := mirror.NewXactWriteBack(bck, e.t, e.statsT)
+	go x.Run()
+	e.xact = x
+	return nil
+}
+func (*writeBackEntry) Kind() string    { return cmn.ActWriteBack }
+func (e *writeBackEntry) Get() cmn.Xact { return e.xact }
+
+func (r *registry) RenewWriteBack(t cluster.Target, statsT stats.Tracker, bck *cluster.Bck) *mirror.XactWriteBack {
+	e := &writeBackEntry{t: t, statsT: statsT}
+	res := r.renewBucketXaction(e, bck)
+	if res.err != nil {
+		return nil
+	}
+	return res.entry.Get().(*mirror.XactWriteBack)
+}
+
+//
+// flushEntry
+//
+type flushEntry struct {
+	baseBckEntry
+	t      cluster.Target
+	statsT stats.Tracker
+	xact   *mirror.XactFlush
+}
+
+func (e *flushEntry) Start(bck cmn.Bck) error {
+	e.xact = mirror.NewXactFlush(bck, e.t, e.statsT, e.uuid)
+	return nil
+}
+func (*flushEntry) Kind() string    { return cmn.ActFlush }
+func (e *flushEntry) Get() cmn.Xact { return e.xact }
+
+func (r *registry) RenewFlush(t cluster.Target, statsT stats.Tracker, bck *cluster.Bck, uuid string) (*mirror.XactFlush, error) {
+	e := &flushEntry{baseBckEntry: baseBckEntry{uuid}, t: t, statsT: statsT}
+	res := r.renewBucketXaction(e, bck)
+	if res.err != nil {
+		return nil, res.err
+	}
+	if !res.isNew {
+		return nil, fmt.Errorf("%s xaction already running", e.Kind())
+	}
+	return res.entry.Get().(*mirror.XactFlush), nil
+}
+
 //
 // bccEntry
 //
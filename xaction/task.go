@@ -16,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/ios"
+	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/objwalk"
 	"golang.org/x/sync/errgroup"
 )
@@ -215,6 +216,17 @@ func (t *bckSummaryTask) Run() error {
 				}
 			}
 
+			// objects that are yet to be written to the cloud (see cmn.WritePolicyConf)
+			if bck.IsCloud() {
+				backlog, err := mirror.DirtyQ.BckBacklog(bck.Bck)
+				if err != nil {
+					errCh <- err
+					return
+				}
+				summary.DirtyCount = uint64(backlog.Count)
+				summary.DirtySize = uint64(backlog.Size)
+			}
+
 			mtx.Lock()
 			summaries = append(summaries, summary)
 			mtx.Unlock()